@@ -0,0 +1,22 @@
+// Package dev holds the commands helping to develop CAD, e.g. to scaffold new investigations
+package dev
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewDevCmd creates the dev command
+func NewDevCmd() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for CAD developers",
+	}
+
+	newInvestigationCmd, err := newNewInvestigationCmd()
+	if err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(newInvestigationCmd)
+
+	return cmd, nil
+}
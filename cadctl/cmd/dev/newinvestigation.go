@@ -0,0 +1,257 @@
+package dev
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+const investigationsImportPath = "github.com/openshift/configuration-anomaly-detection/pkg/investigations"
+
+// investigationNameRegexp matches valid investigation names. The name is used as package and
+// directory name, which backplane-api resolves the metadata.yaml from.
+var investigationNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// platformIdentifiers maps the platforms accepted by --platform to their Go identifiers
+var platformIdentifiers = map[investigation.Platform]string{
+	investigation.PlatformClassic: "investigation.PlatformClassic",
+	investigation.PlatformHCP:     "investigation.PlatformHCP",
+	investigation.PlatformAWS:     "investigation.PlatformAWS",
+	investigation.PlatformGCP:     "investigation.PlatformGCP",
+}
+
+//go:embed templates
+var templates embed.FS
+
+// generatedFiles maps the templates of an investigation package to the files generated from them,
+// relative to the package directory. NAME is replaced by the investigation name.
+var generatedFiles = []struct {
+	template string
+	path     string
+}{
+	{"investigation.go.tmpl", "NAME.go"},
+	{"investigation_test.go.tmpl", "NAME_test.go"},
+	{"metadata.yaml.tmpl", "metadata.yaml"},
+	{"README.md.tmpl", "README.md"},
+	{"testing_README.md.tmpl", "testing/README.md"},
+}
+
+type newInvestigationOptions struct {
+	name        string
+	description string
+	alertTitle  string
+	platforms   []string
+	root        string
+}
+
+// templateData is the data the templates are rendered with
+type templateData struct {
+	Name        string
+	Description string
+	AlertTitle  string
+	// Platforms are the Go identifiers of the supported platforms
+	Platforms []string
+}
+
+func newNewInvestigationCmd() (*cobra.Command, error) {
+	opts := &newInvestigationOptions{}
+	cmd := &cobra.Command{
+		Use:          "new-investigation",
+		SilenceUsage: true,
+		Short:        "Generate the boilerplate of a new investigation",
+		Long: `Generates a new investigation package in pkg/investigations: the investigation, a table-driven
+test running it against the fake ResourceBuilder, a metadata.yaml skeleton and READMEs.
+The investigation is added to the registry in pkg/investigations/registry.go, and a config
+snippet running it for the alert is printed. Alerts are matched to investigations in the
+investigation config, see docs/investigation-config.md.`,
+		Example: `  cadctl dev new-investigation --name nodediskpressure --alert-title NodeDiskPressure \
+    --description "Checks which pods fill up the disks of nodes under disk pressure" --platform classic`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.run(cmd.OutOrStdout())
+		},
+	}
+	cmd.Flags().StringVar(&opts.name, "name", "", "the investigation name, also used as package and directory name (lowercase letters and digits)")
+	cmd.Flags().StringVar(&opts.description, "description", "TODO", "a one-line description of what the investigation checks")
+	cmd.Flags().StringVar(&opts.alertTitle, "alert-title", "", "the PagerDuty alert title substring the config snippet matches")
+	cmd.Flags().StringSliceVar(&opts.platforms, "platform", nil, "the platforms the investigation supports (classic, hcp, aws, gcp), defaults to all")
+	cmd.Flags().StringVar(&opts.root, "root", ".", "the root directory of the repository")
+	for _, flag := range []string{"name", "alert-title"} {
+		if err := cmd.MarkFlagRequired(flag); err != nil {
+			return nil, err
+		}
+	}
+
+	return cmd, nil
+}
+
+func (o *newInvestigationOptions) run(out io.Writer) error {
+	data, err := o.templateData()
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(o.root, "pkg", "investigations", o.name)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("investigation %s already exists in %s", o.name, dir)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Render everything before writing, so that a failure leaves the tree untouched
+	registryPath := filepath.Join(o.root, "pkg", "investigations", "registry.go")
+	registry, err := os.ReadFile(registryPath) // #nosec G304 -- the registry of the repository given by --root
+	if err != nil {
+		return fmt.Errorf("failed to read the registry: %w", err)
+	}
+	registry, err = addToRegistry(registry, o.name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w", o.name, registryPath, err)
+	}
+
+	contents := make([][]byte, len(generatedFiles))
+	for i, f := range generatedFiles {
+		if contents[i], err = render(f.template, data); err != nil {
+			return err
+		}
+	}
+	snippet, err := render("config.yaml.tmpl", data)
+	if err != nil {
+		return err
+	}
+
+	for i, f := range generatedFiles {
+		path := filepath.Join(dir, strings.ReplaceAll(f.path, "NAME", o.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, contents[i], 0o644); err != nil { // #nosec G306 -- source files are world readable
+			return err
+		}
+		fmt.Fprintf(out, "Created %s\n", path)
+	}
+	if err := os.WriteFile(registryPath, registry, 0o644); err != nil { // #nosec G306 -- source files are world readable
+		return err
+	}
+	fmt.Fprintf(out, "Registered %s in %s\n\n", o.name, registryPath)
+
+	_, err = out.Write(snippet)
+	return err
+}
+
+func (o *newInvestigationOptions) templateData() (templateData, error) {
+	if !investigationNameRegexp.MatchString(o.name) {
+		return templateData{}, fmt.Errorf("invalid investigation name %q: must start with a lowercase letter and contain only lowercase letters and digits", o.name)
+	}
+	if strings.TrimSpace(o.alertTitle) == "" {
+		return templateData{}, fmt.Errorf("the alert title must not be empty")
+	}
+
+	data := templateData{Name: o.name, Description: o.description, AlertTitle: o.alertTitle}
+	for _, p := range o.platforms {
+		identifier, ok := platformIdentifiers[investigation.Platform(p)]
+		if !ok {
+			return templateData{}, fmt.Errorf("unknown platform %q: must be one of classic, hcp, aws, gcp", p)
+		}
+		if !slices.Contains(data.Platforms, identifier) {
+			data.Platforms = append(data.Platforms, identifier)
+		}
+	}
+	return data, nil
+}
+
+// render renders a template, formatting the result if it is Go source
+func render(name string, data templateData) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"join": strings.Join}).ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return buf.Bytes(), nil
+	}
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %w", name, err)
+	}
+	return formatted, nil
+}
+
+// addToRegistry adds the investigation to the end of availableInvestigations in the source of registry.go
+// and imports its package
+func addToRegistry(src []byte, name string) ([]byte, error) {
+	importPath := investigationsImportPath + "/" + name
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "registry.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, imp := range file.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == importPath {
+			return nil, fmt.Errorf("%s is already registered", name)
+		}
+	}
+	list := availableInvestigations(file)
+	if list == nil {
+		return nil, fmt.Errorf("availableInvestigations not found")
+	}
+
+	// Insert the entry on its own line, before the line of the closing brace
+	lineStart := bytes.LastIndexByte(src[:fset.Position(list.Rbrace).Offset], '\n') + 1
+	src = slices.Concat(src[:lineStart], []byte("\t&"+name+".Investigation{},\n"), src[lineStart:])
+
+	fset = token.NewFileSet()
+	file, err = parser.ParseFile(fset, "registry.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	astutil.AddImport(fset, file, importPath)
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// availableInvestigations returns the composite literal the availableInvestigations variable is initialized with
+func availableInvestigations(file *ast.File) *ast.CompositeLit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value, ok := spec.(*ast.ValueSpec)
+			if !ok || len(value.Names) != 1 || value.Names[0].Name != "availableInvestigations" || len(value.Values) != 1 {
+				continue
+			}
+			if list, ok := value.Values[0].(*ast.CompositeLit); ok {
+				return list
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package dev
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRegistry = `package investigations
+
+import (
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/ccam"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/precheck"
+)
+
+// availableInvestigations holds all Investigation implementations.
+var availableInvestigations = []investigation.Investigation{
+	&precheck.ClusterStatePrecheck{},
+	&ccam.CloudCredentialsCheck{},
+}
+`
+
+func TestAddToRegistry(t *testing.T) {
+	got, err := addToRegistry([]byte(testRegistry), "diskpressure")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `package investigations
+
+import (
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/ccam"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/diskpressure"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/precheck"
+)
+
+// availableInvestigations holds all Investigation implementations.
+var availableInvestigations = []investigation.Investigation{
+	&precheck.ClusterStatePrecheck{},
+	&ccam.CloudCredentialsCheck{},
+	&diskpressure.Investigation{},
+}
+`
+	if string(got) != want {
+		t.Errorf("unexpected registry:\n%s", got)
+	}
+
+	if _, err := addToRegistry(got, "diskpressure"); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("expected an already registered error, got %v", err)
+	}
+	if _, err := addToRegistry([]byte("package investigations\n"), "diskpressure"); err == nil {
+		t.Errorf("expected an error for a registry without availableInvestigations")
+	}
+}
+
+func TestNewInvestigation(t *testing.T) {
+	root := t.TempDir()
+	registryPath := filepath.Join(root, "pkg", "investigations", "registry.go")
+	if err := os.MkdirAll(filepath.Dir(registryPath), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(registryPath, []byte(testRegistry), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &newInvestigationOptions{
+		name:        "diskpressure",
+		description: `Checks "full" disks`,
+		alertTitle:  "NodeDiskPressure",
+		platforms:   []string{"classic", "aws", "classic"},
+		root:        root,
+	}
+	var out bytes.Buffer
+	if err := opts.run(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dir := filepath.Join(root, "pkg", "investigations", "diskpressure")
+	for _, name := range []string{"diskpressure.go", "diskpressure_test.go"} {
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.AllErrors); err != nil {
+			t.Errorf("generated %s doesn't parse: %v", name, err)
+		}
+	}
+	source, err := os.ReadFile(filepath.Join(dir, "diskpressure.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`Description:       "Checks \"full\" disks",`,
+		"Platforms:         []investigation.Platform{investigation.PlatformClassic, investigation.PlatformAWS},",
+		"return i.RunContext(context.Background(), rb)",
+		"func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {",
+		"func (i *Investigation) Describe() investigation.Capabilities {",
+	} {
+		if !bytes.Contains(source, []byte(want)) {
+			t.Errorf("expected the investigation to contain %q, got:\n%s", want, source)
+		}
+	}
+	for _, name := range []string{"metadata.yaml", "README.md", "testing/README.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be generated: %v", name, err)
+		}
+	}
+	if !strings.Contains(out.String(), `#   - alert_title: "NodeDiskPressure"`) {
+		t.Errorf("expected the config snippet in the output, got:\n%s", out.String())
+	}
+
+	// Running again must not overwrite the investigation
+	if err := opts.run(&out); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an already exists error, got %v", err)
+	}
+}
+
+func TestNewInvestigation_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    newInvestigationOptions
+		wantErr string
+	}{
+		{
+			name:    "uppercase name",
+			opts:    newInvestigationOptions{name: "DiskPressure", alertTitle: "NodeDiskPressure"},
+			wantErr: "invalid investigation name",
+		},
+		{
+			name:    "name with a dash",
+			opts:    newInvestigationOptions{name: "disk-pressure", alertTitle: "NodeDiskPressure"},
+			wantErr: "invalid investigation name",
+		},
+		{
+			name:    "empty alert title",
+			opts:    newInvestigationOptions{name: "diskpressure", alertTitle: " "},
+			wantErr: "alert title must not be empty",
+		},
+		{
+			name:    "unknown platform",
+			opts:    newInvestigationOptions{name: "diskpressure", alertTitle: "NodeDiskPressure", platforms: []string{"azure"}},
+			wantErr: `unknown platform "azure"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.root = t.TempDir()
+			err := tt.opts.run(&bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
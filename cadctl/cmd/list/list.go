@@ -0,0 +1,60 @@
+// Package list holds the list-investigations command
+package list
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/spf13/cobra"
+)
+
+// ListInvestigationsCmd represents the list-investigations command
+var ListInvestigationsCmd = &cobra.Command{
+	Use:          "list-investigations",
+	SilenceUsage: true,
+	Short:        "List the available investigations and their declared capabilities",
+	Long: `Lists every registered investigation with the platforms it supports, the resources it needs,
+whether it mutates the cluster and whether it collects customer data. Investigations are skipped
+on clusters outside of their supported platforms. A "-" means the investigation declares no restriction.`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPLATFORMS\tRESOURCES\tMUTATES\tCUSTOMER DATA\tDESCRIPTION")
+	for _, inv := range investigations.GetAvailableInvestigations() {
+		capabilities := investigation.Describe(inv)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			inv.Name(),
+			join(capabilities.Platforms),
+			join(capabilities.RequiredResources),
+			yesNo(capabilities.MutatesCluster),
+			yesNo(capabilities.NeedsCustomerData),
+			capabilities.Description,
+		)
+	}
+	return w.Flush()
+}
+
+func join[T ~string](values []T) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		names = append(names, string(v))
+	}
+	return strings.Join(names, ",")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -2,8 +2,10 @@ package manual
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/controller"
 	"github.com/spf13/cobra"
@@ -16,8 +18,11 @@ var (
 	dryRunFlag        = false
 	withFilteringFlag = false
 	configPath        = ""
+	incidentFileFlag  = ""
+	recordDirFlag     = ""
 	pipelineNameEnv   = ""
 	paramsFlag        []string
+	helpParamsFlag    = false
 )
 
 func NewManualCmd() (*cobra.Command, error) {
@@ -32,12 +37,12 @@ func NewManualCmd() (*cobra.Command, error) {
 	cmd.Flags().BoolVarP(&dryRunFlag, "dry-run", "d", false, "run investigation without performing any external operations")
 	cmd.Flags().BoolVar(&withFilteringFlag, "with-filtering", false, "evaluate investigation filters during manual runs (default: filters are bypassed)")
 	cmd.Flags().StringVar(&configPath, "config", "", "path to investigation config file (overrides CAD_INVESTIGATION_CONFIG_PATH)")
+	cmd.Flags().StringVar(&incidentFileFlag, "incident-file", "", "record incident operations (notes, escalations, silences) as JSON lines to this file instead of skipping them")
+	cmd.Flags().StringVar(&recordDirFlag, "record", "", "record all OCM, backplane, cluster and AWS traffic and the resulting actions to this directory for offline replay")
 	cmd.Flags().StringArrayVarP(&paramsFlag, "params", "p", nil, "investigation-specific parameters as KEY=VALUE (can be specified multiple times)")
-	err := cmd.MarkFlagRequired("cluster-id")
-	if err != nil {
-		return nil, err
-	}
-	err = cmd.MarkFlagRequired("investigation")
+	cmd.Flags().BoolVar(&helpParamsFlag, "help-params", false, "list the parameters the investigation accepts and exit")
+	// cluster-id is checked in run, as it isn't needed for --help-params
+	err := cmd.MarkFlagRequired("investigation")
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +53,14 @@ func NewManualCmd() (*cobra.Command, error) {
 	return cmd, nil
 }
 
-func run(_ *cobra.Command, _ []string) error {
+func run(cmd *cobra.Command, _ []string) error {
+	if helpParamsFlag {
+		return printParams(cmd.OutOrStdout(), investigationFlag)
+	}
+	if clusterIdFlag == "" {
+		return fmt.Errorf(`required flag(s) "cluster-id" not set`)
+	}
+
 	params, err := parseParams(paramsFlag)
 	if err != nil {
 		return err
@@ -66,6 +78,8 @@ func run(_ *cobra.Command, _ []string) error {
 			InvestigationName: investigationFlag,
 			DryRun:            dryRunFlag,
 			WithFiltering:     withFilteringFlag,
+			IncidentFile:      incidentFileFlag,
+			RecordDir:         recordDirFlag,
 			Params:            params,
 		},
 	}
@@ -85,3 +99,29 @@ func parseParams(raw []string) (map[string]string, error) {
 	}
 	return params, nil
 }
+
+// printParams lists the parameter schema of the investigation
+func printParams(out io.Writer, name string) error {
+	fullName, schema, err := controller.InvestigationParams(name)
+	if err != nil {
+		return err
+	}
+	if len(schema) == 0 {
+		_, err := fmt.Fprintf(out, "%s takes no parameters\n", fullName)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tDEFAULT\tALLOWED\tDESCRIPTION")
+	for _, p := range schema {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, p.Type, orDash(p.Default), orDash(strings.Join(p.Allowed, ",")), p.Description)
+	}
+	return w.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
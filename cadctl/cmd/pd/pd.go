@@ -0,0 +1,92 @@
+// Package pd holds PagerDuty helper commands
+package pd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations"
+	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	payloadPath = ""
+	configPath  = ""
+)
+
+// NewPdCmd creates the pd command and its subcommands
+func NewPdCmd() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:   "pd",
+		Short: "PagerDuty helpers",
+	}
+
+	extractCmd := &cobra.Command{
+		Use:          "extract-cluster-id",
+		SilenceUsage: true,
+		Short:        "Test cluster ID extraction rules against a captured alert payload",
+		Long: `Evaluates the cluster ID extraction rules of the investigation config, followed by the built-in rules,
+against a captured PagerDuty alert payload. The payload may be a single alert body, an alert, a list of alerts
+or a list incident alerts API response.`,
+		RunE: runExtractClusterID,
+	}
+	extractCmd.Flags().StringVar(&payloadPath, "payload", "", "path to the captured alert payload")
+	extractCmd.Flags().StringVar(&configPath, "config", "", "path to investigation config file (overrides CAD_INVESTIGATION_CONFIG_PATH)")
+	if err := extractCmd.MarkFlagRequired("payload"); err != nil {
+		return nil, err
+	}
+	cmd.AddCommand(extractCmd)
+
+	return cmd, nil
+}
+
+func runExtractClusterID(cmd *cobra.Command, _ []string) error {
+	payload, err := os.ReadFile(payloadPath) //nolint:gosec // path is provided by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read payload: %w", err)
+	}
+	bodies, err := pagerduty.AlertBodies(payload)
+	if err != nil {
+		return err
+	}
+
+	var rules []config.ClusterIDRule
+	if configPath == "" {
+		configPath = os.Getenv("CAD_INVESTIGATION_CONFIG_PATH")
+	}
+	if configPath != "" {
+		cfg, err := config.LoadConfig(configPath, investigations.GetAvailableInvestigationsNames())
+		if err != nil {
+			return fmt.Errorf("failed to load investigation config: %w", err)
+		}
+		rules = cfg.GetClusterIDRules()
+	}
+
+	extractor, err := clusterid.NewExtractor(rules)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	found := false
+	for i, body := range bodies {
+		id, rule, err := extractor.Extract(clusterid.SourcePagerDuty, body)
+		if err != nil {
+			_, _ = fmt.Fprintf(out, "alert %d: no cluster ID found: %v\n", i, err)
+			continue
+		}
+		found = true
+		idType := "internal ID"
+		if !clusterid.IsInternalID(id) {
+			idType = "external ID or name, resolved through OCM at runtime"
+		}
+		_, _ = fmt.Fprintf(out, "alert %d: %s (%s) matched by rule %q\n", i, id, idType, rule)
+	}
+	if !found {
+		return fmt.Errorf("no cluster ID found in %d alert(s)", len(bodies))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package replay holds the replay command
+package replay
+
+import (
+	"os"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/controller"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logLevelFlag      = ""
+	fixtureFlag       = ""
+	investigationFlag = ""
+	configPath        = ""
+	updateFlag        = false
+)
+
+// NewReplayCmd creates the replay command
+func NewReplayCmd() (*cobra.Command, error) {
+	cmd := &cobra.Command{
+		Use:          "replay",
+		SilenceUsage: true,
+		Short:        "Rerun a recorded investigation offline and diff its actions against the golden file",
+		Long: `Reruns an investigation recorded with 'cadctl run --record <dir>' without any network access.
+OCM, backplane, cluster and AWS requests are served from the fixture, and the resulting actions are
+compared against the fixture's actions.json. Actions are never executed.`,
+		RunE: run,
+	}
+	cmd.Flags().StringVar(&fixtureFlag, "fixture", "", "the fixture directory created by 'cadctl run --record'")
+	cmd.Flags().StringVarP(&investigationFlag, "investigation", "i", "", "the investigation to replay, defaults to the recorded investigation")
+	cmd.Flags().StringVar(&configPath, "config", "", "path to investigation config file (overrides CAD_INVESTIGATION_CONFIG_PATH)")
+	cmd.Flags().BoolVar(&updateFlag, "update", false, "rewrite the golden file with the replayed actions instead of comparing")
+	if err := cmd.MarkFlagRequired("fixture"); err != nil {
+		return nil, err
+	}
+
+	logLevelFlag = os.Getenv("LOG_LEVEL")
+
+	return cmd, nil
+}
+
+func run(_ *cobra.Command, _ []string) error {
+	return controller.Replay(
+		controller.CommonConfig{
+			LogLevel:   logLevelFlag,
+			ConfigPath: configPath,
+		},
+		controller.ReplayConfig{
+			FixtureDir:        fixtureFlag,
+			InvestigationName: investigationFlag,
+			Update:            updateFlag,
+		},
+	)
+}
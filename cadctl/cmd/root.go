@@ -17,8 +17,12 @@ limitations under the License.
 package cmd
 
 import (
+	"github.com/openshift/configuration-anomaly-detection/cadctl/cmd/dev"
 	"github.com/openshift/configuration-anomaly-detection/cadctl/cmd/investigate"
+	"github.com/openshift/configuration-anomaly-detection/cadctl/cmd/list"
 	"github.com/openshift/configuration-anomaly-detection/cadctl/cmd/manual"
+	"github.com/openshift/configuration-anomaly-detection/cadctl/cmd/pd"
+	"github.com/openshift/configuration-anomaly-detection/cadctl/cmd/replay"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
 	"github.com/spf13/cobra"
@@ -34,11 +38,27 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	rootCmd.AddCommand(investigate.InvestigateCmd)
+	rootCmd.AddCommand(list.ListInvestigationsCmd)
 	c, err := manual.NewManualCmd()
 	if err != nil {
 		logging.Fatal(err)
 	}
 	rootCmd.AddCommand(c)
+	p, err := pd.NewPdCmd()
+	if err != nil {
+		logging.Fatal(err)
+	}
+	rootCmd.AddCommand(p)
+	r, err := replay.NewReplayCmd()
+	if err != nil {
+		logging.Fatal(err)
+	}
+	rootCmd.AddCommand(r)
+	d, err := dev.NewDevCmd()
+	if err != nil {
+		logging.Fatal(err)
+	}
+	rootCmd.AddCommand(d)
 
 	err = rootCmd.Execute()
 	metrics.Push()
@@ -14,6 +14,7 @@ import (
 
 	"github.com/openshift/configuration-anomaly-detection/interceptor/pkg/interceptor"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/source"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"knative.dev/pkg/signals"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
@@ -49,7 +50,7 @@ func main() {
 
 	mux := http.NewServeMux()
 	configPath := os.Getenv("CAD_INVESTIGATION_CONFIG_PATH")
-	handler, err := interceptor.CreateInterceptorHandler(signatures, configPath)
+	handler, err := interceptor.CreateInterceptorHandler(signatures, configPath, loadExtraSources()...)
 	if err != nil {
 		logger.Fatalf("failed to create interceptor handler: %v", err)
 	}
@@ -104,12 +105,28 @@ func loadPDSignatures() ([]string, error) {
 	if tokens == "" {
 		return nil, errors.New("PD_SIGNATURE environment variable missing")
 	}
-	parts := strings.Split(tokens, ",")
+	return splitSecrets(tokens), nil
+}
+
+// loadExtraSources enables the non-PagerDuty alert sources whose secrets are configured.
+func loadExtraSources() []source.Source {
+	var sources []source.Source
+	if secrets := os.Getenv("CAD_GENERIC_SOURCE_SECRETS"); secrets != "" {
+		sources = append(sources, source.NewGeneric(splitSecrets(secrets)))
+	}
+	if tokens := os.Getenv("CAD_ALERTMANAGER_TOKENS"); tokens != "" {
+		sources = append(sources, source.NewAlertmanager(splitSecrets(tokens)))
+	}
+	return sources
+}
+
+func splitSecrets(value string) []string {
+	parts := strings.Split(value, ",")
 
-	signatures := make([]string, 0, len(parts))
-	for _, sig := range parts {
-		trim := strings.TrimSpace(sig)
-		signatures = append(signatures, trim)
+	secrets := make([]string, 0, len(parts))
+	for _, secret := range parts {
+		trim := strings.TrimSpace(secret)
+		secrets = append(secrets, trim)
 	}
-	return signatures, nil
+	return secrets
 }
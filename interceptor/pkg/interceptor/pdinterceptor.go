@@ -13,19 +13,28 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/PagerDuty/go-pagerduty/webhookv3"
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
 	"github.com/openshift/configuration-anomaly-detection/pkg/config"
 	investigations "github.com/openshift/configuration-anomaly-detection/pkg/investigations"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
+	"github.com/openshift/configuration-anomaly-detection/pkg/routing"
+	"github.com/openshift/configuration-anomaly-detection/pkg/source"
+	"github.com/openshift/configuration-anomaly-detection/pkg/storm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 	"github.com/prometheus/client_golang/prometheus"
 	triggersv1 "github.com/tektoncd/triggers/pkg/apis/triggers/v1beta1"
 	"github.com/tektoncd/triggers/pkg/interceptors"
 	"google.golang.org/grpc/codes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
+// stormStateConfigMap is the ConfigMap the replicas of the interceptor share the storm state in
+const stormStateConfigMap = "cad-storm-state"
+
 // ErrInvalidContentType is returned when the content-type is not a JSON body.
 var ErrInvalidContentType = errors.New("form parameter encoding not supported, please change the hook to send JSON payloads")
 
@@ -39,35 +48,78 @@ var (
 		Name: "cad_interceptor_errors_total",
 		Help: "Number of times CAD interceptor has been failed to process a request",
 	}, []string{"error_code", "reason"})
+
+	stormCorrelationsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cad_interceptor_storm_correlations_total",
+		Help: "Number of incidents correlated with an incident storm",
+	}, []string{"dimension"})
 )
 
 func init() {
-	metrics.Registry.MustRegister(requestsCounter, errorsCounter)
-}
-
-// OrgEscalationMapping represents the structure of the org-to-policy mapping
-type OrgEscalationMapping struct {
-	Organizations []Organization `json:"organizations"`
-}
-
-// Organization represents a customer organization with its escalation policy
-type Organization struct {
-	Name             string   `json:"name"`
-	OrgIDs           []string `json:"org_ids"`
-	EscalationPolicy string   `json:"escalation_policy"`
+	metrics.Registry.MustRegister(requestsCounter, errorsCounter, stormCorrelationsCounter)
 }
 
 type interceptorHandler struct {
-	PDTokens []string
-	cfg      *config.Config
+	PDTokens     []string
+	cfg          *config.Config
+	router       *routing.Engine
+	stormTracker *storm.Tracker // nil when storm detection is not configured
+	pdSource     source.Source
+	sources      []source.Source // alert sources in detection order, PagerDuty is the fallback
+	clusterIDs   *clusterid.Extractor
 }
 
-func CreateInterceptorHandler(pdTokens []string, configPath string) (http.Handler, error) {
+// CreateInterceptorHandler creates the interceptor handler. PagerDuty webhooks are always
+// accepted; extraSources enables additional alert sources such as Alertmanager.
+func CreateInterceptorHandler(pdTokens []string, configPath string, extraSources ...source.Source) (http.Handler, error) {
 	cfg, err := config.LoadConfig(configPath, investigations.GetAvailableInvestigationsNames())
 	if err != nil {
 		return nil, fmt.Errorf("loading investigation config: %w", err)
 	}
-	return &interceptorHandler{PDTokens: pdTokens, cfg: cfg}, nil
+	clusterIDs, err := clusterid.NewExtractor(cfg.GetClusterIDRules())
+	if err != nil {
+		return nil, fmt.Errorf("loading cluster ID extraction rules: %w", err)
+	}
+	for _, s := range extraSources {
+		if c, ok := s.(source.ClusterIDConfigurable); ok {
+			c.SetClusterIDExtractor(clusterIDs)
+		}
+	}
+	pdSource := source.NewPagerDuty(pdTokens)
+	handler := &interceptorHandler{
+		PDTokens:   pdTokens,
+		cfg:        cfg,
+		router:     routing.NewEngine(cfg),
+		pdSource:   pdSource,
+		sources:    append([]source.Source{pdSource}, extraSources...),
+		clusterIDs: clusterIDs,
+	}
+	if stormCfg := cfg.GetStormConfig(); stormCfg != nil {
+		handler.stormTracker = storm.NewSharedTracker(stormCfg, newStormStore())
+	}
+	return handler, nil
+}
+
+// newStormStore returns the store of the storm tracker. The replicas of the interceptor share
+// the state in a ConfigMap in their namespace, taken from POD_NAMESPACE. Outside a cluster, the
+// state is kept in memory.
+func newStormStore() storm.Store {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		logging.Warn("POD_NAMESPACE is not set, storm state is kept in memory and not shared between replicas")
+		return &storm.MemoryStore{}
+	}
+	restConfig, err := ctrlconfig.GetConfig()
+	if err != nil {
+		logging.Warnf("Failed to load the in-cluster config, storm state is kept in memory and not shared between replicas: %v", err)
+		return &storm.MemoryStore{}
+	}
+	k8sClient, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		logging.Warnf("Failed to create the k8s client, storm state is kept in memory and not shared between replicas: %v", err)
+		return &storm.MemoryStore{}
+	}
+	return storm.NewConfigMapStore(k8sClient, namespace, stormStateConfigMap)
 }
 
 func (pdi interceptorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -144,28 +196,25 @@ func (pdi *interceptorHandler) executeInterceptor(r *http.Request) ([]byte, *htt
 
 	logging.Debug("Unwrapped Request body: ", originalReq.Body)
 
-	var sigErrs []error
-	for _, signature := range pdi.PDTokens {
-		err = webhookv3.VerifySignature(extractedRequest, signature)
-		if err != nil {
-			sigErrs = append(sigErrs, err)
-		} else {
-			// A signature successfully verified we can continue
-			break
-		}
-	}
-	if len(sigErrs) == len(pdi.PDTokens) {
-		return nil, pdi.badRequest("failed to verify signature against all signatures", errors.Join(sigErrs...))
+	// Requests that no other source detects are treated as PagerDuty webhooks.
+	src := source.Select(pdi.sources, extractedRequest.Header, pdi.pdSource)
+	if err := src.Verify(extractedRequest.Header, []byte(originalReq.Body)); err != nil {
+		return nil, pdi.badRequest(fmt.Sprintf("failed to verify %s signature against all signatures", src.Name()), err)
 	}
 
-	logging.Info("Signature verified successfully")
+	logging.Infof("Signature verified successfully for source %s", src.Name())
 
 	if err := json.Unmarshal(bodyBytes, &ireq); err != nil {
 		return nil, pdi.badRequest("failed to parse body as InterceptorRequest", err)
 	}
 	logging.Debugf("Interceptor request body is: %s", ireq.Body)
 
-	iresp := pdi.process(ctx, &ireq)
+	var iresp *triggersv1.InterceptorResponse
+	if src.Name() == source.NamePagerDuty {
+		iresp = pdi.process(ctx, &ireq)
+	} else {
+		iresp = pdi.processSourceIncident(src, &ireq)
+	}
 	logging.Debugf("Interceptor response is: %+v", iresp)
 	respBytes, err := json.Marshal(iresp)
 	if err != nil {
@@ -179,15 +228,9 @@ func (pdi *interceptorHandler) process(ctx context.Context, r *triggersv1.Interc
 	if err != nil {
 		return interceptors.Failf(codes.InvalidArgument, "could not initialize pagerduty client: %v", err)
 	}
+	pdClient.SetClusterIDExtractor(pdi.clusterIDs)
 
-	// Load org mapping
-	orgMap, err := loadOrgEscalationMapping()
-	if err != nil {
-		logging.Warnf("Failed to load org mapping: %v", err)
-		orgMap = make(map[string]string)
-	}
-
-	// Create OCM client - required for AI investigations and org routing
+	// Create OCM client - required for AI investigations, storm detection and routing
 	ocmClientID := os.Getenv("CAD_OCM_CLIENT_ID")
 	ocmClientSecret := os.Getenv("CAD_OCM_CLIENT_SECRET")
 	ocmURL := os.Getenv("CAD_OCM_URL")
@@ -201,20 +244,21 @@ func (pdi *interceptorHandler) process(ctx context.Context, r *triggersv1.Interc
 		return interceptors.Failf(codes.Internal, "failed to create OCM client: %v", err)
 	}
 
-	// Perform org-based routing if org mapping is configured
-	if len(orgMap) > 0 {
-		reassignToOrgEscalationPolicy(pdClient, ocmClient, orgMap)
-	}
-
 	experimentalEnabledVar := os.Getenv("CAD_EXPERIMENTAL_ENABLED")
 	experimentalEnabled, _ := strconv.ParseBool(experimentalEnabledVar)
 
 	// Check if an alert config exists for this alert (config loaded at handler creation)
-	hasAlert := pdi.cfg != nil && pdi.cfg.GetAlert(pdClient.GetTitle(), experimentalEnabled) != nil
+	alertConfig := pdi.cfg.GetAlert(pdClient.GetTitle(), experimentalEnabled)
 
-	if hasAlert {
+	alertName := pdClient.GetTitle()
+	if alertConfig != nil {
+		alertName = alertConfig.GetName()
+	}
+	correlationID := pdi.correlateIncident(ctx, pdClient, ocmClient, alertName)
+
+	if alertConfig != nil {
 		logging.Infof("Incident %s has a configured alert, returning InterceptorResponse `Continue: true`.", pdClient.GetIncidentID())
-		return continueWithEncodedPayload(r.Body)
+		return continueWithEncodedPayload(r.Body, correlationID)
 	}
 
 	// AI fallback: if ai_agent is configured, allow the pipeline to run for AI investigation
@@ -225,10 +269,16 @@ func (pdi *interceptorHandler) process(ctx context.Context, r *triggersv1.Interc
 			return resp
 		}
 		logging.Infof("Launching AI investigation for incident %s", pdClient.GetIncidentID())
-		return continueWithEncodedPayload(r.Body)
+		return continueWithEncodedPayload(r.Body, correlationID)
 	}
 
-	// No chain and no AI — escalate to SRE
+	// No chain and no AI — route and escalate to SRE. Incidents forwarded to the
+	// pipeline are routed by the controller instead, so each incident is routed once.
+	pdi.routeIncident(pdClient, ocmClient, &types.FilterContext{
+		AlertTitle:  pdClient.GetTitle(),
+		ServiceName: pdClient.GetServiceName(),
+	})
+
 	logging.Infof("Incident %s is not mapped to an investigation, escalating incident and returning InterceptorResponse `Continue: false`.", pdClient.GetIncidentID())
 	if err = pdClient.EscalateIncidentWithNote("🤖 No automation implemented for this alert; escalated to SRE. 🤖"); err != nil {
 		logging.Errorf("failed to escalate incident '%s': %v", pdClient.GetIncidentID(), err)
@@ -236,18 +286,124 @@ func (pdi *interceptorHandler) process(ctx context.Context, r *triggersv1.Interc
 	return &triggersv1.InterceptorResponse{Continue: false}
 }
 
+// processSourceIncident handles alerts from non-PagerDuty sources. Every firing
+// incident that has a cluster ID and a configured alert is normalized, and all of them
+// are forwarded to the pipeline in a single payload. There is no incident to escalate
+// to for these sources, so unhandled alerts are dropped.
+func (pdi *interceptorHandler) processSourceIncident(src source.Source, r *triggersv1.InterceptorRequest) *triggersv1.InterceptorResponse {
+	incidents, err := src.Parse([]byte(r.Body))
+	if err != nil {
+		return interceptors.Failf(codes.InvalidArgument, "could not parse %s payload: %v", src.Name(), err)
+	}
+
+	experimentalEnabled, _ := strconv.ParseBool(os.Getenv("CAD_EXPERIMENTAL_ENABLED"))
+
+	var matched []source.Incident
+	for _, incident := range incidents {
+		if incident.ClusterID == "" {
+			logging.Infof("Skipping %s alert %q without cluster ID", src.Name(), incident.Title)
+			continue
+		}
+		if pdi.cfg.GetAlert(incident.Title, experimentalEnabled) == nil {
+			logging.Infof("Skipping %s alert %q, no alert config matches", src.Name(), incident.Title)
+			continue
+		}
+		logging.Infof("%s alert %q for cluster %s has a configured alert", src.Name(), incident.Title, incident.ClusterID)
+		matched = append(matched, incident)
+	}
+
+	if len(matched) == 0 {
+		logging.Infof("No %s alert is mapped to an investigation, returning InterceptorResponse `Continue: false`.", src.Name())
+		return &triggersv1.InterceptorResponse{Continue: false}
+	}
+
+	payload, err := source.Encode(matched...)
+	if err != nil {
+		return interceptors.Failf(codes.Internal, "%v", err)
+	}
+	logging.Infof("Forwarding %d of %d %s alert(s), returning InterceptorResponse `Continue: true`.", len(matched), len(incidents), src.Name())
+	return continueWithEncodedPayload(string(payload), "")
+}
+
 // continueWithEncodedPayload returns a Continue response with the webhook payload
 // base64-encoded as an extension. The TriggerBinding references this extension so
 // the payload reaches the Tekton task without shell metacharacter issues.
-func continueWithEncodedPayload(body string) *triggersv1.InterceptorResponse {
+// The correlation_id extension is always set (empty when the incident is not part
+// of a storm) because the TriggerBinding cannot reference a missing extension.
+func continueWithEncodedPayload(body string, correlationID string) *triggersv1.InterceptorResponse {
 	return &triggersv1.InterceptorResponse{
 		Continue: true,
 		Extensions: map[string]interface{}{
 			"payload_base64": base64.StdEncoding.EncodeToString([]byte(body)),
+			"correlation_id": correlationID,
 		},
 	}
 }
 
+// correlateIncident records the incident with the storm tracker and, when it is part
+// of an incident storm, posts a correlation note to the incident. The first incident of a
+// storm also posts the note to the earlier incidents of the storm. Their pipelines already
+// started without the correlation ID, so their cluster actions aren't skipped.
+// It returns the correlation ID, or an empty string if the incident is not correlated.
+func (pdi *interceptorHandler) correlateIncident(ctx context.Context, pdClient pagerduty.Client, ocmClient ocm.Client, alertName string) string {
+	if pdi.stormTracker == nil {
+		return ""
+	}
+
+	clusterID, err := pdClient.RetrieveClusterID()
+	if err != nil {
+		logging.Debugf("Skipping storm detection, could not retrieve cluster id: %v", err)
+		return ""
+	}
+
+	cluster, err := ocmClient.GetClusterInfo(clusterID)
+	if err != nil {
+		logging.Warnf("Skipping storm detection, could not retrieve cluster %s from OCM: %v", clusterID, err)
+		return ""
+	}
+
+	incident := storm.Incident{
+		ID:         pdClient.GetIncidentID(),
+		ClusterID:  cluster.ID(),
+		AlertName:  alertName,
+		Region:     cluster.Region().ID(),
+		AWSAccount: cluster.AWS().AccountID(),
+	}
+	if cluster.Hypershift().Enabled() {
+		hcpConfig, err := ocmClient.GetClusterHypershiftConfig(cluster)
+		if err != nil {
+			logging.Warnf("Could not retrieve management cluster for %s: %v", clusterID, err)
+		} else {
+			incident.ManagementCluster = hcpConfig.ManagementCluster()
+		}
+	}
+
+	correlation, err := pdi.stormTracker.Observe(ctx, incident)
+	if err != nil {
+		logging.Warnf("Skipping storm detection for cluster %s: %v", clusterID, err)
+		return ""
+	}
+	if correlation == nil {
+		return ""
+	}
+
+	stormCorrelationsCounter.WithLabelValues(correlation.Dimension).Inc()
+	logging.Infof("Incident for cluster %s correlated with storm %s (%s, %d clusters)", clusterID, correlation.ID, correlation.Summary(), correlation.ClusterCount)
+
+	note := fmt.Sprintf("🌩️ This incident is likely part of a wider event affecting %d clusters (%s) within the last %d minutes. Correlation ID: %s",
+		correlation.ClusterCount, correlation.Summary(), pdi.cfg.Storm.WindowMinutes, correlation.ID)
+	if err := pdClient.AddNote(note); err != nil {
+		logging.Warnf("Failed to add storm correlation note: %v", err)
+	}
+	for _, incidentID := range correlation.Uncorrelated {
+		earlierNote := note + "\nThe storm was detected after this incident's investigation started, so its cluster actions were not skipped."
+		if err := pdClient.AddNoteToIncident(incidentID, earlierNote); err != nil {
+			logging.Warnf("Failed to add storm correlation note to earlier incident %s: %v", incidentID, err)
+		}
+	}
+	return correlation.ID
+}
+
 // clusterExists retrieves the cluster ID from PagerDuty and verifies it
 // exists in OCM. It returns the cluster ID on success, or a short-circuit
 // InterceptorResponse (Continue: false) on failure.
@@ -267,59 +423,26 @@ func clusterExists(pdClient pagerduty.Client, ocmClient ocm.Client) *triggersv1.
 	return nil
 }
 
-func loadOrgEscalationMapping() (map[string]string, error) {
-	mappingJSON, hasMappingJSON := os.LookupEnv("CAD_ORG_POLICY_MAPPING")
-	if !hasMappingJSON || mappingJSON == "" {
-		return make(map[string]string), nil
-	}
-
-	var mapping OrgEscalationMapping
-	if err := json.Unmarshal([]byte(mappingJSON), &mapping); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal org policy mapping: %w", err)
-	}
-
-	result := make(map[string]string)
-	for _, org := range mapping.Organizations {
-		for _, orgID := range org.OrgIDs {
-			result[orgID] = org.EscalationPolicy
-		}
-	}
-	return result, nil
-}
-
-func reassignToOrgEscalationPolicy(pdClient pagerduty.Client, ocmClient ocm.Client, orgMap map[string]string) {
-	if len(orgMap) == 0 {
+// routeIncident moves the incident to the escalation policy of the first matching
+// routing rule. Routing is best-effort: failures are logged and processing continues.
+func (pdi *interceptorHandler) routeIncident(pdClient pagerduty.Client, ocmClient ocm.Client, filterCtx *types.FilterContext) {
+	if !pdi.router.HasRules() {
 		return
 	}
 
 	clusterID, err := pdClient.RetrieveClusterID()
 	if err != nil {
+		logging.Debugf("Skipping incident routing, could not retrieve cluster id: %v", err)
 		return
 	}
 
-	orgID, err := ocmClient.GetOrganizationID(clusterID)
+	cluster, err := ocmClient.GetClusterInfo(clusterID)
 	if err != nil {
-		logging.Warnf("Failed to get org ID for cluster %s: %v", clusterID, err)
-		return
-	}
-	if orgID == "" {
-		return
-	}
-
-	policy, found := orgMap[orgID]
-	if !found {
-		return
-	}
-
-	if err := pdClient.MoveToEscalationPolicy(policy); err != nil {
-		if noteErr := pdClient.AddNote(fmt.Sprintf("This cluster belongs to organization %s and should be escalated to policy %s, but CAD failed to reassign: %v. Please manually route to the appropriate team.", orgID, policy, err)); noteErr != nil {
-			logging.Warnf("Failed to add note about reassignment failure: %v", noteErr)
-		}
-		logging.Errorf("Failed to reassign to org policy %s: %v", policy, err)
+		logging.Warnf("Skipping incident routing, could not retrieve cluster %s from OCM: %v", clusterID, err)
 		return
 	}
 
-	if err := pdClient.AddNote(fmt.Sprintf("Reassigned to organization %s escalation policy.", orgID)); err != nil {
-		logging.Warnf("Failed to add note about successful reassignment: %v", err)
+	if err := pdi.router.Route(pdClient, ocmClient, cluster, filterCtx); err != nil {
+		logging.Warnf("Incident routing failed: %v", err)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -12,85 +13,67 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
 	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
 	pdmock "github.com/openshift/configuration-anomaly-detection/pkg/pagerduty/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/routing"
+	"github.com/openshift/configuration-anomaly-detection/pkg/source"
+	"github.com/openshift/configuration-anomaly-detection/pkg/storm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 	"go.uber.org/mock/gomock"
 )
 
-func TestReassignToOrgEscalationPolicy(t *testing.T) {
+func TestRouteIncident(t *testing.T) {
+	routingCfg, err := config.ParseConfig([]byte(`
+routing:
+  - name: "Customer Alpha"
+    escalation_policy: POL123
+    when:
+      field: OrganizationID
+      operator: in
+      values: ["org-123"]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - precheck
+`), []string{"precheck"})
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+
 	tests := []struct {
-		name                 string
-		orgMap               map[string]string
-		clusterID            string
-		clusterIDErr         error
-		orgID                string
-		orgIDErr             error
-		moveToEPErr          error
-		addNoteErr           error
-		expectMoveToEP       bool
-		expectMoveToEPPolicy string
-		expectAddNote        bool
-		expectNoteContains   string
+		name           string
+		cfg            *config.Config
+		clusterIDErr   error
+		clusterInfoErr error
+		orgID          string
+		expectMoveToEP bool
 	}{
 		{
-			name:                 "scenario 1: cluster in mapped org should reassign",
-			orgMap:               map[string]string{"org-123": "POL123"},
-			clusterID:            "cluster-1",
-			orgID:                "org-123",
-			expectMoveToEP:       true,
-			expectMoveToEPPolicy: "POL123",
-			expectAddNote:        true,
-			expectNoteContains:   "Reassigned to organization org-123",
+			name: "no routing rules should skip",
+			cfg:  &config.Config{},
 		},
 		{
-			name:           "scenario 2: cluster in unmapped org should skip",
-			orgMap:         map[string]string{"org-123": "POL123"},
-			clusterID:      "cluster-1",
-			orgID:          "org-456",
-			expectMoveToEP: false,
-			expectAddNote:  false,
+			name:         "retrieve cluster ID fails should skip",
+			cfg:          routingCfg,
+			clusterIDErr: errors.New("failed to retrieve cluster ID"),
 		},
 		{
-			name:           "scenario 3: empty org mapping should skip",
-			orgMap:         map[string]string{},
-			clusterID:      "cluster-1",
-			orgID:          "org-123",
-			expectMoveToEP: false,
-			expectAddNote:  false,
-		},
-		{
-			name:           "scenario 4: retrieve cluster ID fails should skip",
-			orgMap:         map[string]string{"org-123": "POL123"},
-			clusterIDErr:   errors.New("failed to retrieve cluster ID"),
-			expectMoveToEP: false,
-			expectAddNote:  false,
-		},
-		{
-			name:           "scenario 5: get org ID fails should skip",
-			orgMap:         map[string]string{"org-123": "POL123"},
-			clusterID:      "cluster-1",
-			orgIDErr:       errors.New("OCM error"),
-			expectMoveToEP: false,
-			expectAddNote:  false,
+			name:           "cluster lookup fails should skip",
+			cfg:            routingCfg,
+			clusterInfoErr: errors.New("cluster not found in OCM"),
 		},
 		{
-			name:                 "scenario 6: move to escalation policy fails should add failure note",
-			orgMap:               map[string]string{"org-123": "POL123"},
-			clusterID:            "cluster-1",
-			orgID:                "org-123",
-			moveToEPErr:          errors.New("invalid policy"),
-			expectMoveToEP:       true,
-			expectMoveToEPPolicy: "POL123",
-			expectAddNote:        true,
-			expectNoteContains:   "CAD failed to reassign",
+			name:  "cluster in unmapped org should skip",
+			cfg:   routingCfg,
+			orgID: "org-456",
 		},
 		{
-			name:           "scenario 7: empty org ID should skip",
-			orgMap:         map[string]string{"org-123": "POL123"},
-			clusterID:      "cluster-1",
-			orgID:          "",
-			expectMoveToEP: false,
-			expectAddNote:  false,
+			name:           "cluster in mapped org should reassign",
+			cfg:            routingCfg,
+			orgID:          "org-123",
+			expectMoveToEP: true,
 		},
 	}
 
@@ -102,35 +85,38 @@ func TestReassignToOrgEscalationPolicy(t *testing.T) {
 			mockPD := pdmock.NewMockClient(ctrl)
 			mockOCM := ocmmock.NewMockClient(ctrl)
 
+			cluster, err := cmv1.NewCluster().ID("cluster-1").Build()
+			if err != nil {
+				t.Fatalf("failed to build cluster: %v", err)
+			}
+
 			// Setup expectations
 			switch {
-			case len(tt.orgMap) == 0:
-				// Empty map, no expectations
+			case len(tt.cfg.Routing) == 0:
+				// No rules, no expectations
 			case tt.clusterIDErr != nil:
-				mockPD.EXPECT().RetrieveClusterID().Return(tt.clusterID, tt.clusterIDErr).Times(1)
-			case tt.orgIDErr != nil || tt.orgID == "":
-				mockPD.EXPECT().RetrieveClusterID().Return(tt.clusterID, nil).Times(1)
-				mockOCM.EXPECT().GetOrganizationID(tt.clusterID).Return(tt.orgID, tt.orgIDErr).Times(1)
-			case tt.expectMoveToEP:
-				mockPD.EXPECT().RetrieveClusterID().Return(tt.clusterID, nil).Times(1)
-				mockOCM.EXPECT().GetOrganizationID(tt.clusterID).Return(tt.orgID, nil).Times(1)
-				mockPD.EXPECT().MoveToEscalationPolicy(tt.expectMoveToEPPolicy).Return(tt.moveToEPErr).Times(1)
-				if tt.expectAddNote {
+				mockPD.EXPECT().RetrieveClusterID().Return("", tt.clusterIDErr).Times(1)
+			case tt.clusterInfoErr != nil:
+				mockPD.EXPECT().RetrieveClusterID().Return("cluster-1", nil).Times(1)
+				mockOCM.EXPECT().GetClusterInfo("cluster-1").Return(nil, tt.clusterInfoErr).Times(1)
+			default:
+				mockPD.EXPECT().RetrieveClusterID().Return("cluster-1", nil).Times(1)
+				mockOCM.EXPECT().GetClusterInfo("cluster-1").Return(cluster, nil).Times(1)
+				mockOCM.EXPECT().GetOrganizationID("cluster-1").Return(tt.orgID, nil).Times(1)
+				if tt.expectMoveToEP {
+					mockPD.EXPECT().MoveToEscalationPolicy("POL123").Return(nil).Times(1)
 					mockPD.EXPECT().AddNote(gomock.Any()).DoAndReturn(func(note string) error {
-						if tt.expectNoteContains != "" && !contains(note, tt.expectNoteContains) {
-							t.Errorf("AddNote() note = %q, want to contain %q", note, tt.expectNoteContains)
+						if !contains(note, "Reassigned to escalation policy POL123") {
+							t.Errorf("AddNote() note = %q", note)
 						}
-						return tt.addNoteErr
+						return nil
 					}).Times(1)
 				}
-			default:
-				// Org not in mapping
-				mockPD.EXPECT().RetrieveClusterID().Return(tt.clusterID, nil).Times(1)
-				mockOCM.EXPECT().GetOrganizationID(tt.clusterID).Return(tt.orgID, nil).Times(1)
 			}
 
 			// Execute
-			reassignToOrgEscalationPolicy(mockPD, mockOCM, tt.orgMap)
+			pdi := &interceptorHandler{cfg: tt.cfg, router: routing.NewEngine(tt.cfg)}
+			pdi.routeIncident(mockPD, mockOCM, &types.FilterContext{})
 		})
 	}
 }
@@ -201,6 +187,107 @@ func TestClusterExists(t *testing.T) {
 	}
 }
 
+func TestCorrelateIncident(t *testing.T) {
+	t.Run("storm detection disabled returns no correlation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		pdi := &interceptorHandler{cfg: &config.Config{}}
+		if id := pdi.correlateIncident(context.Background(), pdmock.NewMockClient(ctrl), ocmmock.NewMockClient(ctrl), "chgm"); id != "" {
+			t.Errorf("correlateIncident() = %q, want empty", id)
+		}
+	})
+
+	t.Run("threshold crossed posts correlation note to the incident and earlier incidents", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stormCfg := &config.StormConfig{Threshold: 2, WindowMinutes: 15, GroupBy: []string{config.StormGroupByRegion}}
+		pdi := &interceptorHandler{cfg: &config.Config{Storm: stormCfg}, stormTracker: storm.NewTracker(stormCfg)}
+
+		var ids []string
+		for _, clusterID := range []string{"cluster-1", "cluster-2"} {
+			cluster, err := cmv1.NewCluster().ID(clusterID).Region(cmv1.NewCloudRegion().ID("us-east-1")).Build()
+			if err != nil {
+				t.Fatalf("failed to build cluster: %v", err)
+			}
+			mockPD := pdmock.NewMockClient(ctrl)
+			mockOCM := ocmmock.NewMockClient(ctrl)
+			mockPD.EXPECT().RetrieveClusterID().Return(clusterID, nil).Times(1)
+			mockPD.EXPECT().GetIncidentID().Return("incident-" + clusterID).AnyTimes()
+			mockOCM.EXPECT().GetClusterInfo(clusterID).Return(cluster, nil).Times(1)
+			if clusterID == "cluster-2" {
+				mockPD.EXPECT().AddNote(gomock.Any()).DoAndReturn(func(note string) error {
+					if !contains(note, "wider event affecting 2 clusters") {
+						t.Errorf("unexpected note: %s", note)
+					}
+					return nil
+				}).Times(1)
+				mockPD.EXPECT().AddNoteToIncident("incident-cluster-1", gomock.Any()).DoAndReturn(func(_, note string) error {
+					if !contains(note, "wider event affecting 2 clusters") || !contains(note, "cluster actions were not skipped") {
+						t.Errorf("unexpected note: %s", note)
+					}
+					return nil
+				}).Times(1)
+			}
+			ids = append(ids, pdi.correlateIncident(context.Background(), mockPD, mockOCM, "chgm"))
+		}
+
+		if ids[0] != "" {
+			t.Errorf("first incident correlation = %q, want empty", ids[0])
+		}
+		if ids[1] == "" {
+			t.Error("second incident should be correlated")
+		}
+	})
+
+	t.Run("cluster lookup failure returns no correlation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stormCfg := &config.StormConfig{Threshold: 2, WindowMinutes: 15, GroupBy: []string{config.StormGroupByRegion}}
+		pdi := &interceptorHandler{cfg: &config.Config{Storm: stormCfg}, stormTracker: storm.NewTracker(stormCfg)}
+
+		mockPD := pdmock.NewMockClient(ctrl)
+		mockPD.EXPECT().RetrieveClusterID().Return("", errors.New("no cluster ID found")).Times(1)
+		if id := pdi.correlateIncident(context.Background(), mockPD, ocmmock.NewMockClient(ctrl), "chgm"); id != "" {
+			t.Errorf("correlateIncident() = %q, want empty", id)
+		}
+	})
+
+	t.Run("storm store failure returns no correlation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		stormCfg := &config.StormConfig{Threshold: 2, WindowMinutes: 15, GroupBy: []string{config.StormGroupByRegion}}
+		pdi := &interceptorHandler{cfg: &config.Config{Storm: stormCfg}, stormTracker: storm.NewSharedTracker(stormCfg, failingStore{})}
+
+		cluster, err := cmv1.NewCluster().ID("cluster-1").Region(cmv1.NewCloudRegion().ID("us-east-1")).Build()
+		if err != nil {
+			t.Fatalf("failed to build cluster: %v", err)
+		}
+		mockPD := pdmock.NewMockClient(ctrl)
+		mockOCM := ocmmock.NewMockClient(ctrl)
+		mockPD.EXPECT().RetrieveClusterID().Return("cluster-1", nil).Times(1)
+		mockPD.EXPECT().GetIncidentID().Return("incident-1").AnyTimes()
+		mockOCM.EXPECT().GetClusterInfo("cluster-1").Return(cluster, nil).Times(1)
+		if id := pdi.correlateIncident(context.Background(), mockPD, mockOCM, "chgm"); id != "" {
+			t.Errorf("correlateIncident() = %q, want empty", id)
+		}
+	})
+}
+
+// failingStore is a storm store that can't be reached
+type failingStore struct{}
+
+func (failingStore) Load(context.Context) ([]byte, string, error) {
+	return nil, "", errors.New("connection refused")
+}
+
+func (failingStore) Save(context.Context, []byte, string) error {
+	return errors.New("connection refused")
+}
+
 func boolPtr(b bool) *bool { return &b }
 
 func contains(s, substr string) bool {
@@ -370,3 +457,88 @@ func TestSignatureVerification(t *testing.T) {
 		})
 	}
 }
+
+// TestAlertmanagerSource covers requests from an Alertmanager webhook receiver,
+// which are authenticated with a bearer token instead of a PagerDuty signature.
+func TestAlertmanagerSource(t *testing.T) {
+	const alertmanagerBody = `{"version":"4","status":"firing","alerts":[` +
+		`{"status":"firing","labels":{"alertname":"unmapped","_id":"cluster-1"},"fingerprint":"f0"},` +
+		`{"status":"firing","labels":{"alertname":"test","_id":"cluster-2"},"fingerprint":"f1"},` +
+		`{"status":"firing","labels":{"alertname":"test","_id":"cluster-3"},"fingerprint":"f2"}]}`
+
+	tests := []struct {
+		name         string
+		token        string
+		wantStatus   int
+		wantContinue bool
+	}{
+		{
+			name:         "valid token forwards every mapped alert",
+			token:        "am-token",
+			wantStatus:   http.StatusOK,
+			wantContinue: true,
+		},
+		{
+			name:       "invalid token is rejected",
+			token:      "wrong-token",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outer, err := json.Marshal(map[string]interface{}{
+				"body": alertmanagerBody,
+				"header": map[string][]string{
+					"Authorization": {"Bearer " + tt.token},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(outer))
+			rec := httptest.NewRecorder()
+
+			handler, err := CreateInterceptorHandler([]string{"pd-token"}, "testdata/minimal-config.yaml", source.NewAlertmanager([]string{"am-token"}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Continue   bool              `json:"continue"`
+				Extensions map[string]string `json:"extensions"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Continue != tt.wantContinue {
+				t.Fatalf("Continue = %v, want %v", resp.Continue, tt.wantContinue)
+			}
+
+			payload, err := base64.StdEncoding.DecodeString(resp.Extensions["payload_base64"])
+			if err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+			incidents, ok := source.Decode(payload)
+			if !ok {
+				t.Fatalf("forwarded payload is not made of normalized incidents: %s", payload)
+			}
+			if len(incidents) != 2 {
+				t.Fatalf("forwarded %d incidents, want 2: %+v", len(incidents), incidents)
+			}
+			for i, clusterID := range []string{"cluster-2", "cluster-3"} {
+				if incidents[i].ClusterID != clusterID || incidents[i].Handle.Source != source.NameAlertmanager {
+					t.Errorf("unexpected forwarded incident %d: %+v", i, incidents[i])
+				}
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,8 +24,12 @@ import (
 	elbv1 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	iamv2 "github.com/aws/aws-sdk-go-v2/service/iam"
+	iamv2types "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	route53v2 "github.com/aws/aws-sdk-go-v2/service/route53"
 	route53v2types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	servicequotasv2 "github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	servicequotasv2types "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
 	stsv2 "github.com/aws/aws-sdk-go-v2/service/sts"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
@@ -45,6 +52,13 @@ type EC2API interface {
 	DescribeRouteTables(ctx context.Context, in *ec2v2.DescribeRouteTablesInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeRouteTablesOutput, error)
 	DescribeVpcs(ctx context.Context, in *ec2v2.DescribeVpcsInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeVpcsOutput, error)
 	DescribeDhcpOptions(ctx context.Context, in *ec2v2.DescribeDhcpOptionsInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeDhcpOptionsOutput, error)
+	DescribeInstanceStatus(ctx context.Context, in *ec2v2.DescribeInstanceStatusInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeInstanceStatusOutput, error)
+	DescribeNatGateways(ctx context.Context, in *ec2v2.DescribeNatGatewaysInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeNatGatewaysOutput, error)
+	DescribeInternetGateways(ctx context.Context, in *ec2v2.DescribeInternetGatewaysInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeInternetGatewaysOutput, error)
+	DescribeNetworkAcls(ctx context.Context, in *ec2v2.DescribeNetworkAclsInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeNetworkAclsOutput, error)
+	DescribeVpcEndpoints(ctx context.Context, in *ec2v2.DescribeVpcEndpointsInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeVpcEndpointsOutput, error)
+	DescribeAddresses(ctx context.Context, in *ec2v2.DescribeAddressesInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeAddressesOutput, error)
+	DescribeNetworkInterfaces(ctx context.Context, in *ec2v2.DescribeNetworkInterfacesInput, optFns ...func(*ec2v2.Options)) (*ec2v2.DescribeNetworkInterfacesOutput, error)
 }
 
 type CloudTrailAPI interface {
@@ -55,6 +69,17 @@ type StsAPI interface {
 	AssumeRole(ctx context.Context, in *stsv2.AssumeRoleInput, optFns ...func(*stsv2.Options)) (*stsv2.AssumeRoleOutput, error)
 }
 
+type IAMAPI interface {
+	GetRole(ctx context.Context, in *iamv2.GetRoleInput, optFns ...func(*iamv2.Options)) (*iamv2.GetRoleOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, in *iamv2.ListAttachedRolePoliciesInput, optFns ...func(*iamv2.Options)) (*iamv2.ListAttachedRolePoliciesOutput, error)
+	GetOpenIDConnectProvider(ctx context.Context, in *iamv2.GetOpenIDConnectProviderInput, optFns ...func(*iamv2.Options)) (*iamv2.GetOpenIDConnectProviderOutput, error)
+}
+
+type ServiceQuotasAPI interface {
+	GetServiceQuota(ctx context.Context, in *servicequotasv2.GetServiceQuotaInput, optFns ...func(*servicequotasv2.Options)) (*servicequotasv2.GetServiceQuotaOutput, error)
+	GetAWSDefaultServiceQuota(ctx context.Context, in *servicequotasv2.GetAWSDefaultServiceQuotaInput, optFns ...func(*servicequotasv2.Options)) (*servicequotasv2.GetAWSDefaultServiceQuotaOutput, error)
+}
+
 type AgentCoreAPI interface {
 	InvokeAgentRuntime(ctx context.Context, in *bedrockagentcore.InvokeAgentRuntimeInput, optFns ...func(*bedrockagentcore.Options)) (*bedrockagentcore.InvokeAgentRuntimeOutput, error)
 }
@@ -91,15 +116,88 @@ type CLBInstanceHealth struct {
 	Description string
 }
 
+// InstanceStatus represents the status checks and scheduled events of an EC2 instance.
+type InstanceStatus struct {
+	InstanceID     string
+	State          string // "running", "stopped", etc.
+	SystemStatus   string // "ok", "impaired", "insufficient-data", "not-applicable", "initializing"
+	InstanceStatus string // same values as SystemStatus
+	Events         []InstanceEvent
+}
+
+// InstanceEvent represents an event AWS scheduled for an instance, e.g. a retirement.
+type InstanceEvent struct {
+	Code        string // "instance-retirement", "system-maintenance", "instance-stop", etc.
+	Description string // prefixed with "[Completed]" or "[Canceled]" once the event is over
+	NotBefore   time.Time
+}
+
+// VpcNetworkResources are the IDs of the resources that make up the network of a cluster's VPC.
+type VpcNetworkResources struct {
+	VpcID              string
+	RouteTableIDs      []string
+	NatGatewayIDs      []string // pending or available, and deleted NAT gateways routes still point to
+	InternetGatewayIDs []string
+	NetworkAclIDs      []string
+	SecurityGroupIDs   []string
+	VpcEndpointIDs     []string
+	// RouteTablesWithoutEgress are the route tables associated with subnets that have no active default route,
+	// e.g. because the route was deleted or points to a deleted gateway
+	RouteTablesWithoutEgress []string
+}
+
+// IDs returns the IDs of the VPC and of all of its resources.
+func (r VpcNetworkResources) IDs() []string {
+	ids := []string{r.VpcID}
+	for _, group := range [][]string{r.RouteTableIDs, r.NatGatewayIDs, r.InternetGatewayIDs, r.NetworkAclIDs, r.SecurityGroupIDs, r.VpcEndpointIDs} {
+		ids = append(ids, group...)
+	}
+	return ids
+}
+
+// Contains reports whether id is the ID of the VPC or of one of its resources.
+func (r VpcNetworkResources) Contains(id string) bool {
+	if id == "" {
+		return false
+	}
+	return id == r.VpcID || slices.Contains(r.RouteTableIDs, id) || slices.Contains(r.NatGatewayIDs, id) ||
+		slices.Contains(r.InternetGatewayIDs, id) || slices.Contains(r.NetworkAclIDs, id) ||
+		slices.Contains(r.SecurityGroupIDs, id) || slices.Contains(r.VpcEndpointIDs, id)
+}
+
+// IAMRole represents an IAM role with its trust policy and attached managed policies.
+type IAMRole struct {
+	Name               string
+	ARN                string
+	TrustPolicy        string // decoded JSON document of the assume role policy
+	AttachedPolicyARNs []string
+}
+
+// ServiceQuota is the value of a service quota of the account in the region of the client.
+type ServiceQuota struct {
+	ServiceCode string // e.g. "ec2"
+	QuotaCode   string // e.g. "L-1216C47A"
+	Name        string
+	Value       float64
+}
+
+// EC2ResourceUsage is the usage of the EC2 resources of the account in the region of the client
+// that count against service quotas.
+type EC2ResourceUsage struct {
+	VCPUsByFamily     map[string]int32 // vCPUs of the running and pending On-Demand instances by instance family, e.g. "m5"
+	ElasticIPs        int
+	NetworkInterfaces int
+}
+
 type Client interface {
-	ListRunningInstances(infraID string) ([]ec2v2types.Instance, error)
-	ListNonRunningInstances(infraID string) ([]ec2v2types.Instance, error)
-	PollInstanceStopEventsFor(instances []ec2v2types.Instance, retryTimes int) ([]cloudtrailv2types.Event, error)
+	ListRunningInstances(ctx context.Context, infraID string) ([]ec2v2types.Instance, error)
+	ListNonRunningInstances(ctx context.Context, infraID string) ([]ec2v2types.Instance, error)
+	PollInstanceStopEventsFor(ctx context.Context, instances []ec2v2types.Instance, retryTimes int) ([]cloudtrailv2types.Event, error)
 	GetBaseConfig() *awsv2.Config
-	GetSecurityGroupID(infraID string) (string, error)
-	GetSubnetID(infraID string) ([]string, error)
-	IsSubnetPrivate(subnet string) (bool, error)
-	GetRouteTableForSubnet(subnetID string) (ec2v2types.RouteTable, error)
+	GetSecurityGroupID(ctx context.Context, infraID string) (string, error)
+	GetSubnetID(ctx context.Context, infraID string) ([]string, error)
+	IsSubnetPrivate(ctx context.Context, subnet string) (bool, error)
+	GetRouteTableForSubnet(ctx context.Context, subnetID string) (ec2v2types.RouteTable, error)
 	FindHostedZone(ctx context.Context, dnsName string, private bool) (string, error)
 	HasResourceRecordSet(ctx context.Context, hostedZoneID, recordName, recordType string) (bool, error)
 	GetVpcDhcpConfiguration(ctx context.Context, infraID string) ([]string, error)
@@ -109,6 +207,13 @@ type Client interface {
 	GetCLBInstanceHealth(ctx context.Context, lbName string) ([]CLBInstanceHealth, error)
 	GetSecurityGroupRules(ctx context.Context, sgIDs []string) ([]ec2v2types.SecurityGroup, error)
 	GetInstanceSecurityGroupIDs(ctx context.Context, instanceIDs []string) ([]string, error)
+	DescribeInstanceStatus(ctx context.Context, instanceIDs []string) ([]InstanceStatus, error)
+	LookupResourceEvents(ctx context.Context, resourceIDs []string, eventNames []string, since time.Time) ([]cloudtrailv2types.Event, bool, error)
+	GetVpcNetworkResources(ctx context.Context, infraID string) (VpcNetworkResources, error)
+	GetIAMRole(ctx context.Context, roleName string) (*IAMRole, error)
+	OIDCProviderExists(ctx context.Context, providerARN string) (bool, error)
+	GetServiceQuota(ctx context.Context, serviceCode, quotaCode string) (ServiceQuota, error)
+	GetEC2ResourceUsage(ctx context.Context) (EC2ResourceUsage, error)
 }
 
 type SdkClient struct {
@@ -120,6 +225,8 @@ type SdkClient struct {
 	Route53Client    Route53API
 	Elbv2Client      ELBV2API
 	ElbClient        ELBAPI
+	IamClient        IAMAPI
+	QuotasClient     ServiceQuotasAPI
 }
 
 func NewClient(config awsv2.Config) (*SdkClient, error) {
@@ -131,6 +238,8 @@ func NewClient(config awsv2.Config) (*SdkClient, error) {
 		Route53Client:    route53v2.NewFromConfig(config),
 		Elbv2Client:      elbv2.NewFromConfig(config),
 		ElbClient:        elbv1.NewFromConfig(config),
+		IamClient:        iamv2.NewFromConfig(config),
+		QuotasClient:     servicequotasv2.NewFromConfig(config),
 	}, nil
 }
 
@@ -194,7 +303,7 @@ func (c *SdkClient) GetBaseConfig() *awsv2.Config {
 }
 
 // ListRunningInstances lists all running or starting instances that belong to a cluster
-func (c *SdkClient) ListRunningInstances(infraID string) ([]ec2v2types.Instance, error) {
+func (c *SdkClient) ListRunningInstances(ctx context.Context, infraID string) ([]ec2v2types.Instance, error) {
 	filters := []ec2v2types.Filter{
 		{
 			Name:   awsv2.String("tag:kubernetes.io/cluster/" + infraID),
@@ -205,17 +314,17 @@ func (c *SdkClient) ListRunningInstances(infraID string) ([]ec2v2types.Instance,
 			Values: []string{"running", "pending"},
 		},
 	}
-	return c.listInstancesWithFilter(filters)
+	return c.listInstancesWithFilter(ctx, filters)
 }
 
-func (c *SdkClient) listInstancesWithFilter(filters []ec2v2types.Filter) ([]ec2v2types.Instance, error) {
+func (c *SdkClient) listInstancesWithFilter(ctx context.Context, filters []ec2v2types.Filter) ([]ec2v2types.Instance, error) {
 	in := &ec2v2.DescribeInstancesInput{
 		Filters: filters,
 	}
 
 	var instances []ec2v2types.Instance
 	for {
-		out, err := c.Ec2Client.DescribeInstances(context.TODO(), in)
+		out, err := c.Ec2Client.DescribeInstances(ctx, in)
 		if err != nil {
 			return []ec2v2types.Instance{}, err
 		}
@@ -231,7 +340,7 @@ func (c *SdkClient) listInstancesWithFilter(filters []ec2v2types.Filter) ([]ec2v
 }
 
 // ListNonRunningInstances lists all non-running instances that belong to a cluster
-func (c *SdkClient) ListNonRunningInstances(infraID string) ([]ec2v2types.Instance, error) {
+func (c *SdkClient) ListNonRunningInstances(ctx context.Context, infraID string) ([]ec2v2types.Instance, error) {
 	filters := []ec2v2types.Filter{
 		{
 			Name:   awsv2.String("tag:kubernetes.io/cluster/" + infraID),
@@ -247,10 +356,10 @@ func (c *SdkClient) ListNonRunningInstances(infraID string) ([]ec2v2types.Instan
 			},
 		},
 	}
-	return c.listInstancesWithFilter(filters)
+	return c.listInstancesWithFilter(ctx, filters)
 }
 
-func (c *SdkClient) PollInstanceStopEventsFor(instances []ec2v2types.Instance, retryTimes int) ([]cloudtrailv2types.Event, error) {
+func (c *SdkClient) PollInstanceStopEventsFor(ctx context.Context, instances []ec2v2types.Instance, retryTimes int) ([]cloudtrailv2types.Event, error) {
 	if len(instances) == 0 {
 		return nil, nil
 	}
@@ -281,12 +390,12 @@ func (c *SdkClient) PollInstanceStopEventsFor(instances []ec2v2types.Instance, r
 	var executionError error
 	stoppedInstanceEvents := make([]cloudtrailv2types.Event, 0)
 	terminatedInstanceEvents := make([]cloudtrailv2types.Event, 0)
-	err = wait.ExponentialBackoff(backoffOptions, func() (bool, error) {
+	err = wait.ExponentialBackoffWithContext(ctx, backoffOptions, func(ctx context.Context) (bool, error) {
 		executionError = nil
 
 		// Retry only in case we haven't retrieved these events in the previous iteration.
 		if len(stoppedInstanceEvents) == 0 {
-			stoppedInstanceEvents, err = c.ListAllInstanceStopEventsV2()
+			stoppedInstanceEvents, err = c.ListAllInstanceStopEventsV2(ctx)
 			if err != nil {
 				executionError = fmt.Errorf("an error occurred in ListAllInstanceStopEvents: %w", err)
 				//nolint:nilerr
@@ -298,7 +407,7 @@ func (c *SdkClient) PollInstanceStopEventsFor(instances []ec2v2types.Instance, r
 		// iteration (this should never be != 0 as the code is sequential and
 		// this is the only part that can trigger the partial-retry)
 		if len(terminatedInstanceEvents) == 0 {
-			terminatedInstanceEvents, err = c.ListAllTerminatedInstancesV2()
+			terminatedInstanceEvents, err = c.ListAllTerminatedInstancesV2(ctx)
 			if err != nil {
 				executionError = fmt.Errorf("an error occurred in ListAllTerminatedInstances: %w", err)
 				//nolint:nilerr
@@ -386,25 +495,25 @@ func (c *SdkClient) PollInstanceStopEventsFor(instances []ec2v2types.Instance, r
 }
 
 // ListAllInstanceStopEvents lists StopInstances events from CloudTrail
-func (c *SdkClient) ListAllInstanceStopEventsV2() ([]cloudtrailv2types.Event, error) {
+func (c *SdkClient) ListAllInstanceStopEventsV2(ctx context.Context) ([]cloudtrailv2types.Event, error) {
 	att := cloudtrailv2types.LookupAttribute{
 		AttributeKey:   "EventName",
 		AttributeValue: awsv2.String("StopInstances"),
 	}
-	return c.listAllInstancesAttribute(att)
+	return c.listAllInstancesAttribute(ctx, att)
 }
 
 // ListAllTerminatedInstances lists TerminatedInstances events from CloudTrail
-func (c *SdkClient) ListAllTerminatedInstancesV2() ([]cloudtrailv2types.Event, error) {
+func (c *SdkClient) ListAllTerminatedInstancesV2(ctx context.Context) ([]cloudtrailv2types.Event, error) {
 	att := cloudtrailv2types.LookupAttribute{
 		AttributeKey:   "EventName",
 		AttributeValue: awsv2.String("TerminateInstances"),
 	}
-	return c.listAllInstancesAttribute(att)
+	return c.listAllInstancesAttribute(ctx, att)
 }
 
 // GetSecurityGroupID will return the security group id needed for the network verifier
-func (c *SdkClient) GetSecurityGroupID(infraID string) (string, error) {
+func (c *SdkClient) GetSecurityGroupID(ctx context.Context, infraID string) (string, error) {
 	in := &ec2v2.DescribeSecurityGroupsInput{
 		Filters: []ec2v2types.Filter{
 			{
@@ -415,7 +524,7 @@ func (c *SdkClient) GetSecurityGroupID(infraID string) (string, error) {
 			},
 		},
 	}
-	out, err := c.Ec2Client.DescribeSecurityGroups(context.TODO(), in)
+	out, err := c.Ec2Client.DescribeSecurityGroups(ctx, in)
 	if err != nil {
 		return "", fmt.Errorf("failed to list security group: %w", err)
 	}
@@ -429,7 +538,7 @@ func (c *SdkClient) GetSecurityGroupID(infraID string) (string, error) {
 }
 
 // GetSubnetID will return the private subnets needed for the network verifier
-func (c *SdkClient) GetSubnetID(infraID string) ([]string, error) {
+func (c *SdkClient) GetSubnetID(ctx context.Context, infraID string) ([]string, error) {
 	in := &ec2v2.DescribeSubnetsInput{
 		Filters: []ec2v2types.Filter{
 			{
@@ -442,7 +551,7 @@ func (c *SdkClient) GetSubnetID(infraID string) ([]string, error) {
 			},
 		},
 	}
-	out, err := c.Ec2Client.DescribeSubnets(context.TODO(), in)
+	out, err := c.Ec2Client.DescribeSubnets(ctx, in)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find private subnet for %s: %w", infraID, err)
 	}
@@ -453,12 +562,12 @@ func (c *SdkClient) GetSubnetID(infraID string) ([]string, error) {
 }
 
 // IsSubnetPrivate checks if the provided subnet is private
-func (c *SdkClient) IsSubnetPrivate(subnet string) (bool, error) {
+func (c *SdkClient) IsSubnetPrivate(ctx context.Context, subnet string) (bool, error) {
 	in := &ec2v2.DescribeSubnetsInput{
 		SubnetIds: []string{subnet},
 	}
 
-	out, err := c.Ec2Client.DescribeSubnets(context.TODO(), in)
+	out, err := c.Ec2Client.DescribeSubnets(ctx, in)
 	if err != nil {
 		return false, err
 	}
@@ -473,12 +582,12 @@ func (c *SdkClient) IsSubnetPrivate(subnet string) (bool, error) {
 		},
 	}
 	var rtb *ec2v2types.RouteTable
-	rtbs, err := c.Ec2Client.DescribeRouteTables(context.TODO(), rtbIn)
+	rtbs, err := c.Ec2Client.DescribeRouteTables(ctx, rtbIn)
 	if err != nil {
 		return false, err
 	}
 	if len(rtbs.RouteTables) == 0 {
-		rtb, err = c.defaultRouteTableForVpc(*out.Subnets[0].VpcId)
+		rtb, err = c.defaultRouteTableForVpc(ctx, *out.Subnets[0].VpcId)
 		if err != nil {
 			return false, err
 		}
@@ -499,8 +608,8 @@ func (c *SdkClient) IsSubnetPrivate(subnet string) (bool, error) {
 }
 
 // GetRouteTableForSubnet returns the subnets routeTable
-func (c *SdkClient) GetRouteTableForSubnet(subnetID string) (ec2v2types.RouteTable, error) {
-	out, err := c.Ec2Client.DescribeRouteTables(context.TODO(), &ec2v2.DescribeRouteTablesInput{
+func (c *SdkClient) GetRouteTableForSubnet(ctx context.Context, subnetID string) (ec2v2types.RouteTable, error) {
+	out, err := c.Ec2Client.DescribeRouteTables(ctx, &ec2v2.DescribeRouteTablesInput{
 		Filters: []ec2v2types.Filter{
 			{
 				Name:   awsv2.String("association.subnet-id"),
@@ -516,13 +625,13 @@ func (c *SdkClient) GetRouteTableForSubnet(subnetID string) (ec2v2types.RouteTab
 
 	// If there are no associated RouteTables, then the subnet uses the default RoutTable for the VPC
 	if len(out.RouteTables) == 0 {
-		vpcID, err := c.findVpcIDForSubnet(subnetID)
+		vpcID, err := c.findVpcIDForSubnet(ctx, subnetID)
 		if err != nil {
 			return ec2v2types.RouteTable{}, err
 		}
 
 		// Set the route table to the default for the VPC
-		routeTable, err = c.findDefaultRouteTableForVPC(vpcID)
+		routeTable, err = c.findDefaultRouteTableForVPC(ctx, vpcID)
 		if err != nil {
 			return ec2v2types.RouteTable{}, err
 		}
@@ -531,11 +640,11 @@ func (c *SdkClient) GetRouteTableForSubnet(subnetID string) (ec2v2types.RouteTab
 		routeTable = *out.RouteTables[0].RouteTableId
 	}
 
-	return c.getRouteTable(routeTable)
+	return c.getRouteTable(ctx, routeTable)
 }
 
-func (c *SdkClient) defaultRouteTableForVpc(vpcId string) (*ec2v2types.RouteTable, error) {
-	describeRouteTablesOutput, err := c.Ec2Client.DescribeRouteTables(context.TODO(), &ec2v2.DescribeRouteTablesInput{
+func (c *SdkClient) defaultRouteTableForVpc(ctx context.Context, vpcId string) (*ec2v2types.RouteTable, error) {
+	describeRouteTablesOutput, err := c.Ec2Client.DescribeRouteTables(ctx, &ec2v2.DescribeRouteTablesInput{
 		Filters: []ec2v2types.Filter{{Name: awsv2.String("vpc-id"), Values: []string{vpcId}}},
 	})
 	if err != nil {
@@ -552,7 +661,7 @@ func (c *SdkClient) defaultRouteTableForVpc(vpcId string) (*ec2v2types.RouteTabl
 	return nil, fmt.Errorf("no default route table found for vpc: %s", vpcId)
 }
 
-func (c *SdkClient) listAllInstancesAttribute(att cloudtrailv2types.LookupAttribute) ([]cloudtrailv2types.Event, error) {
+func (c *SdkClient) listAllInstancesAttribute(ctx context.Context, att cloudtrailv2types.LookupAttribute) ([]cloudtrailv2types.Event, error) {
 	// We only look up events that are not older than 2 hours
 	since := time.Now().UTC().Add(time.Duration(-2) * time.Hour)
 	// We will only capture this many events via pagination - looping till we
@@ -568,7 +677,7 @@ func (c *SdkClient) listAllInstancesAttribute(att cloudtrailv2types.LookupAttrib
 	// FIXME: Decide if we should just always retrieve *all* events which could
 	// be wasteful
 	for paginator.HasMorePages() && len(events) < maxNumberEvents {
-		out, err := paginator.NextPage(context.TODO())
+		out, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -578,8 +687,8 @@ func (c *SdkClient) listAllInstancesAttribute(att cloudtrailv2types.LookupAttrib
 }
 
 // findVpcIDForSubnet returns the VPC ID for the subnet
-func (c *SdkClient) findVpcIDForSubnet(subnetID string) (string, error) {
-	describeSubnetOutput, err := c.Ec2Client.DescribeSubnets(context.TODO(), &ec2v2.DescribeSubnetsInput{
+func (c *SdkClient) findVpcIDForSubnet(ctx context.Context, subnetID string) (string, error) {
+	describeSubnetOutput, err := c.Ec2Client.DescribeSubnets(ctx, &ec2v2.DescribeSubnetsInput{
 		SubnetIds: []string{subnetID},
 	})
 	if err != nil {
@@ -593,8 +702,8 @@ func (c *SdkClient) findVpcIDForSubnet(subnetID string) (string, error) {
 }
 
 // findDefaultRouteTableForVPC returns the AWS Route Table ID of the VPC's default Route Table
-func (c *SdkClient) findDefaultRouteTableForVPC(vpcID string) (string, error) {
-	describeRouteTablesOutput, err := c.Ec2Client.DescribeRouteTables(context.TODO(), &ec2v2.DescribeRouteTablesInput{
+func (c *SdkClient) findDefaultRouteTableForVPC(ctx context.Context, vpcID string) (string, error) {
+	describeRouteTablesOutput, err := c.Ec2Client.DescribeRouteTables(ctx, &ec2v2.DescribeRouteTablesInput{
 		Filters: []ec2v2types.Filter{
 			{
 				Name:   awsv2.String("vpc-id"),
@@ -618,8 +727,8 @@ func (c *SdkClient) findDefaultRouteTableForVPC(vpcID string) (string, error) {
 }
 
 // GetRouteTable takes a routeTable ID and returns a RouteTablesOutput
-func (c *SdkClient) getRouteTable(routeTableID string) (ec2v2types.RouteTable, error) {
-	describeRouteTablesOutput, err := c.Ec2Client.DescribeRouteTables(context.TODO(), &ec2v2.DescribeRouteTablesInput{
+func (c *SdkClient) getRouteTable(ctx context.Context, routeTableID string) (ec2v2types.RouteTable, error) {
+	describeRouteTablesOutput, err := c.Ec2Client.DescribeRouteTables(ctx, &ec2v2.DescribeRouteTablesInput{
 		RouteTableIds: []string{routeTableID},
 	})
 	if err != nil {
@@ -864,6 +973,210 @@ func (c *SdkClient) GetInstanceSecurityGroupIDs(ctx context.Context, instanceIDs
 	return result, nil
 }
 
+// DescribeInstanceStatus returns the status checks and scheduled events of the instances,
+// including those of instances that aren't running.
+func (c *SdkClient) DescribeInstanceStatus(ctx context.Context, instanceIDs []string) ([]InstanceStatus, error) {
+	if len(instanceIDs) == 0 {
+		return nil, nil
+	}
+	in := &ec2v2.DescribeInstanceStatusInput{
+		InstanceIds:         instanceIDs,
+		IncludeAllInstances: awsv2.Bool(true),
+	}
+
+	var statuses []InstanceStatus
+	for {
+		out, err := c.Ec2Client.DescribeInstanceStatus(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance status: %w", err)
+		}
+		for _, s := range out.InstanceStatuses {
+			status := InstanceStatus{InstanceID: awsv2.ToString(s.InstanceId)}
+			if s.InstanceState != nil {
+				status.State = string(s.InstanceState.Name)
+			}
+			if s.SystemStatus != nil {
+				status.SystemStatus = string(s.SystemStatus.Status)
+			}
+			if s.InstanceStatus != nil {
+				status.InstanceStatus = string(s.InstanceStatus.Status)
+			}
+			for _, e := range s.Events {
+				status.Events = append(status.Events, InstanceEvent{
+					Code:        string(e.Code),
+					Description: awsv2.ToString(e.Description),
+					NotBefore:   awsv2.ToTime(e.NotBefore),
+				})
+			}
+			statuses = append(statuses, status)
+		}
+		if out.NextToken == nil {
+			break
+		}
+		in.NextToken = out.NextToken
+	}
+	return statuses, nil
+}
+
+// cloudTrailLookupInterval paces the LookupEvents requests, as CloudTrail only allows 2 per second and account
+var cloudTrailLookupInterval = 500 * time.Millisecond
+
+// LookupResourceEvents returns the CloudTrail events with one of the names that referenced one of the resources
+// since the given time. CloudTrail only allows a single lookup attribute per request, so the resources are looked
+// up one after the other and the names are filtered here. The events per resource are bounded, the returned bool
+// is set if the lookup of a resource stopped before its oldest event.
+func (c *SdkClient) LookupResourceEvents(ctx context.Context, resourceIDs []string, eventNames []string, since time.Time) ([]cloudtrailv2types.Event, bool, error) {
+	// Bounds the events per resource like listAllInstancesAttribute, e.g. security groups can have many events
+	maxNumberEvents := 500
+	var events []cloudtrailv2types.Event
+	seen := map[string]bool{}
+	truncated := false
+	requests := 0
+	for _, id := range resourceIDs {
+		if id == "" {
+			continue
+		}
+		in := &cloudtrailv2.LookupEventsInput{
+			LookupAttributes: []cloudtrailv2types.LookupAttribute{{
+				AttributeKey:   cloudtrailv2types.LookupAttributeKeyResourceName,
+				AttributeValue: awsv2.String(id),
+			}},
+			StartTime: awsv2.Time(since),
+		}
+		paginator := cloudtrailv2.NewLookupEventsPaginator(c.CloudtrailClient, in)
+		count := 0
+		for paginator.HasMorePages() {
+			if count >= maxNumberEvents {
+				truncated = true
+				break
+			}
+			if requests > 0 {
+				select {
+				case <-ctx.Done():
+					return nil, false, ctx.Err()
+				case <-time.After(cloudTrailLookupInterval):
+				}
+			}
+			requests++
+			out, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to look up the events of %s: %w", id, err)
+			}
+			count += len(out.Events)
+			for _, event := range out.Events {
+				eventID := awsv2.ToString(event.EventId)
+				if seen[eventID] || !slices.Contains(eventNames, awsv2.ToString(event.EventName)) {
+					continue
+				}
+				seen[eventID] = true
+				events = append(events, event)
+			}
+		}
+	}
+	return events, truncated, nil
+}
+
+// GetVpcNetworkResources returns the IDs of the network resources of the VPC the cluster's subnets are in
+func (c *SdkClient) GetVpcNetworkResources(ctx context.Context, infraID string) (VpcNetworkResources, error) {
+	subnets, err := c.GetSubnetID(ctx, infraID)
+	if err != nil {
+		return VpcNetworkResources{}, err
+	}
+	vpcID, err := c.findVpcIDForSubnet(ctx, subnets[0])
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to find the VPC of subnet %s: %w", subnets[0], err)
+	}
+	resources := VpcNetworkResources{VpcID: vpcID}
+	vpcFilter := []ec2v2types.Filter{{Name: awsv2.String("vpc-id"), Values: []string{vpcID}}}
+
+	routeTables, err := c.Ec2Client.DescribeRouteTables(ctx, &ec2v2.DescribeRouteTablesInput{Filters: vpcFilter})
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to describe the route tables of %s: %w", vpcID, err)
+	}
+	for _, rt := range routeTables.RouteTables {
+		resources.RouteTableIDs = append(resources.RouteTableIDs, awsv2.ToString(rt.RouteTableId))
+		if routeTableHasSubnets(rt) && !routeTableHasEgress(rt) {
+			resources.RouteTablesWithoutEgress = append(resources.RouteTablesWithoutEgress, awsv2.ToString(rt.RouteTableId))
+		}
+		for _, route := range rt.Routes {
+			// Routes keep pointing to deleted gateways as blackholes
+			if id := awsv2.ToString(route.NatGatewayId); id != "" && !slices.Contains(resources.NatGatewayIDs, id) {
+				resources.NatGatewayIDs = append(resources.NatGatewayIDs, id)
+			}
+			if id := awsv2.ToString(route.GatewayId); strings.HasPrefix(id, "igw-") && !slices.Contains(resources.InternetGatewayIDs, id) {
+				resources.InternetGatewayIDs = append(resources.InternetGatewayIDs, id)
+			}
+		}
+	}
+
+	natGateways, err := c.Ec2Client.DescribeNatGateways(ctx, &ec2v2.DescribeNatGatewaysInput{Filter: vpcFilter})
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to describe the NAT gateways of %s: %w", vpcID, err)
+	}
+	for _, nat := range natGateways.NatGateways {
+		// Deleted NAT gateways are listed for about an hour
+		if nat.State != ec2v2types.NatGatewayStatePending && nat.State != ec2v2types.NatGatewayStateAvailable {
+			continue
+		}
+		if id := awsv2.ToString(nat.NatGatewayId); !slices.Contains(resources.NatGatewayIDs, id) {
+			resources.NatGatewayIDs = append(resources.NatGatewayIDs, id)
+		}
+	}
+
+	internetGateways, err := c.Ec2Client.DescribeInternetGateways(ctx, &ec2v2.DescribeInternetGatewaysInput{
+		Filters: []ec2v2types.Filter{{Name: awsv2.String("attachment.vpc-id"), Values: []string{vpcID}}},
+	})
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to describe the internet gateways of %s: %w", vpcID, err)
+	}
+	for _, igw := range internetGateways.InternetGateways {
+		if id := awsv2.ToString(igw.InternetGatewayId); !slices.Contains(resources.InternetGatewayIDs, id) {
+			resources.InternetGatewayIDs = append(resources.InternetGatewayIDs, id)
+		}
+	}
+
+	networkAcls, err := c.Ec2Client.DescribeNetworkAcls(ctx, &ec2v2.DescribeNetworkAclsInput{Filters: vpcFilter})
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to describe the network ACLs of %s: %w", vpcID, err)
+	}
+	for _, acl := range networkAcls.NetworkAcls {
+		resources.NetworkAclIDs = append(resources.NetworkAclIDs, awsv2.ToString(acl.NetworkAclId))
+	}
+
+	securityGroups, err := c.Ec2Client.DescribeSecurityGroups(ctx, &ec2v2.DescribeSecurityGroupsInput{Filters: vpcFilter})
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to describe the security groups of %s: %w", vpcID, err)
+	}
+	for _, sg := range securityGroups.SecurityGroups {
+		resources.SecurityGroupIDs = append(resources.SecurityGroupIDs, awsv2.ToString(sg.GroupId))
+	}
+
+	vpcEndpoints, err := c.Ec2Client.DescribeVpcEndpoints(ctx, &ec2v2.DescribeVpcEndpointsInput{Filters: vpcFilter})
+	if err != nil {
+		return VpcNetworkResources{}, fmt.Errorf("failed to describe the VPC endpoints of %s: %w", vpcID, err)
+	}
+	for _, endpoint := range vpcEndpoints.VpcEndpoints {
+		resources.VpcEndpointIDs = append(resources.VpcEndpointIDs, awsv2.ToString(endpoint.VpcEndpointId))
+	}
+
+	return resources, nil
+}
+
+// routeTableHasSubnets reports whether subnets are explicitly associated with the route table
+func routeTableHasSubnets(rt ec2v2types.RouteTable) bool {
+	return slices.ContainsFunc(rt.Associations, func(a ec2v2types.RouteTableAssociation) bool {
+		return awsv2.ToString(a.SubnetId) != ""
+	})
+}
+
+// routeTableHasEgress reports whether the route table has an active default route.
+// Routes to deleted gateways stay in the route table as blackholes.
+func routeTableHasEgress(rt ec2v2types.RouteTable) bool {
+	return slices.ContainsFunc(rt.Routes, func(route ec2v2types.Route) bool {
+		return awsv2.ToString(route.DestinationCidrBlock) == "0.0.0.0/0" && route.State == ec2v2types.RouteStateActive
+	})
+}
+
 func populateStopTime(instances []ec2v2types.Instance) (map[string]time.Time, error) {
 	idToStopTime := make(map[string]time.Time)
 	for _, instance := range instances {
@@ -923,3 +1236,125 @@ func getTime(rawReason string) (time.Time, error) {
 
 	return extractedTime, nil
 }
+
+// GetIAMRole returns the role with its trust policy and attached managed policies,
+// or nil if the role doesn't exist.
+func (c *SdkClient) GetIAMRole(ctx context.Context, roleName string) (*IAMRole, error) {
+	out, err := c.IamClient.GetRole(ctx, &iamv2.GetRoleInput{RoleName: awsv2.String(roleName)})
+	if err != nil {
+		var notFound *iamv2types.NoSuchEntityException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get role %s: %w", roleName, err)
+	}
+	if out.Role == nil {
+		return nil, fmt.Errorf("failed to get role %s: empty response", roleName)
+	}
+
+	role := &IAMRole{
+		Name: awsv2.ToString(out.Role.RoleName),
+		ARN:  awsv2.ToString(out.Role.Arn),
+	}
+	// IAM returns the trust policy URL encoded
+	role.TrustPolicy, err = url.QueryUnescape(awsv2.ToString(out.Role.AssumeRolePolicyDocument))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the trust policy of role %s: %w", roleName, err)
+	}
+
+	paginator := iamv2.NewListAttachedRolePoliciesPaginator(c.IamClient, &iamv2.ListAttachedRolePoliciesInput{RoleName: awsv2.String(roleName)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the attached policies of role %s: %w", roleName, err)
+		}
+		for _, policy := range page.AttachedPolicies {
+			role.AttachedPolicyARNs = append(role.AttachedPolicyARNs, awsv2.ToString(policy.PolicyArn))
+		}
+	}
+	return role, nil
+}
+
+// OIDCProviderExists reports whether the IAM OIDC identity provider exists
+func (c *SdkClient) OIDCProviderExists(ctx context.Context, providerARN string) (bool, error) {
+	_, err := c.IamClient.GetOpenIDConnectProvider(ctx, &iamv2.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: awsv2.String(providerARN)})
+	if err != nil {
+		var notFound *iamv2types.NoSuchEntityException
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get OIDC provider %s: %w", providerARN, err)
+	}
+	return true, nil
+}
+
+// GetServiceQuota returns the value of a service quota applied to the account, or its AWS default
+// if the account never changed it.
+func (c *SdkClient) GetServiceQuota(ctx context.Context, serviceCode, quotaCode string) (ServiceQuota, error) {
+	quota := ServiceQuota{ServiceCode: serviceCode, QuotaCode: quotaCode}
+	out, err := c.QuotasClient.GetServiceQuota(ctx, &servicequotasv2.GetServiceQuotaInput{ServiceCode: awsv2.String(serviceCode), QuotaCode: awsv2.String(quotaCode)})
+	var sq *servicequotasv2types.ServiceQuota
+	if err == nil {
+		sq = out.Quota
+	} else {
+		var notFound *servicequotasv2types.NoSuchResourceException
+		if !errors.As(err, &notFound) {
+			return quota, fmt.Errorf("failed to get service quota %s/%s: %w", serviceCode, quotaCode, err)
+		}
+		defaultOut, err := c.QuotasClient.GetAWSDefaultServiceQuota(ctx, &servicequotasv2.GetAWSDefaultServiceQuotaInput{ServiceCode: awsv2.String(serviceCode), QuotaCode: awsv2.String(quotaCode)})
+		if err != nil {
+			return quota, fmt.Errorf("failed to get default service quota %s/%s: %w", serviceCode, quotaCode, err)
+		}
+		sq = defaultOut.Quota
+	}
+	if sq == nil || sq.Value == nil {
+		return quota, fmt.Errorf("service quota %s/%s has no value", serviceCode, quotaCode)
+	}
+	quota.Name = awsv2.ToString(sq.QuotaName)
+	quota.Value = *sq.Value
+	return quota, nil
+}
+
+// GetEC2ResourceUsage counts the vCPUs of the running instances, the Elastic IPs and the network interfaces
+// of the whole account in the region, as service quotas apply to the account rather than to a cluster.
+// Spot instances are left out of the vCPUs, they count against the separate Spot quotas.
+func (c *SdkClient) GetEC2ResourceUsage(ctx context.Context) (EC2ResourceUsage, error) {
+	usage := EC2ResourceUsage{VCPUsByFamily: map[string]int32{}}
+
+	instances := ec2v2.NewDescribeInstancesPaginator(c.Ec2Client, &ec2v2.DescribeInstancesInput{
+		Filters: []ec2v2types.Filter{{Name: awsv2.String("instance-state-name"), Values: []string{"running", "pending"}}},
+	})
+	for instances.HasMorePages() {
+		page, err := instances.NextPage(ctx)
+		if err != nil {
+			return usage, fmt.Errorf("failed to describe instances: %w", err)
+		}
+		for _, res := range page.Reservations {
+			for _, instance := range res.Instances {
+				if instance.CpuOptions == nil || instance.InstanceLifecycle == ec2v2types.InstanceLifecycleTypeSpot {
+					continue
+				}
+				family, _, _ := strings.Cut(string(instance.InstanceType), ".")
+				usage.VCPUsByFamily[family] += awsv2.ToInt32(instance.CpuOptions.CoreCount) * awsv2.ToInt32(instance.CpuOptions.ThreadsPerCore)
+			}
+		}
+	}
+
+	addresses, err := c.Ec2Client.DescribeAddresses(ctx, &ec2v2.DescribeAddressesInput{
+		Filters: []ec2v2types.Filter{{Name: awsv2.String("domain"), Values: []string{"vpc"}}},
+	})
+	if err != nil {
+		return usage, fmt.Errorf("failed to describe addresses: %w", err)
+	}
+	usage.ElasticIPs = len(addresses.Addresses)
+
+	interfaces := ec2v2.NewDescribeNetworkInterfacesPaginator(c.Ec2Client, &ec2v2.DescribeNetworkInterfacesInput{})
+	for interfaces.HasMorePages() {
+		page, err := interfaces.NextPage(ctx)
+		if err != nil {
+			return usage, fmt.Errorf("failed to describe network interfaces: %w", err)
+		}
+		usage.NetworkInterfaces += len(page.NetworkInterfaces)
+	}
+	return usage, nil
+}
@@ -1,11 +1,20 @@
 package aws_test
 
 import (
+	"context"
+	"reflect"
 	"testing"
+	"time"
 
 	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	cloudtrailv2 "github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cloudtrailv2types "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
 	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2v2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	iamv2 "github.com/aws/aws-sdk-go-v2/service/iam"
+	iamv2types "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	servicequotasv2 "github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	servicequotasv2types "github.com/aws/aws-sdk-go-v2/service/servicequotas/types"
 	"go.uber.org/mock/gomock"
 
 	cadaws "github.com/openshift/configuration-anomaly-detection/pkg/aws"
@@ -128,7 +137,7 @@ func TestSdkClient_IsSubnetPrivate(t *testing.T) {
 				CloudtrailClient: tt.fields.CloudTrailClient,
 				BaseConfig:       &tt.fields.BaseConfig,
 			}
-			got, err := c.IsSubnetPrivate(tt.args.subnet)
+			got, err := c.IsSubnetPrivate(context.Background(), tt.args.subnet)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SdkClient.IsSubnetPrivate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -139,3 +148,293 @@ func TestSdkClient_IsSubnetPrivate(t *testing.T) {
 		})
 	}
 }
+
+func TestSdkClient_DescribeInstanceStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ec2api := awsmock.NewMockEC2API(ctrl)
+	notBefore := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	gomock.InOrder(
+		ec2api.EXPECT().DescribeInstanceStatus(gomock.Any(), &ec2v2.DescribeInstanceStatusInput{
+			InstanceIds:         []string{"i-1", "i-2"},
+			IncludeAllInstances: awsv2.Bool(true),
+		}).Return(&ec2v2.DescribeInstanceStatusOutput{
+			InstanceStatuses: []ec2v2types.InstanceStatus{{
+				InstanceId:     awsv2.String("i-1"),
+				InstanceState:  &ec2v2types.InstanceState{Name: ec2v2types.InstanceStateNameRunning},
+				SystemStatus:   &ec2v2types.InstanceStatusSummary{Status: ec2v2types.SummaryStatusImpaired},
+				InstanceStatus: &ec2v2types.InstanceStatusSummary{Status: ec2v2types.SummaryStatusOk},
+				Events: []ec2v2types.InstanceStatusEvent{{
+					Code:        ec2v2types.EventCodeInstanceRetirement,
+					Description: awsv2.String("The instance is running on degraded hardware"),
+					NotBefore:   &notBefore,
+				}},
+			}},
+			NextToken: awsv2.String("next"),
+		}, nil),
+		ec2api.EXPECT().DescribeInstanceStatus(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeInstanceStatusOutput{
+			InstanceStatuses: []ec2v2types.InstanceStatus{{
+				InstanceId:    awsv2.String("i-2"),
+				InstanceState: &ec2v2types.InstanceState{Name: ec2v2types.InstanceStateNameStopped},
+			}},
+		}, nil),
+	)
+
+	c := &cadaws.SdkClient{Ec2Client: ec2api}
+	got, err := c.DescribeInstanceStatus(context.Background(), []string{"i-1", "i-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []cadaws.InstanceStatus{
+		{
+			InstanceID:     "i-1",
+			State:          "running",
+			SystemStatus:   "impaired",
+			InstanceStatus: "ok",
+			Events: []cadaws.InstanceEvent{{
+				Code:        "instance-retirement",
+				Description: "The instance is running on degraded hardware",
+				NotBefore:   notBefore,
+			}},
+		},
+		{InstanceID: "i-2", State: "stopped"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SdkClient.DescribeInstanceStatus() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSdkClient_GetVpcNetworkResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ec2api := awsmock.NewMockEC2API(ctrl)
+	ec2api.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeSubnetsOutput{
+		Subnets: []ec2v2types.Subnet{{SubnetId: awsv2.String("subnet-1"), VpcId: awsv2.String("vpc-1")}},
+	}, nil).Times(2)
+	ec2api.EXPECT().DescribeRouteTables(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeRouteTablesOutput{
+		RouteTables: []ec2v2types.RouteTable{
+			{
+				RouteTableId: awsv2.String("rtb-private"),
+				Associations: []ec2v2types.RouteTableAssociation{{SubnetId: awsv2.String("subnet-1")}},
+				// The NAT gateway was deleted, the route is a blackhole
+				Routes: []ec2v2types.Route{{DestinationCidrBlock: awsv2.String("0.0.0.0/0"), NatGatewayId: awsv2.String("nat-deleted"), State: ec2v2types.RouteStateBlackhole}},
+			},
+			{
+				RouteTableId: awsv2.String("rtb-public"),
+				Associations: []ec2v2types.RouteTableAssociation{{SubnetId: awsv2.String("subnet-2")}},
+				Routes: []ec2v2types.Route{
+					{DestinationCidrBlock: awsv2.String("10.0.0.0/16"), GatewayId: awsv2.String("local"), State: ec2v2types.RouteStateActive},
+					{DestinationCidrBlock: awsv2.String("0.0.0.0/0"), GatewayId: awsv2.String("igw-1"), State: ec2v2types.RouteStateActive},
+				},
+			},
+			{
+				// The main route table without subnets doesn't need egress
+				RouteTableId: awsv2.String("rtb-main"),
+				Associations: []ec2v2types.RouteTableAssociation{{Main: awsv2.Bool(true)}},
+			},
+		},
+	}, nil)
+	ec2api.EXPECT().DescribeNatGateways(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeNatGatewaysOutput{
+		NatGateways: []ec2v2types.NatGateway{
+			{NatGatewayId: awsv2.String("nat-deleted"), State: ec2v2types.NatGatewayStateDeleted},
+			{NatGatewayId: awsv2.String("nat-gone"), State: ec2v2types.NatGatewayStateDeleted},
+			{NatGatewayId: awsv2.String("nat-2"), State: ec2v2types.NatGatewayStateAvailable},
+		},
+	}, nil)
+	ec2api.EXPECT().DescribeInternetGateways(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeInternetGatewaysOutput{
+		InternetGateways: []ec2v2types.InternetGateway{{InternetGatewayId: awsv2.String("igw-1")}},
+	}, nil)
+	ec2api.EXPECT().DescribeNetworkAcls(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeNetworkAclsOutput{
+		NetworkAcls: []ec2v2types.NetworkAcl{{NetworkAclId: awsv2.String("acl-1")}},
+	}, nil)
+	ec2api.EXPECT().DescribeSecurityGroups(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []ec2v2types.SecurityGroup{{GroupId: awsv2.String("sg-1")}},
+	}, nil)
+	ec2api.EXPECT().DescribeVpcEndpoints(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeVpcEndpointsOutput{
+		VpcEndpoints: []ec2v2types.VpcEndpoint{{VpcEndpointId: awsv2.String("vpce-1")}},
+	}, nil)
+
+	c := &cadaws.SdkClient{Ec2Client: ec2api}
+	got, err := c.GetVpcNetworkResources(context.Background(), "infra-id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := cadaws.VpcNetworkResources{
+		VpcID:              "vpc-1",
+		RouteTableIDs:      []string{"rtb-private", "rtb-public", "rtb-main"},
+		NatGatewayIDs:      []string{"nat-deleted", "nat-2"},
+		InternetGatewayIDs: []string{"igw-1"},
+		NetworkAclIDs:      []string{"acl-1"},
+		SecurityGroupIDs:   []string{"sg-1"},
+		VpcEndpointIDs:     []string{"vpce-1"},
+		// Only the route table of the blackholed NAT gateway lost its egress
+		RouteTablesWithoutEgress: []string{"rtb-private"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SdkClient.GetVpcNetworkResources() = %+v, want %+v", got, want)
+	}
+	if !got.Contains("nat-deleted") || got.Contains("nat-other") || got.Contains("") {
+		t.Errorf("VpcNetworkResources.Contains() doesn't match the IDs of the resources")
+	}
+}
+
+func TestSdkClient_GetIAMRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	iamapi := awsmock.NewMockIAMAPI(ctrl)
+	iamapi.EXPECT().GetRole(gomock.Any(), &iamv2.GetRoleInput{RoleName: awsv2.String("operator-role")}).Return(&iamv2.GetRoleOutput{
+		Role: &iamv2types.Role{
+			RoleName:                 awsv2.String("operator-role"),
+			Arn:                      awsv2.String("arn:aws:iam::123456789012:role/operator-role"),
+			AssumeRolePolicyDocument: awsv2.String("%7B%22Version%22%3A%222012-10-17%22%7D"),
+		},
+	}, nil)
+	// The paginator passes an option to the client
+	gomock.InOrder(
+		iamapi.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any(), gomock.Any()).Return(&iamv2.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []iamv2types.AttachedPolicy{{PolicyArn: awsv2.String("arn:aws:iam::aws:policy/service-role/policy-1")}},
+			IsTruncated:      true,
+			Marker:           awsv2.String("next"),
+		}, nil),
+		iamapi.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any(), gomock.Any()).Return(&iamv2.ListAttachedRolePoliciesOutput{
+			AttachedPolicies: []iamv2types.AttachedPolicy{{PolicyArn: awsv2.String("arn:aws:iam::123456789012:policy/policy-2")}},
+		}, nil),
+	)
+	iamapi.EXPECT().GetRole(gomock.Any(), &iamv2.GetRoleInput{RoleName: awsv2.String("deleted-role")}).Return(nil, &iamv2types.NoSuchEntityException{})
+
+	c := &cadaws.SdkClient{IamClient: iamapi}
+	got, err := c.GetIAMRole(context.Background(), "operator-role")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &cadaws.IAMRole{
+		Name:               "operator-role",
+		ARN:                "arn:aws:iam::123456789012:role/operator-role",
+		TrustPolicy:        `{"Version":"2012-10-17"}`,
+		AttachedPolicyARNs: []string{"arn:aws:iam::aws:policy/service-role/policy-1", "arn:aws:iam::123456789012:policy/policy-2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SdkClient.GetIAMRole() = %+v, want %+v", got, want)
+	}
+
+	got, err = c.GetIAMRole(context.Background(), "deleted-role")
+	if err != nil || got != nil {
+		t.Errorf("SdkClient.GetIAMRole() of a deleted role = %+v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestSdkClient_OIDCProviderExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	iamapi := awsmock.NewMockIAMAPI(ctrl)
+	iamapi.EXPECT().GetOpenIDConnectProvider(gomock.Any(), &iamv2.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: awsv2.String("provider")}).Return(&iamv2.GetOpenIDConnectProviderOutput{}, nil)
+	iamapi.EXPECT().GetOpenIDConnectProvider(gomock.Any(), &iamv2.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: awsv2.String("deleted-provider")}).Return(nil, &iamv2types.NoSuchEntityException{})
+
+	c := &cadaws.SdkClient{IamClient: iamapi}
+	for arn, want := range map[string]bool{"provider": true, "deleted-provider": false} {
+		got, err := c.OIDCProviderExists(context.Background(), arn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != want {
+			t.Errorf("SdkClient.OIDCProviderExists(%q) = %v, want %v", arn, got, want)
+		}
+	}
+}
+
+func TestSdkClient_GetServiceQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	quotas := awsmock.NewMockServiceQuotasAPI(ctrl)
+	applied := &servicequotasv2.GetServiceQuotaInput{ServiceCode: awsv2.String("ec2"), QuotaCode: awsv2.String("L-1216C47A")}
+	quotas.EXPECT().GetServiceQuota(gomock.Any(), applied).Return(&servicequotasv2.GetServiceQuotaOutput{
+		Quota: &servicequotasv2types.ServiceQuota{QuotaName: awsv2.String("Running On-Demand Standard instances"), Value: awsv2.Float64(256)},
+	}, nil)
+	unchanged := &servicequotasv2.GetServiceQuotaInput{ServiceCode: awsv2.String("vpc"), QuotaCode: awsv2.String("L-DF5E4CA3")}
+	quotas.EXPECT().GetServiceQuota(gomock.Any(), unchanged).Return(nil, &servicequotasv2types.NoSuchResourceException{})
+	quotas.EXPECT().GetAWSDefaultServiceQuota(gomock.Any(), &servicequotasv2.GetAWSDefaultServiceQuotaInput{ServiceCode: awsv2.String("vpc"), QuotaCode: awsv2.String("L-DF5E4CA3")}).Return(&servicequotasv2.GetAWSDefaultServiceQuotaOutput{
+		Quota: &servicequotasv2types.ServiceQuota{QuotaName: awsv2.String("Network interfaces per Region"), Value: awsv2.Float64(5000)},
+	}, nil)
+
+	c := &cadaws.SdkClient{QuotasClient: quotas}
+	tests := []struct {
+		service, code string
+		want          cadaws.ServiceQuota
+	}{
+		{"ec2", "L-1216C47A", cadaws.ServiceQuota{ServiceCode: "ec2", QuotaCode: "L-1216C47A", Name: "Running On-Demand Standard instances", Value: 256}},
+		{"vpc", "L-DF5E4CA3", cadaws.ServiceQuota{ServiceCode: "vpc", QuotaCode: "L-DF5E4CA3", Name: "Network interfaces per Region", Value: 5000}},
+	}
+	for _, tt := range tests {
+		got, err := c.GetServiceQuota(context.Background(), tt.service, tt.code)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("SdkClient.GetServiceQuota(%s, %s) = %+v, want %+v", tt.service, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestSdkClient_GetEC2ResourceUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ec2api := awsmock.NewMockEC2API(ctrl)
+	instance := func(instanceType ec2v2types.InstanceType, cores int32) ec2v2types.Instance {
+		return ec2v2types.Instance{InstanceType: instanceType, CpuOptions: &ec2v2types.CpuOptions{CoreCount: awsv2.Int32(cores), ThreadsPerCore: awsv2.Int32(2)}}
+	}
+	spot := instance("m5.4xlarge", 8)
+	spot.InstanceLifecycle = ec2v2types.InstanceLifecycleTypeSpot
+	// Paginators pass an extra option function
+	ec2api.EXPECT().DescribeInstances(gomock.Any(), gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeInstancesOutput{
+		Reservations: []ec2v2types.Reservation{
+			{Instances: []ec2v2types.Instance{instance("m5.xlarge", 2), instance("m5.2xlarge", 4)}},
+			{Instances: []ec2v2types.Instance{instance("g4dn.xlarge", 2)}},
+			// Spot instances don't count against the On-Demand quotas
+			{Instances: []ec2v2types.Instance{spot}},
+		},
+	}, nil)
+	ec2api.EXPECT().DescribeAddresses(gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeAddressesOutput{
+		Addresses: []ec2v2types.Address{{}, {}},
+	}, nil)
+	ec2api.EXPECT().DescribeNetworkInterfaces(gomock.Any(), gomock.Any(), gomock.Any()).Return(&ec2v2.DescribeNetworkInterfacesOutput{
+		NetworkInterfaces: []ec2v2types.NetworkInterface{{}, {}, {}},
+	}, nil)
+
+	c := &cadaws.SdkClient{Ec2Client: ec2api}
+	got, err := c.GetEC2ResourceUsage(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := cadaws.EC2ResourceUsage{VCPUsByFamily: map[string]int32{"m5": 12, "g4dn": 4}, ElasticIPs: 2, NetworkInterfaces: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SdkClient.GetEC2ResourceUsage() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSdkClient_LookupResourceEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	cloudtrailapi := awsmock.NewMockCloudTrailAPI(ctrl)
+	event := func(id, name string) cloudtrailv2types.Event {
+		return cloudtrailv2types.Event{EventId: awsv2.String(id), EventName: awsv2.String(name)}
+	}
+	cloudtrailapi.EXPECT().LookupEvents(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, in *cloudtrailv2.LookupEventsInput, _ ...func(*cloudtrailv2.Options)) (*cloudtrailv2.LookupEventsOutput, error) {
+			if in.LookupAttributes[0].AttributeKey != cloudtrailv2types.LookupAttributeKeyResourceName {
+				t.Errorf("expected a lookup by resource name, got %s", in.LookupAttributes[0].AttributeKey)
+			}
+			switch awsv2.ToString(in.LookupAttributes[0].AttributeValue) {
+			case "rtb-1":
+				// The event references both resources
+				return &cloudtrailv2.LookupEventsOutput{Events: []cloudtrailv2types.Event{event("1", "DeleteRoute"), event("2", "CreateTags")}}, nil
+			default:
+				return &cloudtrailv2.LookupEventsOutput{Events: []cloudtrailv2types.Event{event("1", "DeleteRoute"), event("3", "DeleteNatGateway")}}, nil
+			}
+		}).Times(2)
+
+	c := &cadaws.SdkClient{CloudtrailClient: cloudtrailapi}
+	events, truncated, err := c.LookupResourceEvents(context.Background(), []string{"rtb-1", "nat-1"}, []string{"DeleteRoute", "DeleteNatGateway"}, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, awsv2.ToString(e.EventId))
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "3"}) || truncated {
+		t.Errorf("SdkClient.LookupResourceEvents() = %v (truncated %v), want the deduplicated events with the names [1 3]", ids, truncated)
+	}
+}
@@ -12,6 +12,7 @@ package awsmock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	aws "github.com/aws/aws-sdk-go-v2/aws"
 	bedrockagentcore "github.com/aws/aws-sdk-go-v2/service/bedrockagentcore"
@@ -21,7 +22,9 @@ import (
 	types0 "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	elasticloadbalancing "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	elasticloadbalancingv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	iam "github.com/aws/aws-sdk-go-v2/service/iam"
 	route53 "github.com/aws/aws-sdk-go-v2/service/route53"
+	servicequotas "github.com/aws/aws-sdk-go-v2/service/servicequotas"
 	sts "github.com/aws/aws-sdk-go-v2/service/sts"
 	aws0 "github.com/openshift/configuration-anomaly-detection/pkg/aws"
 	gomock "go.uber.org/mock/gomock"
@@ -51,6 +54,26 @@ func (m *MockEC2API) EXPECT() *MockEC2APIMockRecorder {
 	return m.recorder
 }
 
+// DescribeAddresses mocks base method.
+func (m *MockEC2API) DescribeAddresses(ctx context.Context, in *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAddresses", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeAddressesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAddresses indicates an expected call of DescribeAddresses.
+func (mr *MockEC2APIMockRecorder) DescribeAddresses(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAddresses", reflect.TypeOf((*MockEC2API)(nil).DescribeAddresses), varargs...)
+}
+
 // DescribeDhcpOptions mocks base method.
 func (m *MockEC2API) DescribeDhcpOptions(ctx context.Context, in *ec2.DescribeDhcpOptionsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeDhcpOptionsOutput, error) {
 	m.ctrl.T.Helper()
@@ -71,6 +94,26 @@ func (mr *MockEC2APIMockRecorder) DescribeDhcpOptions(ctx, in any, optFns ...any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDhcpOptions", reflect.TypeOf((*MockEC2API)(nil).DescribeDhcpOptions), varargs...)
 }
 
+// DescribeInstanceStatus mocks base method.
+func (m *MockEC2API) DescribeInstanceStatus(ctx context.Context, in *ec2.DescribeInstanceStatusInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceStatusOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeInstanceStatus", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeInstanceStatusOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInstanceStatus indicates an expected call of DescribeInstanceStatus.
+func (mr *MockEC2APIMockRecorder) DescribeInstanceStatus(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstanceStatus", reflect.TypeOf((*MockEC2API)(nil).DescribeInstanceStatus), varargs...)
+}
+
 // DescribeInstances mocks base method.
 func (m *MockEC2API) DescribeInstances(ctx context.Context, in *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
 	m.ctrl.T.Helper()
@@ -91,6 +134,86 @@ func (mr *MockEC2APIMockRecorder) DescribeInstances(ctx, in any, optFns ...any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstances", reflect.TypeOf((*MockEC2API)(nil).DescribeInstances), varargs...)
 }
 
+// DescribeInternetGateways mocks base method.
+func (m *MockEC2API) DescribeInternetGateways(ctx context.Context, in *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeInternetGateways", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeInternetGatewaysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInternetGateways indicates an expected call of DescribeInternetGateways.
+func (mr *MockEC2APIMockRecorder) DescribeInternetGateways(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInternetGateways", reflect.TypeOf((*MockEC2API)(nil).DescribeInternetGateways), varargs...)
+}
+
+// DescribeNatGateways mocks base method.
+func (m *MockEC2API) DescribeNatGateways(ctx context.Context, in *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeNatGateways", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeNatGatewaysOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNatGateways indicates an expected call of DescribeNatGateways.
+func (mr *MockEC2APIMockRecorder) DescribeNatGateways(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNatGateways", reflect.TypeOf((*MockEC2API)(nil).DescribeNatGateways), varargs...)
+}
+
+// DescribeNetworkAcls mocks base method.
+func (m *MockEC2API) DescribeNetworkAcls(ctx context.Context, in *ec2.DescribeNetworkAclsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkAclsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeNetworkAcls", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeNetworkAclsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNetworkAcls indicates an expected call of DescribeNetworkAcls.
+func (mr *MockEC2APIMockRecorder) DescribeNetworkAcls(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNetworkAcls", reflect.TypeOf((*MockEC2API)(nil).DescribeNetworkAcls), varargs...)
+}
+
+// DescribeNetworkInterfaces mocks base method.
+func (m *MockEC2API) DescribeNetworkInterfaces(ctx context.Context, in *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeNetworkInterfaces", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeNetworkInterfacesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeNetworkInterfaces indicates an expected call of DescribeNetworkInterfaces.
+func (mr *MockEC2APIMockRecorder) DescribeNetworkInterfaces(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNetworkInterfaces", reflect.TypeOf((*MockEC2API)(nil).DescribeNetworkInterfaces), varargs...)
+}
+
 // DescribeRouteTables mocks base method.
 func (m *MockEC2API) DescribeRouteTables(ctx context.Context, in *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
 	m.ctrl.T.Helper()
@@ -151,6 +274,26 @@ func (mr *MockEC2APIMockRecorder) DescribeSubnets(ctx, in any, optFns ...any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubnets", reflect.TypeOf((*MockEC2API)(nil).DescribeSubnets), varargs...)
 }
 
+// DescribeVpcEndpoints mocks base method.
+func (m *MockEC2API) DescribeVpcEndpoints(ctx context.Context, in *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeVpcEndpoints", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeVpcEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeVpcEndpoints indicates an expected call of DescribeVpcEndpoints.
+func (mr *MockEC2APIMockRecorder) DescribeVpcEndpoints(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVpcEndpoints", reflect.TypeOf((*MockEC2API)(nil).DescribeVpcEndpoints), varargs...)
+}
+
 // DescribeVpcs mocks base method.
 func (m *MockEC2API) DescribeVpcs(ctx context.Context, in *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
 	m.ctrl.T.Helper()
@@ -259,6 +402,154 @@ func (mr *MockStsAPIMockRecorder) AssumeRole(ctx, in any, optFns ...any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssumeRole", reflect.TypeOf((*MockStsAPI)(nil).AssumeRole), varargs...)
 }
 
+// MockIAMAPI is a mock of IAMAPI interface.
+type MockIAMAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAMAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockIAMAPIMockRecorder is the mock recorder for MockIAMAPI.
+type MockIAMAPIMockRecorder struct {
+	mock *MockIAMAPI
+}
+
+// NewMockIAMAPI creates a new mock instance.
+func NewMockIAMAPI(ctrl *gomock.Controller) *MockIAMAPI {
+	mock := &MockIAMAPI{ctrl: ctrl}
+	mock.recorder = &MockIAMAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAMAPI) EXPECT() *MockIAMAPIMockRecorder {
+	return m.recorder
+}
+
+// GetOpenIDConnectProvider mocks base method.
+func (m *MockIAMAPI) GetOpenIDConnectProvider(ctx context.Context, in *iam.GetOpenIDConnectProviderInput, optFns ...func(*iam.Options)) (*iam.GetOpenIDConnectProviderOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetOpenIDConnectProvider", varargs...)
+	ret0, _ := ret[0].(*iam.GetOpenIDConnectProviderOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenIDConnectProvider indicates an expected call of GetOpenIDConnectProvider.
+func (mr *MockIAMAPIMockRecorder) GetOpenIDConnectProvider(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenIDConnectProvider", reflect.TypeOf((*MockIAMAPI)(nil).GetOpenIDConnectProvider), varargs...)
+}
+
+// GetRole mocks base method.
+func (m *MockIAMAPI) GetRole(ctx context.Context, in *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRole", varargs...)
+	ret0, _ := ret[0].(*iam.GetRoleOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRole indicates an expected call of GetRole.
+func (mr *MockIAMAPIMockRecorder) GetRole(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRole", reflect.TypeOf((*MockIAMAPI)(nil).GetRole), varargs...)
+}
+
+// ListAttachedRolePolicies mocks base method.
+func (m *MockIAMAPI) ListAttachedRolePolicies(ctx context.Context, in *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAttachedRolePolicies", varargs...)
+	ret0, _ := ret[0].(*iam.ListAttachedRolePoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttachedRolePolicies indicates an expected call of ListAttachedRolePolicies.
+func (mr *MockIAMAPIMockRecorder) ListAttachedRolePolicies(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttachedRolePolicies", reflect.TypeOf((*MockIAMAPI)(nil).ListAttachedRolePolicies), varargs...)
+}
+
+// MockServiceQuotasAPI is a mock of ServiceQuotasAPI interface.
+type MockServiceQuotasAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceQuotasAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceQuotasAPIMockRecorder is the mock recorder for MockServiceQuotasAPI.
+type MockServiceQuotasAPIMockRecorder struct {
+	mock *MockServiceQuotasAPI
+}
+
+// NewMockServiceQuotasAPI creates a new mock instance.
+func NewMockServiceQuotasAPI(ctrl *gomock.Controller) *MockServiceQuotasAPI {
+	mock := &MockServiceQuotasAPI{ctrl: ctrl}
+	mock.recorder = &MockServiceQuotasAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceQuotasAPI) EXPECT() *MockServiceQuotasAPIMockRecorder {
+	return m.recorder
+}
+
+// GetAWSDefaultServiceQuota mocks base method.
+func (m *MockServiceQuotasAPI) GetAWSDefaultServiceQuota(ctx context.Context, in *servicequotas.GetAWSDefaultServiceQuotaInput, optFns ...func(*servicequotas.Options)) (*servicequotas.GetAWSDefaultServiceQuotaOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAWSDefaultServiceQuota", varargs...)
+	ret0, _ := ret[0].(*servicequotas.GetAWSDefaultServiceQuotaOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAWSDefaultServiceQuota indicates an expected call of GetAWSDefaultServiceQuota.
+func (mr *MockServiceQuotasAPIMockRecorder) GetAWSDefaultServiceQuota(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAWSDefaultServiceQuota", reflect.TypeOf((*MockServiceQuotasAPI)(nil).GetAWSDefaultServiceQuota), varargs...)
+}
+
+// GetServiceQuota mocks base method.
+func (m *MockServiceQuotasAPI) GetServiceQuota(ctx context.Context, in *servicequotas.GetServiceQuotaInput, optFns ...func(*servicequotas.Options)) (*servicequotas.GetServiceQuotaOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, in}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetServiceQuota", varargs...)
+	ret0, _ := ret[0].(*servicequotas.GetServiceQuotaOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceQuota indicates an expected call of GetServiceQuota.
+func (mr *MockServiceQuotasAPIMockRecorder) GetServiceQuota(ctx, in any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, in}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceQuota", reflect.TypeOf((*MockServiceQuotasAPI)(nil).GetServiceQuota), varargs...)
+}
+
 // MockAgentCoreAPI is a mock of AgentCoreAPI interface.
 type MockAgentCoreAPI struct {
 	ctrl     *gomock.Controller
@@ -539,6 +830,21 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// DescribeInstanceStatus mocks base method.
+func (m *MockClient) DescribeInstanceStatus(ctx context.Context, instanceIDs []string) ([]aws0.InstanceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeInstanceStatus", ctx, instanceIDs)
+	ret0, _ := ret[0].([]aws0.InstanceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInstanceStatus indicates an expected call of DescribeInstanceStatus.
+func (mr *MockClientMockRecorder) DescribeInstanceStatus(ctx, instanceIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstanceStatus", reflect.TypeOf((*MockClient)(nil).DescribeInstanceStatus), ctx, instanceIDs)
+}
+
 // FindCLBByDNSName mocks base method.
 func (m *MockClient) FindCLBByDNSName(ctx context.Context, dnsName string) (string, []string, error) {
 	m.ctrl.T.Helper()
@@ -616,6 +922,36 @@ func (mr *MockClientMockRecorder) GetCLBInstanceHealth(ctx, lbName any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCLBInstanceHealth", reflect.TypeOf((*MockClient)(nil).GetCLBInstanceHealth), ctx, lbName)
 }
 
+// GetEC2ResourceUsage mocks base method.
+func (m *MockClient) GetEC2ResourceUsage(ctx context.Context) (aws0.EC2ResourceUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEC2ResourceUsage", ctx)
+	ret0, _ := ret[0].(aws0.EC2ResourceUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEC2ResourceUsage indicates an expected call of GetEC2ResourceUsage.
+func (mr *MockClientMockRecorder) GetEC2ResourceUsage(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEC2ResourceUsage", reflect.TypeOf((*MockClient)(nil).GetEC2ResourceUsage), ctx)
+}
+
+// GetIAMRole mocks base method.
+func (m *MockClient) GetIAMRole(ctx context.Context, roleName string) (*aws0.IAMRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIAMRole", ctx, roleName)
+	ret0, _ := ret[0].(*aws0.IAMRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIAMRole indicates an expected call of GetIAMRole.
+func (mr *MockClientMockRecorder) GetIAMRole(ctx, roleName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIAMRole", reflect.TypeOf((*MockClient)(nil).GetIAMRole), ctx, roleName)
+}
+
 // GetInstanceSecurityGroupIDs mocks base method.
 func (m *MockClient) GetInstanceSecurityGroupIDs(ctx context.Context, instanceIDs []string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -647,33 +983,33 @@ func (mr *MockClientMockRecorder) GetNLBTargetHealth(ctx, lbARN any) *gomock.Cal
 }
 
 // GetRouteTableForSubnet mocks base method.
-func (m *MockClient) GetRouteTableForSubnet(subnetID string) (types0.RouteTable, error) {
+func (m *MockClient) GetRouteTableForSubnet(ctx context.Context, subnetID string) (types0.RouteTable, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRouteTableForSubnet", subnetID)
+	ret := m.ctrl.Call(m, "GetRouteTableForSubnet", ctx, subnetID)
 	ret0, _ := ret[0].(types0.RouteTable)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRouteTableForSubnet indicates an expected call of GetRouteTableForSubnet.
-func (mr *MockClientMockRecorder) GetRouteTableForSubnet(subnetID any) *gomock.Call {
+func (mr *MockClientMockRecorder) GetRouteTableForSubnet(ctx, subnetID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouteTableForSubnet", reflect.TypeOf((*MockClient)(nil).GetRouteTableForSubnet), subnetID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRouteTableForSubnet", reflect.TypeOf((*MockClient)(nil).GetRouteTableForSubnet), ctx, subnetID)
 }
 
 // GetSecurityGroupID mocks base method.
-func (m *MockClient) GetSecurityGroupID(infraID string) (string, error) {
+func (m *MockClient) GetSecurityGroupID(ctx context.Context, infraID string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSecurityGroupID", infraID)
+	ret := m.ctrl.Call(m, "GetSecurityGroupID", ctx, infraID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSecurityGroupID indicates an expected call of GetSecurityGroupID.
-func (mr *MockClientMockRecorder) GetSecurityGroupID(infraID any) *gomock.Call {
+func (mr *MockClientMockRecorder) GetSecurityGroupID(ctx, infraID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroupID", reflect.TypeOf((*MockClient)(nil).GetSecurityGroupID), infraID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroupID", reflect.TypeOf((*MockClient)(nil).GetSecurityGroupID), ctx, infraID)
 }
 
 // GetSecurityGroupRules mocks base method.
@@ -691,19 +1027,34 @@ func (mr *MockClientMockRecorder) GetSecurityGroupRules(ctx, sgIDs any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityGroupRules", reflect.TypeOf((*MockClient)(nil).GetSecurityGroupRules), ctx, sgIDs)
 }
 
+// GetServiceQuota mocks base method.
+func (m *MockClient) GetServiceQuota(ctx context.Context, serviceCode, quotaCode string) (aws0.ServiceQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceQuota", ctx, serviceCode, quotaCode)
+	ret0, _ := ret[0].(aws0.ServiceQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceQuota indicates an expected call of GetServiceQuota.
+func (mr *MockClientMockRecorder) GetServiceQuota(ctx, serviceCode, quotaCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceQuota", reflect.TypeOf((*MockClient)(nil).GetServiceQuota), ctx, serviceCode, quotaCode)
+}
+
 // GetSubnetID mocks base method.
-func (m *MockClient) GetSubnetID(infraID string) ([]string, error) {
+func (m *MockClient) GetSubnetID(ctx context.Context, infraID string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSubnetID", infraID)
+	ret := m.ctrl.Call(m, "GetSubnetID", ctx, infraID)
 	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSubnetID indicates an expected call of GetSubnetID.
-func (mr *MockClientMockRecorder) GetSubnetID(infraID any) *gomock.Call {
+func (mr *MockClientMockRecorder) GetSubnetID(ctx, infraID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetID", reflect.TypeOf((*MockClient)(nil).GetSubnetID), infraID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubnetID", reflect.TypeOf((*MockClient)(nil).GetSubnetID), ctx, infraID)
 }
 
 // GetVpcDhcpConfiguration mocks base method.
@@ -721,6 +1072,21 @@ func (mr *MockClientMockRecorder) GetVpcDhcpConfiguration(ctx, infraID any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVpcDhcpConfiguration", reflect.TypeOf((*MockClient)(nil).GetVpcDhcpConfiguration), ctx, infraID)
 }
 
+// GetVpcNetworkResources mocks base method.
+func (m *MockClient) GetVpcNetworkResources(ctx context.Context, infraID string) (aws0.VpcNetworkResources, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVpcNetworkResources", ctx, infraID)
+	ret0, _ := ret[0].(aws0.VpcNetworkResources)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVpcNetworkResources indicates an expected call of GetVpcNetworkResources.
+func (mr *MockClientMockRecorder) GetVpcNetworkResources(ctx, infraID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVpcNetworkResources", reflect.TypeOf((*MockClient)(nil).GetVpcNetworkResources), ctx, infraID)
+}
+
 // HasResourceRecordSet mocks base method.
 func (m *MockClient) HasResourceRecordSet(ctx context.Context, hostedZoneID, recordName, recordType string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -737,61 +1103,92 @@ func (mr *MockClientMockRecorder) HasResourceRecordSet(ctx, hostedZoneID, record
 }
 
 // IsSubnetPrivate mocks base method.
-func (m *MockClient) IsSubnetPrivate(subnet string) (bool, error) {
+func (m *MockClient) IsSubnetPrivate(ctx context.Context, subnet string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsSubnetPrivate", subnet)
+	ret := m.ctrl.Call(m, "IsSubnetPrivate", ctx, subnet)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IsSubnetPrivate indicates an expected call of IsSubnetPrivate.
-func (mr *MockClientMockRecorder) IsSubnetPrivate(subnet any) *gomock.Call {
+func (mr *MockClientMockRecorder) IsSubnetPrivate(ctx, subnet any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSubnetPrivate", reflect.TypeOf((*MockClient)(nil).IsSubnetPrivate), subnet)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSubnetPrivate", reflect.TypeOf((*MockClient)(nil).IsSubnetPrivate), ctx, subnet)
 }
 
 // ListNonRunningInstances mocks base method.
-func (m *MockClient) ListNonRunningInstances(infraID string) ([]types0.Instance, error) {
+func (m *MockClient) ListNonRunningInstances(ctx context.Context, infraID string) ([]types0.Instance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListNonRunningInstances", infraID)
+	ret := m.ctrl.Call(m, "ListNonRunningInstances", ctx, infraID)
 	ret0, _ := ret[0].([]types0.Instance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListNonRunningInstances indicates an expected call of ListNonRunningInstances.
-func (mr *MockClientMockRecorder) ListNonRunningInstances(infraID any) *gomock.Call {
+func (mr *MockClientMockRecorder) ListNonRunningInstances(ctx, infraID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNonRunningInstances", reflect.TypeOf((*MockClient)(nil).ListNonRunningInstances), infraID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNonRunningInstances", reflect.TypeOf((*MockClient)(nil).ListNonRunningInstances), ctx, infraID)
 }
 
 // ListRunningInstances mocks base method.
-func (m *MockClient) ListRunningInstances(infraID string) ([]types0.Instance, error) {
+func (m *MockClient) ListRunningInstances(ctx context.Context, infraID string) ([]types0.Instance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRunningInstances", infraID)
+	ret := m.ctrl.Call(m, "ListRunningInstances", ctx, infraID)
 	ret0, _ := ret[0].([]types0.Instance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListRunningInstances indicates an expected call of ListRunningInstances.
-func (mr *MockClientMockRecorder) ListRunningInstances(infraID any) *gomock.Call {
+func (mr *MockClientMockRecorder) ListRunningInstances(ctx, infraID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunningInstances", reflect.TypeOf((*MockClient)(nil).ListRunningInstances), ctx, infraID)
+}
+
+// LookupResourceEvents mocks base method.
+func (m *MockClient) LookupResourceEvents(ctx context.Context, resourceIDs, eventNames []string, since time.Time) ([]types.Event, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupResourceEvents", ctx, resourceIDs, eventNames, since)
+	ret0, _ := ret[0].([]types.Event)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LookupResourceEvents indicates an expected call of LookupResourceEvents.
+func (mr *MockClientMockRecorder) LookupResourceEvents(ctx, resourceIDs, eventNames, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupResourceEvents", reflect.TypeOf((*MockClient)(nil).LookupResourceEvents), ctx, resourceIDs, eventNames, since)
+}
+
+// OIDCProviderExists mocks base method.
+func (m *MockClient) OIDCProviderExists(ctx context.Context, providerARN string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCProviderExists", ctx, providerARN)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCProviderExists indicates an expected call of OIDCProviderExists.
+func (mr *MockClientMockRecorder) OIDCProviderExists(ctx, providerARN any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRunningInstances", reflect.TypeOf((*MockClient)(nil).ListRunningInstances), infraID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCProviderExists", reflect.TypeOf((*MockClient)(nil).OIDCProviderExists), ctx, providerARN)
 }
 
 // PollInstanceStopEventsFor mocks base method.
-func (m *MockClient) PollInstanceStopEventsFor(instances []types0.Instance, retryTimes int) ([]types.Event, error) {
+func (m *MockClient) PollInstanceStopEventsFor(ctx context.Context, instances []types0.Instance, retryTimes int) ([]types.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PollInstanceStopEventsFor", instances, retryTimes)
+	ret := m.ctrl.Call(m, "PollInstanceStopEventsFor", ctx, instances, retryTimes)
 	ret0, _ := ret[0].([]types.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PollInstanceStopEventsFor indicates an expected call of PollInstanceStopEventsFor.
-func (mr *MockClientMockRecorder) PollInstanceStopEventsFor(instances, retryTimes any) *gomock.Call {
+func (mr *MockClientMockRecorder) PollInstanceStopEventsFor(ctx, instances, retryTimes any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollInstanceStopEventsFor", reflect.TypeOf((*MockClient)(nil).PollInstanceStopEventsFor), instances, retryTimes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollInstanceStopEventsFor", reflect.TypeOf((*MockClient)(nil).PollInstanceStopEventsFor), ctx, instances, retryTimes)
 }
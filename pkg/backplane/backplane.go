@@ -13,12 +13,17 @@ import (
 	bpapi "github.com/openshift/backplane-api/pkg/client"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
 )
 
 // Client provides methods for interacting with the backplane API
 type Client interface {
 	// CreateReport creates a new cluster report
 	CreateReport(ctx context.Context, clusterId string, summary string, reportData string) (*bpapi.Report, error)
+	// ListReports lists the last reports of a cluster, without their data
+	ListReports(ctx context.Context, clusterId string, last int) (*bpapi.ListReports, error)
+	// GetReport returns a report of a cluster with its data decoded
+	GetReport(ctx context.Context, clusterId string, reportId string) (*bpapi.Report, error)
 	// GetRestConfig creates a remediation and returns a rest.Config for connecting to the cluster's API server through the backplane proxy
 	GetRestConfig(ctx context.Context, clusterId string, remediationName string, isManagementCluster bool) (*RestConfig, error)
 }
@@ -40,18 +45,27 @@ type RestConfig struct {
 
 // ClientImpl implements the Client interface
 type ClientImpl struct {
-	bpClient  *bpapi.ClientWithResponses
-	baseURL   string
-	ocmClient ocm.Client
-	proxyURL  string
+	bpClient                *bpapi.ClientWithResponses
+	baseURL                 string
+	ocmClient               ocm.Client
+	proxyURL                string
+	clusterTransportWrapper TransportWrapper
 }
 
 type Config struct {
 	BaseURL   string
 	OcmClient ocm.Client
 	ProxyURL  string
+	// TransportWrapper optionally intercepts requests to the backplane API
+	TransportWrapper TransportWrapper
+	// ClusterTransportWrapper optionally intercepts requests to cluster API servers
+	// made through the rest configs returned by GetRestConfig
+	ClusterTransportWrapper TransportWrapper
 }
 
+// TransportWrapper wraps the transport of an HTTP client, e.g. to record or replay its traffic
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
 // NewClient creates a new backplane client
 func NewClient(config Config) (Client, error) {
 	if config.BaseURL == "" {
@@ -65,6 +79,9 @@ func NewClient(config Config) (Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create http client: %w", err)
 	}
+	if config.TransportWrapper != nil {
+		httpClient.Transport = config.TransportWrapper(httpClient.Transport)
+	}
 
 	// Create the backplane API client with authentication and a custom httpClient configured with to use
 	apiClient, err := bpapi.NewClientWithResponses(
@@ -86,10 +103,11 @@ func NewClient(config Config) (Client, error) {
 	}
 
 	return &ClientImpl{
-		bpClient:  apiClient,
-		baseURL:   config.BaseURL,
-		ocmClient: config.OcmClient,
-		proxyURL:  config.ProxyURL,
+		bpClient:                apiClient,
+		baseURL:                 config.BaseURL,
+		ocmClient:               config.OcmClient,
+		proxyURL:                config.ProxyURL,
+		clusterTransportWrapper: config.ClusterTransportWrapper,
 	}, nil
 }
 
@@ -118,6 +136,49 @@ func (c *ClientImpl) CreateReport(ctx context.Context, clusterId string, summary
 	return resp.JSON201, nil
 }
 
+// ListReports lists the last reports of a cluster using the backplane API
+func (c *ClientImpl) ListReports(ctx context.Context, clusterId string, last int) (*bpapi.ListReports, error) {
+	if clusterId == "" {
+		return nil, fmt.Errorf("clusterId is required")
+	}
+
+	resp, err := c.bpClient.GetReportsByClusterWithResponse(ctx, clusterId, &bpapi.GetReportsByClusterParams{Last: &last})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected status code %d when listing reports: %s", resp.StatusCode(), resp.Body)
+	}
+
+	return resp.JSON200, nil
+}
+
+// GetReport returns a report of a cluster using the backplane API, with its data decoded
+func (c *ClientImpl) GetReport(ctx context.Context, clusterId string, reportId string) (*bpapi.Report, error) {
+	if clusterId == "" || reportId == "" {
+		return nil, fmt.Errorf("clusterId and reportId are required")
+	}
+
+	resp, err := c.bpClient.GetReportByIdWithResponse(ctx, clusterId, reportId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK || resp.JSON200 == nil {
+		return nil, fmt.Errorf("unexpected status code %d when getting report %s: %s", resp.StatusCode(), reportId, resp.Body)
+	}
+
+	report := *resp.JSON200
+	decoded, err := base64.StdEncoding.DecodeString(report.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data of report %s: %w", reportId, err)
+	}
+	report.Data = string(decoded)
+
+	return &report, nil
+}
+
 func (c *ClientImpl) GetRestConfig(ctx context.Context, clusterId string, remediationName string, isManagementCluster bool) (*RestConfig, error) {
 	createRemediationParams := bpapi.CreateRemediationParams{
 		RemediationName: remediationName,
@@ -166,6 +227,9 @@ func (c *ClientImpl) GetRestConfig(ctx context.Context, clusterId string, remedi
 		}
 		cfg.Proxy = http.ProxyURL(proxyURLParsed)
 	}
+	if c.clusterTransportWrapper != nil {
+		cfg.WrapTransport = transport.WrapperFunc(c.clusterTransportWrapper)
+	}
 
 	deleteRemediationParams := bpapi.DeleteRemediationParams{
 		RemediationInstanceId: response.JSON200.RemediationInstanceId,
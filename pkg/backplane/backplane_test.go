@@ -3,9 +3,12 @@ package backplane
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	bpapi "github.com/openshift/backplane-api/pkg/client"
 	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -153,6 +156,42 @@ func TestCreateReport_DataEncoding(t *testing.T) {
 	assert.Equal(t, "VGVzdCBSZXBvcnQgRGF0YSB3aXRoIHNwZWNpYWwgY2hhcnM6ICFAIyQlXiYqKCk=", expectedEncoded)
 }
 
+func TestClientImpl_ListAndGetReports(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/backplane/cluster/test-cluster-123/reports":
+			assert.Equal(t, "5", r.URL.Query().Get("last"))
+			_, _ = w.Write([]byte(`{"cluster_id":"test-cluster-123","reports":[{"report_id":"r1","summary":"Test Summary"}]}`))
+		case "/backplane/cluster/test-cluster-123/reports/r1":
+			_, _ = fmt.Fprintf(w, `{"report_id":"r1","summary":"Test Summary","data":%q}`, base64.StdEncoding.EncodeToString([]byte("Test Report Data")))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	apiClient, err := bpapi.NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	client := &ClientImpl{bpClient: apiClient}
+
+	reports, err := client.ListReports(context.Background(), "test-cluster-123", 5)
+	require.NoError(t, err)
+	require.Len(t, reports.Reports, 1)
+	assert.Equal(t, "r1", *reports.Reports[0].ReportId)
+
+	report, err := client.GetReport(context.Background(), "test-cluster-123", "r1")
+	require.NoError(t, err)
+	assert.Equal(t, "Test Report Data", report.Data, "The report data should be decoded")
+
+	_, err = client.GetReport(context.Background(), "test-cluster-123", "missing")
+	assert.ErrorContains(t, err, "unexpected status code 404")
+
+	_, err = client.ListReports(context.Background(), "", 5)
+	assert.ErrorContains(t, err, "clusterId is required")
+}
+
 func TestHttpDoerWithProxy(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -2,6 +2,7 @@ package backplanemock
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	bpapi "github.com/openshift/backplane-api/pkg/client"
@@ -21,6 +22,14 @@ func (m *MockClient) CreateReport(_ context.Context, _ string, summary string, r
 	}, nil
 }
 
+func (m *MockClient) ListReports(_ context.Context, clusterId string, _ int) (*bpapi.ListReports, error) {
+	return &bpapi.ListReports{ClusterId: clusterId}, nil
+}
+
+func (m *MockClient) GetReport(_ context.Context, _ string, reportId string) (*bpapi.Report, error) {
+	return nil, fmt.Errorf("report %s not found", reportId)
+}
+
 func (m *MockClient) GetRestConfig(_ context.Context, _ string, _ string, _ bool) (*backplane.RestConfig, error) {
 	return &backplane.RestConfig{
 		Cleaner: backplane.CleanerFunc(func() error { return nil }),
@@ -0,0 +1,159 @@
+// Package clusterid extracts cluster IDs from alert bodies using declarative rules
+// and resolves external cluster IDs to OCM internal IDs.
+package clusterid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+)
+
+// Alert source names rules can be scoped to. They match the names in pkg/source.
+const (
+	SourcePagerDuty    = config.ClusterIDSourcePagerDuty
+	SourceAlertmanager = config.ClusterIDSourceAlertmanager
+	SourceGeneric      = config.ClusterIDSourceGeneric
+)
+
+// DefaultRules are evaluated after the configured rules. They cover the alert shapes
+// CAD has always supported.
+var DefaultRules = []config.ClusterIDRule{
+	// cluster_id directly contained in custom details
+	{Name: "pagerduty-details-cluster-id", Source: SourcePagerDuty, Path: "{.details.cluster_id}"},
+	// [OSD-18006] old format, cluster_id contained in the notes YAML of the custom details
+	{Name: "pagerduty-details-notes", Source: SourcePagerDuty, Path: "{.details.notes}", Regex: `(?m)^\s*cluster_id:\s*["']?(?P<id>[^"'\s]+)`},
+	// HCPNodepoolUpgradeDelay sets neither notes nor cluster_id, the ID is part of the free-text firing field.
+	// (?:^|\s) prevents matches inside compound names such as "hosted_cluster_id".
+	{Name: "pagerduty-details-firing", Source: SourcePagerDuty, Path: "{.details.firing}", Regex: `(?:^|\s)cluster_id = (?P<id>\S+)`},
+	// "_id" is the label set on alerts forwarded from managed clusters
+	{Name: "alertmanager-label-id", Source: SourceAlertmanager, Path: "{.labels._id}"},
+	{Name: "alertmanager-label-cluster-id", Source: SourceAlertmanager, Path: "{.labels.cluster_id}"},
+	{Name: "generic-cluster-id", Source: SourceGeneric, Path: "{.cluster_id}"},
+}
+
+// internalIDRe matches OCM internal cluster IDs.
+var internalIDRe = regexp.MustCompile(`^[a-z0-9]{32}$`)
+
+type rule struct {
+	config.ClusterIDRule
+	regex *regexp.Regexp
+}
+
+// Extractor evaluates extraction rules in order. It is safe for concurrent use.
+type Extractor struct {
+	rules []rule
+}
+
+// NewExtractor creates an extractor evaluating rules followed by DefaultRules.
+func NewExtractor(rules []config.ClusterIDRule) (*Extractor, error) {
+	all := make([]config.ClusterIDRule, 0, len(rules)+len(DefaultRules))
+	all = append(all, rules...)
+	all = append(all, DefaultRules...)
+
+	e := &Extractor{rules: make([]rule, 0, len(all))}
+	for _, r := range all {
+		if _, err := r.ParsePath(); err != nil {
+			return nil, fmt.Errorf("rule %q: invalid path: %w", r.Name, err)
+		}
+		re, err := r.CompileRegex()
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: invalid regex: %w", r.Name, err)
+		}
+		e.rules = append(e.rules, rule{ClusterIDRule: r, regex: re})
+	}
+	return e, nil
+}
+
+// Default returns an extractor evaluating only DefaultRules.
+func Default() *Extractor {
+	e, err := NewExtractor(nil)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default cluster ID rules: %v", err))
+	}
+	return e
+}
+
+// Extract returns the cluster ID found by the first matching rule for the given source,
+// along with the name of that rule.
+func (e *Extractor) Extract(source string, body any) (id string, ruleName string, err error) {
+	var errs []error
+	for _, r := range e.rules {
+		if r.Source != "" && r.Source != source {
+			continue
+		}
+		id, err := r.extract(body)
+		if err != nil {
+			logging.Debugf("cluster ID rule %q did not match (continuing): %v", r.Name, err)
+			errs = append(errs, fmt.Errorf("rule %q: %w", r.Name, err))
+			continue
+		}
+		return id, r.Name, nil
+	}
+	if len(errs) == 0 {
+		return "", "", fmt.Errorf("no cluster ID rules for source %q", source)
+	}
+	return "", "", errors.Join(errs...)
+}
+
+// extract evaluates the rule against body. The path is parsed on every call, as a JSONPath keeps
+// the state of range templates while executing and can't be shared between concurrent requests.
+func (r *rule) extract(body any) (string, error) {
+	path, err := r.ParsePath()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := path.Execute(&buf, body); err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(buf.String())
+	if value == "" {
+		return "", errors.New("path not found or empty")
+	}
+	if r.regex == nil {
+		return value, nil
+	}
+
+	match := r.regex.FindStringSubmatch(value)
+	if match == nil {
+		return "", errors.New("regex did not match")
+	}
+	group := r.regex.SubexpIndex("id")
+	if group < 0 {
+		group = 1
+	}
+	if match[group] == "" {
+		return "", errors.New("regex capture group is empty")
+	}
+	return match[group], nil
+}
+
+// IsInternalID reports whether id has the format of an OCM internal cluster ID.
+func IsInternalID(id string) bool {
+	return internalIDRe.MatchString(id)
+}
+
+// Resolver looks up clusters in OCM. It is implemented by ocm.Client.
+type Resolver interface {
+	GetClusterInfo(identifier string) (*cmv1.Cluster, error)
+}
+
+// Resolve returns the OCM internal ID for id. Internal IDs are returned as is, any other
+// identifier (external ID, display name) is looked up in OCM.
+func Resolve(resolver Resolver, id string) (string, error) {
+	if IsInternalID(id) {
+		return id, nil
+	}
+	cluster, err := resolver.GetClusterInfo(id)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve cluster %q: %w", id, err)
+	}
+	logging.Infof("Resolved cluster %q to internal ID %s", id, cluster.ID())
+	return cluster.ID(), nil
+}
@@ -0,0 +1,182 @@
+package clusterid
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+)
+
+func decode(t *testing.T, body string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(body), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestExtractDefaultRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		body     string
+		wantID   string
+		wantRule string
+		wantErr  bool
+	}{
+		{
+			name:     "details field",
+			source:   SourcePagerDuty,
+			body:     `{"details":{"cluster_id":"654321"}}`,
+			wantID:   "654321",
+			wantRule: "pagerduty-details-cluster-id",
+		},
+		{
+			name:     "notes yaml",
+			source:   SourcePagerDuty,
+			body:     `{"details":{"notes":"cluster_id: 654321\nnamespace: foo"}}`,
+			wantID:   "654321",
+			wantRule: "pagerduty-details-notes",
+		},
+		{
+			name:     "quoted notes yaml",
+			source:   SourcePagerDuty,
+			body:     `{"details":{"notes":"cluster_id: \"654321\""}}`,
+			wantID:   "654321",
+			wantRule: "pagerduty-details-notes",
+		},
+		{
+			name:     "multi-line firing text",
+			source:   SourcePagerDuty,
+			body:     `{"details":{"firing":"alertname = X\n - cluster_id = 654321\n - nodepool = workers"}}`,
+			wantID:   "654321",
+			wantRule: "pagerduty-details-firing",
+		},
+		{
+			name:    "hosted_cluster_id is not a cluster_id",
+			source:  SourcePagerDuty,
+			body:    `{"details":{"firing":"hosted_cluster_id = abc123"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "details of the wrong type",
+			source:  SourcePagerDuty,
+			body:    `{"details":"bad details"}`,
+			wantErr: true,
+		},
+		{
+			name:     "alertmanager label",
+			source:   SourceAlertmanager,
+			body:     `{"labels":{"cluster_id":"654321"}}`,
+			wantID:   "654321",
+			wantRule: "alertmanager-label-cluster-id",
+		},
+		{
+			name:    "rules are scoped to their source",
+			source:  SourceAlertmanager,
+			body:    `{"details":{"cluster_id":"654321"}}`,
+			wantErr: true,
+		},
+	}
+
+	extractor := Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, rule, err := extractor.Extract(tt.source, decode(t, tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID || rule != tt.wantRule {
+				t.Errorf("Extract() = (%q, %q), want (%q, %q)", id, rule, tt.wantID, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestExtractConfiguredRulesFirst(t *testing.T) {
+	extractor, err := NewExtractor([]config.ClusterIDRule{
+		{Name: "summary", Path: ".details.summary", Regex: `cluster (\S+) is down`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := decode(t, `{"details":{"summary":"cluster abc is down","cluster_id":"654321"}}`)
+	id, rule, err := extractor.Extract(SourcePagerDuty, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "abc" || rule != "summary" {
+		t.Errorf("Extract() = (%q, %q), want configured rule to win", id, rule)
+	}
+
+	// Falls back to the built-in rules
+	id, rule, err = extractor.Extract(SourcePagerDuty, decode(t, `{"details":{"cluster_id":"654321"}}`))
+	if err != nil || id != "654321" || rule != "pagerduty-details-cluster-id" {
+		t.Errorf("Extract() = (%q, %q, %v)", id, rule, err)
+	}
+}
+
+func TestExtractConcurrentRangeRules(t *testing.T) {
+	extractor, err := NewExtractor([]config.ClusterIDRule{
+		{Name: "tags", Path: `{range .details.tags[*]}{.key}={.value} {end}`, Regex: `cluster=(\S+)`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		want := fmt.Sprintf("cluster-%d", i)
+		body := decode(t, fmt.Sprintf(`{"details":{"tags":[{"key":"env","value":"prod"},{"key":"cluster","value":%q}]}}`, want))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				id, _, err := extractor.Extract(SourcePagerDuty, body)
+				if err != nil || id != want {
+					t.Errorf("Extract() = (%q, %v), want %q", id, err, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type fakeResolver struct {
+	cluster *cmv1.Cluster
+	err     error
+	calls   int
+}
+
+func (f *fakeResolver) GetClusterInfo(_ string) (*cmv1.Cluster, error) {
+	f.calls++
+	return f.cluster, f.err
+}
+
+func TestResolve(t *testing.T) {
+	internalID := "2abc3def4ghi5jkl6mno7pqr8stu9vwx"
+	cluster, err := cmv1.NewCluster().ID(internalID).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &fakeResolver{cluster: cluster}
+	if id, err := Resolve(r, internalID); err != nil || id != internalID || r.calls != 0 {
+		t.Errorf("internal ID: Resolve() = (%q, %v), calls = %d", id, err, r.calls)
+	}
+	if id, err := Resolve(r, "4b3f1c7e-1234-4d6a-9c2b-1f2e3d4c5b6a"); err != nil || id != internalID || r.calls != 1 {
+		t.Errorf("external ID: Resolve() = (%q, %v), calls = %d", id, err, r.calls)
+	}
+
+	r = &fakeResolver{err: errors.New("not found")}
+	if _, err := Resolve(r, "my-cluster"); err == nil {
+		t.Error("expected error when the cluster can't be resolved")
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Actor policy verdicts
+const (
+	ActorVerdictAllow = "allow"
+	ActorVerdictDeny  = "deny"
+)
+
+var validActorVerdicts = []string{ActorVerdictAllow, ActorVerdictDeny}
+
+// ActorPolicy classifies the cloud principals that acted on cluster resources, e.g. stopped
+// instances, as authorized (Red Hat automation or SRE) or not (the customer).
+// Rules are evaluated in order; the first matching rule wins. Actors matching no rule are not authorized.
+type ActorPolicy struct {
+	Rules []ActorRule `yaml:"rules"`
+}
+
+// ActorRule matches actors by their principal and the account type of the cluster.
+// Patterns are unanchored regular expressions, a list of patterns matches if any pattern does.
+// A rule matches if all of its set fields match.
+type ActorRule struct {
+	Name string `yaml:"name"`
+	// UserNames match the user name of the event, e.g. the IAM user or the role session name
+	UserNames []string `yaml:"user_names,omitempty"`
+	// IssuerNames match the name of the role that issued the session
+	IssuerNames []string `yaml:"issuer_names,omitempty"`
+	// ARNs match the ARN of the principal or of the role that issued the session
+	ARNs []string `yaml:"arns,omitempty"`
+	// CCS and STS restrict the rule to clusters of the account type, unset matches both
+	CCS     *bool  `yaml:"ccs,omitempty"`
+	STS     *bool  `yaml:"sts,omitempty"`
+	Verdict string `yaml:"verdict"`
+
+	// userNames, issuerNames and arns are the compiled patterns, set by Validate
+	userNames   []*regexp.Regexp
+	issuerNames []*regexp.Regexp
+	arns        []*regexp.Regexp
+}
+
+// Actor is a principal from a cloud audit log event, along with the account type of the cluster it acted on
+type Actor struct {
+	UserName   string
+	IssuerName string
+	ARN        string
+	IssuerARN  string
+	CCS        bool
+	STS        bool
+}
+
+// ActorClassification is the result of classifying an actor with an ActorPolicy
+type ActorClassification struct {
+	Allowed bool
+	// Rule is the name of the rule that matched, empty if none did
+	Rule string
+	// Reason explains the classification, e.g. for PagerDuty notes
+	Reason string
+}
+
+// Classify returns the verdict of the first rule matching actor. The patterns of the rules are compiled
+// by Validate, so the policy must have been loaded from the config or created with NewActorPolicy.
+func (p *ActorPolicy) Classify(actor Actor) ActorClassification {
+	if p != nil {
+		for i := range p.Rules {
+			if reason, matched := p.Rules[i].matches(actor); matched {
+				return ActorClassification{
+					Allowed: p.Rules[i].Verdict == ActorVerdictAllow,
+					Rule:    p.Rules[i].Name,
+					Reason:  fmt.Sprintf("rule %q (%s): %s", p.Rules[i].Name, p.Rules[i].Verdict, reason),
+				}
+			}
+		}
+	}
+	return ActorClassification{
+		Reason: fmt.Sprintf("no rule matched user %q (issuer %q), unknown actors are not authorized", actor.UserName, actor.IssuerName),
+	}
+}
+
+// matches reports whether the rule matches actor, and if so which patterns matched
+func (r *ActorRule) matches(actor Actor) (string, bool) {
+	if r.CCS != nil && *r.CCS != actor.CCS {
+		return "", false
+	}
+	if r.STS != nil && *r.STS != actor.STS {
+		return "", false
+	}
+
+	var reasons []string
+	for _, field := range []struct {
+		name     string
+		patterns []*regexp.Regexp
+		values   []string
+	}{
+		{"user name", r.userNames, []string{actor.UserName}},
+		{"issuer name", r.issuerNames, []string{actor.IssuerName}},
+		{"ARN", r.arns, []string{actor.ARN, actor.IssuerARN}},
+	} {
+		if len(field.patterns) == 0 {
+			continue
+		}
+		value, pattern, ok := matchAny(field.patterns, field.values)
+		if !ok {
+			return "", false
+		}
+		reasons = append(reasons, fmt.Sprintf("%s %q matches %q", field.name, value, pattern))
+	}
+	// A rule without compiled patterns wasn't validated, it must not classify every actor
+	if len(reasons) == 0 {
+		return "", false
+	}
+	return strings.Join(reasons, ", "), true
+}
+
+// matchAny returns the first non-empty value matching one of the patterns, and that pattern
+func matchAny(patterns []*regexp.Regexp, values []string) (string, string, bool) {
+	for _, re := range patterns {
+		for _, value := range values {
+			if value != "" && re.MatchString(value) {
+				return value, re.String(), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// NewActorPolicy creates a policy from rules that are built in code rather than loaded from the config,
+// and validates it, which compiles the patterns of its rules.
+func NewActorPolicy(rules ...ActorRule) (*ActorPolicy, error) {
+	policy := &ActorPolicy{Rules: slices.Clone(rules)}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate checks that every rule is named, has a valid verdict and matches on valid principal patterns,
+// and compiles the patterns. Rules without patterns would classify every actor and are rejected.
+func (p *ActorPolicy) Validate() error {
+	seen := make(map[string]bool)
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("rules[%d]: name must not be empty", i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("rules[%d]: duplicate name %q", i, rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if !slices.Contains(validActorVerdicts, rule.Verdict) {
+			return fmt.Errorf("rules[%d] (name %q): unknown verdict %q; valid verdicts: %v", i, rule.Name, rule.Verdict, validActorVerdicts)
+		}
+		if len(rule.UserNames)+len(rule.IssuerNames)+len(rule.ARNs) == 0 {
+			return fmt.Errorf("rules[%d] (name %q): at least one of user_names, issuer_names or arns must be set", i, rule.Name)
+		}
+		var err error
+		if rule.userNames, err = compilePatterns(rule.UserNames); err != nil {
+			return fmt.Errorf("rules[%d] (name %q): %w", i, rule.Name, err)
+		}
+		if rule.issuerNames, err = compilePatterns(rule.IssuerNames); err != nil {
+			return fmt.Errorf("rules[%d] (name %q): %w", i, rule.Name, err)
+		}
+		if rule.arns, err = compilePatterns(rule.ARNs); err != nil {
+			return fmt.Errorf("rules[%d] (name %q): %w", i, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// compilePatterns compiles regular expressions of the config
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// GetActorPolicy returns the configured actor policy, or nil if not set.
+func (c *Config) GetActorPolicy() *ActorPolicy {
+	if c == nil {
+		return nil
+	}
+	return c.ActorPolicy
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestActorPolicyClassify(t *testing.T) {
+	policy, err := NewActorPolicy([]ActorRule{
+		{Name: "customer-automation", UserNames: []string{"^ci-"}, Verdict: ActorVerdictDeny},
+		{Name: "installer", IssuerNames: []string{"-Installer-Role$"}, Verdict: ActorVerdictAllow},
+		{Name: "sre-account", ARNs: []string{`^arn:aws:iam::111111111111:`}, Verdict: ActorVerdictAllow},
+		{Name: "org-access", IssuerNames: []string{"OrganizationAccountAccessRole"}, CCS: new(false), Verdict: ActorVerdictAllow},
+		{Name: "support-on-sts", UserNames: []string{"support"}, IssuerNames: []string{"-Support-Role"}, STS: new(true), Verdict: ActorVerdictAllow},
+	}...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		actor       Actor
+		wantAllowed bool
+		wantRule    string
+		wantReason  string
+	}{
+		{
+			name:       "first matching rule wins",
+			actor:      Actor{UserName: "ci-bot", IssuerName: "ManagedOpenShift-Installer-Role"},
+			wantRule:   "customer-automation",
+			wantReason: `rule "customer-automation" (deny): user name "ci-bot" matches "^ci-"`,
+		},
+		{
+			name:        "issuer name",
+			actor:       Actor{UserName: "12345", IssuerName: "ManagedOpenShift-Installer-Role"},
+			wantAllowed: true,
+			wantRule:    "installer",
+			wantReason:  `rule "installer" (allow): issuer name "ManagedOpenShift-Installer-Role" matches "-Installer-Role$"`,
+		},
+		{
+			name:        "issuer ARN",
+			actor:       Actor{UserName: "12345", ARN: "arn:aws:sts::222222222222:assumed-role/x/y", IssuerARN: "arn:aws:iam::111111111111:role/x"},
+			wantAllowed: true,
+			wantRule:    "sre-account",
+		},
+		{
+			name:        "account type matches",
+			actor:       Actor{UserName: "12345", IssuerName: "OrganizationAccountAccessRole"},
+			wantAllowed: true,
+			wantRule:    "org-access",
+		},
+		{
+			name:       "account type doesn't match",
+			actor:      Actor{UserName: "12345", IssuerName: "OrganizationAccountAccessRole", CCS: true},
+			wantReason: `no rule matched user "12345" (issuer "OrganizationAccountAccessRole"), unknown actors are not authorized`,
+		},
+		{
+			name:        "all fields of a rule must match",
+			actor:       Actor{UserName: "support-session", IssuerName: "ManagedOpenShift-Support-Role", STS: true},
+			wantAllowed: true,
+			wantRule:    "support-on-sts",
+			wantReason:  `rule "support-on-sts" (allow): user name "support-session" matches "support", issuer name "ManagedOpenShift-Support-Role" matches "-Support-Role"`,
+		},
+		{
+			name:  "one field of a rule doesn't match",
+			actor: Actor{UserName: "customer", IssuerName: "ManagedOpenShift-Support-Role", STS: true},
+		},
+		{
+			name:  "empty values don't match",
+			actor: Actor{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Classify(tt.actor)
+			if got.Allowed != tt.wantAllowed {
+				t.Errorf("expected allowed %v, got %v", tt.wantAllowed, got.Allowed)
+			}
+			if got.Rule != tt.wantRule {
+				t.Errorf("expected rule %q, got %q", tt.wantRule, got.Rule)
+			}
+			if tt.wantReason != "" && got.Reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, got.Reason)
+			}
+		})
+	}
+}
+
+func TestActorPolicyClassifyNil(t *testing.T) {
+	var policy *ActorPolicy
+	got := policy.Classify(Actor{UserName: "someone"})
+	if got.Allowed || got.Rule != "" {
+		t.Errorf("expected unknown actors not to be allowed, got %+v", got)
+	}
+}
+
+func TestActorPolicyClassifyUnvalidated(t *testing.T) {
+	policy := &ActorPolicy{Rules: []ActorRule{{Name: "all", UserNames: []string{"."}, Verdict: ActorVerdictAllow}}}
+	if got := policy.Classify(Actor{UserName: "someone"}); got.Allowed || got.Rule != "" {
+		t.Errorf("expected rules without compiled patterns not to match, got %+v", got)
+	}
+}
+
+func TestNewActorPolicyInvalid(t *testing.T) {
+	if _, err := NewActorPolicy(ActorRule{Name: "a", UserNames: []string{"("}, Verdict: ActorVerdictAllow}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestParseConfigActorPolicy(t *testing.T) {
+	const alerts = `
+alerts:
+  - alert_title: "has gone missing"
+    investigations:
+      - chgm
+`
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "valid policy",
+			policy: `
+actor_policy:
+  rules:
+    - name: installer
+      issuer_names: ["-Installer-Role$"]
+      sts: true
+      verdict: allow
+    - name: customer-org-access
+      issuer_names: ["OrganizationAccountAccessRole"]
+      ccs: true
+      verdict: deny
+`,
+		},
+		{
+			name: "missing name",
+			policy: `
+actor_policy:
+  rules:
+    - user_names: ["x"]
+      verdict: allow
+`,
+			wantErr: "actor_policy: rules[0]: name must not be empty",
+		},
+		{
+			name: "duplicate name",
+			policy: `
+actor_policy:
+  rules:
+    - name: a
+      user_names: ["x"]
+      verdict: allow
+    - name: a
+      user_names: ["y"]
+      verdict: deny
+`,
+			wantErr: `rules[1]: duplicate name "a"`,
+		},
+		{
+			name: "unknown verdict",
+			policy: `
+actor_policy:
+  rules:
+    - name: a
+      user_names: ["x"]
+      verdict: maybe
+`,
+			wantErr: `unknown verdict "maybe"`,
+		},
+		{
+			name: "no patterns",
+			policy: `
+actor_policy:
+  rules:
+    - name: all-non-ccs
+      ccs: false
+      verdict: allow
+`,
+			wantErr: "at least one of user_names, issuer_names or arns must be set",
+		},
+		{
+			name: "invalid regex",
+			policy: `
+actor_policy:
+  rules:
+    - name: a
+      arns: ["arn:aws:iam::(123"]
+      verdict: allow
+`,
+			wantErr: `invalid regex "arn:aws:iam::(123"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(tt.policy+alerts), testInvestigations)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			policy := cfg.GetActorPolicy()
+			if policy == nil || len(policy.Rules) != 2 {
+				t.Fatalf("expected 2 rules, got %+v", policy)
+			}
+			if policy.Rules[0].STS == nil || !*policy.Rules[0].STS || policy.Rules[0].CCS != nil {
+				t.Errorf("expected the first rule to be restricted to STS clusters, got %+v", policy.Rules[0])
+			}
+			if len(policy.Rules[0].userNames)+len(policy.Rules[0].issuerNames)+len(policy.Rules[0].arns) == 0 {
+				t.Errorf("expected the patterns of the loaded rules to be compiled")
+			}
+		})
+	}
+}
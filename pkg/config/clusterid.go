@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// Alert sources a cluster ID rule can be restricted to. They match the names in pkg/source.
+const (
+	ClusterIDSourcePagerDuty    = "pagerduty"
+	ClusterIDSourceAlertmanager = "alertmanager"
+	ClusterIDSourceGeneric      = "generic"
+)
+
+var validClusterIDSources = []string{ClusterIDSourcePagerDuty, ClusterIDSourceAlertmanager, ClusterIDSourceGeneric}
+
+// ClusterIDRule extracts the cluster ID from an alert body. Rules are evaluated in order
+// before the built-in rules; the first rule yielding a non-empty value wins.
+type ClusterIDRule struct {
+	Name string `yaml:"name"`
+	// Source restricts the rule to one alert source (pagerduty, alertmanager, generic).
+	// Rules without a source apply to all sources.
+	Source string `yaml:"source,omitempty"`
+	// Path is a kubectl-style JSONPath into the alert body, e.g. "{.details.cluster_id}".
+	Path string `yaml:"path"`
+	// Regex is optionally applied to the value at Path. The capture group named "id",
+	// or else the first capture group, is the cluster ID.
+	Regex string `yaml:"regex,omitempty"`
+}
+
+// ParsePath parses the rule's JSONPath. Paths without braces are wrapped, so
+// ".details.cluster_id" and "{.details.cluster_id}" are equivalent.
+func (r *ClusterIDRule) ParsePath() (*jsonpath.JSONPath, error) {
+	path := strings.TrimSpace(r.Path)
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	jp := jsonpath.New(r.Name).AllowMissingKeys(true)
+	if err := jp.Parse(path); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+// CompileRegex compiles the rule's regex, returning nil if the rule has none.
+func (r *ClusterIDRule) CompileRegex() (*regexp.Regexp, error) {
+	if r.Regex == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return nil, err
+	}
+	if re.NumSubexp() == 0 {
+		return nil, fmt.Errorf("regex %q has no capture group", r.Regex)
+	}
+	return re, nil
+}
+
+// GetClusterIDRules returns the configured cluster ID extraction rules.
+// Safe to call on a nil config.
+func (c *Config) GetClusterIDRules() []ClusterIDRule {
+	if c == nil {
+		return nil
+	}
+	return c.ClusterIDExtraction
+}
+
+// validateClusterIDExtraction checks that every rule is named, restricted to a known source if any,
+// and has a valid path and regex.
+func (c *Config) validateClusterIDExtraction() error {
+	seen := make(map[string]bool)
+	for i, rule := range c.ClusterIDExtraction {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("cluster_id_extraction[%d]: name must not be empty", i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("cluster_id_extraction[%d]: duplicate name %q", i, rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if rule.Source != "" && !slices.Contains(validClusterIDSources, rule.Source) {
+			return fmt.Errorf("cluster_id_extraction[%d] (name %q): unknown source %q; valid sources: %v", i, rule.Name, rule.Source, validClusterIDSources)
+		}
+		if strings.TrimSpace(rule.Path) == "" {
+			return fmt.Errorf("cluster_id_extraction[%d] (name %q): path must not be empty", i, rule.Name)
+		}
+		if _, err := rule.ParsePath(); err != nil {
+			return fmt.Errorf("cluster_id_extraction[%d] (name %q): invalid path: %w", i, rule.Name, err)
+		}
+		if _, err := rule.CompileRegex(); err != nil {
+			return fmt.Errorf("cluster_id_extraction[%d] (name %q): invalid regex: %w", i, rule.Name, err)
+		}
+	}
+	return nil
+}
@@ -30,10 +30,41 @@ func (c *AIAgentConfig) GetTimeout() time.Duration {
 	return time.Duration(c.TimeoutSeconds) * time.Second
 }
 
+// Storm grouping dimensions. Every dimension is combined with the alert name, so
+// "region" groups incidents of the same alert firing in the same region.
+const (
+	StormGroupByRegion            = "region"
+	StormGroupByManagementCluster = "management_cluster"
+	StormGroupByAlertName         = "alert_name"
+	StormGroupByAWSAccount        = "aws_account"
+)
+
+var validStormGroupBy = []string{StormGroupByRegion, StormGroupByManagementCluster, StormGroupByAlertName, StormGroupByAWSAccount}
+
+// StormConfig configures incident storm detection in the interceptor.
+// When at least Threshold distinct clusters raise the same alert within WindowMinutes
+// and share a grouping dimension, the incidents are tagged with a correlation ID.
+type StormConfig struct {
+	Threshold          int      `yaml:"threshold"`                      // Number of distinct clusters that constitutes a storm
+	WindowMinutes      int      `yaml:"window_minutes,omitempty"`       // Sliding window incidents are tracked for (default: 15)
+	GroupBy            []string `yaml:"group_by,omitempty"`             // Grouping dimensions (default: region, management_cluster)
+	SkipClusterActions bool     `yaml:"skip_cluster_actions,omitempty"` // Skip per-cluster LS/SL/silence/remediation for correlated incidents
+}
+
+// GetWindow returns the tracking window as a time.Duration.
+func (s *StormConfig) GetWindow() time.Duration {
+	return time.Duration(s.WindowMinutes) * time.Minute
+}
+
 // Config holds the complete investigation configuration.
 type Config struct {
-	AIAgent *AIAgentConfig `yaml:"ai_agent,omitempty"`
-	Alerts  []AlertConfig  `yaml:"alerts"`
+	AIAgent             *AIAgentConfig     `yaml:"ai_agent,omitempty"`
+	Storm               *StormConfig       `yaml:"storm,omitempty"`
+	Routing             []RoutingRule      `yaml:"routing,omitempty"`
+	ClusterIDExtraction []ClusterIDRule    `yaml:"cluster_id_extraction,omitempty"`
+	ActorPolicy         *ActorPolicy       `yaml:"actor_policy,omitempty"`
+	EtcdCleanup         *EtcdCleanupPolicy `yaml:"etcd_cleanup,omitempty"`
+	Alerts              []AlertConfig      `yaml:"alerts"`
 }
 
 // AlertConfig defines which investigations to run for a given alert.
@@ -56,10 +87,20 @@ func (ac *AlertConfig) GetName() string {
 }
 
 // InvestigationEntry is a single investigation step within an alert's investigation list.
-// In YAML it can be a bare string (investigation name) or an object with name + optional when filter.
+// In YAML it can be a bare string (investigation name) or an object with name + optional when filter,
+// params and timeout.
 type InvestigationEntry struct {
 	Name string      `yaml:"name"`
 	When *FilterNode `yaml:"when,omitempty"`
+	// Params are passed to the investigation, they are validated against its parameter schema
+	Params map[string]string `yaml:"params,omitempty"`
+	// TimeoutSeconds cancels the investigation after the given time, 0 means no timeout
+	TimeoutSeconds int `yaml:"timeout_seconds,omitempty"`
+}
+
+// GetTimeout returns the timeout as a time.Duration for use with context.WithTimeout.
+func (e *InvestigationEntry) GetTimeout() time.Duration {
+	return time.Duration(e.TimeoutSeconds) * time.Second
 }
 
 // UnmarshalYAML allows InvestigationEntry to be specified as either a bare string or a mapping.
@@ -76,6 +117,7 @@ func (e *InvestigationEntry) UnmarshalYAML(value *yaml.Node) error {
 // Returns an error if the path is empty or the file cannot be read.
 // The validInvestigations parameter is the list of known investigation names used to
 // validate that each chain entry references a real investigation.
+// The routing rules of the deprecated CAD_ORG_POLICY_MAPPING environment variable are appended, if set.
 func LoadConfig(path string, validInvestigations []string) (*Config, error) {
 	if path == "" {
 		return nil, fmt.Errorf("investigation config path must not be empty")
@@ -85,7 +127,14 @@ func LoadConfig(path string, validInvestigations []string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
 	}
-	return ParseConfig(data, validInvestigations)
+	cfg, err := ParseConfig(data, validInvestigations)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.applyLegacyOrgPolicyMapping(os.Getenv(LegacyOrgPolicyMappingEnv)); err != nil {
+		return nil, fmt.Errorf("invalid investigation config: %w", err)
+	}
+	return cfg, nil
 }
 
 // ParseConfig parses and validates a YAML config from raw bytes.
@@ -106,6 +155,16 @@ func ParseConfig(data []byte, validInvestigations []string) (*Config, error) {
 		cfg.AIAgent.TimeoutSeconds = 900
 	}
 
+	// Set defaults for storm detection
+	if cfg.Storm != nil {
+		if cfg.Storm.WindowMinutes == 0 {
+			cfg.Storm.WindowMinutes = 15
+		}
+		if len(cfg.Storm.GroupBy) == 0 {
+			cfg.Storm.GroupBy = []string{StormGroupByRegion, StormGroupByManagementCluster}
+		}
+	}
+
 	return &cfg, nil
 }
 
@@ -136,9 +195,31 @@ func (c *Config) GetAIAgentConfig() *AIAgentConfig {
 	return c.AIAgent
 }
 
+// GetStormConfig returns the storm detection configuration, or nil if not set.
+func (c *Config) GetStormConfig() *StormConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Storm
+}
+
 // Validate checks that all investigation names are known, all filter expressions
 // reference valid FilterContext fields, and chain-level/entry-level when clauses are valid.
 func (c *Config) Validate(validInvestigations []string) error {
+	if c.Storm != nil {
+		if c.Storm.Threshold < 2 {
+			return fmt.Errorf("storm: threshold must be at least 2, got %d", c.Storm.Threshold)
+		}
+		if c.Storm.WindowMinutes < 0 {
+			return fmt.Errorf("storm: window_minutes must not be negative")
+		}
+		for i, dim := range c.Storm.GroupBy {
+			if !slices.Contains(validStormGroupBy, dim) {
+				return fmt.Errorf("storm.group_by[%d]: unknown dimension %q; valid dimensions: %v", i, dim, validStormGroupBy)
+			}
+		}
+	}
+
 	if c.AIAgent != nil {
 		if c.AIAgent.RuntimeARN == "" {
 			return fmt.Errorf("ai_agent: runtime_arn must not be empty")
@@ -154,6 +235,26 @@ func (c *Config) Validate(validInvestigations []string) error {
 		}
 	}
 
+	if err := c.validateRouting(); err != nil {
+		return err
+	}
+
+	if err := c.validateClusterIDExtraction(); err != nil {
+		return err
+	}
+
+	if c.ActorPolicy != nil {
+		if err := c.ActorPolicy.Validate(); err != nil {
+			return fmt.Errorf("actor_policy: %w", err)
+		}
+	}
+
+	if c.EtcdCleanup != nil {
+		if err := c.EtcdCleanup.Validate(); err != nil {
+			return fmt.Errorf("etcd_cleanup: %w", err)
+		}
+	}
+
 	seen := make(map[string]bool)
 	hasAIAssisted := false
 
@@ -187,6 +288,10 @@ func (c *Config) Validate(validInvestigations []string) error {
 				return fmt.Errorf("alerts[%d].investigations[%d]: unknown investigation %q; valid investigations: %v", i, j, entry.Name, validInvestigations)
 			}
 
+			if entry.TimeoutSeconds < 0 {
+				return fmt.Errorf("alerts[%d].investigations[%d]: timeout_seconds must not be negative", i, j)
+			}
+
 			if entry.Name == "aiassisted" {
 				hasAIAssisted = true
 			}
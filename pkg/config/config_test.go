@@ -1,10 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 )
 
 const testMustgatherChainYAML = `
@@ -507,6 +511,286 @@ alerts:
         when:
           operator: sample
           values: ["1.5"]
+`,
+			wantErr: true,
+		},
+		// --- routing ---
+		{
+			name: "valid routing rules",
+			yaml: `
+routing:
+  - name: "Customer Alpha"
+    escalation_policy: P123ABC
+    when:
+      field: OrganizationID
+      operator: in
+      values: ["org-id-1", "org-id-2"]
+  - name: "HCP in eu-west-1"
+    escalation_policy: P456DEF
+    when:
+      and:
+        - field: HCP
+          operator: in
+          values: ["true"]
+        - field: Region
+          operator: in
+          values: ["eu-west-1"]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			check: func(t *testing.T, cfg *Config) { //nolint:thelper // not a helper, inline check
+				if len(cfg.Routing) != 2 {
+					t.Fatalf("expected 2 routing rules, got %d", len(cfg.Routing))
+				}
+				if cfg.Routing[0].EscalationPolicy != "P123ABC" {
+					t.Errorf("routing[0].escalation_policy = %q, want P123ABC", cfg.Routing[0].EscalationPolicy)
+				}
+				keys := cfg.RoutingKeys()
+				if len(keys) != 3 || keys[0] != FieldOrganizationID || keys[1] != FieldHCP || keys[2] != FieldRegion {
+					t.Errorf("RoutingKeys() = %v", keys)
+				}
+			},
+		},
+		{
+			name: "routing rule without escalation policy is invalid",
+			yaml: `
+routing:
+  - name: "Customer Alpha"
+    when:
+      field: OrganizationID
+      operator: in
+      values: ["org-id-1"]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "routing rule without when is invalid",
+			yaml: `
+routing:
+  - name: "Everything"
+    escalation_policy: P123ABC
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "routing rule with unknown field is invalid",
+			yaml: `
+routing:
+  - name: "Customer Alpha"
+    escalation_policy: P123ABC
+    when:
+      field: OrgID
+      operator: in
+      values: ["org-id-1"]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "duplicate routing rule names are invalid",
+			yaml: `
+routing:
+  - name: "Customer Alpha"
+    escalation_policy: P123ABC
+    when:
+      field: OrganizationID
+      operator: in
+      values: ["org-id-1"]
+  - name: "Customer Alpha"
+    escalation_policy: P456DEF
+    when:
+      field: OrganizationID
+      operator: in
+      values: ["org-id-2"]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		// --- cluster_id_extraction ---
+		{
+			name: "valid cluster ID extraction rules",
+			yaml: `
+cluster_id_extraction:
+  - name: external-id-annotation
+    source: pagerduty
+    path: "{.details.annotations.external_id}"
+  - name: summary-text
+    path: .details.summary
+    regex: 'cluster (?P<id>[a-z0-9]+) is'
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			check: func(t *testing.T, cfg *Config) { //nolint:thelper // not a helper, inline check
+				rules := cfg.GetClusterIDRules()
+				if len(rules) != 2 {
+					t.Fatalf("expected 2 cluster ID rules, got %d", len(rules))
+				}
+				if rules[0].Source != "pagerduty" || rules[1].Regex == "" {
+					t.Errorf("unexpected rules: %+v", rules)
+				}
+			},
+		},
+		{
+			name: "cluster ID rule without path is invalid",
+			yaml: `
+cluster_id_extraction:
+  - name: no-path
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "cluster ID rule with invalid path is invalid",
+			yaml: `
+cluster_id_extraction:
+  - name: bad-path
+    path: "{.details[}"
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "cluster ID rule with unknown source is invalid",
+			yaml: `
+cluster_id_extraction:
+  - name: misspelled-source
+    source: pagerdutty
+    path: "{.details.cluster_id}"
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "cluster ID rule regex without capture group is invalid",
+			yaml: `
+cluster_id_extraction:
+  - name: no-group
+    path: "{.details.summary}"
+    regex: 'cluster [a-z0-9]+'
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		// --- storm detection ---
+		{
+			name: "storm config applies defaults",
+			yaml: `
+storm:
+  threshold: 5
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			check: func(t *testing.T, cfg *Config) { //nolint:thelper // not a helper, inline check
+				if cfg.Storm == nil {
+					t.Fatal("expected storm config")
+				}
+				if cfg.Storm.GetWindow() != 15*time.Minute {
+					t.Errorf("window = %v, want 15m", cfg.Storm.GetWindow())
+				}
+				if len(cfg.Storm.GroupBy) != 2 || cfg.Storm.GroupBy[0] != StormGroupByRegion || cfg.Storm.GroupBy[1] != StormGroupByManagementCluster {
+					t.Errorf("group_by = %v, want [region management_cluster]", cfg.Storm.GroupBy)
+				}
+				if cfg.Storm.SkipClusterActions {
+					t.Error("expected skip_cluster_actions to default to false")
+				}
+			},
+		},
+		{
+			name: "storm config with explicit values",
+			yaml: `
+storm:
+  threshold: 3
+  window_minutes: 5
+  group_by: [alert_name]
+  skip_cluster_actions: true
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			check: func(t *testing.T, cfg *Config) { //nolint:thelper // not a helper, inline check
+				if cfg.Storm.GetWindow() != 5*time.Minute {
+					t.Errorf("window = %v, want 5m", cfg.Storm.GetWindow())
+				}
+				if len(cfg.Storm.GroupBy) != 1 || cfg.Storm.GroupBy[0] != StormGroupByAlertName {
+					t.Errorf("group_by = %v, want [alert_name]", cfg.Storm.GroupBy)
+				}
+				if !cfg.Storm.SkipClusterActions {
+					t.Error("expected skip_cluster_actions to be true")
+				}
+			},
+		},
+		{
+			name: "storm grouped by aws account",
+			yaml: `
+storm:
+  threshold: 3
+  group_by: [region, aws_account]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			check: func(t *testing.T, cfg *Config) { //nolint:thelper // not a helper, inline check
+				if len(cfg.Storm.GroupBy) != 2 || cfg.Storm.GroupBy[1] != StormGroupByAWSAccount {
+					t.Errorf("group_by = %v, want [region aws_account]", cfg.Storm.GroupBy)
+				}
+			},
+		},
+		{
+			name: "storm threshold below 2 is invalid",
+			yaml: `
+storm:
+  threshold: 1
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
+`,
+			wantErr: true,
+		},
+		{
+			name: "storm unknown group_by dimension is invalid",
+			yaml: `
+storm:
+  threshold: 3
+  group_by: [cloud_provider]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - mustgather
 `,
 			wantErr: true,
 		},
@@ -661,6 +945,43 @@ func TestLoadConfig(t *testing.T) {
 			t.Fatal("expected error for invalid investigation name")
 		}
 	})
+
+	t.Run("legacy org policy mapping is appended to the routing rules", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(testMustgatherChainYAML), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(LegacyOrgPolicyMappingEnv, `{"organizations": [{"name": "Customer Alpha", "org_ids": ["org-id-1", "org-id-2"], "escalation_policy": "P123ABC"}]}`)
+
+		cfg, err := LoadConfig(path, testInvestigations)
+		if err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+		if len(cfg.Routing) != 1 {
+			t.Fatalf("expected 1 routing rule, got %d", len(cfg.Routing))
+		}
+		rule := cfg.Routing[0]
+		if rule.EscalationPolicy != "P123ABC" {
+			t.Errorf("escalation_policy = %q, want P123ABC", rule.EscalationPolicy)
+		}
+		for orgID, want := range map[string]bool{"org-id-2": true, "org-id-3": false} {
+			if got, _, _ := rule.Matches(&types.FilterContext{OrganizationID: orgID}); got != want {
+				t.Errorf("Matches(%s) = %v, want %v", orgID, got, want)
+			}
+		}
+	})
+
+	t.Run("invalid legacy org policy mapping returns error", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(testMustgatherChainYAML), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(LegacyOrgPolicyMappingEnv, `{"organizations": [{"name": "No policy", "org_ids": ["org-id-1"]}]}`)
+
+		if _, err := LoadConfig(path, testInvestigations); err == nil {
+			t.Fatal("expected error for an organization without escalation policy")
+		}
+	})
 }
 
 // NOTE: The env var fallback (CAD_INVESTIGATION_CONFIG_PATH) is handled by
@@ -705,3 +1026,51 @@ alerts:
 		t.Errorf("chain[1].When.Operator = %q, want sample", cfg.Alerts[0].Investigations[1].When.Operator)
 	}
 }
+
+func TestInvestigationEntryParams(t *testing.T) {
+	yaml := `
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - precheck
+      - name: mustgather
+        params:
+          TIMEOUT: "10"
+`
+	cfg, err := ParseConfig([]byte(yaml), testInvestigations)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if cfg.Alerts[0].Investigations[0].Params != nil {
+		t.Errorf("chain[0].Params = %v, want nil for bare string entry", cfg.Alerts[0].Investigations[0].Params)
+	}
+	if got := cfg.Alerts[0].Investigations[1].Params["TIMEOUT"]; got != "10" {
+		t.Errorf("chain[1].Params[TIMEOUT] = %q, want 10", got)
+	}
+}
+
+func TestInvestigationEntryTimeout(t *testing.T) {
+	yaml := `
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - precheck
+      - name: mustgather
+        timeout_seconds: %d
+`
+	cfg, err := ParseConfig([]byte(fmt.Sprintf(yaml, 1800)), testInvestigations)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if got := cfg.Alerts[0].Investigations[0].GetTimeout(); got != 0 {
+		t.Errorf("chain[0].GetTimeout() = %v, want 0", got)
+	}
+	if got := cfg.Alerts[0].Investigations[1].GetTimeout(); got != 30*time.Minute {
+		t.Errorf("chain[1].GetTimeout() = %v, want 30m", got)
+	}
+
+	_, err = ParseConfig([]byte(fmt.Sprintf(yaml, -1)), testInvestigations)
+	if err == nil || !strings.Contains(err.Error(), "timeout_seconds must not be negative") {
+		t.Errorf("ParseConfig() error = %v, want negative timeout error", err)
+	}
+}
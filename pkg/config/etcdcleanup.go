@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultEtcdOffenderMaxDeletions caps the objects pruned per offender and run if max_deletions is not set
+const defaultEtcdOffenderMaxDeletions = 500
+
+// EtcdPrunableKinds are the kinds offenders may select, by API group. The etcddatabasequotalowspace investigation
+// can only list and delete what its metadata.yaml grants, so a kind added here needs its rules added there as well.
+var EtcdPrunableKinds = map[string][]string{
+	"":              {"Event"},
+	"events.k8s.io": {"Event"},
+}
+
+// EtcdCleanupPolicy is the allowlist of known offenders the etcddatabasequotalowspace investigation
+// may prune when it remediates a full etcd database. Objects of kinds that are not listed are never deleted.
+type EtcdCleanupPolicy struct {
+	Offenders []EtcdOffender `yaml:"offenders"`
+}
+
+// EtcdOffender selects objects of a kind that are known to fill up etcd, e.g. stale events.
+// Only objects older than MinAgeMinutes, in namespaces matching one of the Namespaces patterns, are pruned.
+type EtcdOffender struct {
+	Name    string `yaml:"name"`
+	Group   string `yaml:"group,omitempty"` // API group, empty for the core group
+	Version string `yaml:"version"`
+	Kind    string `yaml:"kind"`
+	// Namespaces are unanchored regular expressions and must be set, so customer namespaces are only
+	// pruned if they are selected explicitly
+	Namespaces    []string `yaml:"namespaces"`
+	MinAgeMinutes int      `yaml:"min_age_minutes"`
+	MaxDeletions  int      `yaml:"max_deletions,omitempty"` // Objects pruned per run (default: 500)
+
+	// namespaces are the compiled Namespaces patterns, set by Validate
+	namespaces []*regexp.Regexp
+}
+
+// GetMinAge returns the minimum age of pruned objects as a time.Duration.
+func (o *EtcdOffender) GetMinAge() time.Duration {
+	return time.Duration(o.MinAgeMinutes) * time.Minute
+}
+
+// GetMaxDeletions returns the number of objects pruned per run, falling back to the default.
+func (o *EtcdOffender) GetMaxDeletions() int {
+	if o.MaxDeletions <= 0 {
+		return defaultEtcdOffenderMaxDeletions
+	}
+	return o.MaxDeletions
+}
+
+// MatchesNamespace reports whether objects in namespace are selected by the offender. The patterns are compiled
+// by Validate, an offender that wasn't loaded from the config or created with NewEtcdCleanupPolicy matches nothing.
+func (o *EtcdOffender) MatchesNamespace(namespace string) bool {
+	for _, re := range o.namespaces {
+		if re.MatchString(namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewEtcdCleanupPolicy creates a policy from offenders that are built in code rather than loaded from the config,
+// and validates it, which compiles the namespace patterns of its offenders.
+func NewEtcdCleanupPolicy(offenders ...EtcdOffender) (*EtcdCleanupPolicy, error) {
+	policy := &EtcdCleanupPolicy{Offenders: slices.Clone(offenders)}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate checks that every offender is named, selects a prunable kind in explicit namespaces and only selects
+// objects of a minimum age, so objects that are still in use are not pruned. It compiles the namespace patterns.
+func (p *EtcdCleanupPolicy) Validate() error {
+	seen := make(map[string]bool)
+	for i := range p.Offenders {
+		offender := &p.Offenders[i]
+		if strings.TrimSpace(offender.Name) == "" {
+			return fmt.Errorf("offenders[%d]: name must not be empty", i)
+		}
+		if seen[offender.Name] {
+			return fmt.Errorf("offenders[%d]: duplicate name %q", i, offender.Name)
+		}
+		seen[offender.Name] = true
+
+		if offender.Version == "" || offender.Kind == "" {
+			return fmt.Errorf("offenders[%d] (name %q): version and kind must be set", i, offender.Name)
+		}
+		if !slices.Contains(EtcdPrunableKinds[offender.Group], offender.Kind) {
+			return fmt.Errorf("offenders[%d] (name %q): kind %q in group %q can't be pruned; prunable kinds by group: %v",
+				i, offender.Name, offender.Kind, offender.Group, EtcdPrunableKinds)
+		}
+		if offender.MinAgeMinutes <= 0 {
+			return fmt.Errorf("offenders[%d] (name %q): min_age_minutes must be positive", i, offender.Name)
+		}
+		if offender.MaxDeletions < 0 {
+			return fmt.Errorf("offenders[%d] (name %q): max_deletions must not be negative", i, offender.Name)
+		}
+		if len(offender.Namespaces) == 0 {
+			return fmt.Errorf("offenders[%d] (name %q): namespaces must be set", i, offender.Name)
+		}
+		offender.namespaces = make([]*regexp.Regexp, 0, len(offender.Namespaces))
+		for _, pattern := range offender.Namespaces {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("offenders[%d] (name %q): invalid regex %q: %w", i, offender.Name, pattern, err)
+			}
+			offender.namespaces = append(offender.namespaces, re)
+		}
+	}
+	return nil
+}
+
+// GetEtcdCleanupPolicy returns the configured etcd cleanup policy, or nil if not set.
+func (c *Config) GetEtcdCleanupPolicy() *EtcdCleanupPolicy {
+	if c == nil {
+		return nil
+	}
+	return c.EtcdCleanup
+}
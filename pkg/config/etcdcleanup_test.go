@@ -0,0 +1,190 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigEtcdCleanup(t *testing.T) {
+	const alerts = `
+alerts:
+  - alert_title: "has gone missing"
+    investigations:
+      - chgm
+`
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "valid policy",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: stale-events
+      version: v1
+      kind: Event
+      namespaces: ["^openshift-"]
+      min_age_minutes: 1440
+    - name: old-monitoring-events
+      group: events.k8s.io
+      version: v1
+      kind: Event
+      namespaces: ["^openshift-monitoring$"]
+      min_age_minutes: 10080
+      max_deletions: 100
+`,
+		},
+		{
+			name: "kind not prunable",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: old-pipelineruns
+      group: tekton.dev
+      version: v1
+      kind: PipelineRun
+      namespaces: ["^openshift-"]
+      min_age_minutes: 60
+`,
+			wantErr: `offenders[0] (name "old-pipelineruns"): kind "PipelineRun" in group "tekton.dev" can't be pruned`,
+		},
+		{
+			name: "kind in wrong group",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: a
+      group: apps
+      version: v1
+      kind: Event
+      namespaces: ["^openshift-"]
+      min_age_minutes: 60
+`,
+			wantErr: `kind "Event" in group "apps" can't be pruned`,
+		},
+		{
+			name: "missing name",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - version: v1
+      kind: Event
+      namespaces: ["^openshift-"]
+      min_age_minutes: 60
+`,
+			wantErr: "etcd_cleanup: offenders[0]: name must not be empty",
+		},
+		{
+			name: "duplicate name",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: a
+      version: v1
+      kind: Event
+      namespaces: ["^openshift-"]
+      min_age_minutes: 60
+    - name: a
+      version: v1
+      kind: Event
+      namespaces: ["^openshift-"]
+      min_age_minutes: 60
+`,
+			wantErr: `offenders[1]: duplicate name "a"`,
+		},
+		{
+			name: "missing kind",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: a
+      version: v1
+      min_age_minutes: 60
+`,
+			wantErr: "version and kind must be set",
+		},
+		{
+			name: "missing minimum age",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: a
+      version: v1
+      kind: Event
+      namespaces: ["^openshift-"]
+`,
+			wantErr: "min_age_minutes must be positive",
+		},
+		{
+			name: "missing namespaces",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: all-events
+      version: v1
+      kind: Event
+      min_age_minutes: 60
+`,
+			wantErr: `offenders[0] (name "all-events"): namespaces must be set`,
+		},
+		{
+			name: "invalid regex",
+			policy: `
+etcd_cleanup:
+  offenders:
+    - name: a
+      version: v1
+      kind: Event
+      namespaces: ["(ci"]
+      min_age_minutes: 60
+`,
+			wantErr: `invalid regex "(ci"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(tt.policy+alerts), testInvestigations)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			policy := cfg.GetEtcdCleanupPolicy()
+			if policy == nil || len(policy.Offenders) != 2 {
+				t.Fatalf("expected 2 offenders, got %+v", policy)
+			}
+			events, monitoringEvents := policy.Offenders[0], policy.Offenders[1]
+			if events.GetMinAge() != 24*time.Hour || events.GetMaxDeletions() != defaultEtcdOffenderMaxDeletions {
+				t.Errorf("unexpected defaults of %+v", events)
+			}
+			if !events.MatchesNamespace("openshift-etcd") || events.MatchesNamespace("customer-app") {
+				t.Errorf("expected %+v to only match namespaces starting with openshift-", events)
+			}
+			if !monitoringEvents.MatchesNamespace("openshift-monitoring") || monitoringEvents.MatchesNamespace("openshift-etcd") {
+				t.Errorf("expected %+v to only match openshift-monitoring", monitoringEvents)
+			}
+		})
+	}
+}
+
+func TestEtcdOffenderMatchesNamespaceUnvalidated(t *testing.T) {
+	offender := EtcdOffender{Name: "stale-events", Version: "v1", Kind: "Event", Namespaces: []string{".*"}, MinAgeMinutes: 60}
+	if offender.MatchesNamespace("openshift-etcd") {
+		t.Errorf("expected an offender that wasn't validated to match nothing")
+	}
+
+	policy, err := NewEtcdCleanupPolicy(offender)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !policy.Offenders[0].MatchesNamespace("openshift-etcd") {
+		t.Errorf("expected the validated offender to match")
+	}
+}
@@ -49,6 +49,8 @@ const (
 	FieldCloudProvider  = "CloudProvider"
 	FieldHCP            = "HCP"
 	FieldClusterState   = "ClusterState"
+	FieldProduct        = "Product"
+	FieldRegion         = "Region"
 	FieldAlertName      = "AlertName"
 	FieldAlertTitle     = "AlertTitle"
 	FieldServiceName    = "ServiceName"
@@ -65,6 +67,8 @@ var validFields = []string{
 	FieldCloudProvider,
 	FieldHCP,
 	FieldClusterState,
+	FieldProduct,
+	FieldRegion,
 	FieldAlertName,
 	FieldAlertTitle,
 	FieldServiceName,
@@ -369,6 +373,10 @@ func resolveField(field string, ctx *types.FilterContext) (string, error) {
 		return strconv.FormatBool(ctx.HCP), nil
 	case FieldClusterState:
 		return ctx.ClusterState, nil
+	case FieldProduct:
+		return ctx.Product, nil
+	case FieldRegion:
+		return ctx.Region, nil
 	case FieldAlertName:
 		return ctx.AlertName, nil
 	case FieldAlertTitle:
@@ -789,6 +789,8 @@ func TestResolveAllFields(t *testing.T) {
 		CloudProvider:  "aws",
 		HCP:            true,
 		ClusterState:   "ready",
+		Product:        "rosa",
+		Region:         "us-east-1",
 		AlertName:      "alert",
 		AlertTitle:     "title",
 		ServiceName:    "svc",
@@ -803,6 +805,8 @@ func TestResolveAllFields(t *testing.T) {
 		FieldCloudProvider:  "aws",
 		FieldHCP:            "true",
 		FieldClusterState:   "ready",
+		FieldProduct:        "rosa",
+		FieldRegion:         "us-east-1",
 		FieldAlertName:      "alert",
 		FieldAlertTitle:     "title",
 		FieldServiceName:    "svc",
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+)
+
+// RoutingRule moves matching incidents to a dedicated PagerDuty escalation policy.
+// Rules are evaluated in order; the first matching rule wins.
+type RoutingRule struct {
+	Name             string      `yaml:"name"`
+	EscalationPolicy string      `yaml:"escalation_policy"`
+	When             *FilterNode `yaml:"when"`
+}
+
+// Matches evaluates the rule's filter against the FilterContext.
+// Returns (result, reason, error).
+func (r *RoutingRule) Matches(ctx *types.FilterContext) (bool, string, error) {
+	if ctx == nil {
+		return false, "no filter context", nil
+	}
+	return r.When.evaluate(ctx)
+}
+
+// RoutingKeys returns all field names referenced by the routing rules.
+// Used to determine which FilterContext fields need to be populated.
+func (c *Config) RoutingKeys() []string {
+	keys := make([]string, 0)
+	if c == nil {
+		return keys
+	}
+	for i := range c.Routing {
+		if c.Routing[i].When != nil {
+			c.Routing[i].When.Keys(&keys)
+		}
+	}
+	return keys
+}
+
+// validateRouting checks that every routing rule is named, targets an escalation policy
+// and has a valid filter. A rule without a filter would route every incident and is rejected.
+func (c *Config) validateRouting() error {
+	seen := make(map[string]bool)
+	for i, rule := range c.Routing {
+		if strings.TrimSpace(rule.Name) == "" {
+			return fmt.Errorf("routing[%d]: name must not be empty", i)
+		}
+		if seen[rule.Name] {
+			return fmt.Errorf("routing[%d]: duplicate name %q", i, rule.Name)
+		}
+		seen[rule.Name] = true
+
+		if strings.TrimSpace(rule.EscalationPolicy) == "" {
+			return fmt.Errorf("routing[%d] (name %q): escalation_policy must not be empty", i, rule.Name)
+		}
+		if rule.When == nil {
+			return fmt.Errorf("routing[%d] (name %q): when must not be empty", i, rule.Name)
+		}
+		if err := rule.When.validate(fmt.Sprintf("routing[%d].when", i)); err != nil {
+			return fmt.Errorf("routing[%d] (name %q): %w", i, rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// LegacyOrgPolicyMappingEnv is the environment variable that configured organization-based
+// routing before the routing section. It is translated into routing rules while deployments migrate.
+const LegacyOrgPolicyMappingEnv = "CAD_ORG_POLICY_MAPPING"
+
+// legacyOrgPolicyMapping is the JSON format of CAD_ORG_POLICY_MAPPING
+type legacyOrgPolicyMapping struct {
+	Organizations []struct {
+		Name             string   `json:"name"`
+		OrgIDs           []string `json:"org_ids"`
+		EscalationPolicy string   `json:"escalation_policy"`
+	} `json:"organizations"`
+}
+
+// LegacyOrgRoutingRules translates the JSON of CAD_ORG_POLICY_MAPPING into the equivalent routing rules,
+// one per organization, matching the OrganizationID of the cluster.
+func LegacyOrgRoutingRules(mappingJSON string) ([]RoutingRule, error) {
+	var mapping legacyOrgPolicyMapping
+	if err := json.Unmarshal([]byte(mappingJSON), &mapping); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", LegacyOrgPolicyMappingEnv, err)
+	}
+
+	rules := make([]RoutingRule, 0, len(mapping.Organizations))
+	for i, org := range mapping.Organizations {
+		rules = append(rules, RoutingRule{
+			Name:             fmt.Sprintf("%s[%d] %s", LegacyOrgPolicyMappingEnv, i, org.Name),
+			EscalationPolicy: org.EscalationPolicy,
+			When:             &FilterNode{Field: FieldOrganizationID, Operator: OperatorIn, Values: org.OrgIDs},
+		})
+	}
+	return rules, nil
+}
+
+// applyLegacyOrgPolicyMapping appends the routing rules of CAD_ORG_POLICY_MAPPING, if set, after the configured
+// rules, so the configured rules take precedence.
+func (c *Config) applyLegacyOrgPolicyMapping(mappingJSON string) error {
+	if strings.TrimSpace(mappingJSON) == "" {
+		return nil
+	}
+	rules, err := LegacyOrgRoutingRules(mappingJSON)
+	if err != nil {
+		return err
+	}
+	logging.Warnf("%s is deprecated and will be removed, move its %d organization(s) to the routing section of the investigation config",
+		LegacyOrgPolicyMappingEnv, len(rules))
+	c.Routing = append(c.Routing, rules...)
+	if err := c.validateRouting(); err != nil {
+		return fmt.Errorf("%s: %w", LegacyOrgPolicyMappingEnv, err)
+	}
+	return nil
+}
@@ -7,21 +7,29 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
 	"github.com/openshift/configuration-anomaly-detection/pkg/config"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/aiassisted"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/chgm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/etcddatabasequotalowspace"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/managedcloud"
 	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
+	"github.com/openshift/configuration-anomaly-detection/pkg/replay"
+	"github.com/openshift/configuration-anomaly-detection/pkg/routing"
+	"github.com/openshift/configuration-anomaly-detection/pkg/source"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 	"go.uber.org/zap"
 )
@@ -48,8 +56,11 @@ type ManualConfig struct {
 	ClusterId         string
 	InvestigationName string
 	DryRun            bool
-	WithFiltering     bool // When true, evaluate investigation filters during manual runs
-	Params            map[string]string
+	WithFiltering     bool   // When true, evaluate investigation filters during manual runs
+	IncidentFile      string // When set, incident operations are recorded to this file instead of being skipped
+	RecordDir         string // When set, all client traffic and resulting actions are recorded as a replay fixture
+	// Params are passed to the investigation, not to precheck and ccam
+	Params map[string]string
 }
 
 func (p *ManualConfig) Validate() error {
@@ -76,7 +87,8 @@ type investigationRunner struct {
 	logger       *zap.SugaredLogger
 	dependencies *Dependencies
 	dryRun       bool
-	notifier     incidentNotifier
+	manual       bool // true for manual CLI runs, used to track manual investigation metrics
+	backend      incident.Backend
 }
 
 type ControllerOptions struct {
@@ -88,11 +100,14 @@ type ControllerOptions struct {
 type Dependencies struct {
 	OCMClient           *ocm.SdkClient
 	BackplaneClient     backplane.Client
+	OCMURL              string
 	BackplaneURL        string
 	BackplaneProxy      string
 	AWSProxy            string
 	ExperimentalEnabled bool
+	ClusterRemediation  bool // Opt-in for actions changing the cluster, see executor.WithClusterRemediation
 	Cfg                 *config.Config
+	CorrelationID       string // Set by the interceptor when the incident is part of an incident storm
 }
 
 // Retry configuration for transient infrastructure errors
@@ -102,6 +117,15 @@ const (
 	maxRetryBackoff         = 10 * time.Second
 )
 
+// ExecutorOptions returns the options of the executors of a run
+func (d *Dependencies) ExecutorOptions() []executor.Option {
+	var opts []executor.Option
+	if d.ClusterRemediation {
+		opts = append(opts, executor.WithClusterRemediation())
+	}
+	return opts
+}
+
 func (d *Dependencies) Cleanup() {
 	// Currently no cleanup needed at dependency level
 	// Individual investigations handle their own cleanup (RestConfig, OCClient)
@@ -111,7 +135,8 @@ func (d *Dependencies) Cleanup() {
 // initializeDependencies loads environment variables and creates shared clients.
 // configPath is the path to the investigation config file;
 // if empty, the CAD_INVESTIGATION_CONFIG_PATH env var is used as a fallback.
-func initializeDependencies(configPath string) (*Dependencies, error) {
+// If recorder is not nil, the traffic of all clients is recorded.
+func initializeDependencies(configPath string, recorder *replay.Recorder) (*Dependencies, error) {
 	if configPath == "" {
 		configPath = os.Getenv("CAD_INVESTIGATION_CONFIG_PATH")
 	}
@@ -129,12 +154,18 @@ func initializeDependencies(configPath string) (*Dependencies, error) {
 
 	backplaneProxy := os.Getenv("BACKPLANE_PROXY")
 	awsProxy := os.Getenv("AWS_PROXY")
+	gcpCredentialsFile := os.Getenv("CAD_GCP_CREDENTIALS_FILE")
 
 	// Set managedcloud environment configuration for this session
 	managedcloud.SetBackplaneURL(backplaneURL)
 	managedcloud.SetBackplaneInitialARN(backplaneInitialARN)
 	managedcloud.SetBackplaneProxy(backplaneProxy)
 	managedcloud.SetAWSProxy(awsProxy)
+	managedcloud.SetGCPCredentialsFile(gcpCredentialsFile)
+	if recorder != nil {
+		managedcloud.SetTransportWrapper(recorder.Wrap(replay.ClientAWS))
+		managedcloud.SetGCPTransportWrapper(recorder.Wrap(replay.ClientGCP))
+	}
 
 	// Load OCM environment variables
 	ocmClientID := os.Getenv("CAD_OCM_CLIENT_ID")
@@ -155,6 +186,10 @@ func initializeDependencies(configPath string) (*Dependencies, error) {
 	experimentalEnabledVar := os.Getenv("CAD_EXPERIMENTAL_ENABLED")
 	experimentalEnabled, _ := strconv.ParseBool(experimentalEnabledVar)
 
+	clusterRemediation, _ := strconv.ParseBool(os.Getenv("CAD_CLUSTER_REMEDIATION_ENABLED"))
+
+	correlationID := os.Getenv("CAD_CORRELATION_ID")
+
 	// Load investigation config (optional for manual runs)
 	var cfg *config.Config
 	var err error
@@ -163,10 +198,17 @@ func initializeDependencies(configPath string) (*Dependencies, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to load investigation config: %w", err)
 		}
+		if err := validateConfigParams(cfg); err != nil {
+			return nil, fmt.Errorf("invalid investigation config: %w", err)
+		}
 	}
 
 	// Create OCM client
-	ocmClient, err := ocm.New(ocmClientID, ocmClientSecret, ocmURL)
+	var ocmWrappers []sdk.TransportWrapper
+	if recorder != nil {
+		ocmWrappers = append(ocmWrappers, recorder.Wrap(replay.ClientOCM))
+	}
+	ocmClient, err := ocm.New(ocmClientID, ocmClientSecret, ocmURL, ocmWrappers...)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize ocm client: %w", err)
 	}
@@ -177,6 +219,10 @@ func initializeDependencies(configPath string) (*Dependencies, error) {
 		BaseURL:   backplaneURL,
 		ProxyURL:  backplaneProxy,
 	}
+	if recorder != nil {
+		config.TransportWrapper = recorder.Wrap(replay.ClientBackplane)
+		config.ClusterTransportWrapper = recorder.Wrap(replay.ClientCluster)
+	}
 	bpClient, err := backplane.NewClient(config)
 	if err != nil {
 		return nil, fmt.Errorf("could not construct backplane-client")
@@ -185,18 +231,26 @@ func initializeDependencies(configPath string) (*Dependencies, error) {
 	return &Dependencies{
 		OCMClient:           ocmClient,
 		BackplaneClient:     bpClient,
+		OCMURL:              ocmURL,
 		BackplaneURL:        backplaneURL,
 		BackplaneProxy:      backplaneProxy,
 		AWSProxy:            awsProxy,
 		ExperimentalEnabled: experimentalEnabled,
+		ClusterRemediation:  clusterRemediation,
 		Cfg:                 cfg,
+		CorrelationID:       correlationID,
 	}, nil
 }
 
 // This is the main function to interact with the controller.
 // It will determine which type of controller to build based on the passed options and run the required investigation.
 func Run(opts ControllerOptions) error {
-	deps, err := initializeDependencies(opts.Common.ConfigPath)
+	var recorder *replay.Recorder
+	if opts.Manual != nil && opts.Manual.RecordDir != "" {
+		recorder = replay.NewRecorder()
+	}
+
+	deps, err := initializeDependencies(opts.Common.ConfigPath, recorder)
 	if err != nil {
 		return err
 	}
@@ -211,6 +265,9 @@ func Run(opts ControllerOptions) error {
 		return err
 	}
 
+	if recorder != nil {
+		return recordInvestigation(ctrl, *opts.Manual, deps, recorder)
+	}
 	return ctrl.Investigate(context.Background())
 }
 
@@ -238,10 +295,24 @@ func NewController(opts ControllerOptions, deps *Dependencies) (Controller, erro
 			return nil, fmt.Errorf("failed to base64-decode webhook payload: %w", err)
 		}
 
+		// Alerts from non-PagerDuty sources are normalized by the interceptor.
+		if incidents, ok := source.Decode(payload); ok {
+			return &SourceController{
+				config:       opts.Common,
+				incidents:    incidents,
+				dependencies: deps,
+			}, nil
+		}
+
 		pdClient, err := pagerduty.GetPDClient(payload)
 		if err != nil {
 			return nil, fmt.Errorf("could not initialize pagerduty client: %w", err)
 		}
+		clusterIDs, err := clusterid.NewExtractor(deps.Cfg.GetClusterIDRules())
+		if err != nil {
+			return nil, fmt.Errorf("could not load cluster ID extraction rules: %w", err)
+		}
+		pdClient.SetClusterIDExtractor(clusterIDs)
 
 		// Initialize logger early (we'll update with cluster ID later)
 		logger := logging.InitLogger(opts.Common.LogLevel, opts.Common.Identifier, "")
@@ -253,10 +324,10 @@ func NewController(opts ControllerOptions, deps *Dependencies) (Controller, erro
 			investigationRunner: investigationRunner{
 				ocmClient:    deps.OCMClient,
 				bpClient:     deps.BackplaneClient,
-				executor:     executor.NewWebhookExecutor(deps.OCMClient, pdClient, deps.BackplaneClient, logger),
+				executor:     executor.NewWebhookExecutor(deps.OCMClient, pdClient, deps.BackplaneClient, logger, deps.ExecutorOptions()...),
 				logger:       logger,
 				dependencies: deps,
-				notifier:     newPDIncidentNotifier(pdClient),
+				backend:      pdClient,
 			},
 		}, nil
 	}
@@ -269,17 +340,37 @@ func NewController(opts ControllerOptions, deps *Dependencies) (Controller, erro
 		// Initialize logger for manual runs
 		logger := logging.InitLogger(opts.Common.LogLevel, opts.Common.Identifier, opts.Manual.ClusterId)
 
+		meta := incident.Metadata{
+			Title:     opts.Manual.InvestigationName,
+			ClusterID: opts.Manual.ClusterId,
+		}
+		var backend incident.Backend = incident.NewNoop(meta)
+		exec := executor.NewManualExecutor(deps.OCMClient, deps.BackplaneClient, logger, deps.ExecutorOptions()...)
+		if opts.Manual.IncidentFile != "" {
+			// Record incident operations to a file instead of skipping them
+			meta.ID = "manual-" + opts.Manual.ClusterId
+			backend = incident.NewFile(opts.Manual.IncidentFile, meta)
+			exec = executor.NewWebhookExecutor(deps.OCMClient, backend, deps.BackplaneClient, logger, deps.ExecutorOptions()...)
+		}
+		var recording *executor.RecordingExecutor
+		if opts.Manual.RecordDir != "" {
+			recording = executor.NewRecordingExecutor(exec)
+			exec = recording
+		}
+
 		return &ManualController{
-			config: opts.Common,
-			manual: *opts.Manual,
+			config:    opts.Common,
+			manual:    *opts.Manual,
+			recording: recording,
 			investigationRunner: investigationRunner{
 				ocmClient:    deps.OCMClient,
 				bpClient:     deps.BackplaneClient,
-				executor:     executor.NewManualExecutor(deps.OCMClient, deps.BackplaneClient, logger),
+				executor:     exec,
 				logger:       logger,
 				dependencies: deps,
 				dryRun:       opts.Manual.DryRun,
-				notifier:     newNoopIncidentNotifier(),
+				manual:       true,
+				backend:      backend,
 			},
 		}, nil
 	}
@@ -295,7 +386,6 @@ func (c *investigationRunner) runChain(
 	clusterId string,
 	alertConfig *config.AlertConfig,
 	filterCtx *types.FilterContext,
-	params map[string]string,
 ) (err error) {
 	if len(alertConfig.Investigations) > 0 {
 		metrics.Inc(metrics.Alerts, alertConfig.GetName())
@@ -306,22 +396,34 @@ func (c *investigationRunner) runChain(
 		if err != nil && !errors.Is(err, errAlertFiltered) {
 			c.recordManualCompletion(alertConfig.AlertTitle, "error")
 			if latestBuilder != nil {
-				handleCADFailure(err, latestBuilder, c.notifier)
+				handleCADFailure(err, latestBuilder, c.backend)
 			}
 		}
 	}()
 
+	chainParams, err := resolveChainParams(alertConfig)
+	if err != nil {
+		return err
+	}
+
+	// Read-only OCM and AWS resources are fetched once per chain, rest configs stay per investigation
+	cache := investigation.NewChainCache()
+	defer func() {
+		fetches, hits := cache.Stats()
+		logging.Debugf("Chain cache for alert %q: %d fetches, %d hits", alertConfig.AlertTitle, fetches, hits)
+	}()
+
 	// Alert-level filter: evaluated once before running any investigation.
 	if filterCtx != nil && alertConfig.When != nil {
 		filterBuilder, fErr := investigation.NewResourceBuilder(
 			c.ocmClient, c.bpClient, clusterId, alertConfig.GetName(),
-			c.dependencies.BackplaneURL, params)
+			c.dependencies.BackplaneURL, nil, cache)
 		if fErr != nil {
 			return fmt.Errorf("failed to create filter builder: %w", fErr)
 		}
 		var requiredKeys []string
 		alertConfig.When.Keys(&requiredKeys)
-		if populateErr := c.populateFilterContextFromOCM(filterCtx, filterBuilder, clusterId, requiredKeys); populateErr != nil {
+		if populateErr := c.populateFilterContextFromOCM(filterCtx, filterBuilder, cache, requiredKeys); populateErr != nil {
 			return fmt.Errorf("could not populate filter context for alert %q: %w", alertConfig.AlertTitle, populateErr)
 		}
 		pass, reason, filterErr := alertConfig.ShouldRun(filterCtx)
@@ -337,30 +439,43 @@ func (c *investigationRunner) runChain(
 	}
 
 	hasFindings := false
-	for _, entry := range alertConfig.Investigations {
+	var skipped []string
+	for i, entry := range alertConfig.Investigations {
 		inv := investigations.GetInvestigationByName(entry.Name)
 		if inv == nil {
 			return fmt.Errorf("unknown investigation %q for alert %q", entry.Name, alertConfig.AlertTitle)
 		}
 
-		// Create a fresh aiassisted instance with the runtime config to avoid mutating the registry singleton.
+		// Create fresh instances of the investigations taking runtime config to avoid mutating the registry singletons.
 		if _, ok := inv.(*aiassisted.Investigation); ok && c.dependencies.Cfg != nil {
 			inv = &aiassisted.Investigation{AIConfig: c.dependencies.Cfg.GetAIAgentConfig()}
 		}
+		if _, ok := inv.(*chgm.Investigation); ok && c.dependencies.Cfg != nil {
+			inv = &chgm.Investigation{ActorPolicy: c.dependencies.Cfg.GetActorPolicy()}
+		}
+		if _, ok := inv.(*etcddatabasequotalowspace.Investigation); ok && c.dependencies.Cfg != nil {
+			inv = &etcddatabasequotalowspace.Investigation{CleanupPolicy: c.dependencies.Cfg.GetEtcdCleanupPolicy()}
+		}
 
-		builder, bErr := investigation.NewResourceBuilder(
-			c.ocmClient, c.bpClient, clusterId, inv.Name(),
-			c.dependencies.BackplaneURL, params)
+		newBuilder := func() (investigation.ResourceBuilder, error) {
+			builder, err := investigation.NewResourceBuilder(
+				c.ocmClient, c.bpClient, clusterId, inv.Name(),
+				c.dependencies.BackplaneURL, chainParams[i], cache)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create builder for %q: %w", inv.Name(), err)
+			}
+			return builder.WithIncident(c.backend), nil
+		}
+		builder, bErr := newBuilder()
 		if bErr != nil {
-			return fmt.Errorf("failed to create builder for %q: %w", inv.Name(), bErr)
+			return bErr
 		}
-		c.notifier.AttachToBuilder(builder)
 		latestBuilder = builder
 
 		// Per-entry filter evaluation
 		if entry.When != nil && filterCtx != nil {
 			requiredKeys := entry.Keys()
-			if populateErr := c.populateFilterContextFromOCM(filterCtx, builder, clusterId, requiredKeys); populateErr != nil {
+			if populateErr := c.populateFilterContextFromOCM(filterCtx, builder, cache, requiredKeys); populateErr != nil {
 				cleanupBuilder(builder)
 				return fmt.Errorf("could not populate filter context for %q: %w", entry.Name, populateErr)
 			}
@@ -377,8 +492,47 @@ func (c *investigationRunner) runChain(
 			}
 		}
 
+		if supported, reason := supportsCluster(inv, builder); !supported {
+			logging.Infof("Skipping investigation %q: %s", inv.Name(), reason)
+			skipped = append(skipped, fmt.Sprintf("%s: %s", inv.Name(), reason))
+			cleanupBuilder(builder)
+			continue
+		}
+
 		logging.Infof("Running investigation %q", inv.Name())
-		result, attempts, runErr := runInvestigationWithRetry(inv, builder)
+		runCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := entry.GetTimeout(); timeout > 0 {
+			runCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		result, attempts, runErr := runInvestigationWithRetry(runCtx, inv, builder)
+		timedOut := errors.Is(runCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		var abandoned *investigation.AbandonedError
+		if errors.As(runErr, &abandoned) {
+			// The abandoned investigation still uses its builder, so it is only cleaned up once the
+			// investigation returns, and the rest of the chain continues with a fresh builder
+			go func(builder investigation.ResourceBuilder) {
+				<-abandoned.Done
+				cleanupBuilder(builder)
+			}(builder)
+			if builder, bErr = newBuilder(); bErr != nil {
+				return bErr
+			}
+			builder.WithCluster()
+			latestBuilder = builder
+		}
+		// Actions and cleanup shouldn't fail on the expired context of the investigation
+		builder.WithContext(ctx)
+
+		if timedOut {
+			reportErr := c.reportTimeout(builder, inv.Name(), entry.GetTimeout(), result, abandoned == nil)
+			cleanupBuilder(builder)
+			if reportErr != nil {
+				return reportErr
+			}
+			c.recordManualCompletion(alertConfig.AlertTitle, "timeout")
+			return nil
+		}
 		if runErr != nil {
 			cleanupBuilder(builder)
 			return fmt.Errorf("investigation %q failed after %d attempt(s): %w", inv.Name(), attempts, runErr)
@@ -397,18 +551,23 @@ func (c *investigationRunner) runChain(
 		if result.StopInvestigations != nil {
 			logging.Infof("Stopping investigations due to %q: %v", inv.Name(), result.StopInvestigations)
 			c.recordManualCompletion(alertConfig.AlertTitle, "stopped")
-			return nil
+			// The stopping investigation has already acted on the alert, so there is nothing to escalate
+			return c.reportSkipped(latestBuilder, alertConfig.AlertTitle, skipped, true)
 		}
 	}
 
+	if err := c.reportSkipped(latestBuilder, alertConfig.AlertTitle, skipped, hasFindings); err != nil {
+		return err
+	}
+
 	if hasFindings {
 		c.recordManualCompletion(alertConfig.AlertTitle, "success")
 	} else {
 		c.recordManualCompletion(alertConfig.AlertTitle, "no_findings")
 	}
 
-	// Post-chain: title update (PD mode only)
-	if c.notifier.HasPagerDuty() && latestBuilder != nil {
+	// Post-chain: title update (skipped by the manual executor)
+	if latestBuilder != nil {
 		a := executor.PagerDutyTitleUpdate{Prefix: pagerdutyTitlePrefix}
 		titleResult := investigation.InvestigationResult{
 			Actions: []types.Action{&a},
@@ -418,6 +577,54 @@ func (c *investigationRunner) runChain(
 	return nil
 }
 
+// supportsCluster reports whether inv declares support for the cluster of the builder.
+// Investigations that don't implement investigation.Describer, and clusters that can't be
+// retrieved, are treated as supported so the investigation can handle them itself.
+func supportsCluster(inv investigation.Investigation, builder investigation.ResourceBuilder) (bool, string) {
+	describer, ok := inv.(investigation.Describer)
+	if !ok {
+		return true, ""
+	}
+	resources, err := builder.WithCluster().Build()
+	if err != nil || resources == nil || resources.Cluster == nil {
+		return true, ""
+	}
+	return describer.Describe().Supports(resources.Cluster)
+}
+
+// reportSkipped adds a note listing the investigations that were skipped because they don't
+// support the cluster. If no investigation produced findings, the alert is escalated as well.
+func (c *investigationRunner) reportSkipped(builder investigation.ResourceBuilder, alertTitle string, skipped []string, hasFindings bool) error {
+	if len(skipped) == 0 || builder == nil {
+		return nil
+	}
+	note := "CAD skipped investigations that don't support this cluster:\n- " + strings.Join(skipped, "\n- ")
+	result := investigation.InvestigationResult{Actions: []types.Action{executor.Note(note)}}
+	if !hasFindings {
+		result.Actions = append(result.Actions, executor.Escalate("CAD skipped all investigations that don't support this cluster, please investigate manually"))
+	}
+	return c.executeActions(builder, &result, alertTitle)
+}
+
+// reportTimeout escalates an investigation that timed out. Investigations that stop on their
+// context return the actions for what they found so far, which are executed as usual. For the others,
+// the notes they gathered through the builder are posted if withNotes is set, which it can't be for
+// an abandoned investigation that is still writing them.
+func (c *investigationRunner) reportTimeout(builder investigation.ResourceBuilder, name string, timeout time.Duration, result investigation.InvestigationResult, withNotes bool) error {
+	reason := fmt.Sprintf("Investigation %s timed out after %s, please investigate manually", name, timeout)
+	logging.Warn(reason)
+	metrics.Inc(metrics.InvestigationTimeouts, name)
+
+	if len(result.Actions) == 0 && withNotes {
+		if resources, _ := builder.Build(); resources != nil && resources.Notes != nil {
+			resources.Notes.AppendWarning("%s, the notes above are incomplete", reason)
+			result.Actions = append(result.Actions, executor.NoteFrom(resources.Notes))
+		}
+	}
+	result.Actions = append(result.Actions, executor.Escalate(reason))
+	return c.executeActions(builder, &result, name)
+}
+
 // cleanupBuilder cleans up all resources on the builder that have Clean() methods.
 func cleanupBuilder(builder investigation.ResourceBuilder) {
 	resources, _ := builder.Build()
@@ -454,6 +661,7 @@ func cleanupBuilder(builder investigation.ResourceBuilder) {
 // It retries up to maxInvestigationRetries times with exponential backoff for InfrastructureErrors.
 // Returns the result, the number of attempts made, and the final error.
 func runInvestigationWithRetry(
+	ctx context.Context,
 	inv investigation.Investigation,
 	builder investigation.ResourceBuilder,
 ) (investigation.InvestigationResult, int, error) {
@@ -463,7 +671,7 @@ func runInvestigationWithRetry(
 	maxAttempts := maxInvestigationRetries + 1
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
-		result, err = inv.Run(builder)
+		result, err = investigation.RunWithContext(ctx, inv, builder)
 
 		if err == nil { // Success
 			if attempt > 1 {
@@ -476,13 +684,21 @@ func runInvestigationWithRetry(
 			logging.Debugf("Non-retriable error encountered: %v", err)
 			return result, attempt, err
 		}
+		if ctx.Err() != nil {
+			logging.Warnf("Not retrying after the investigation context is done: %v", err)
+			return result, attempt, err
+		}
 
 		// Infra error; retry if any attempts left
 		if attempt < maxAttempts {
 			backoff := calculateBackoff(attempt)
 			logging.Warnf("Infrastructure error on attempt %d/%d, retrying in %v: %v",
 				attempt, maxAttempts, backoff, err)
-			time.Sleep(backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return result, attempt, err
+			}
 		} else {
 			logging.Errorf("Infrastructure error on final attempt %d/%d: %v",
 				attempt, maxAttempts, err)
@@ -503,15 +719,15 @@ func calculateBackoff(attempt int) time.Duration {
 }
 
 // recordManualCompletion records manual investigation completion metric.
-// Only tracks if this is a manual investigation (notifier is inactive).
+// Only tracks if this is a manual investigation.
 func (c *investigationRunner) recordManualCompletion(invName string, status string) {
-	if !c.notifier.HasPagerDuty() {
+	if c.manual {
 		dryRun := strconv.FormatBool(c.dryRun)
 		metrics.Inc(metrics.ManualInvestigationCompleted, invName, status, dryRun)
 	}
 }
 
-func handleCADFailure(err error, rb investigation.ResourceBuilder, notifier incidentNotifier) {
+func handleCADFailure(err error, rb investigation.ResourceBuilder, backend incident.Backend) {
 	logging.Errorf("CAD investigation failed: %v", err)
 	resources, buildErr := rb.Build()
 	if buildErr != nil {
@@ -520,7 +736,7 @@ func handleCADFailure(err error, rb investigation.ResourceBuilder, notifier inci
 
 	var docErr *ocm.DocumentationMismatchError
 	if errors.As(err, &docErr) {
-		escalateDocumentationMismatch(docErr, resources, notifier)
+		escalateDocumentationMismatch(docErr, resources, backend)
 		return
 	}
 
@@ -532,10 +748,10 @@ func handleCADFailure(err error, rb investigation.ResourceBuilder, notifier inci
 		notes = "🚨 CAD investigation failed prior to resource initialization, CAD team has been notified. Please investigate manually. 🚨"
 	}
 
-	if escErr := notifier.EscalateWithNote(notes); escErr != nil {
-		logging.Errorf("Failed to escalate notes to PagerDuty: %v", escErr)
+	if escErr := backend.EscalateIncidentWithNote(notes); escErr != nil {
+		logging.Errorf("Failed to escalate notes to incident: %v", escErr)
 	} else {
-		logging.Info("CAD failure & incident notes added to PagerDuty")
+		logging.Info("CAD failure & incident notes added to incident")
 	}
 }
 
@@ -563,6 +779,11 @@ func (c *investigationRunner) executeActions(
 		exec = executor.NewInfraClusterExecutor(exec, c.logger, resources.IsInfrastructureClusterUncertain)
 	}
 
+	if c.skipCorrelatedClusterActions() {
+		logging.Infof("Incident is part of wider event %s: wrapping executor to skip LS/Silence/ServiceLog/remediation actions", c.dependencies.CorrelationID)
+		exec = executor.NewCorrelatedIncidentExecutor(exec, c.logger, c.dependencies.CorrelationID)
+	}
+
 	// Execute actions with default options using controller's executor
 	input := &executor.ExecutorInput{
 		InvestigationName: investigationName,
@@ -589,10 +810,21 @@ func (c *investigationRunner) executeActions(
 	return nil
 }
 
+// skipCorrelatedClusterActions reports whether customer-facing actions should be skipped
+// because the interceptor correlated this incident with an incident storm.
+func (c *investigationRunner) skipCorrelatedClusterActions() bool {
+	if c.dependencies == nil || c.dependencies.CorrelationID == "" {
+		return false
+	}
+	stormCfg := c.dependencies.Cfg.GetStormConfig()
+	return stormCfg != nil && stormCfg.SkipClusterActions
+}
+
 // populateFilterContextFromOCM enriches the filter context with OCM cluster fields.
 // This is called before filter evaluation so the cluster object is available from the builder cache.
+// Subscription lookups are cached in the chain cache, as every filtered entry of a chain needs them.
 // Failures to populate individual fields are logged as warnings but do not fail the investigation.
-func (c *investigationRunner) populateFilterContextFromOCM(filterCtx *types.FilterContext, builder investigation.ResourceBuilder, clusterID string, requiredKeys []string) error {
+func (c *investigationRunner) populateFilterContextFromOCM(filterCtx *types.FilterContext, builder investigation.ResourceBuilder, cache *investigation.ChainCache, requiredKeys []string) error {
 	resources, err := builder.WithCluster().Build()
 	if err != nil {
 		logging.Warnf("Could not populate filter context: builder error: %v", err)
@@ -602,35 +834,5 @@ func (c *investigationRunner) populateFilterContextFromOCM(filterCtx *types.Filt
 		return fmt.Errorf("could not populate filter context: cluster not available from builder")
 	}
 
-	cluster := resources.Cluster
-
-	filterCtx.ClusterID = cluster.ID()
-	filterCtx.ClusterName = cluster.Name()
-	filterCtx.ClusterState = string(cluster.State())
-	filterCtx.HCP = cluster.Hypershift() != nil && cluster.Hypershift().Enabled()
-
-	if cp := cluster.CloudProvider(); cp != nil {
-		filterCtx.CloudProvider = cp.ID()
-	}
-
-	// Organization ID requires a subscription lookup — only call if a filter needs it.
-	if slices.Contains(requiredKeys, config.FieldOrganizationID) {
-		orgID, err := c.ocmClient.GetOrganizationID(cluster.ID())
-		if err != nil {
-			return fmt.Errorf("could not populate filter context organization ID: %w", err)
-		}
-		filterCtx.OrganizationID = orgID
-	}
-
-	// Owner ID and email require subscription + account lookups — only call if a filter needs them.
-	if slices.Contains(requiredKeys, config.FieldOwnerID) || slices.Contains(requiredKeys, config.FieldOwnerEmail) {
-		creator, err := c.ocmClient.GetCreatorFromCluster(cluster)
-		if err != nil {
-			return fmt.Errorf("could not populate filter context owner fields: %w", err)
-		}
-		filterCtx.OwnerID = creator.ID()
-		filterCtx.OwnerEmail = creator.Email()
-	}
-
-	return nil
+	return routing.PopulateFilterContext(cache.OCMClient(c.ocmClient), resources.Cluster, filterCtx, requiredKeys)
 }
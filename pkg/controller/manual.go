@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations"
 	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
@@ -25,6 +26,7 @@ var shortNameToInvestigation = map[string]string{
 	"machine-health-check":     "machinehealthcheckunterminatedshortcircuitsre",
 	"must-gather":              "mustgather",
 	"ocmagentresponsefailure":  "ocmagentresponsefailure",
+	"pruning-cronjob-error":    "pruningcronjoberror",
 	"restart-controlplane":     "restartcontrolplane",
 	"upgrade-config":           "upgradeconfigsyncfailureover4hr",
 	"describe-nodes":           "describenodes",
@@ -32,8 +34,9 @@ var shortNameToInvestigation = map[string]string{
 }
 
 type ManualController struct {
-	config CommonConfig
-	manual ManualConfig
+	config    CommonConfig
+	manual    ManualConfig
+	recording *executor.RecordingExecutor // set when the run is recorded as a replay fixture
 	investigationRunner
 }
 
@@ -73,7 +76,7 @@ func (c *ManualController) Investigate(ctx context.Context) error {
 	if inv.Name() != "ccam" && inv.Name() != "precheck" {
 		invEntries = append(invEntries, config.InvestigationEntry{Name: "ccam"})
 	}
-	invEntries = append(invEntries, config.InvestigationEntry{Name: inv.Name()})
+	invEntries = append(invEntries, config.InvestigationEntry{Name: inv.Name(), Params: c.manual.Params})
 
 	alertConfig := &config.AlertConfig{
 		AlertTitle:     inv.Name(),
@@ -90,5 +93,5 @@ func (c *ManualController) Investigate(ctx context.Context) error {
 		}
 	}
 
-	return c.runChain(ctx, c.manual.ClusterId, alertConfig, filterCtx, c.manual.Params)
+	return c.runChain(ctx, c.manual.ClusterId, alertConfig, filterCtx)
 }
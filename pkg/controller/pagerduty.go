@@ -7,11 +7,14 @@ import (
 	"os"
 	"strconv"
 
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
 	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
+	"github.com/openshift/configuration-anomaly-detection/pkg/routing"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 )
 
@@ -27,6 +30,10 @@ func (c *PagerDutyController) Investigate(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	clusterID, err = clusterid.Resolve(c.ocmClient, clusterID)
+	if err != nil {
+		return err
+	}
 
 	// Update logger with cluster ID now that we have it
 	c.logger = logging.InitLogger(c.config.LogLevel, c.config.Identifier, clusterID)
@@ -44,6 +51,8 @@ func (c *PagerDutyController) Investigate(ctx context.Context) error {
 		alertConfig = cfg.GetAlert(alertTitle, experimentalEnabled)
 	}
 
+	c.routeIncident(clusterID, alertConfig)
+
 	// If we matched a config, try running its chain. If the alert-level When
 	// filter rejects, fall through to AI/escalation instead of stopping.
 	if alertConfig != nil {
@@ -52,7 +61,7 @@ func (c *PagerDutyController) Investigate(ctx context.Context) error {
 			AlertTitle:  alertTitle,
 			ServiceName: c.pdClient.GetServiceName(),
 		}
-		err := c.runChain(ctx, clusterID, alertConfig, filterCtx, nil)
+		err := c.runChain(ctx, clusterID, alertConfig, filterCtx)
 		if !errors.Is(err, errAlertFiltered) {
 			return err
 		}
@@ -75,7 +84,7 @@ func (c *PagerDutyController) Investigate(ctx context.Context) error {
 			AlertTitle:  alertTitle,
 			ServiceName: c.pdClient.GetServiceName(),
 		}
-		if err := c.runChain(ctx, clusterID, alertConfig, filterCtx, nil); err != nil {
+		if err := c.runChain(ctx, clusterID, alertConfig, filterCtx); err != nil {
 			return err
 		}
 	}
@@ -86,7 +95,35 @@ func (c *PagerDutyController) Investigate(ctx context.Context) error {
 	return nil
 }
 
-func escalateDocumentationMismatch(docErr *ocm.DocumentationMismatchError, resources *investigation.Resources, notifier incidentNotifier) {
+// routeIncident moves the incident to the escalation policy of the first matching
+// routing rule before any investigation notes or escalations are posted.
+// Routing is best-effort: failures are logged and the investigation continues.
+func (c *PagerDutyController) routeIncident(clusterID string, alertConfig *config.AlertConfig) {
+	engine := routing.NewEngine(c.dependencies.Cfg)
+	if !engine.HasRules() {
+		return
+	}
+
+	cluster, err := c.ocmClient.GetClusterInfo(clusterID)
+	if err != nil {
+		logging.Warnf("Skipping incident routing, could not retrieve cluster %s: %v", clusterID, err)
+		return
+	}
+
+	filterCtx := &types.FilterContext{
+		AlertTitle:  c.pdClient.GetTitle(),
+		ServiceName: c.pdClient.GetServiceName(),
+	}
+	if alertConfig != nil {
+		filterCtx.AlertName = alertConfig.AlertTitle
+	}
+
+	if err := engine.Route(c.pdClient, c.ocmClient, cluster, filterCtx); err != nil {
+		logging.Warnf("Incident routing failed: %v", err)
+	}
+}
+
+func escalateDocumentationMismatch(docErr *ocm.DocumentationMismatchError, resources *investigation.Resources, backend incident.Backend) {
 	message := docErr.EscalationMessage()
 
 	if resources != nil && resources.Notes != nil {
@@ -94,10 +131,10 @@ func escalateDocumentationMismatch(docErr *ocm.DocumentationMismatchError, resou
 		message = resources.Notes.String()
 	}
 
-	if err := notifier.EscalateWithNote(message); err != nil {
-		logging.Errorf("Failed to escalate documentation mismatch notes to PagerDuty: %v", err)
+	if err := backend.EscalateIncidentWithNote(message); err != nil {
+		logging.Errorf("Failed to escalate documentation mismatch notes to incident: %v", err)
 		return
 	}
 
-	logging.Info("Escalated documentation mismatch to PagerDuty")
+	logging.Info("Escalated documentation mismatch to incident")
 }
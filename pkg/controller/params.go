@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+)
+
+// resolveChainParams validates the params of every entry of the chain against the parameter
+// schema of its investigation, and returns them with defaults filled in, by entry index.
+// The whole chain is checked up front, so a typo fails the run before any investigation acts.
+func resolveChainParams(alertConfig *config.AlertConfig) ([]map[string]string, error) {
+	resolved := make([]map[string]string, len(alertConfig.Investigations))
+	for i, entry := range alertConfig.Investigations {
+		inv := investigations.GetInvestigationByName(entry.Name)
+		if inv == nil {
+			return nil, fmt.Errorf("unknown investigation %q for alert %q", entry.Name, alertConfig.AlertTitle)
+		}
+		params, err := investigation.ResolveParams(investigation.Describe(inv).Params, entry.Params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid params for investigation %q of alert %q: %w", entry.Name, alertConfig.AlertTitle, err)
+		}
+		resolved[i] = params
+	}
+	return resolved, nil
+}
+
+// validateConfigParams checks the static params of all alert chains in cfg
+func validateConfigParams(cfg *config.Config) error {
+	for i := range cfg.Alerts {
+		if _, err := resolveChainParams(&cfg.Alerts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvestigationParams returns the parameter schema of the investigation with the given full or short name
+func InvestigationParams(name string) (string, []investigation.Param, error) {
+	inv := investigations.GetInvestigationByName(resolveInvestigationName(name))
+	if inv == nil {
+		return "", nil, fmt.Errorf("unknown investigation: %s", name)
+	}
+	return inv.Name(), investigation.Describe(inv).Params, nil
+}
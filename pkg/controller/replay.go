@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/managedcloud"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/replay"
+)
+
+// ReplayConfig configures an offline replay of a recorded investigation
+type ReplayConfig struct {
+	FixtureDir        string
+	InvestigationName string // Optional, must match the recorded investigation if set
+	Update            bool   // Rewrite the golden file instead of comparing against it
+}
+
+func (r *ReplayConfig) Validate() error {
+	if r.FixtureDir == "" {
+		return fmt.Errorf("FixtureDir can not be empty")
+	}
+	return nil
+}
+
+// ErrReplayMismatch is returned by Replay when the replayed actions differ from the golden file
+var ErrReplayMismatch = errors.New("replayed actions differ from golden file")
+
+// recordInvestigation runs a manual investigation and stores the recorded client traffic and
+// the resulting actions as a replay fixture. The fixture is written even if the investigation fails.
+func recordInvestigation(ctrl Controller, manual ManualConfig, deps *Dependencies, recorder *replay.Recorder) error {
+	investigateErr := ctrl.Investigate(context.Background())
+
+	manualCtrl, ok := ctrl.(*ManualController)
+	if !ok || manualCtrl.recording == nil {
+		return errors.Join(investigateErr, fmt.Errorf("recording is only supported for manual investigations"))
+	}
+
+	fixture := recorder.Fixture(replay.Meta{
+		ClusterID:     manual.ClusterId,
+		Investigation: resolveInvestigationName(manual.InvestigationName),
+		Params:        manual.Params,
+		OCMURL:        deps.OCMURL,
+		BackplaneURL:  deps.BackplaneURL,
+		RecordedAt:    time.Now().UTC(),
+	})
+	if err := fixture.Save(manual.RecordDir); err != nil {
+		return errors.Join(investigateErr, err)
+	}
+	if err := replay.WriteGolden(manual.RecordDir, manualCtrl.recording.Actions()); err != nil {
+		return errors.Join(investigateErr, err)
+	}
+	logging.Infof("Recorded %d exchanges to %s", len(fixture.Exchanges), manual.RecordDir)
+
+	return investigateErr
+}
+
+// Replay reruns a recorded investigation fully offline and compares the resulting actions
+// against the golden file of the fixture. Actions are recorded, never executed.
+func Replay(common CommonConfig, replayCfg ReplayConfig) error {
+	if err := replayCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid replay config: %w", err)
+	}
+
+	fixture, err := replay.Load(replayCfg.FixtureDir)
+	if err != nil {
+		return err
+	}
+	meta := fixture.Meta
+	if replayCfg.InvestigationName != "" && resolveInvestigationName(replayCfg.InvestigationName) != meta.Investigation {
+		return fmt.Errorf("fixture was recorded for investigation %q, not %q", meta.Investigation, replayCfg.InvestigationName)
+	}
+
+	logger := logging.InitLogger(common.LogLevel, common.Identifier, meta.ClusterID)
+	player := replay.NewPlayer(fixture.Exchanges)
+
+	ocmClient, err := ocm.NewOffline(meta.OCMURL, player.Wrap(replay.ClientOCM))
+	if err != nil {
+		return fmt.Errorf("could not initialize offline ocm client: %w", err)
+	}
+	bpClient, err := backplane.NewClient(backplane.Config{
+		OcmClient:               ocmClient,
+		BaseURL:                 meta.BackplaneURL,
+		TransportWrapper:        player.Wrap(replay.ClientBackplane),
+		ClusterTransportWrapper: player.Wrap(replay.ClientCluster),
+	})
+	if err != nil {
+		return fmt.Errorf("could not construct offline backplane-client: %w", err)
+	}
+	managedcloud.SetTransportWrapper(player.Wrap(replay.ClientAWS))
+	managedcloud.SetGCPTransportWrapper(player.Wrap(replay.ClientGCP))
+	managedcloud.SetOffline(true)
+
+	var cfg *config.Config
+	if configPath := common.ConfigPath; configPath != "" {
+		cfg, err = config.LoadConfig(configPath, investigations.GetAvailableInvestigationsNames())
+		if err != nil {
+			return fmt.Errorf("failed to load investigation config: %w", err)
+		}
+	}
+
+	recording := executor.NewRecordingExecutor(nil)
+	ctrl := &ManualController{
+		config: common,
+		manual: ManualConfig{
+			ClusterId:         meta.ClusterID,
+			InvestigationName: meta.Investigation,
+			Params:            meta.Params,
+		},
+		recording: recording,
+		investigationRunner: investigationRunner{
+			ocmClient: ocmClient,
+			bpClient:  bpClient,
+			executor:  recording,
+			logger:    logger,
+			dependencies: &Dependencies{
+				OCMClient:       ocmClient,
+				BackplaneClient: bpClient,
+				OCMURL:          meta.OCMURL,
+				BackplaneURL:    meta.BackplaneURL,
+				Cfg:             cfg,
+			},
+			backend: incident.NewNoop(incident.Metadata{
+				Title:     meta.Investigation,
+				ClusterID: meta.ClusterID,
+			}),
+		},
+	}
+
+	if err := ctrl.Investigate(context.Background()); err != nil {
+		logging.Warnf("Replayed investigation failed: %v", err)
+	}
+	if misses := player.Misses(); len(misses) > 0 {
+		return fmt.Errorf("replay requested %d call(s) missing from the fixture:\n%s", len(misses), strings.Join(misses, "\n"))
+	}
+
+	if replayCfg.Update {
+		if err := replay.WriteGolden(replayCfg.FixtureDir, recording.Actions()); err != nil {
+			return err
+		}
+		logging.Infof("Updated golden file in %s", replayCfg.FixtureDir)
+		return nil
+	}
+
+	diff, err := replay.CompareGolden(replayCfg.FixtureDir, recording.Actions())
+	if err != nil {
+		return err
+	}
+	if diff != "" {
+		return fmt.Errorf("%w:\n%s", ErrReplayMismatch, diff)
+	}
+	logging.Infof("Replayed actions match %s", replay.GoldenFile)
+	return nil
+}
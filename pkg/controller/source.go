@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/source"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+)
+
+// SourceController investigates alerts from non-PagerDuty sources (Alertmanager,
+// generic JSON) that the interceptor normalized into source.Incidents. A payload
+// may hold several alerts, each of them is investigated in turn.
+type SourceController struct {
+	config       CommonConfig
+	incidents    []source.Incident
+	dependencies *Dependencies
+}
+
+func (c *SourceController) Investigate(ctx context.Context) error {
+	var errs []error
+	for _, inc := range c.incidents {
+		if err := c.investigateIncident(ctx, inc); err != nil {
+			logging.Errorf("Investigation of %s alert '%s' (%s) failed: %v", inc.Handle.Source, inc.Handle.ID, inc.Title, err)
+			errs = append(errs, fmt.Errorf("%s alert '%s' (%s): %w", inc.Handle.Source, inc.Handle.ID, inc.Title, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newSourceRunner creates the runner for a single alert. These sources can't be written
+// back to: notes and silences are only logged, and escalations fail the run so the alert
+// isn't left unattended.
+func (c *SourceController) newSourceRunner(inc source.Incident) investigationRunner {
+	logger := logging.InitLogger(c.config.LogLevel, c.config.Identifier, inc.ClusterID)
+	backend := incident.NewReadOnly(incident.Metadata{
+		ID:        inc.Handle.ID,
+		Title:     inc.Title,
+		ServiceID: inc.Handle.Source,
+		ClusterID: inc.ClusterID,
+	})
+	return investigationRunner{
+		ocmClient:    c.dependencies.OCMClient,
+		bpClient:     c.dependencies.BackplaneClient,
+		executor:     executor.NewWebhookExecutor(c.dependencies.OCMClient, backend, c.dependencies.BackplaneClient, logger, c.dependencies.ExecutorOptions()...),
+		logger:       logger,
+		dependencies: c.dependencies,
+		backend:      backend,
+	}
+}
+
+func (c *SourceController) investigateIncident(ctx context.Context, inc source.Incident) error {
+	if inc.ClusterID == "" {
+		return fmt.Errorf("%s alert %q has no cluster ID", inc.Handle.Source, inc.Title)
+	}
+	runner := c.newSourceRunner(inc)
+	clusterID, err := clusterid.Resolve(runner.ocmClient, inc.ClusterID)
+	if err != nil {
+		return err
+	}
+	runner.logger.Infof("Investigating %s alert '%s' (%s)", inc.Handle.Source, inc.Handle.ID, inc.Title)
+
+	if c.dependencies.Cfg == nil {
+		return fmt.Errorf("investigation config is required for %s alerts", inc.Handle.Source)
+	}
+
+	experimentalEnabled, _ := strconv.ParseBool(os.Getenv("CAD_EXPERIMENTAL_ENABLED"))
+	alertConfig := c.dependencies.Cfg.GetAlert(inc.Title, experimentalEnabled)
+	if alertConfig == nil {
+		return fmt.Errorf("no alert config matches %s alert %q", inc.Handle.Source, inc.Title)
+	}
+
+	filterCtx := &types.FilterContext{
+		AlertName:   alertConfig.AlertTitle,
+		AlertTitle:  inc.Title,
+		ServiceName: inc.Handle.Source,
+	}
+	err = runner.runChain(ctx, clusterID, alertConfig, filterCtx)
+	if errors.Is(err, errAlertFiltered) {
+		logging.Infof("Alert %q filtered out, nothing to investigate", alertConfig.AlertTitle)
+		return nil
+	}
+	return err
+}
@@ -266,6 +266,19 @@ func NoteAndReportFrom(nw *notewriter.NoteWriter, clusterID, summary string) []A
 	}
 }
 
+// NoteAndReportWithAttachment is NoteAndReportFrom with an attachment appended to the backplane report only,
+// for output that is too long for the PagerDuty note, e.g. the raw output of a tool
+func NoteAndReportWithAttachment(nw *notewriter.NoteWriter, clusterID, summary, attachmentTitle, attachment string) []Action {
+	if attachment == "" {
+		return NoteAndReportFrom(nw, clusterID, summary)
+	}
+	data := fmt.Sprintf("%s\n%s:\n%s", nw.String(), attachmentTitle, attachment)
+	return []Action{
+		NewBackplaneReportAction(clusterID, fmt.Sprintf("%s : %s", time.Now().UTC().Format(time.RFC3339), summary), data).Build(),
+		NoteFrom(nw),
+	}
+}
+
 // ServiceLog creates a basic service log action
 func ServiceLog(severity, summary, description string) Action {
 	return NewServiceLogAction(severity, summary).
@@ -279,6 +292,11 @@ func LimitedSupport(summary, details, context string) Action {
 	return NewLimitedSupportAction(summary, details, context).Build()
 }
 
+// Remediation creates a cluster remediation action
+func Remediation(summary, plan string, remediate RemediateFunc) Action {
+	return &ClusterRemediationAction{Summary: summary, Plan: plan, Remediate: remediate}
+}
+
 // Note creates a PagerDuty note action
 func Note(content string) Action {
 	return NewPagerDutyNoteAction(content).Build()
@@ -28,6 +28,7 @@ const (
 	ActionTypeSilenceIncident      ActionType = "silence_incident"
 	ActionTypeEscalateIncident     ActionType = "escalate_incident"
 	ActionTypeBackplaneReport      ActionType = "backplane_report"
+	ActionTypeClusterRemediation   ActionType = "cluster_remediation"
 )
 
 // ServiceLogAction sends a service log via OCM
@@ -176,12 +177,17 @@ func (a *PagerDutyNoteAction) Validate() error {
 }
 
 func (a *PagerDutyNoteAction) Execute(ctx context.Context, execCtx *ExecutionContext) error {
-	content := a.Content
+	content := a.ResolvedContent()
+	execCtx.Logger.Infof("Adding PagerDuty note (%d chars)", len(content))
+	return execCtx.Incident.AddNote(content)
+}
+
+// ResolvedContent returns the note content as it would be sent at this point in time
+func (a *PagerDutyNoteAction) ResolvedContent() string {
 	if a.noteWriter != nil {
-		content = a.noteWriter.String()
+		return a.noteWriter.String()
 	}
-	execCtx.Logger.Infof("Adding PagerDuty note (%d chars)", len(content))
-	return execCtx.PDClient.AddNote(content)
+	return a.Content
 }
 
 // SilenceIncidentAction silences the current PagerDuty incident
@@ -204,7 +210,7 @@ func (a *SilenceIncidentAction) Validate() error {
 
 func (a *SilenceIncidentAction) Execute(ctx context.Context, execCtx *ExecutionContext) error {
 	execCtx.Logger.Infof("Silencing incident: %s", a.Reason)
-	return execCtx.PDClient.SilenceIncident()
+	return execCtx.Incident.SilenceIncident()
 }
 
 // EscalateIncidentAction escalates the current PagerDuty incident
@@ -227,7 +233,7 @@ func (a *EscalateIncidentAction) Validate() error {
 
 func (a *EscalateIncidentAction) Execute(ctx context.Context, execCtx *ExecutionContext) error {
 	execCtx.Logger.Infof("Escalating incident: %s", a.Reason)
-	return execCtx.PDClient.EscalateIncident()
+	return execCtx.Incident.EscalateIncident()
 }
 
 // BackplaneReport is the interface for cluster report payloads
@@ -315,6 +321,55 @@ func (a *BackplaneReportAction) GenerateStringForNoteWriter() string {
 		"osdctl cluster reports get --cluster-id %s --report-id %s", a.createdReport.ClusterID, a.createdReport.ReportID)
 }
 
+// RemediateFunc changes the cluster and records the changes it made in notes
+type RemediateFunc func(ctx context.Context, notes *notewriter.NoteWriter) error
+
+// ClusterRemediationAction changes the cluster to remediate what an investigation found.
+// Like all actions it is skipped in dry-run mode, so investigations only plan remediations
+// and leave the changes to the action.
+type ClusterRemediationAction struct {
+	// Summary is a brief description of the remediation
+	Summary string
+
+	// Plan lists the planned changes (for logging and recordings)
+	Plan string
+
+	// Remediate performs the changes
+	Remediate RemediateFunc
+}
+
+func (a *ClusterRemediationAction) Type() string {
+	return string(ActionTypeClusterRemediation)
+}
+
+func (a *ClusterRemediationAction) ActionType() ActionType {
+	return ActionTypeClusterRemediation
+}
+
+func (a *ClusterRemediationAction) Validate() error {
+	if a.Summary == "" {
+		return fmt.Errorf("summary is required")
+	}
+	if a.Remediate == nil {
+		return fmt.Errorf("remediate function is required")
+	}
+	return nil
+}
+
+func (a *ClusterRemediationAction) Execute(ctx context.Context, execCtx *ExecutionContext) error {
+	execCtx.Logger.Infof("Running cluster remediation: %s", a.Summary)
+
+	notes := execCtx.Notes
+	if notes == nil {
+		notes = notewriter.New(execCtx.InvestigationName, execCtx.Logger)
+	}
+	if err := a.Remediate(ctx, notes); err != nil {
+		notes.AppendWarning("Remediation '%s' failed: %v", a.Summary, err)
+		return err
+	}
+	return nil
+}
+
 type PagerDutyTitleUpdate struct {
 	Prefix string
 }
@@ -337,12 +392,12 @@ func (a *PagerDutyTitleUpdate) Validate() error {
 func (a *PagerDutyTitleUpdate) Execute(ctx context.Context, execCtx *ExecutionContext) error {
 	execCtx.Logger.Infof("Updating pagerduty title with prefix: %s", a.Prefix)
 
-	currentTitle := execCtx.PDClient.GetTitle()
+	currentTitle := execCtx.Incident.GetTitle()
 	if strings.Contains(currentTitle, a.Prefix) {
 		return nil
 	}
 	newTitle := fmt.Sprintf("%s %s", a.Prefix, currentTitle)
-	err := execCtx.PDClient.UpdateIncidentTitle(newTitle)
+	err := execCtx.Incident.UpdateIncidentTitle(newTitle)
 	if err != nil {
 		return fmt.Errorf("failed to update PagerDuty incident title: %w", err)
 	}
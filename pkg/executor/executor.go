@@ -31,7 +31,7 @@ func (e *DefaultExecutor) executeSequential(
 		actionExecCtx := &ExecutionContext{
 			Cluster:           execCtx.Cluster,
 			OCMClient:         execCtx.OCMClient,
-			PDClient:          execCtx.PDClient,
+			Incident:          execCtx.Incident,
 			BackplaneClient:   execCtx.BackplaneClient,
 			Notes:             execCtx.Notes,
 			InvestigationName: execCtx.InvestigationName,
@@ -86,9 +86,10 @@ func (e *DefaultExecutor) executeConcurrent(
 	}
 
 	var (
-		pdActions  []actionWithIndex
-		ocmActions []actionWithIndex
-		bpActions  []actionWithIndex
+		remediationActions []actionWithIndex
+		pdActions          []actionWithIndex
+		ocmActions         []actionWithIndex
+		bpActions          []actionWithIndex
 	)
 
 	for i, action := range actions {
@@ -100,11 +101,16 @@ func (e *DefaultExecutor) executeConcurrent(
 			ocmActions = append(ocmActions, actionWithIndex{action, i})
 		case string(ActionTypeBackplaneReport):
 			bpActions = append(bpActions, actionWithIndex{action, i})
+		case string(ActionTypeClusterRemediation):
+			remediationActions = append(remediationActions, actionWithIndex{action, i})
 		}
 	}
 
 	// If dry-run mode, just log what would be executed
 	if opts.DryRun {
+		for _, a := range remediationActions {
+			logDryRunAction(a.action, execCtx.Logger)
+		}
 		for _, a := range pdActions {
 			logDryRunAction(a.action, execCtx.Logger)
 		}
@@ -119,6 +125,21 @@ func (e *DefaultExecutor) executeConcurrent(
 
 	errorsChan := make(chan error, len(actions))
 
+	// Phase 0: Execute cluster remediations sequentially (in original order)
+	// before all other actions, so the notes contain what they changed.
+	for _, a := range remediationActions {
+		if err := e.executeWithRetry(ctx, a.action, execCtx, opts.MaxRetries); err != nil {
+			errorsChan <- ActionExecutionError{
+				ActionType: ActionType(a.action.Type()),
+				Attempt:    opts.MaxRetries + 1,
+				Err:        err,
+			}
+			if opts.StopOnError {
+				break
+			}
+		}
+	}
+
 	// Phase 1: Execute Backplane and OCM actions in parallel.
 	// Backplane actions must complete before PD actions because
 	// BackplaneReportAction appends report links to the NoteWriter
@@ -217,8 +238,9 @@ func (e *DefaultExecutor) executeWithRetry(
 			return nil
 		}
 
-		// Check if error is retryable
-		if !isRetryable(lastErr) {
+		// Check if error is retryable. Remediations change the cluster step by step
+		// and stop on the first failed step, so they are not repeated.
+		if !isRetryable(lastErr) || action.Type() == string(ActionTypeClusterRemediation) {
 			execCtx.Logger.Warnf("Action %s failed with non-retryable error: %v",
 				action.Type(), lastErr)
 			return lastErr
@@ -302,6 +324,9 @@ func logDryRunAction(action Action, logger *zap.SugaredLogger) {
 	case *BackplaneReportAction:
 		logger.Infof("DRY RUN: Would create backplane report - ClusterID: %s, Summary: %s",
 			a.ClusterID, a.Summary)
+	case *ClusterRemediationAction:
+		logger.Infof("DRY RUN: Would run cluster remediation - Summary: %s, Plan: %s",
+			a.Summary, a.Plan)
 	default:
 		logger.Infof("DRY RUN: Would execute action %s", action.Type())
 	}
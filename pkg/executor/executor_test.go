@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	bpmock "github.com/openshift/configuration-anomaly-detection/pkg/backplane/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
 	pdmock "github.com/openshift/configuration-anomaly-detection/pkg/pagerduty/mock"
 )
@@ -826,3 +827,356 @@ func TestWebhookExecutor_DryRunMode_ConcurrentActions(t *testing.T) {
 	assert.False(t, silenceExecuted, "Silence should NOT execute in dry-run mode")
 	assert.False(t, backplaneExecuted, "Backplane report should NOT execute in dry-run mode")
 }
+
+func TestCorrelatedIncidentExecutor_SkipsClusterActions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOCMClient := ocmmock.NewMockClient(ctrl)
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	mockBPClient := &bpmock.MockClient{}
+	logger := zap.NewNop().Sugar()
+
+	mockPDClient.EXPECT().AddNote(gomock.Any()).DoAndReturn(func(note string) error {
+		assert.Contains(t, note, "storm-abc")
+		assert.Contains(t, note, "Limited Support, ServiceLog, Silence and Cluster remediation")
+		return nil
+	})
+	mockPDClient.EXPECT().EscalateIncident().Return(nil)
+
+	inner := NewWebhookExecutor(mockOCMClient, mockPDClient, mockBPClient, logger)
+	exec := NewCorrelatedIncidentExecutor(inner, logger, "storm-abc")
+
+	limitedSupportExecuted := false
+	serviceLogExecuted := false
+	silenceExecuted := false
+	remediationExecuted := false
+
+	actions := []Action{
+		&mockAction{actionType: ActionTypeLimitedSupport, executed: &limitedSupportExecuted},
+		&mockAction{actionType: ActionTypeServiceLog, executed: &serviceLogExecuted},
+		&mockAction{actionType: ActionTypeSilenceIncident, executed: &silenceExecuted},
+		&mockAction{actionType: ActionTypeClusterRemediation, executed: &remediationExecuted},
+	}
+
+	cluster, _ := cmv1.NewCluster().ID("test-cluster").Build()
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions:           actions,
+		Cluster:           cluster,
+		Options: ExecutionOptions{
+			ConcurrentActions: false,
+		},
+	}
+
+	err := exec.Execute(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.False(t, limitedSupportExecuted, "LimitedSupport should be skipped for correlated incidents")
+	assert.False(t, serviceLogExecuted, "ServiceLog should be skipped for correlated incidents")
+	assert.False(t, silenceExecuted, "Silence should be skipped for correlated incidents")
+	assert.False(t, remediationExecuted, "Cluster remediation should be skipped for correlated incidents")
+}
+
+func TestCorrelatedIncidentExecutor_KeepsExistingEscalation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOCMClient := ocmmock.NewMockClient(ctrl)
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	mockBPClient := &bpmock.MockClient{}
+	logger := zap.NewNop().Sugar()
+
+	mockPDClient.EXPECT().AddNote(gomock.Any()).Return(nil)
+
+	inner := NewWebhookExecutor(mockOCMClient, mockPDClient, mockBPClient, logger)
+	exec := NewCorrelatedIncidentExecutor(inner, logger, "storm-abc")
+
+	limitedSupportExecuted := false
+	escalateExecuted := false
+
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{
+			&mockAction{actionType: ActionTypeLimitedSupport, executed: &limitedSupportExecuted},
+			&mockAction{actionType: ActionTypeEscalateIncident, executed: &escalateExecuted},
+		},
+	}
+
+	err := exec.Execute(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.False(t, limitedSupportExecuted, "LimitedSupport should be skipped for correlated incidents")
+	assert.True(t, escalateExecuted, "The existing escalation should be used instead of adding a second one")
+}
+
+func TestCorrelatedIncidentExecutor_PassesThroughWithoutNote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOCMClient := ocmmock.NewMockClient(ctrl)
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	mockBPClient := &bpmock.MockClient{}
+	logger := zap.NewNop().Sugar()
+
+	inner := NewWebhookExecutor(mockOCMClient, mockPDClient, mockBPClient, logger)
+	exec := NewCorrelatedIncidentExecutor(inner, logger, "storm-abc")
+
+	escalateExecuted := false
+
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{
+			&mockAction{actionType: ActionTypeEscalateIncident, executed: &escalateExecuted},
+		},
+	}
+
+	err := exec.Execute(context.Background(), input)
+
+	assert.NoError(t, err)
+	assert.True(t, escalateExecuted, "Escalate should execute normally")
+}
+
+func TestRecordingExecutor_RecordsAndDelegates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOCMClient := ocmmock.NewMockClient(ctrl)
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	mockBPClient := &bpmock.MockClient{}
+	logger := zap.NewNop().Sugar()
+
+	inner := NewWebhookExecutor(mockOCMClient, mockPDClient, mockBPClient, logger)
+	exec := NewRecordingExecutor(inner)
+
+	silenceExecuted := false
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{
+			&mockAction{actionType: ActionTypeSilenceIncident, executed: &silenceExecuted},
+			&EscalateIncidentAction{Reason: "needs a human"},
+		},
+	}
+	mockPDClient.EXPECT().EscalateIncident().Return(nil)
+
+	err := exec.Execute(context.Background(), input)
+
+	require.NoError(t, err)
+	assert.True(t, silenceExecuted, "actions should be passed to the inner executor")
+	assert.Equal(t, []RecordedAction{
+		{Investigation: "test-investigation", Type: string(ActionTypeSilenceIncident)},
+		{Investigation: "test-investigation", Type: string(ActionTypeEscalateIncident), Summary: "needs a human"},
+	}, exec.Actions())
+}
+
+func TestRecordingExecutor_WithoutInner(t *testing.T) {
+	exec := NewRecordingExecutor(nil)
+
+	executed := false
+	err := exec.Execute(context.Background(), &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{
+			&mockAction{actionType: ActionTypeServiceLog, executed: &executed},
+			&PagerDutyNoteAction{Content: "note"},
+			&BackplaneReportAction{ClusterID: "c", Summary: "2026-01-02T03:04:05Z : test-investigation", Data: "data"},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.False(t, executed, "actions should only be recorded")
+	require.Len(t, exec.Actions(), 3)
+	assert.Equal(t, "note", exec.Actions()[1].Details)
+	assert.Equal(t, "test-investigation", exec.Actions()[2].Summary, "report timestamps should not be recorded")
+	assert.Error(t, exec.Execute(context.Background(), nil))
+}
+
+func TestWebhookExecutor_RunsRemediationBeforeNotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOCMClient := ocmmock.NewMockClient(ctrl)
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	mockBPClient := &bpmock.MockClient{}
+	logger := zap.NewNop().Sugar()
+
+	nw := notewriter.New("test-investigation", logger)
+	mockPDClient.EXPECT().AddNote(gomock.Any()).DoAndReturn(func(content string) error {
+		assert.Contains(t, content, "Defragmented member-1", "The note should contain what the remediation changed")
+		return nil
+	})
+
+	exec := NewWebhookExecutor(mockOCMClient, mockPDClient, mockBPClient, logger, WithClusterRemediation())
+
+	actions := []Action{
+		NoteFrom(nw),
+		Remediation("Defragment etcd", "Defragment member-1", func(ctx context.Context, notes *notewriter.NoteWriter) error {
+			notes.AppendAutomation("Defragmented member-1")
+			return nil
+		}),
+	}
+
+	cluster, _ := cmv1.NewCluster().ID("test-cluster").Build()
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions:           actions,
+		Cluster:           cluster,
+		Notes:             nw,
+		Options: ExecutionOptions{
+			MaxRetries:        0,
+			ConcurrentActions: true,
+		},
+	}
+
+	assert.NoError(t, exec.Execute(context.Background(), input))
+}
+
+func TestWebhookExecutor_DryRunMode_SkipsRemediation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := zap.NewNop().Sugar()
+	exec := NewWebhookExecutor(ocmmock.NewMockClient(ctrl), pdmock.NewMockClient(ctrl), &bpmock.MockClient{}, logger, WithClusterRemediation())
+
+	for _, concurrent := range []bool{true, false} {
+		remediated := false
+		cluster, _ := cmv1.NewCluster().ID("test-cluster").Build()
+		input := &ExecutorInput{
+			InvestigationName: "test-investigation",
+			Actions: []Action{Remediation("Defragment etcd", "Defragment member-1", func(ctx context.Context, notes *notewriter.NoteWriter) error {
+				remediated = true
+				return nil
+			})},
+			Cluster: cluster,
+			Options: ExecutionOptions{
+				DryRun:            true,
+				ConcurrentActions: concurrent,
+			},
+		}
+
+		assert.NoError(t, exec.Execute(context.Background(), input))
+		assert.False(t, remediated, "Remediation should NOT run in dry-run mode (concurrent: %v)", concurrent)
+	}
+}
+
+func TestWebhookExecutor_DoesNotRetryRemediation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := zap.NewNop().Sugar()
+	exec := NewWebhookExecutor(ocmmock.NewMockClient(ctrl), pdmock.NewMockClient(ctrl), &bpmock.MockClient{}, logger, WithClusterRemediation())
+
+	attempts := 0
+	cluster, _ := cmv1.NewCluster().ID("test-cluster").Build()
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{Remediation("Defragment etcd", "Defragment member-1", func(ctx context.Context, notes *notewriter.NoteWriter) error {
+			attempts++
+			return errors.New("context deadline exceeded: timeout")
+		})},
+		Cluster: cluster,
+		Options: ExecutionOptions{
+			MaxRetries:        3,
+			ConcurrentActions: true,
+		},
+	}
+
+	assert.Error(t, exec.Execute(context.Background(), input))
+	assert.Equal(t, 1, attempts, "A failed remediation should not be retried")
+}
+
+func TestInfraClusterExecutor_InterceptsRemediation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	logger := zap.NewNop().Sugar()
+
+	mockPDClient.EXPECT().AddNote(gomock.Any()).Return(nil)
+	mockPDClient.EXPECT().EscalateIncident().Return(nil)
+
+	inner := NewWebhookExecutor(ocmmock.NewMockClient(ctrl), mockPDClient, &bpmock.MockClient{}, logger, WithClusterRemediation())
+	exec := NewInfraClusterExecutor(inner, logger, false)
+
+	remediated := false
+	cluster, _ := cmv1.NewCluster().ID("test-infra-cluster").Build()
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{Remediation("Defragment etcd", "Defragment member-1", func(ctx context.Context, notes *notewriter.NoteWriter) error {
+			remediated = true
+			return nil
+		})},
+		Cluster: cluster,
+		Options: ExecutionOptions{
+			ConcurrentActions: false,
+		},
+	}
+
+	assert.NoError(t, exec.Execute(context.Background(), input))
+	assert.False(t, remediated, "Remediation should be intercepted on infra cluster")
+}
+
+func TestWebhookExecutor_GatesRemediationWithoutOptIn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPDClient := pdmock.NewMockClient(ctrl)
+	logger := zap.NewNop().Sugar()
+
+	mockPDClient.EXPECT().AddNote(gomock.Any()).DoAndReturn(func(note string) error {
+		assert.Contains(t, note, "Cluster remediation is not enabled")
+		assert.Contains(t, note, "Defragment etcd")
+		return nil
+	})
+	mockPDClient.EXPECT().EscalateIncident().Return(nil)
+
+	exec := NewWebhookExecutor(ocmmock.NewMockClient(ctrl), mockPDClient, &bpmock.MockClient{}, logger)
+
+	remediated := false
+	cluster, _ := cmv1.NewCluster().ID("test-cluster").Build()
+	input := &ExecutorInput{
+		InvestigationName: "test-investigation",
+		Actions: []Action{Remediation("Defragment etcd", "Defragment member-1", func(ctx context.Context, notes *notewriter.NoteWriter) error {
+			remediated = true
+			return nil
+		})},
+		Cluster: cluster,
+		Options: ExecutionOptions{
+			ConcurrentActions: true,
+		},
+	}
+
+	assert.NoError(t, exec.Execute(context.Background(), input))
+	assert.False(t, remediated, "Remediation should not run without opting in")
+}
+
+func TestManualExecutor_GatesRemediation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := zap.NewNop().Sugar()
+
+	for _, optIn := range []bool{false, true} {
+		var opts []Option
+		if optIn {
+			opts = append(opts, WithClusterRemediation())
+		}
+		exec := NewManualExecutor(ocmmock.NewMockClient(ctrl), &bpmock.MockClient{}, logger, opts...)
+
+		remediated := false
+		cluster, _ := cmv1.NewCluster().ID("test-cluster").Build()
+		input := &ExecutorInput{
+			InvestigationName: "test-investigation",
+			Actions: []Action{Remediation("Defragment etcd", "Defragment member-1", func(ctx context.Context, notes *notewriter.NoteWriter) error {
+				remediated = true
+				return nil
+			})},
+			Cluster: cluster,
+			Options: ExecutionOptions{
+				ConcurrentActions: true,
+			},
+		}
+
+		assert.NoError(t, exec.Execute(context.Background(), input))
+		assert.Equal(t, optIn, remediated, "Manual runs should only remediate when opted in")
+	}
+}
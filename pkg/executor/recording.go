@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedAction is a comparable summary of an action handed to a RecordingExecutor
+type RecordedAction struct {
+	Investigation string `json:"investigation"`
+	Type          string `json:"type"`
+	Summary       string `json:"summary,omitempty"`
+	Details       string `json:"details,omitempty"`
+}
+
+// RecordingExecutor records every action it is asked to execute before passing them on to an
+// inner executor. It backs the golden action files of offline replays.
+type RecordingExecutor struct {
+	inner   Executor
+	mu      sync.Mutex
+	actions []RecordedAction
+}
+
+// NewRecordingExecutor creates a RecordingExecutor. If inner is nil, actions are only recorded.
+func NewRecordingExecutor(inner Executor) *RecordingExecutor {
+	return &RecordingExecutor{inner: inner}
+}
+
+func (e *RecordingExecutor) Execute(ctx context.Context, input *ExecutorInput) error {
+	if input == nil {
+		return fmt.Errorf("ExecutorInput cannot be nil")
+	}
+
+	e.mu.Lock()
+	for _, action := range input.Actions {
+		e.actions = append(e.actions, recordAction(input.InvestigationName, action))
+	}
+	e.mu.Unlock()
+
+	if e.inner == nil {
+		return nil
+	}
+	return e.inner.Execute(ctx, input)
+}
+
+// Actions returns the actions recorded so far, in execution order
+func (e *RecordingExecutor) Actions() []RecordedAction {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]RecordedAction{}, e.actions...)
+}
+
+func recordAction(investigationName string, action Action) RecordedAction {
+	recorded := RecordedAction{Investigation: investigationName, Type: action.Type()}
+	switch a := action.(type) {
+	case *ServiceLogAction:
+		if a.ServiceLog != nil {
+			recorded.Summary = a.ServiceLog.Summary
+			recorded.Details = a.ServiceLog.Description
+		}
+	case *LimitedSupportAction:
+		if a.Reason != nil {
+			recorded.Summary = a.Reason.Summary
+			recorded.Details = a.Reason.Details
+		}
+	case *PagerDutyNoteAction:
+		recorded.Details = a.ResolvedContent()
+	case *SilenceIncidentAction:
+		recorded.Summary = a.Reason
+	case *EscalateIncidentAction:
+		recorded.Summary = a.Reason
+	case *BackplaneReportAction:
+		recorded.Summary = stripReportTimestamp(a.Summary)
+		recorded.Details = a.Data
+	case *PagerDutyTitleUpdate:
+		recorded.Summary = a.Prefix
+	case *ClusterRemediationAction:
+		recorded.Summary = a.Summary
+		recorded.Details = a.Plan
+	}
+	return recorded
+}
+
+// stripReportTimestamp removes the creation timestamp NoteAndReportFrom prepends to report
+// summaries, so recordings of the same run compare equal
+func stripReportTimestamp(summary string) string {
+	timestamp, rest, found := strings.Cut(summary, " : ")
+	if !found {
+		return summary
+	}
+	if _, err := time.Parse(time.RFC3339, timestamp); err != nil {
+		return summary
+	}
+	return rest
+}
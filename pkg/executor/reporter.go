@@ -6,9 +6,9 @@ import (
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
-	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
 	"go.uber.org/zap"
 )
 
@@ -54,28 +54,51 @@ type ExecutionOptions struct {
 // DefaultExecutor is the production implementation of Executor
 type DefaultExecutor struct {
 	ocmClient       ocm.Client
-	pdClient        pagerduty.Client
+	incident        incident.Backend
 	backplaneClient backplane.Client
 
 	logger *zap.SugaredLogger
+
+	// clusterRemediation allows cluster remediation actions to run, see WithClusterRemediation
+	clusterRemediation bool
+}
+
+// Option configures an executor
+type Option func(*DefaultExecutor)
+
+// WithClusterRemediation opts the executor in to running cluster remediation actions.
+// Remediations change the customer's cluster, so without it they are replaced with a
+// note and an escalation.
+func WithClusterRemediation() Option {
+	return func(e *DefaultExecutor) {
+		e.clusterRemediation = true
+	}
+}
+
+func newDefaultExecutor(ocmClient ocm.Client, backend incident.Backend, bpClient backplane.Client, logger *zap.SugaredLogger, opts []Option) *DefaultExecutor {
+	e := &DefaultExecutor{
+		ocmClient:       ocmClient,
+		incident:        backend,
+		backplaneClient: bpClient,
+		logger:          logger,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
-// WebhookExecutor executes all actions including PagerDuty actions
+// WebhookExecutor executes all actions including incident actions
 // Used for webhook-triggered investigations
 type WebhookExecutor struct {
 	*DefaultExecutor
 }
 
 // NewWebhookExecutor creates an executor for webhook-triggered investigations
-// Executes all action types including PagerDuty actions
-func NewWebhookExecutor(ocmClient ocm.Client, pdClient pagerduty.Client, bpClient backplane.Client, logger *zap.SugaredLogger) Executor {
+// Executes all action types including incident actions (notes, silence, escalate, title)
+func NewWebhookExecutor(ocmClient ocm.Client, backend incident.Backend, bpClient backplane.Client, logger *zap.SugaredLogger, opts ...Option) Executor {
 	return &WebhookExecutor{
-		DefaultExecutor: &DefaultExecutor{
-			ocmClient:       ocmClient,
-			pdClient:        pdClient,
-			backplaneClient: bpClient,
-			logger:          logger,
-		},
+		DefaultExecutor: newDefaultExecutor(ocmClient, backend, bpClient, logger, opts),
 	}
 }
 
@@ -87,14 +110,10 @@ type ManualExecutor struct {
 
 // NewManualExecutor creates an executor for manual investigations
 // Filters out PagerDuty actions (notes, silence, escalate) since there's no incident
-func NewManualExecutor(ocmClient ocm.Client, bpClient backplane.Client, logger *zap.SugaredLogger) Executor {
+func NewManualExecutor(ocmClient ocm.Client, bpClient backplane.Client, logger *zap.SugaredLogger, opts ...Option) Executor {
 	return &ManualExecutor{
-		DefaultExecutor: &DefaultExecutor{
-			ocmClient:       ocmClient,
-			pdClient:        nil, // No PD client for manual runs
-			backplaneClient: bpClient,
-			logger:          logger,
-		},
+		// No incident for manual runs
+		DefaultExecutor: newDefaultExecutor(ocmClient, nil, bpClient, logger, opts),
 	}
 }
 
@@ -109,13 +128,21 @@ func (e *ManualExecutor) Execute(ctx context.Context, input *ExecutorInput) erro
 		return nil
 	}
 
+	// Gate remediations first, so their replacement escalation is reported below
+	actions := e.gateClusterRemediation(input.Actions)
+
 	// Filter out PagerDuty actions
-	filteredActions := make([]Action, 0, len(input.Actions))
+	filteredActions := make([]Action, 0, len(actions))
 	skippedCount := 0
 
-	for _, action := range input.Actions {
+	for _, action := range actions {
 		if isPagerDutyAction(action) {
-			e.logger.Infof("Skipping PagerDuty action in manual mode: %s", action.Type())
+			if escalation, ok := action.(*EscalateIncidentAction); ok {
+				// Nobody is paged for manual runs, so the operator has to act on the escalation
+				e.logger.Warnf("Investigation escalated, please investigate manually: %s", escalation.Reason)
+			} else {
+				e.logger.Infof("Skipping PagerDuty action in manual mode: %s", action.Type())
+			}
 			skippedCount++
 			continue
 		}
@@ -154,7 +181,7 @@ func isPagerDutyAction(action Action) bool {
 
 // InfraClusterExecutor wraps another executor and transforms actions that should not
 // be performed on infrastructure clusters (hive, management, or service clusters).
-// Limited Support, Silence, and SL actions are replaced with an escalation and
+// Limited Support, Silence, SL and cluster remediation actions are replaced with an escalation and
 // a PagerDuty note explaining the substitution.
 type InfraClusterExecutor struct {
 	inner     Executor
@@ -200,6 +227,11 @@ func (e *InfraClusterExecutor) Execute(ctx context.Context, input *ExecutorInput
 			interceptedDescriptions = append(interceptedDescriptions, "ServiceLog")
 			needsEscalation = true
 
+		case string(ActionTypeClusterRemediation):
+			e.logger.Infof("Infrastructure cluster: intercepting ClusterRemediation action")
+			interceptedDescriptions = append(interceptedDescriptions, "Cluster remediation")
+			needsEscalation = true
+
 		default:
 			if action.Type() == string(ActionTypeEscalateIncident) {
 				hasEscalation = true
@@ -238,6 +270,76 @@ func (e *InfraClusterExecutor) Execute(ctx context.Context, input *ExecutorInput
 	return e.inner.Execute(ctx, &filteredInput)
 }
 
+// CorrelatedIncidentExecutor wraps another executor for incidents that the interceptor
+// tagged as part of an incident storm. Limited Support, Silence, SL and cluster remediation
+// actions are dropped, as the cause is most likely shared and not specific to the customer's
+// cluster. A PagerDuty note records what was skipped and the incident is escalated.
+type CorrelatedIncidentExecutor struct {
+	inner         Executor
+	logger        *zap.SugaredLogger
+	correlationID string
+}
+
+// NewCorrelatedIncidentExecutor creates an executor that skips per-cluster actions
+// for incidents correlated under correlationID.
+func NewCorrelatedIncidentExecutor(inner Executor, logger *zap.SugaredLogger, correlationID string) Executor {
+	return &CorrelatedIncidentExecutor{inner: inner, logger: logger, correlationID: correlationID}
+}
+
+func (e *CorrelatedIncidentExecutor) Execute(ctx context.Context, input *ExecutorInput) error {
+	if input == nil {
+		return fmt.Errorf("ExecutorInput cannot be nil")
+	}
+
+	filteredActions := make([]Action, 0, len(input.Actions))
+	hasEscalation := false
+	var skippedDescriptions []string
+
+	for _, action := range input.Actions {
+		switch action.Type() {
+		case string(ActionTypeLimitedSupport):
+			e.logger.Infof("Correlated incident %s: skipping LimitedSupport action", e.correlationID)
+			skippedDescriptions = append(skippedDescriptions, "Limited Support")
+
+		case string(ActionTypeSilenceIncident):
+			e.logger.Infof("Correlated incident %s: skipping Silence action", e.correlationID)
+			skippedDescriptions = append(skippedDescriptions, "Silence")
+
+		case string(ActionTypeServiceLog):
+			e.logger.Infof("Correlated incident %s: skipping ServiceLog action", e.correlationID)
+			skippedDescriptions = append(skippedDescriptions, "ServiceLog")
+
+		case string(ActionTypeClusterRemediation):
+			e.logger.Infof("Correlated incident %s: skipping ClusterRemediation action", e.correlationID)
+			skippedDescriptions = append(skippedDescriptions, "Cluster remediation")
+
+		default:
+			if action.Type() == string(ActionTypeEscalateIncident) {
+				hasEscalation = true
+			}
+			filteredActions = append(filteredActions, action)
+		}
+	}
+
+	if len(skippedDescriptions) > 0 {
+		filteredActions = append(filteredActions, &PagerDutyNoteAction{Content: fmt.Sprintf(
+			"⚠️ This incident is part of wider event %s: the following action(s) were not executed: %s. "+
+				"Please verify the shared cause before taking per-cluster action.",
+			e.correlationID, joinDescriptions(skippedDescriptions),
+		)})
+		if !hasEscalation {
+			filteredActions = append(
+				filteredActions,
+				&EscalateIncidentAction{Reason: fmt.Sprintf("Correlated incident %s: actions skipped", e.correlationID)},
+			)
+		}
+	}
+
+	filteredInput := *input
+	filteredInput.Actions = filteredActions
+	return e.inner.Execute(ctx, &filteredInput)
+}
+
 // joinDescriptions joins action type descriptions for the PD note
 func joinDescriptions(descriptions []string) string {
 	seen := make(map[string]bool)
@@ -286,11 +388,13 @@ func (e *DefaultExecutor) Execute(ctx context.Context, input *ExecutorInput) err
 		opts.MaxRetries = 3 // Default retry count
 	}
 
+	actions := e.gateClusterRemediation(input.Actions)
+
 	e.logger.Infof("Executing %d actions for investigation %s",
-		len(input.Actions), input.InvestigationName)
+		len(actions), input.InvestigationName)
 
 	// Validate all actions first
-	for i, action := range input.Actions {
+	for i, action := range actions {
 		if err := action.Validate(); err != nil {
 			return ActionValidationError{
 				ActionType: ActionType(action.Type()),
@@ -303,7 +407,7 @@ func (e *DefaultExecutor) Execute(ctx context.Context, input *ExecutorInput) err
 	execCtx := &ExecutionContext{
 		Cluster:           input.Cluster,
 		OCMClient:         e.ocmClient,
-		PDClient:          e.pdClient,
+		Incident:          e.incident,
 		BackplaneClient:   e.backplaneClient,
 		Notes:             input.Notes,
 		InvestigationName: input.InvestigationName,
@@ -312,7 +416,47 @@ func (e *DefaultExecutor) Execute(ctx context.Context, input *ExecutorInput) err
 
 	// Execute actions
 	if opts.ConcurrentActions {
-		return e.executeConcurrent(ctx, input.Actions, execCtx, opts)
+		return e.executeConcurrent(ctx, actions, execCtx, opts)
+	}
+	return e.executeSequential(ctx, actions, execCtx, opts)
+}
+
+// gateClusterRemediation replaces cluster remediation actions with a note and an escalation,
+// unless the executor opted in to them with WithClusterRemediation.
+func (e *DefaultExecutor) gateClusterRemediation(actions []Action) []Action {
+	if e.clusterRemediation {
+		return actions
+	}
+
+	gated := make([]Action, 0, len(actions))
+	hasEscalation := false
+	var skipped []string
+	for _, action := range actions {
+		switch action.Type() {
+		case string(ActionTypeClusterRemediation):
+			summary := "Cluster remediation"
+			if remediation, ok := action.(*ClusterRemediationAction); ok {
+				summary = remediation.Summary
+			}
+			e.logger.Infof("Cluster remediation is not enabled: skipping remediation %q", summary)
+			skipped = append(skipped, summary)
+			continue
+		case string(ActionTypeEscalateIncident):
+			hasEscalation = true
+		}
+		gated = append(gated, action)
+	}
+	if len(skipped) == 0 {
+		return actions
+	}
+
+	gated = append(gated, &PagerDutyNoteAction{Content: fmt.Sprintf(
+		"⚠️ Cluster remediation is not enabled for CAD: the following remediation(s) were not executed: %s. "+
+			"Please review the planned changes and apply them manually.",
+		joinDescriptions(skipped),
+	)})
+	if !hasEscalation {
+		gated = append(gated, &EscalateIncidentAction{Reason: "Cluster remediation not enabled: remediation skipped"})
 	}
-	return e.executeSequential(ctx, input.Actions, execCtx, opts)
+	return gated
 }
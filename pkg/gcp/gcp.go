@@ -0,0 +1,478 @@
+// Package gcp contains functions to access the GCP project of OSD-on-GCP clusters
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	computeURL = "https://compute.googleapis.com/compute/v1"
+	loggingURL = "https://logging.googleapis.com/v2"
+	dnsURL     = "https://dns.googleapis.com/dns/v1"
+
+	readOnlyScope = "https://www.googleapis.com/auth/cloud-platform.read-only"
+	tokenURL      = "https://oauth2.googleapis.com/token"
+)
+
+// Instance statuses, see https://cloud.google.com/compute/docs/instances/instance-life-cycle
+const (
+	InstanceRunning    = "RUNNING"
+	InstanceStopping   = "STOPPING"
+	InstanceTerminated = "TERMINATED"
+	InstanceSuspending = "SUSPENDING"
+	InstanceSuspended  = "SUSPENDED"
+)
+
+// stopMethods are the audit log methods of instances being stopped, suspended or deleted.
+// Audit logs prefix them with the API version, e.g. v1.compute.instances.stop.
+var stopMethods = []string{"compute.instances.stop", "compute.instances.suspend", "compute.instances.delete"}
+
+//go:generate mockgen -source=gcp.go -package=gcpmock -destination=mock/gcp.go
+
+// Client is a read-only client to the GCP project of a cluster
+type Client interface {
+	// ProjectID returns the project the instances, audit logs and DNS zones of the cluster are in
+	ProjectID() string
+	// ListInstances lists the compute instances of all zones whose name starts with infraID
+	ListInstances(ctx context.Context, infraID string) ([]Instance, error)
+	// ListInstanceStopEvents lists the admin activity audit log entries of instances starting with infraID
+	// being stopped, suspended or deleted since the given time, latest first
+	ListInstanceStopEvents(ctx context.Context, infraID string, since time.Time) ([]AuditLogEntry, error)
+	// GetNetwork returns the VPC network with the given name, in the network project for shared VPCs
+	GetNetwork(ctx context.Context, name string) (*Network, error)
+	// ListFirewallRules lists the firewall rules of a VPC network
+	ListFirewallRules(ctx context.Context, network string) ([]FirewallRule, error)
+	// ListRoutes lists the routes of a VPC network
+	ListRoutes(ctx context.Context, network string) ([]Route, error)
+	// ListManagedZones lists the Cloud DNS zones serving dnsName, e.g. "example.com."
+	ListManagedZones(ctx context.Context, dnsName string) ([]ManagedZone, error)
+	// ListRecordSets lists the record sets of a Cloud DNS zone with the given name, all of them if name is empty
+	ListRecordSets(ctx context.Context, zone string, name string) ([]RecordSet, error)
+}
+
+// Instance is a compute instance
+type Instance struct {
+	Name   string            `json:"name"`
+	Zone   string            `json:"zone"`
+	Status string            `json:"status"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// LastStopTimestamp is set once the instance was stopped, in RFC3339 format
+	LastStopTimestamp string `json:"lastStopTimestamp,omitempty"`
+}
+
+// IsRunning reports whether the instance is running
+func (i Instance) IsRunning() bool {
+	return i.Status == InstanceRunning
+}
+
+// AuditLogEntry is an admin activity audit log entry
+type AuditLogEntry struct {
+	Timestamp      time.Time
+	MethodName     string
+	ResourceName   string
+	PrincipalEmail string
+}
+
+// Network is a VPC network
+type Network struct {
+	Name                  string   `json:"name"`
+	SelfLink              string   `json:"selfLink"`
+	AutoCreateSubnetworks bool     `json:"autoCreateSubnetworks"`
+	Subnetworks           []string `json:"subnetworks,omitempty"`
+}
+
+// FirewallRule is a VPC firewall rule. Rules with Denied protocols block the matching traffic.
+type FirewallRule struct {
+	Name              string             `json:"name"`
+	Network           string             `json:"network"`
+	Direction         string             `json:"direction"`
+	Priority          int                `json:"priority"`
+	Disabled          bool               `json:"disabled"`
+	Allowed           []FirewallProtocol `json:"allowed,omitempty"`
+	Denied            []FirewallProtocol `json:"denied,omitempty"`
+	SourceRanges      []string           `json:"sourceRanges,omitempty"`
+	DestinationRanges []string           `json:"destinationRanges,omitempty"`
+	TargetTags        []string           `json:"targetTags,omitempty"`
+}
+
+// FirewallProtocol is a protocol and the ports of a firewall rule. No ports means all ports.
+type FirewallProtocol struct {
+	IPProtocol string   `json:"IPProtocol"`
+	Ports      []string `json:"ports,omitempty"`
+}
+
+// Route is a VPC route. Exactly one of the NextHop fields is set.
+type Route struct {
+	Name             string   `json:"name"`
+	Network          string   `json:"network"`
+	DestRange        string   `json:"destRange"`
+	Priority         int      `json:"priority"`
+	Tags             []string `json:"tags,omitempty"`
+	NextHopGateway   string   `json:"nextHopGateway,omitempty"`
+	NextHopInstance  string   `json:"nextHopInstance,omitempty"`
+	NextHopIP        string   `json:"nextHopIp,omitempty"`
+	NextHopILB       string   `json:"nextHopIlb,omitempty"`
+	NextHopNetwork   string   `json:"nextHopNetwork,omitempty"`
+	NextHopPeering   string   `json:"nextHopPeering,omitempty"`
+	NextHopVpnTunnel string   `json:"nextHopVpnTunnel,omitempty"`
+}
+
+// ManagedZone is a Cloud DNS zone
+type ManagedZone struct {
+	Name       string `json:"name"`
+	DNSName    string `json:"dnsName"`
+	Visibility string `json:"visibility"`
+}
+
+// RecordSet is a Cloud DNS record set
+type RecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	Rrdatas []string `json:"rrdatas,omitempty"`
+}
+
+// APIError is an error returned by a GCP API
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gcp api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a GCP API error for a resource that doesn't exist
+func IsNotFound(err error) bool {
+	apiErr := &APIError{}
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// ResourceName returns the last segment of a resource URL, e.g. the zone name of
+// https://www.googleapis.com/compute/v1/projects/p/zones/us-east1-b
+func ResourceName(resourceURL string) string {
+	return resourceURL[strings.LastIndex(resourceURL, "/")+1:]
+}
+
+// SdkClient is a Client using the GCP REST APIs
+type SdkClient struct {
+	httpClient       *http.Client
+	projectID        string
+	networkProjectID string
+
+	computeURL string
+	loggingURL string
+	dnsURL     string
+}
+
+// NewClient creates a client to projectID. httpClient must authenticate its requests.
+// networkProjectID is the host project of a shared VPC, and defaults to projectID if empty.
+func NewClient(httpClient *http.Client, projectID string, networkProjectID string) *SdkClient {
+	if networkProjectID == "" {
+		networkProjectID = projectID
+	}
+	return &SdkClient{
+		httpClient:       httpClient,
+		projectID:        projectID,
+		networkProjectID: networkProjectID,
+		computeURL:       computeURL,
+		loggingURL:       loggingURL,
+		dnsURL:           dnsURL,
+	}
+}
+
+// serviceAccountKey holds the fields of a service account key file needed to get tokens
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// NewClientFromServiceAccountKey creates a client authenticating with a service account key file,
+// requesting read-only access. transport is used for the API requests, if not nil. Tokens are
+// always requested directly, so recorded traffic doesn't contain them.
+func NewClientFromServiceAccountKey(key []byte, projectID string, networkProjectID string, transport http.RoundTripper) (*SdkClient, error) {
+	var sa serviceAccountKey
+	if err := json.Unmarshal(key, &sa); err != nil {
+		return nil, fmt.Errorf("could not parse service account key: %w", err)
+	}
+	if sa.Type != "service_account" {
+		return nil, fmt.Errorf("unsupported credentials type %q, expected a service_account key", sa.Type)
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = tokenURL
+	}
+
+	conf := &jwt.Config{
+		Email:        sa.ClientEmail,
+		PrivateKey:   []byte(sa.PrivateKey),
+		PrivateKeyID: sa.PrivateKeyID,
+		TokenURL:     sa.TokenURI,
+		Scopes:       []string{readOnlyScope},
+	}
+
+	httpClient := &http.Client{Transport: &oauth2.Transport{
+		Source: conf.TokenSource(context.Background()),
+		Base:   transport,
+	}}
+	return NewClient(httpClient, projectID, networkProjectID), nil
+}
+
+func (c *SdkClient) ProjectID() string {
+	return c.projectID
+}
+
+// ListInstances lists the compute instances of all zones whose name starts with infraID
+func (c *SdkClient) ListInstances(ctx context.Context, infraID string) ([]Instance, error) {
+	query := url.Values{"filter": {fmt.Sprintf(`name eq "%s-.*"`, infraID)}}
+	endpoint := fmt.Sprintf("%s/projects/%s/aggregated/instances", c.computeURL, c.projectID)
+
+	var instances []Instance
+	for {
+		var page struct {
+			Items map[string]struct {
+				Instances []Instance `json:"instances"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := c.getJSON(ctx, endpoint, query, &page); err != nil {
+			return nil, fmt.Errorf("could not list instances of %s: %w", infraID, err)
+		}
+		for _, scope := range page.Items {
+			instances = append(instances, scope.Instances...)
+		}
+		if page.NextPageToken == "" {
+			return instances, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// ListInstanceStopEvents lists the admin activity audit log entries of instances starting with infraID
+// being stopped, suspended or deleted since the given time, latest first
+func (c *SdkClient) ListInstanceStopEvents(ctx context.Context, infraID string, since time.Time) ([]AuditLogEntry, error) {
+	methods := make([]string, 0, len(stopMethods))
+	for _, method := range stopMethods {
+		methods = append(methods, fmt.Sprintf("%q", method))
+	}
+	filter := strings.Join([]string{
+		fmt.Sprintf(`logName="projects/%s/logs/cloudaudit.googleapis.com%%2Factivity"`, c.projectID),
+		fmt.Sprintf("protoPayload.methodName:(%s)", strings.Join(methods, " OR ")),
+		fmt.Sprintf(`protoPayload.resourceName:"/instances/%s-"`, infraID),
+		"operation.first=true",
+		fmt.Sprintf(`timestamp>="%s"`, since.UTC().Format(time.RFC3339)),
+	}, " AND ")
+
+	request := struct {
+		ResourceNames []string `json:"resourceNames"`
+		Filter        string   `json:"filter"`
+		OrderBy       string   `json:"orderBy"`
+		PageSize      int      `json:"pageSize"`
+		PageToken     string   `json:"pageToken,omitempty"`
+	}{
+		ResourceNames: []string{"projects/" + c.projectID},
+		Filter:        filter,
+		OrderBy:       "timestamp desc",
+		PageSize:      1000,
+	}
+
+	var entries []AuditLogEntry
+	for {
+		var page struct {
+			Entries []struct {
+				Timestamp    time.Time `json:"timestamp"`
+				ProtoPayload struct {
+					MethodName         string `json:"methodName"`
+					ResourceName       string `json:"resourceName"`
+					AuthenticationInfo struct {
+						PrincipalEmail string `json:"principalEmail"`
+					} `json:"authenticationInfo"`
+				} `json:"protoPayload"`
+			} `json:"entries"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := c.postJSON(ctx, c.loggingURL+"/entries:list", request, &page); err != nil {
+			return nil, fmt.Errorf("could not list audit logs of %s: %w", infraID, err)
+		}
+		for _, e := range page.Entries {
+			entries = append(entries, AuditLogEntry{
+				Timestamp:      e.Timestamp,
+				MethodName:     e.ProtoPayload.MethodName,
+				ResourceName:   e.ProtoPayload.ResourceName,
+				PrincipalEmail: e.ProtoPayload.AuthenticationInfo.PrincipalEmail,
+			})
+		}
+		if page.NextPageToken == "" {
+			return entries, nil
+		}
+		request.PageToken = page.NextPageToken
+	}
+}
+
+// GetNetwork returns the VPC network with the given name
+func (c *SdkClient) GetNetwork(ctx context.Context, name string) (*Network, error) {
+	network := &Network{}
+	endpoint := fmt.Sprintf("%s/projects/%s/global/networks/%s", c.computeURL, c.networkProjectID, url.PathEscape(name))
+	if err := c.getJSON(ctx, endpoint, nil, network); err != nil {
+		return nil, fmt.Errorf("could not get network %s: %w", name, err)
+	}
+	return network, nil
+}
+
+// ListFirewallRules lists the firewall rules of a VPC network
+func (c *SdkClient) ListFirewallRules(ctx context.Context, network string) ([]FirewallRule, error) {
+	query := url.Values{"filter": {fmt.Sprintf(`network eq ".*/networks/%s"`, network)}}
+	endpoint := fmt.Sprintf("%s/projects/%s/global/firewalls", c.computeURL, c.networkProjectID)
+
+	var rules []FirewallRule
+	for {
+		var page struct {
+			Items         []FirewallRule `json:"items"`
+			NextPageToken string         `json:"nextPageToken"`
+		}
+		if err := c.getJSON(ctx, endpoint, query, &page); err != nil {
+			return nil, fmt.Errorf("could not list firewall rules of network %s: %w", network, err)
+		}
+		rules = append(rules, page.Items...)
+		if page.NextPageToken == "" {
+			return rules, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// ListRoutes lists the routes of a VPC network
+func (c *SdkClient) ListRoutes(ctx context.Context, network string) ([]Route, error) {
+	query := url.Values{"filter": {fmt.Sprintf(`network eq ".*/networks/%s"`, network)}}
+	endpoint := fmt.Sprintf("%s/projects/%s/global/routes", c.computeURL, c.networkProjectID)
+
+	var routes []Route
+	for {
+		var page struct {
+			Items         []Route `json:"items"`
+			NextPageToken string  `json:"nextPageToken"`
+		}
+		if err := c.getJSON(ctx, endpoint, query, &page); err != nil {
+			return nil, fmt.Errorf("could not list routes of network %s: %w", network, err)
+		}
+		routes = append(routes, page.Items...)
+		if page.NextPageToken == "" {
+			return routes, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// ListManagedZones lists the Cloud DNS zones serving dnsName
+func (c *SdkClient) ListManagedZones(ctx context.Context, dnsName string) ([]ManagedZone, error) {
+	query := url.Values{"dnsName": {dnsName}}
+	endpoint := fmt.Sprintf("%s/projects/%s/managedZones", c.dnsURL, c.projectID)
+
+	var zones []ManagedZone
+	for {
+		var page struct {
+			ManagedZones  []ManagedZone `json:"managedZones"`
+			NextPageToken string        `json:"nextPageToken"`
+		}
+		if err := c.getJSON(ctx, endpoint, query, &page); err != nil {
+			return nil, fmt.Errorf("could not list managed zones for %s: %w", dnsName, err)
+		}
+		zones = append(zones, page.ManagedZones...)
+		if page.NextPageToken == "" {
+			return zones, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// ListRecordSets lists the record sets of a Cloud DNS zone with the given name, all of them if name is empty
+func (c *SdkClient) ListRecordSets(ctx context.Context, zone string, name string) ([]RecordSet, error) {
+	query := url.Values{}
+	if name != "" {
+		query.Set("name", name)
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/managedZones/%s/rrsets", c.dnsURL, c.projectID, url.PathEscape(zone))
+
+	var recordSets []RecordSet
+	for {
+		var page struct {
+			Rrsets        []RecordSet `json:"rrsets"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if err := c.getJSON(ctx, endpoint, query, &page); err != nil {
+			return nil, fmt.Errorf("could not list record sets of zone %s: %w", zone, err)
+		}
+		recordSets = append(recordSets, page.Rrsets...)
+		if page.NextPageToken == "" {
+			return recordSets, nil
+		}
+		query.Set("pageToken", page.NextPageToken)
+	}
+}
+
+func (c *SdkClient) getJSON(ctx context.Context, endpoint string, query url.Values, out any) error {
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+func (c *SdkClient) postJSON(ctx context.Context, endpoint string, body any, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, out)
+}
+
+func (c *SdkClient) do(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		message := strings.TrimSpace(string(body))
+		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
+			message = errResp.Error.Message
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: message}
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("could not parse response: %w", err)
+	}
+	return nil
+}
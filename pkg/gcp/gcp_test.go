@@ -0,0 +1,175 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient creates a client whose APIs are all served by handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *SdkClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.Client(), "project-1", "")
+	c.computeURL = server.URL + "/compute"
+	c.loggingURL = server.URL + "/logging"
+	c.dnsURL = server.URL + "/dns"
+	return c
+}
+
+func TestListInstances(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compute/projects/project-1/aggregated/instances" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if filter := r.URL.Query().Get("filter"); filter != `name eq "infra-1-.*"` {
+			t.Errorf("unexpected filter %q", filter)
+		}
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			_, _ = w.Write([]byte(`{"items": {
+				"zones/us-east1-b": {"instances": [{"name": "infra-1-master-0", "zone": "https://www.googleapis.com/compute/v1/projects/project-1/zones/us-east1-b", "status": "RUNNING"}]},
+				"zones/us-east1-c": {"warning": {"code": "NO_RESULTS_ON_PAGE"}}
+			}, "nextPageToken": "page-2"}`))
+		case "page-2":
+			_, _ = w.Write([]byte(`{"items": {"zones/us-east1-c": {"instances": [{"name": "infra-1-worker-a", "status": "TERMINATED", "lastStopTimestamp": "2026-10-01T10:00:00Z"}]}}}`))
+		default:
+			t.Errorf("unexpected page token %s", r.URL.Query().Get("pageToken"))
+		}
+	})
+
+	instances, err := c.ListInstances(context.Background(), "infra-1")
+	if err != nil {
+		t.Fatalf("ListInstances() error = %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("ListInstances() returned %d instances, want 2", len(instances))
+	}
+	if !instances[0].IsRunning() || ResourceName(instances[0].Zone) != "us-east1-b" {
+		t.Errorf("unexpected first instance %+v", instances[0])
+	}
+	if instances[1].IsRunning() || instances[1].LastStopTimestamp == "" {
+		t.Errorf("unexpected second instance %+v", instances[1])
+	}
+}
+
+func TestListInstanceStopEvents(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/logging/entries:list" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var request struct {
+			ResourceNames []string `json:"resourceNames"`
+			Filter        string   `json:"filter"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("could not decode request: %v", err)
+		}
+		for _, want := range []string{
+			`logName="projects/project-1/logs/cloudaudit.googleapis.com%2Factivity"`,
+			`"compute.instances.stop" OR "compute.instances.suspend" OR "compute.instances.delete"`,
+			`protoPayload.resourceName:"/instances/infra-1-"`,
+			`timestamp>="2026-10-01T00:00:00Z"`,
+		} {
+			if !strings.Contains(request.Filter, want) {
+				t.Errorf("filter %q does not contain %q", request.Filter, want)
+			}
+		}
+		_, _ = w.Write([]byte(`{"entries": [{
+			"timestamp": "2026-10-02T08:00:00Z",
+			"protoPayload": {
+				"methodName": "v1.compute.instances.stop",
+				"resourceName": "projects/project-1/zones/us-east1-b/instances/infra-1-worker-a",
+				"authenticationInfo": {"principalEmail": "someone@example.com"}
+			}
+		}]}`))
+	})
+
+	events, err := c.ListInstanceStopEvents(context.Background(), "infra-1", since)
+	if err != nil {
+		t.Fatalf("ListInstanceStopEvents() error = %v", err)
+	}
+	want := AuditLogEntry{
+		Timestamp:      time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC),
+		MethodName:     "v1.compute.instances.stop",
+		ResourceName:   "projects/project-1/zones/us-east1-b/instances/infra-1-worker-a",
+		PrincipalEmail: "someone@example.com",
+	}
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("ListInstanceStopEvents() = %+v, want [%+v]", events, want)
+	}
+}
+
+func TestNetworkInSharedVPCProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/compute/projects/host-project/global/networks/vpc-1" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "The resource was not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "vpc-1"}`))
+	}))
+	defer server.Close()
+	c := NewClient(server.Client(), "project-1", "host-project")
+	c.computeURL = server.URL + "/compute"
+
+	network, err := c.GetNetwork(context.Background(), "vpc-1")
+	if err != nil || network.Name != "vpc-1" {
+		t.Fatalf("GetNetwork() = %v, %v", network, err)
+	}
+
+	_, err = c.GetNetwork(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Errorf("GetNetwork() of a missing network error = %v, want a not found error", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "The resource was not found") {
+		t.Errorf("GetNetwork() error = %v, want the API error message", err)
+	}
+}
+
+func TestNewClientFromServiceAccountKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr string
+	}{
+		{
+			name:    "invalid json",
+			key:     "{",
+			wantErr: "could not parse service account key",
+		},
+		{
+			name:    "not a service account",
+			key:     `{"type": "authorized_user"}`,
+			wantErr: `unsupported credentials type "authorized_user"`,
+		},
+		{
+			name: "service account",
+			key:  `{"type": "service_account", "client_email": "cad@project.iam.gserviceaccount.com", "private_key": "key"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewClientFromServiceAccountKey([]byte(tt.key), "project-1", "", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("NewClientFromServiceAccountKey() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewClientFromServiceAccountKey() error = %v", err)
+			}
+			if c.ProjectID() != "project-1" || c.networkProjectID != "project-1" {
+				t.Errorf("unexpected projects %s, %s", c.ProjectID(), c.networkProjectID)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package incident
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Operations recorded by the File backend.
+const (
+	OperationNote        = "note"
+	OperationEscalate    = "escalate"
+	OperationSilence     = "silence"
+	OperationUpdateTitle = "update_title"
+)
+
+// Entry is one operation recorded by the File backend.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Text      string    `json:"text,omitempty"`
+}
+
+// File is a backend for local testing. It appends every incident operation as a JSON
+// line to a file, so the outcome of an investigation can be inspected without PagerDuty.
+type File struct {
+	path string
+	meta Metadata
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewFile creates a backend recording operations on the incident described by meta to path.
+func NewFile(path string, meta Metadata) *File {
+	return &File{path: path, meta: meta, now: time.Now}
+}
+
+func (f *File) AddNote(note string) error {
+	return f.record(OperationNote, note)
+}
+
+func (f *File) EscalateIncident() error {
+	return f.record(OperationEscalate, "")
+}
+
+func (f *File) EscalateIncidentWithNote(note string) error {
+	if err := f.AddNote(note); err != nil {
+		return err
+	}
+	return f.EscalateIncident()
+}
+
+func (f *File) SilenceIncident() error {
+	return f.record(OperationSilence, "")
+}
+
+func (f *File) SilenceIncidentWithNote(note string) error {
+	if err := f.AddNote(note); err != nil {
+		return err
+	}
+	return f.SilenceIncident()
+}
+
+func (f *File) UpdateIncidentTitle(title string) error {
+	if err := f.record(OperationUpdateTitle, title); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.meta.Title = title
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *File) GetIncidentID() string {
+	return f.meta.ID
+}
+
+func (f *File) GetTitle() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.meta.Title
+}
+
+func (f *File) GetServiceID() string {
+	return f.meta.ServiceID
+}
+
+func (f *File) RetrieveClusterID() (string, error) {
+	if f.meta.ClusterID == "" {
+		return "", errors.New("no cluster ID set for incident")
+	}
+	return f.meta.ClusterID, nil
+}
+
+func (f *File) record(operation, text string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	line, err := json.Marshal(Entry{Time: f.now().UTC(), Operation: operation, Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to encode incident %s: %w", operation, err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // path is provided by the operator
+	if err != nil {
+		return fmt.Errorf("failed to open incident file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write incident %s: %w", operation, err)
+	}
+	return file.Close()
+}
+
+// ReadFile returns the operations recorded in an incident file.
+func ReadFile(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is provided by the operator
+	if err != nil {
+		return nil, fmt.Errorf("failed to read incident file: %w", err)
+	}
+
+	var entries []Entry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var e Entry
+		if err := decoder.Decode(&e); err != nil {
+			return nil, fmt.Errorf("failed to parse incident file: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,27 @@
+// Package incident abstracts the system tracking the incident CAD investigates, so
+// investigations and actions are not bound to PagerDuty.
+package incident
+
+//go:generate mockgen --build_flags=--mod=readonly -source $GOFILE -destination ./mock/incidentmock.go -package incidentmock
+
+// Backend is the interface exposing incident operations
+type Backend interface {
+	AddNote(note string) error
+	EscalateIncident() error
+	EscalateIncidentWithNote(note string) error
+	SilenceIncident() error
+	SilenceIncidentWithNote(note string) error
+	UpdateIncidentTitle(title string) error
+	GetIncidentID() string
+	GetTitle() string
+	GetServiceID() string
+	RetrieveClusterID() (string, error)
+}
+
+// Metadata describes the incident a backend without a remote incident operates on.
+type Metadata struct {
+	ID        string
+	Title     string
+	ServiceID string
+	ClusterID string
+}
@@ -0,0 +1,77 @@
+package incident
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Compile-time checks that the backends implement Backend.
+var (
+	_ Backend = (*Noop)(nil)
+	_ Backend = (*File)(nil)
+	_ Backend = (*ReadOnly)(nil)
+)
+
+func TestFileRecordsOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "incident.jsonl")
+	f := NewFile(path, Metadata{ID: "INC1", Title: "ClusterHasGoneMissing", ClusterID: "cluster-1"})
+	f.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	if err := f.EscalateIncidentWithNote("cluster is unreachable"); err != nil {
+		t.Fatalf("EscalateIncidentWithNote() error = %v", err)
+	}
+	if err := f.UpdateIncidentTitle("[CAD Investigated] ClusterHasGoneMissing"); err != nil {
+		t.Fatalf("UpdateIncidentTitle() error = %v", err)
+	}
+	if got := f.GetTitle(); got != "[CAD Investigated] ClusterHasGoneMissing" {
+		t.Errorf("GetTitle() = %q", got)
+	}
+
+	entries, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want := []string{OperationNote, OperationEscalate, OperationUpdateTitle}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(want), len(entries), entries)
+	}
+	for i, op := range want {
+		if entries[i].Operation != op {
+			t.Errorf("entries[%d].Operation = %q, want %q", i, entries[i].Operation, op)
+		}
+	}
+	if entries[0].Text != "cluster is unreachable" {
+		t.Errorf("note text = %q", entries[0].Text)
+	}
+}
+
+func TestRetrieveClusterID(t *testing.T) {
+	if id, err := NewNoop(Metadata{ClusterID: "cluster-1"}).RetrieveClusterID(); err != nil || id != "cluster-1" {
+		t.Errorf("RetrieveClusterID() = %q, %v", id, err)
+	}
+	if _, err := NewFile("unused", Metadata{}).RetrieveClusterID(); err == nil {
+		t.Error("expected error without cluster ID")
+	}
+}
+
+func TestReadOnlyFailsEscalations(t *testing.T) {
+	r := NewReadOnly(Metadata{ID: "fingerprint-1", Title: "ClusterHasGoneMissing", ServiceID: "alertmanager", ClusterID: "cluster-1"})
+
+	if err := r.AddNote("note"); err != nil {
+		t.Errorf("AddNote() error = %v", err)
+	}
+	if err := r.SilenceIncidentWithNote("note"); err != nil {
+		t.Errorf("SilenceIncidentWithNote() error = %v", err)
+	}
+	for name, escalate := range map[string]func() error{
+		"EscalateIncident":         r.EscalateIncident,
+		"EscalateIncidentWithNote": func() error { return r.EscalateIncidentWithNote("note") },
+	} {
+		err := escalate()
+		if err == nil || !strings.Contains(err.Error(), `alertmanager alert "ClusterHasGoneMissing" (fingerprint-1)`) {
+			t.Errorf("%s() error = %v, want undeliverable escalation", name, err)
+		}
+	}
+}
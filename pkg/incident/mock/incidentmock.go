@@ -0,0 +1,181 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: incident.go
+//
+// Generated by this command:
+//
+//	mockgen --build_flags=--mod=readonly -source incident.go -destination ./mock/incidentmock.go -package incidentmock
+//
+
+// Package incidentmock is a generated GoMock package.
+package incidentmock
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBackend is a mock of Backend interface.
+type MockBackend struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackendMockRecorder
+	isgomock struct{}
+}
+
+// MockBackendMockRecorder is the mock recorder for MockBackend.
+type MockBackendMockRecorder struct {
+	mock *MockBackend
+}
+
+// NewMockBackend creates a new mock instance.
+func NewMockBackend(ctrl *gomock.Controller) *MockBackend {
+	mock := &MockBackend{ctrl: ctrl}
+	mock.recorder = &MockBackendMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackend) EXPECT() *MockBackendMockRecorder {
+	return m.recorder
+}
+
+// AddNote mocks base method.
+func (m *MockBackend) AddNote(note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNote", note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNote indicates an expected call of AddNote.
+func (mr *MockBackendMockRecorder) AddNote(note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNote", reflect.TypeOf((*MockBackend)(nil).AddNote), note)
+}
+
+// EscalateIncident mocks base method.
+func (m *MockBackend) EscalateIncident() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateIncident")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EscalateIncident indicates an expected call of EscalateIncident.
+func (mr *MockBackendMockRecorder) EscalateIncident() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateIncident", reflect.TypeOf((*MockBackend)(nil).EscalateIncident))
+}
+
+// EscalateIncidentWithNote mocks base method.
+func (m *MockBackend) EscalateIncidentWithNote(note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateIncidentWithNote", note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EscalateIncidentWithNote indicates an expected call of EscalateIncidentWithNote.
+func (mr *MockBackendMockRecorder) EscalateIncidentWithNote(note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateIncidentWithNote", reflect.TypeOf((*MockBackend)(nil).EscalateIncidentWithNote), note)
+}
+
+// GetIncidentID mocks base method.
+func (m *MockBackend) GetIncidentID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetIncidentID indicates an expected call of GetIncidentID.
+func (mr *MockBackendMockRecorder) GetIncidentID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentID", reflect.TypeOf((*MockBackend)(nil).GetIncidentID))
+}
+
+// GetServiceID mocks base method.
+func (m *MockBackend) GetServiceID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetServiceID indicates an expected call of GetServiceID.
+func (mr *MockBackendMockRecorder) GetServiceID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceID", reflect.TypeOf((*MockBackend)(nil).GetServiceID))
+}
+
+// GetTitle mocks base method.
+func (m *MockBackend) GetTitle() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTitle")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetTitle indicates an expected call of GetTitle.
+func (mr *MockBackendMockRecorder) GetTitle() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTitle", reflect.TypeOf((*MockBackend)(nil).GetTitle))
+}
+
+// RetrieveClusterID mocks base method.
+func (m *MockBackend) RetrieveClusterID() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrieveClusterID")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrieveClusterID indicates an expected call of RetrieveClusterID.
+func (mr *MockBackendMockRecorder) RetrieveClusterID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrieveClusterID", reflect.TypeOf((*MockBackend)(nil).RetrieveClusterID))
+}
+
+// SilenceIncident mocks base method.
+func (m *MockBackend) SilenceIncident() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SilenceIncident")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SilenceIncident indicates an expected call of SilenceIncident.
+func (mr *MockBackendMockRecorder) SilenceIncident() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SilenceIncident", reflect.TypeOf((*MockBackend)(nil).SilenceIncident))
+}
+
+// SilenceIncidentWithNote mocks base method.
+func (m *MockBackend) SilenceIncidentWithNote(note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SilenceIncidentWithNote", note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SilenceIncidentWithNote indicates an expected call of SilenceIncidentWithNote.
+func (mr *MockBackendMockRecorder) SilenceIncidentWithNote(note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SilenceIncidentWithNote", reflect.TypeOf((*MockBackend)(nil).SilenceIncidentWithNote), note)
+}
+
+// UpdateIncidentTitle mocks base method.
+func (m *MockBackend) UpdateIncidentTitle(title string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIncidentTitle", title)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIncidentTitle indicates an expected call of UpdateIncidentTitle.
+func (mr *MockBackendMockRecorder) UpdateIncidentTitle(title any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIncidentTitle", reflect.TypeOf((*MockBackend)(nil).UpdateIncidentTitle), title)
+}
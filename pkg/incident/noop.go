@@ -0,0 +1,67 @@
+package incident
+
+import (
+	"errors"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+)
+
+// Noop is the backend for runs without an incident, e.g. manual CLI runs or alerts
+// from sources that can't be written back to. Operations are logged and skipped.
+type Noop struct {
+	meta Metadata
+}
+
+// NewNoop creates a backend that only logs incident operations.
+func NewNoop(meta Metadata) *Noop {
+	return &Noop{meta: meta}
+}
+
+func (n *Noop) AddNote(note string) error {
+	logging.Infof("Skipping incident note (no incident backend):\n%s", note)
+	return nil
+}
+
+func (n *Noop) EscalateIncident() error {
+	logging.Info("Skipping incident escalation (no incident backend)")
+	return nil
+}
+
+func (n *Noop) EscalateIncidentWithNote(note string) error {
+	logging.Infof("Skipping incident escalation (no incident backend):\n%s", note)
+	return nil
+}
+
+func (n *Noop) SilenceIncident() error {
+	logging.Info("Skipping incident silence (no incident backend)")
+	return nil
+}
+
+func (n *Noop) SilenceIncidentWithNote(note string) error {
+	logging.Infof("Skipping incident silence (no incident backend):\n%s", note)
+	return nil
+}
+
+func (n *Noop) UpdateIncidentTitle(title string) error {
+	logging.Infof("Skipping incident title update to %q (no incident backend)", title)
+	return nil
+}
+
+func (n *Noop) GetIncidentID() string {
+	return n.meta.ID
+}
+
+func (n *Noop) GetTitle() string {
+	return n.meta.Title
+}
+
+func (n *Noop) GetServiceID() string {
+	return n.meta.ServiceID
+}
+
+func (n *Noop) RetrieveClusterID() (string, error) {
+	if n.meta.ClusterID == "" {
+		return "", errors.New("no cluster ID set for incident")
+	}
+	return n.meta.ClusterID, nil
+}
@@ -0,0 +1,34 @@
+package incident
+
+import (
+	"fmt"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+)
+
+// ReadOnly is the backend for alerts from sources that can't be written back to
+// (Alertmanager, generic webhooks). Notes, silences and title updates are logged like
+// with Noop, but escalations fail: an escalation that is only logged would leave the
+// alert without anyone looking at it, so the run has to fail instead.
+type ReadOnly struct {
+	*Noop
+}
+
+// NewReadOnly creates a backend that logs incident operations and fails escalations.
+func NewReadOnly(meta Metadata) *ReadOnly {
+	return &ReadOnly{Noop: NewNoop(meta)}
+}
+
+func (r *ReadOnly) EscalateIncident() error {
+	return r.escalationError()
+}
+
+func (r *ReadOnly) EscalateIncidentWithNote(note string) error {
+	logging.Infof("Incident notes of undeliverable escalation:\n%s", note)
+	return r.escalationError()
+}
+
+func (r *ReadOnly) escalationError() error {
+	return fmt.Errorf("cannot escalate %s alert %q (%s): the source has no incident backend, please investigate manually",
+		r.meta.ServiceID, r.meta.Title, r.meta.ID)
+}
@@ -17,7 +17,6 @@ import (
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
-	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
 )
 
 type Investigation struct {
@@ -44,6 +43,11 @@ func generateSessionID(incidentID string) string {
 }
 
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the agent invocation with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	// Build resources
@@ -86,31 +90,29 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	aiConfig := c.AIConfig
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.TODO(), aiConfig.GetTimeout())
+	ctx, cancel := context.WithTimeout(ctx, aiConfig.GetTimeout())
 	defer cancel()
 
-	// Get PagerDuty incident details
-	pdClient, ok := r.PdClient.(*pagerduty.SdkClient)
-	if !ok {
-		notes.AppendWarning("Failed to access PagerDuty client details")
+	if r.Incident == nil || r.Incident.GetIncidentID() == "" {
+		notes.AppendWarning("No incident to post AI investigation results to")
 		result.Actions = append(
 			executor.NoteAndReportFrom(notes, clusterID, c.Name()),
-			executor.Escalate("Failed to access PagerDuty client"),
+			executor.Escalate("No incident backend"),
 		)
 		return result, nil
 	}
 
 	// Escalate immediately - AI investigations always go to SRE.
 	// Results will be posted async to PD notes for review.
-	if err := r.PdClient.EscalateIncident(); err != nil {
-		// Fail pipeline - if there's no incident or issue reaching PD, there's nothing to post results back to
+	if err := r.Incident.EscalateIncident(); err != nil {
+		// Fail pipeline - if there's no incident or issue reaching it, there's nothing to post results back to
 		logging.Errorf("Failed to escalate incident for AI investigation: %v", err)
-		return result, investigation.WrapInfrastructure(err, "PagerDuty incident escalation failed")
+		return result, investigation.WrapInfrastructure(err, "incident escalation failed")
 	}
 	logging.Info("Incident escalated immediately for AI investigation - SRE can review results async")
 
-	incidentID := pdClient.GetIncidentID()
-	alertName := pdClient.GetTitle()
+	incidentID := r.Incident.GetIncidentID()
+	alertName := r.Incident.GetTitle()
 
 	// Build investigation payload using typed structure
 	investigationData := &InvestigationPayload{
@@ -250,3 +252,10 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 func (c *Investigation) Name() string {
 	return "aiassisted"
 }
+
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description: "Runs an AI agent against the alert and reports its findings",
+		Platforms:   []investigation.Platform{investigation.PlatformClassic},
+	}
+}
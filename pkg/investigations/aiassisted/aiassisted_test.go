@@ -10,11 +10,11 @@ import (
 	awsmock "github.com/openshift/configuration-anomaly-detection/pkg/aws/mock"
 	backplanemock "github.com/openshift/configuration-anomaly-detection/pkg/backplane/mock"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	incidentmock "github.com/openshift/configuration-anomaly-detection/pkg/incident/mock"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
-	pdmock "github.com/openshift/configuration-anomaly-detection/pkg/pagerduty/mock"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	"go.uber.org/mock/gomock"
@@ -81,7 +81,7 @@ var _ = Describe("aiassisted", func() {
 				AwsClient:         awsmock.NewMockClient(mockCtrl),
 				BpClient:          &backplanemock.MockClient{},
 				OcmClient:         ocmmock.NewMockClient(mockCtrl),
-				PdClient:          pdmock.NewMockClient(mockCtrl),
+				Incident:          incidentmock.NewMockBackend(mockCtrl),
 				Notes:             notewriter.New("Test", logging.RawLogger),
 			},
 		}
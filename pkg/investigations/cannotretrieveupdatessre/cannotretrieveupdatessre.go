@@ -1,6 +1,7 @@
 package cannotretrieveupdatessre
 
 import (
+	"context"
 	"fmt"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -18,6 +19,11 @@ type Investigation struct{}
 
 // Run executes the investigation for the CannotRetrieveUpdatesSRE alert
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the network verifier and the requests to the cluster with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 	r, err := rb.WithAwsClient().WithClusterDeployment().Build()
 	if err != nil {
@@ -26,7 +32,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	notes := notewriter.New("CannotRetrieveUpdatesSRE", logging.RawLogger)
 
 	// Run network verifier
-	verifierResult, failureReason, err := networkverifier.Run(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	verifierResult, failureReason, err := networkverifier.Run(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
 	if err != nil {
 		notes.AppendWarning("NetworkVerifier failed to run:\n\t %s", err.Error())
 	} else {
@@ -53,7 +59,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	}
 
 	// Check ClusterVersion
-	clusterVersion, err := version.GetClusterVersion(r.K8sClient)
+	clusterVersion, err := version.GetClusterVersion(ctx, r.K8sClient)
 	if err != nil {
 		notes.AppendWarning("Failed to get ClusterVersion: %s", err.Error())
 	} else {
@@ -98,3 +104,11 @@ func checkCondition(condition configv1.ClusterOperatorStatusCondition) (string,
 func (i *Investigation) Name() string {
 	return "cannotretrieveupdatessre"
 }
+
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks why the cluster version operator cannot retrieve updates",
+		Platforms:         []investigation.Platform{investigation.PlatformClassic, investigation.PlatformAWS},
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s, investigation.ResourceAWS},
+	}
+}
@@ -2,29 +2,32 @@
 package ccam
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
-	"regexp"
+	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 )
 
 type CloudCredentialsCheck struct{}
 
-var ccamLimitedSupport = &ocm.LimitedSupportReason{
-	Summary: "Restore missing cloud credentials",
-	Details: "Your cluster requires you to take action because Red Hat is not able to access the infrastructure with the provided credentials. Please restore the credentials and permissions provided during install",
-}
-
 // Evaluates if the awsError is a cluster credentials are missing error. If it determines that it is,
 // the cluster is placed into limited support (if the cluster state allows it), otherwise an error is returned.
+// Throttling and OCM outages are returned as infrastructure errors so the investigation is retried.
 func (c *CloudCredentialsCheck) Run(r investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), r)
+}
+
+// RunContext runs the investigation. If the credentials work, the OIDC provider and the operator roles
+// of STS clusters are validated through IAM, with requests cancelled with ctx.
+func (c *CloudCredentialsCheck) RunContext(ctx context.Context, r investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 	// Apart from the defaults this investigation requires an AWS client which can fail to build
 	resources, err := r.WithAwsClient().Build()
@@ -33,7 +36,17 @@ func (c *CloudCredentialsCheck) Run(r investigation.ResourceBuilder) (investigat
 	awsClientErr := &investigation.AWSClientError{}
 	if errors.As(err, awsClientErr) {
 		logging.Debug("Inspecting AWS error")
-		if customerRemovedPermissions := customerRemovedPermissions(awsClientErr.Err.Error()); !customerRemovedPermissions {
+		classified := classifyError(awsClientErr.Err)
+		metrics.Inc(metrics.CloudCredentialsErrors, c.Name(), string(classified.Class))
+		logging.Infof("Classified AWS error as %s", classified.Class)
+
+		switch classified.Class {
+		case errorClassThrottling:
+			return result, investigation.WrapInfrastructure(awsClientErr.Err, "AWS/OCM throttled the requests for cloud credentials")
+		case errorClassOCMOutage:
+			return result, investigation.WrapInfrastructure(awsClientErr.Err, "OCM failed to serve the request for cloud credentials")
+		}
+		if !classified.Class.customerCaused() {
 			// We aren't able to jumpRole because of an error that is different than
 			// a removed support role/policy or removed installer role/policy
 			// This would normally be a backplane failure.
@@ -41,81 +54,103 @@ func (c *CloudCredentialsCheck) Run(r investigation.ResourceBuilder) (investigat
 			return result, investigation.WrapInfrastructure(awsClientErr.Err, "AWS/Backplane infrastructure failure")
 		}
 		result.StopInvestigations = err
-		cluster := resources.Cluster
-
-		// The jumprole failed because of a missing support role/policy:
-		// we need to figure out if we cluster state allows us to set limited support
-		// (the cluster is in a ready state, not uninstalling, installing, etc.)
-
-		logging.Debug("Checking cluster state: ", cluster.State())
-		switch cluster.State() {
-		case cmv1.ClusterStateReady:
-			// Cluster is in functional state but we can't jumprole to it: post limited support
-			result.Actions = []types.Action{
-				executor.NewLimitedSupportAction(ccamLimitedSupport.Summary, ccamLimitedSupport.Details, "CCAM").Build(),
-				executor.Silence("Cluster credentials are missing - limited support added"),
-			}
-			return result, nil
-		case cmv1.ClusterStateUninstalling:
-			// A cluster in uninstalling state should not alert primary - we just skip this
-			result.Actions = []types.Action{
-				executor.Silence(fmt.Sprintf("Skipped adding limited support reason '%s': cluster is already uninstalling", ccamLimitedSupport.Summary)),
-			}
-			return result, nil
-		default:
-			// Anything else is an unknown state to us and/or requires investigation.
-			// E.g. we land here if we run into a CPD alert where credentials were removed (installing state) and don't want to put it in LS yet.
-			result.Actions = []types.Action{
-				executor.Escalate(fmt.Sprintf("Cluster has invalid cloud credentials (support role/policy is missing) and the cluster is in state '%s'. Please investigate.", cluster.State())),
-			}
-			return result, nil
-		}
+		ls := classLimitedSupport[classified.Class]
+		result.Actions = customerCausedActions(resources.Cluster, ls, executor.Note("Cloud credentials error: "+classified.String()), string(classified.Class))
+		return result, nil
 	}
-	return result, err
-}
-
-func (c *CloudCredentialsCheck) Name() string {
-	return "ccam"
+	if err != nil {
+		return result, err
+	}
+	return c.checkSTS(ctx, resources, result), nil
 }
 
-// userCausedErrors contains the list of backplane returned error strings that we map to
-// customer modifications/role deletions.
-var userCausedErrors = []string{
-	// OCM can't access the installer role to determine the trust relationship on the support role,
-	// therefore we don't know if it's the isolated access flow or the old flow, e.g.:
-	// status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is '<id>': Failed to find trusted relationship to support role 'RH-Technical-Support-Access'
-	// See https://issues.redhat.com/browse/OSD-24270
-	".*Failed to find trusted relationship to support role 'RH-Technical-Support-Access'.*",
-
-	// OCM role can't access the installer role, this happens when customer deletes/modifies the trust policy of the installer role, e.g.:
-	// status is 400, identifier is '400', code is 'CLUSTERS-MGMT-400' and operation identifier is '<id>': Please make sure IAM role 'arn:aws:iam::<ocm_role_aws_id>:role/ManagedOpenShift-Installer-Role' exists, and add 'arn:aws:iam::<id>:role/RH-Managed-OpenShift-Installer' to the trust policy on IAM role 'arn:aws:iam::<id>:role/ManagedOpenShift-Installer-Role': Failed to assume role: User: arn:aws:sts::<id>:assumed-role/RH-Managed-OpenShift-Installer/OCM is not authorized to perform: sts:AssumeRole on resource: arn:aws:iam::<customer_aws_id>:role/ManagedOpenShift-Installer-Role
-	".*RH-Managed-OpenShift-Installer/OCM is not authorized to perform: sts:AssumeRole on resource.*",
+// checkSTS validates the OIDC provider and the operator roles of STS clusters. Problems that keep the operators from getting
+// credentials are handled like missing credentials, detached policies are reported to the customer in a service log.
+func (c *CloudCredentialsCheck) checkSTS(ctx context.Context, resources *investigation.Resources, result investigation.InvestigationResult) investigation.InvestigationResult {
+	if resources == nil || resources.AwsClient == nil {
+		return result
+	}
+	problems, err := validateSTS(ctx, resources.Cluster, resources.AwsClient)
+	if err != nil {
+		// The credentials work, so the investigations that follow can still run
+		logging.Warnf("Failed to validate the STS roles of the cluster: %v", err)
+		return result
+	}
+	if len(problems) == 0 {
+		return result
+	}
 
-	// Customer deleted the support role, e.g.:
-	// status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is '<id>': Support role, used with cluster '<cluster_id>', does not exist in the customer's AWS account
-	".*Support role, used with cluster '[a-z0-9]{32}', does not exist in the customer's AWS account.*",
+	var blocking, degraded []string
+	for _, p := range problems {
+		metrics.Inc(metrics.CloudCredentialsErrors, c.Name(), string(p.Kind))
+		if p.Kind.blocksOperators() {
+			blocking = append(blocking, p.String())
+		} else {
+			degraded = append(degraded, p.String())
+		}
+	}
+	note := executor.Note("STS role validation found:\n" + strings.Join(append(blocking, degraded...), "\n"))
 
-	// This error is the response from backplane calls when:
-	// trust policy of ManagedOpenShift-Support-Role is changed
-	".*could not assume support role in customer's account: .*AccessDenied:.*",
+	if len(blocking) > 0 {
+		result.StopInvestigations = fmt.Errorf("operators can't get cloud credentials: %s", strings.Join(blocking, "; "))
+		ls := &ocm.LimitedSupportReason{
+			Summary: stsLimitedSupport.Summary,
+			Details: fmt.Sprintf(stsLimitedSupport.Details, strings.Join(blocking, "; ")),
+		}
+		result.Actions = customerCausedActions(resources.Cluster, ls, note, "operator roles or OIDC provider are invalid")
+		return result
+	}
 
-	// Customer removed the `GetRole` permission from the Installer role.
-	// Failed to get role: User: arn:aws:sts::<id>:assumed-role/ManagedOpenShift-Installer-Role/OCM is not authorized to perform: iam:GetRole on resource: role ManagedOpenShift-Support-Role because no identity-based policy allows the iam:GetRole action
-	".*is not authorized to perform: iam:GetRole on resource: role.*",
+	result.Actions = []types.Action{note}
+	if resources.Cluster.State() == cmv1.ClusterStateReady {
+		result.Actions = append(result.Actions,
+			executor.NewServiceLogAction(stsServiceLog.Severity, stsServiceLog.Summary).
+				WithDescription(fmt.Sprintf(stsServiceLog.Description, strings.Join(degraded, "; "))).
+				WithServiceName(stsServiceLog.ServiceName).
+				Build())
+	}
+	return result
 }
 
-func customerRemovedPermissions(backplaneError string) bool {
-	for _, str := range userCausedErrors {
-		re, err := regexp.Compile(str)
-		if err != nil {
-			// This should never happen on production as we would run into it during unit tests
-			log.Fatal("failed to regexp.Compile string in `userCausedErrors`")
+// customerCausedActions returns the actions for cloud credentials the customer broke, depending on the cluster state
+func customerCausedActions(cluster *cmv1.Cluster, ls *ocm.LimitedSupportReason, note types.Action, cause string) []types.Action {
+	// The jumprole failed because of a missing support role/policy:
+	// we need to figure out if we cluster state allows us to set limited support
+	// (the cluster is in a ready state, not uninstalling, installing, etc.)
+
+	logging.Debug("Checking cluster state: ", cluster.State())
+	switch cluster.State() {
+	case cmv1.ClusterStateReady:
+		// Cluster is in functional state but we can't jumprole to it: post limited support
+		return []types.Action{
+			executor.NewLimitedSupportAction(ls.Summary, ls.Details, "CCAM").Build(),
+			note,
+			executor.Silence(fmt.Sprintf("Cluster credentials are missing (%s) - limited support added", cause)),
 		}
-
-		if re.MatchString(backplaneError) {
-			return true
+	case cmv1.ClusterStateUninstalling:
+		// A cluster in uninstalling state should not alert primary - we just skip this
+		return []types.Action{
+			note,
+			executor.Silence(fmt.Sprintf("Skipped adding limited support reason '%s': cluster is already uninstalling", ls.Summary)),
+		}
+	default:
+		// Anything else is an unknown state to us and/or requires investigation.
+		// E.g. we land here if we run into a CPD alert where credentials were removed (installing state) and don't want to put it in LS yet.
+		return []types.Action{
+			note,
+			executor.Escalate(fmt.Sprintf("Cluster has invalid cloud credentials (%s) and the cluster is in state '%s'. Please investigate.", cause, cluster.State())),
 		}
 	}
+}
 
-	return false
+func (c *CloudCredentialsCheck) Name() string {
+	return "ccam"
+}
+
+func (c *CloudCredentialsCheck) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks whether the cloud credentials, OIDC provider or operator roles of the cluster are missing or changed and places the cluster in limited support if they are",
+		Platforms:         []investigation.Platform{investigation.PlatformAWS},
+		RequiredResources: []investigation.Resource{investigation.ResourceAWS},
+	}
 }
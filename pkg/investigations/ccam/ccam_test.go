@@ -1,10 +1,20 @@
 package ccam
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/aws/smithy-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
 )
 
 func TestEvaluateRandomError(t *testing.T) {
@@ -15,7 +25,7 @@ func TestEvaluateRandomError(t *testing.T) {
 			ClusterDeployment: nil,
 			AwsClient:         nil,
 			OcmClient:         nil,
-			PdClient:          nil,
+			Incident:          nil,
 		},
 		BuildError: timeoutError,
 	}
@@ -28,55 +38,366 @@ func TestEvaluateRandomError(t *testing.T) {
 	}
 }
 
-func TestCustomerRemovedPermissions(t *testing.T) {
+func TestClassifyErrorMessages(t *testing.T) {
 	tests := []struct {
 		name          string
 		errorMessage  string
-		expectedMatch bool
+		expectedClass errorClass
 	}{
 		{
-			name:          "Matching error 1",
+			name:          "Trust relationship to support role not found",
 			errorMessage:  "unable to query aws credentials from backplane: failed to determine if cluster is using isolated backlpane access: failed to get sts support jump role ARN for cluster 28testqvq0jpo1hsrch6gvbc0123test: failed to get STS Support Jump Role for cluster 28testqvq0jpo1hsrch6gvbc0qgqtest, status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is 'teste1d1-3844-46f7-82d4-643c5aeeca53': Failed to find trusted relationship to support role 'RH-Technical-Support-Access'",
-			expectedMatch: true,
+			expectedClass: errorClassTrustPolicyChanged,
 		},
 		{
-			name:          "Matching error 2",
+			name:          "Support role missing",
 			errorMessage:  "unable to query aws credentials from backplane: failed to determine if cluster is using isolated backlpane access: failed to get sts support jump role ARN for cluster test9tm92uu49s29plim5dn1sbc1test: failed to get STS Support Jump Role for cluster test9tm92uu49s29plim5dn1sbc1test, status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is 'testf5f3-6591-452f-98cb-3943edf4test': Support role, used with cluster 'test9tm92uu49s29plim5dn1sbc1test', does not exist in the customer's AWS account",
-			expectedMatch: true,
+			expectedClass: errorClassSupportRoleMissing,
 		},
 		{
-			name:          "Matching error 3",
+			name:          "Support role trust policy changed",
 			errorMessage:  "something could not assume support role in customer's account: AccessDenied: something",
-			expectedMatch: true,
+			expectedClass: errorClassTrustPolicyChanged,
 		},
 		{
-			name:          "Matching error 4",
+			name:          "Installer role trust policy changed",
 			errorMessage:  "unable to query aws credentials from backplane: failed to determine if cluster is using isolated backlpane access: failed to get sts support jump role ARN for cluster <cluster_id>: failed to get STS Support Jump Role for cluster <cluster_id>, status is 400, identifier is '400', code is 'CLUSTERS-MGMT-400' and operation identifier is '<op_id>': Please make sure IAM role 'arn:aws:iam::<cluster_aws_account_id>:role/ManagedOpenShift-Installer-Role' exists, and add 'arn:aws:iam::<ocm_aws_account_id>:role/RH-Managed-OpenShift-Installer' to the trust policy on IAM role 'arn:aws:iam::<cluster_aws_account_id>:role/ManagedOpenShift-Installer-Role': Failed to assume role: User: arn:aws:sts::<ocm_aws_account_id>:assumed-role/RH-Managed-OpenShift-Installer/OCM is not authorized to perform: sts:AssumeRole on resource: arn:aws:iam::<cluster_aws_account_id>:role/ManagedOpenShift-Installer-Role",
-			expectedMatch: true,
+			expectedClass: errorClassTrustPolicyChanged,
 		},
 		{
-			name:          "Matching error 5",
+			name:          "Installer role missing iam:GetRole",
 			errorMessage:  "unable to query aws credentials from backplane: failed to determine if cluster is using isolated backlpane access: failed to get sts support jump role ARN for cluster <cluster_id>: failed to get STS Support Jump Role for cluster <cluster_id>, status is 400, identifier is '400', code is 'CLUSTERS-MGMT-400' and operation identifier is '<op_id>': Failed to get role: User: arn:aws:sts::<cluster_aws_account_id>:assumed-role/ManagedOpenShift-Installer-Role/OCM is not authorized to perform: iam:GetRole on resource: role ManagedOpenShift-Support-Role because no identity-based policy allows the iam:GetRole action",
-			expectedMatch: true,
+			expectedClass: errorClassInstallerRoleGetRole,
 		},
 		{
-			name:          "Matching error 6",
+			name:          "Backplane assume support role denied",
 			errorMessage:  "unable to assume-role chain: could not assume support role in customer's account: operation error STS: AssumeRole, https response error StatusCode: 403, RequestID: <opid>, api error AccessDenied: User: <user> is not authorized to perform: sts:AssumeRole on resource: <role>",
-			expectedMatch: true,
+			expectedClass: errorClassTrustPolicyChanged,
 		},
 		{
-			name:          "Non-matching error",
+			name:          "Unknown error",
 			errorMessage:  "Some timeout error",
-			expectedMatch: false,
+			expectedClass: errorClassUnknown,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			match := customerRemovedPermissions(tt.errorMessage)
-			if match != tt.expectedMatch {
-				t.Errorf("customerRemovedPermissions() = %v, expectedMatch %v", match, tt.expectedMatch)
+			got := classifyError(errors.New(tt.errorMessage))
+			if got.Class != tt.expectedClass {
+				t.Errorf("classifyError() = %v, expected %v", got.Class, tt.expectedClass)
 			}
 		})
 	}
 }
+
+func TestClassifyErrorTyped(t *testing.T) {
+	ocmError := func(status int, reason string) error {
+		err, buildErr := ocmerrors.NewError().Status(status).Code(fmt.Sprintf("CLUSTERS-MGMT-%d", status)).OperationID("op-id").Reason(reason).Build()
+		if buildErr != nil {
+			t.Fatalf("failed to build OCM error: %v", buildErr)
+		}
+		return fmt.Errorf("failed to get STS Support Jump Role for cluster: %w", err)
+	}
+
+	tests := []struct {
+		name              string
+		err               error
+		expectedClass     errorClass
+		expectedCode      string
+		expectedAWSCode   string
+		expectedOperation string
+	}{
+		{
+			name:              "OCM support role missing",
+			err:               ocmError(404, "Support role, used with cluster 'test9tm92uu49s29plim5dn1sbc1test', does not exist in the customer's AWS account"),
+			expectedClass:     errorClassSupportRoleMissing,
+			expectedCode:      "CLUSTERS-MGMT-404",
+			expectedOperation: "op-id",
+		},
+		{
+			name:              "OCM outage",
+			err:               ocmError(503, "Service unavailable"),
+			expectedClass:     errorClassOCMOutage,
+			expectedCode:      "CLUSTERS-MGMT-503",
+			expectedOperation: "op-id",
+		},
+		{
+			name:              "OCM outage takes precedence over the message",
+			err:               ocmError(500, "Failed to find trusted relationship to support role 'RH-Technical-Support-Access'"),
+			expectedClass:     errorClassOCMOutage,
+			expectedCode:      "CLUSTERS-MGMT-500",
+			expectedOperation: "op-id",
+		},
+		{
+			name:              "OCM throttling",
+			err:               ocmError(429, "Too many requests"),
+			expectedClass:     errorClassThrottling,
+			expectedCode:      "CLUSTERS-MGMT-429",
+			expectedOperation: "op-id",
+		},
+		{
+			name:            "AWS throttling",
+			err:             fmt.Errorf("could not assume support role in customer's account: %w", &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"}),
+			expectedClass:   errorClassThrottling,
+			expectedAWSCode: "Throttling",
+		},
+		{
+			name:            "Organization SCP deny",
+			err:             fmt.Errorf("could not assume support role in customer's account: %w", &smithy.GenericAPIError{Code: "AccessDenied", Message: "User: <user> is not authorized to perform: sts:AssumeRole on resource: <role> with an explicit deny in a service control policy"}),
+			expectedClass:   errorClassOrgSCPDeny,
+			expectedAWSCode: "AccessDenied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			if got.Class != tt.expectedClass {
+				t.Errorf("expected class %v, got %v", tt.expectedClass, got.Class)
+			}
+			if got.Code != tt.expectedCode || got.OperationID != tt.expectedOperation {
+				t.Errorf("expected code %q and operation ID %q, got %q and %q", tt.expectedCode, tt.expectedOperation, got.Code, got.OperationID)
+			}
+			if got.AWSErrorCode != tt.expectedAWSCode {
+				t.Errorf("expected AWS error code %q, got %q", tt.expectedAWSCode, got.AWSErrorCode)
+			}
+		})
+	}
+}
+
+func TestRunClassifiedErrors(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("test-cluster").State(cmv1.ClusterStateReady).Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+
+	t.Run("customer caused error sets class specific limited support", func(t *testing.T) {
+		input := investigation.ResourceBuilderMock{
+			Resources: &investigation.Resources{Cluster: cluster},
+			BuildError: investigation.AWSClientError{
+				ClusterID: "test-cluster",
+				Err:       errors.New("is not authorized to perform: iam:GetRole on resource: role ManagedOpenShift-Support-Role"),
+			},
+		}
+		inv := CloudCredentialsCheck{}
+		result, err := inv.Run(&input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Actions) != 3 {
+			t.Fatalf("expected limited support, note and silence actions, got %d actions", len(result.Actions))
+		}
+		ls, ok := result.Actions[0].(*executor.LimitedSupportAction)
+		if !ok {
+			t.Fatalf("expected a limited support action, got %T", result.Actions[0])
+		}
+		if ls.Reason.Details != classLimitedSupport[errorClassInstallerRoleGetRole].Details {
+			t.Errorf("expected the limited support details of %s, got %q", errorClassInstallerRoleGetRole, ls.Reason.Details)
+		}
+	})
+
+	t.Run("throttling is retried", func(t *testing.T) {
+		input := investigation.ResourceBuilderMock{
+			Resources: &investigation.Resources{Cluster: cluster},
+			BuildError: investigation.AWSClientError{
+				ClusterID: "test-cluster",
+				Err:       &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"},
+			},
+		}
+		inv := CloudCredentialsCheck{}
+		result, err := inv.Run(&input)
+		if !investigation.IsInfrastructureError(err) {
+			t.Fatalf("expected an infrastructure error, got %v", err)
+		}
+		if len(result.Actions) != 0 {
+			t.Errorf("expected no actions, got %d", len(result.Actions))
+		}
+	})
+}
+
+const (
+	testProviderARN = "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"
+	testRoleARN     = "arn:aws:iam::123456789012:role/prefix-openshift-ingress-operator-cloud-credentials"
+	testRoleName    = "prefix-openshift-ingress-operator-cloud-credentials"
+)
+
+func testTrustPolicy(providerARN, subject string) string {
+	return fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Federated":%q},"Action":"sts:AssumeRoleWithWebIdentity","Condition":{"StringEquals":{"oidc.example.com/abc:sub":[%q]}}}]}`, providerARN, subject)
+}
+
+func testSTSCluster(t *testing.T, state cmv1.ClusterState, managedPolicies bool) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().ID("test-cluster").State(state).AWS(cmv1.NewAWS().STS(cmv1.NewSTS().
+		Enabled(true).
+		ManagedPolicies(managedPolicies).
+		RoleARN("arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role").
+		OIDCEndpointURL("https://oidc.example.com/abc").
+		OperatorIAMRoles(cmv1.NewOperatorIAMRole().Name("cloud-credentials").Namespace("openshift-ingress-operator").ServiceAccount("ingress-operator").RoleARN(testRoleARN)),
+	)).Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+	return cluster
+}
+
+func TestValidateSTS(t *testing.T) {
+	validRole := aws.IAMRole{
+		Name:               testRoleName,
+		ARN:                testRoleARN,
+		TrustPolicy:        testTrustPolicy(testProviderARN, "system:serviceaccount:openshift-ingress-operator:ingress-operator"),
+		AttachedPolicyARNs: []string{"arn:aws:iam::123456789012:policy/prefix-openshift-ingress-operator-cloud-credentials"},
+	}
+
+	tests := []struct {
+		name            string
+		managedPolicies bool
+		data            invtesting.AWSData
+		expectedKinds   []stsProblemKind
+	}{
+		{
+			name: "valid roles and provider",
+			data: invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: validRole}, OIDCProviderARNs: []string{testProviderARN}},
+		},
+		{
+			name:          "OIDC provider deleted",
+			data:          invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: validRole}},
+			expectedKinds: []stsProblemKind{stsOIDCProviderMissing},
+		},
+		{
+			name:          "operator role deleted",
+			data:          invtesting.AWSData{OIDCProviderARNs: []string{testProviderARN}},
+			expectedKinds: []stsProblemKind{stsOperatorRoleMissing},
+		},
+		{
+			name: "trust policy of another provider",
+			data: invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: func() aws.IAMRole {
+				r := validRole
+				r.TrustPolicy = testTrustPolicy("arn:aws:iam::123456789012:oidc-provider/oidc.example.com/other", "system:serviceaccount:openshift-ingress-operator:ingress-operator")
+				return r
+			}()}, OIDCProviderARNs: []string{testProviderARN}},
+			expectedKinds: []stsProblemKind{stsOperatorRoleTrustChanged},
+		},
+		{
+			name: "trust policy of another service account",
+			data: invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: func() aws.IAMRole {
+				r := validRole
+				r.TrustPolicy = testTrustPolicy(testProviderARN, "system:serviceaccount:other:other")
+				return r
+			}()}, OIDCProviderARNs: []string{testProviderARN}},
+			expectedKinds: []stsProblemKind{stsOperatorRoleTrustChanged},
+		},
+		{
+			name: "trust policy with a wildcard subject",
+			data: invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: func() aws.IAMRole {
+				r := validRole
+				r.TrustPolicy = testTrustPolicy(testProviderARN, "system:serviceaccount:openshift-ingress-operator:*")
+				return r
+			}()}, OIDCProviderARNs: []string{testProviderARN}},
+		},
+		{
+			name: "policies detached",
+			data: invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: func() aws.IAMRole {
+				r := validRole
+				r.AttachedPolicyARNs = nil
+				return r
+			}()}, OIDCProviderARNs: []string{testProviderARN}},
+			expectedKinds: []stsProblemKind{stsOperatorRolePolicyDetached},
+		},
+		{
+			name:            "managed policy replaced by a customer policy",
+			managedPolicies: true,
+			data:            invtesting.AWSData{IAMRoles: map[string]aws.IAMRole{testRoleName: validRole}, OIDCProviderARNs: []string{testProviderARN}},
+			expectedKinds:   []stsProblemKind{stsOperatorRolePolicyDetached},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := testSTSCluster(t, cmv1.ClusterStateReady, tt.managedPolicies)
+			problems, err := validateSTS(context.Background(), cluster, &invtesting.FakeAWSClient{Data: tt.data})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var kinds []stsProblemKind
+			for _, p := range problems {
+				kinds = append(kinds, p.Kind)
+			}
+			if !slices.Equal(kinds, tt.expectedKinds) {
+				t.Errorf("expected problems %v, got %v", tt.expectedKinds, problems)
+			}
+		})
+	}
+}
+
+func TestValidateSTSNonSTSCluster(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("test-cluster").Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+	awsCli := &invtesting.FakeAWSClient{Data: invtesting.AWSData{Err: errors.New("IAM must not be called")}}
+	problems, err := validateSTS(context.Background(), cluster, awsCli)
+	if err != nil || problems != nil {
+		t.Errorf("expected no problems for a non STS cluster, got %v, %v", problems, err)
+	}
+}
+
+func TestRunSTSProblems(t *testing.T) {
+	t.Run("missing operator role sets limited support", func(t *testing.T) {
+		input := investigation.ResourceBuilderMock{
+			Resources: &investigation.Resources{
+				Cluster:   testSTSCluster(t, cmv1.ClusterStateReady, false),
+				AwsClient: &invtesting.FakeAWSClient{Data: invtesting.AWSData{OIDCProviderARNs: []string{testProviderARN}}},
+			},
+		}
+		inv := CloudCredentialsCheck{}
+		result, err := inv.Run(&input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.StopInvestigations == nil {
+			t.Errorf("expected the investigations to stop")
+		}
+		ls, ok := result.Actions[0].(*executor.LimitedSupportAction)
+		if !ok {
+			t.Fatalf("expected a limited support action, got %T", result.Actions[0])
+		}
+		if !strings.Contains(ls.Reason.Details, testRoleARN+" does not exist") {
+			t.Errorf("expected the limited support details to name the missing role, got %q", ls.Reason.Details)
+		}
+	})
+
+	t.Run("detached policies send a service log", func(t *testing.T) {
+		input := investigation.ResourceBuilderMock{
+			Resources: &investigation.Resources{
+				Cluster: testSTSCluster(t, cmv1.ClusterStateReady, false),
+				AwsClient: &invtesting.FakeAWSClient{Data: invtesting.AWSData{
+					IAMRoles: map[string]aws.IAMRole{testRoleName: {
+						Name:        testRoleName,
+						ARN:         testRoleARN,
+						TrustPolicy: testTrustPolicy(testProviderARN, "system:serviceaccount:openshift-ingress-operator:ingress-operator"),
+					}},
+					OIDCProviderARNs: []string{testProviderARN},
+				}},
+			},
+		}
+		inv := CloudCredentialsCheck{}
+		result, err := inv.Run(&input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.StopInvestigations != nil {
+			t.Errorf("expected the investigations to continue, got %v", result.StopInvestigations)
+		}
+		if len(result.Actions) != 2 {
+			t.Fatalf("expected note and service log actions, got %d actions", len(result.Actions))
+		}
+		sl, ok := result.Actions[1].(*executor.ServiceLogAction)
+		if !ok {
+			t.Fatalf("expected a service log action, got %T", result.Actions[1])
+		}
+		if !strings.Contains(sl.ServiceLog.Description, testRoleARN+" has no permissions policy attached") {
+			t.Errorf("expected the service log to name the role, got %q", sl.ServiceLog.Description)
+		}
+	})
+}
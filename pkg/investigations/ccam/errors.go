@@ -0,0 +1,154 @@
+package ccam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/aws/smithy-go"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+// errorClass is the class of an error that prevented CAD from getting credentials to the cloud account of a cluster
+type errorClass string
+
+const (
+	// errorClassSupportRoleMissing: the customer deleted the support role
+	errorClassSupportRoleMissing errorClass = "SupportRoleMissing"
+	// errorClassTrustPolicyChanged: the customer changed the trust policy of the support or installer role
+	errorClassTrustPolicyChanged errorClass = "TrustPolicyChanged"
+	// errorClassInstallerRoleGetRole: the customer removed the iam:GetRole permission from the installer role
+	errorClassInstallerRoleGetRole errorClass = "InstallerRoleMissingGetRole"
+	// errorClassOrgSCPDeny: a service control policy of the customer's AWS organization denies the access
+	errorClassOrgSCPDeny errorClass = "OrgSCPDeny"
+	// errorClassThrottling: AWS or OCM rate limited the requests
+	errorClassThrottling errorClass = "Throttling"
+	// errorClassOCMOutage: OCM failed to serve the request
+	errorClassOCMOutage errorClass = "OCMOutage"
+	// errorClassUnknown: any other error, e.g. a backplane failure
+	errorClassUnknown errorClass = "Unknown"
+)
+
+// customerCaused reports whether the customer caused errors of the class, and the cluster can be put in limited support for them
+func (c errorClass) customerCaused() bool {
+	_, ok := classLimitedSupport[c]
+	return ok
+}
+
+// classLimitedSupport are the limited support reasons of the error classes caused by the customer
+var classLimitedSupport = map[errorClass]*ocm.LimitedSupportReason{
+	errorClassSupportRoleMissing: {
+		Summary: "Restore missing cloud credentials",
+		Details: "Your cluster requires you to take action because Red Hat is not able to access the infrastructure: the support role of the cluster does not exist in your AWS account anymore. Please restore the support role and its policy as they were provided during install",
+	},
+	errorClassTrustPolicyChanged: {
+		Summary: "Restore missing cloud credentials",
+		Details: "Your cluster requires you to take action because Red Hat is not able to access the infrastructure: Red Hat is not allowed to assume the support or installer role of the cluster anymore. Please restore the trust policies of the roles as they were provided during install",
+	},
+	errorClassInstallerRoleGetRole: {
+		Summary: "Restore missing cloud credentials",
+		Details: "Your cluster requires you to take action because Red Hat is not able to access the infrastructure: the installer role of the cluster is not allowed to perform iam:GetRole anymore. Please restore the permissions policy of the installer role as it was provided during install",
+	},
+	errorClassOrgSCPDeny: {
+		Summary: "Restore missing cloud credentials",
+		Details: "Your cluster requires you to take action because Red Hat is not able to access the infrastructure: a service control policy of your AWS organization explicitly denies the access. Please allow Red Hat's access to the cluster's account in the service control policies of your organization",
+	},
+}
+
+// errorPatterns map the messages of errors to their class, the first matching pattern wins
+var errorPatterns = []struct {
+	class   errorClass
+	pattern *regexp.Regexp
+}{
+	// An explicit deny of an SCP can show up in any AccessDenied error, so it takes precedence, e.g.:
+	// User: arn:aws:sts::<id>:assumed-role/<role>/<session> is not authorized to perform: sts:AssumeRole on resource: <role> with an explicit deny in a service control policy
+	{errorClassOrgSCPDeny, regexp.MustCompile(`with an explicit deny in a service control policy`)},
+
+	// Customer deleted the support role, e.g.:
+	// status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is '<id>': Support role, used with cluster '<cluster_id>', does not exist in the customer's AWS account
+	{errorClassSupportRoleMissing, regexp.MustCompile(`Support role, used with cluster '[a-z0-9]{32}', does not exist in the customer's AWS account`)},
+
+	// OCM can't access the installer role to determine the trust relationship on the support role,
+	// therefore we don't know if it's the isolated access flow or the old flow, e.g.:
+	// status is 404, identifier is '404', code is 'CLUSTERS-MGMT-404' and operation identifier is '<id>': Failed to find trusted relationship to support role 'RH-Technical-Support-Access'
+	// See https://issues.redhat.com/browse/OSD-24270
+	{errorClassTrustPolicyChanged, regexp.MustCompile(`Failed to find trusted relationship to support role 'RH-Technical-Support-Access'`)},
+
+	// OCM role can't access the installer role, this happens when customer deletes/modifies the trust policy of the installer role, e.g.:
+	// status is 400, identifier is '400', code is 'CLUSTERS-MGMT-400' and operation identifier is '<id>': Please make sure IAM role 'arn:aws:iam::<ocm_role_aws_id>:role/ManagedOpenShift-Installer-Role' exists, and add 'arn:aws:iam::<id>:role/RH-Managed-OpenShift-Installer' to the trust policy on IAM role 'arn:aws:iam::<id>:role/ManagedOpenShift-Installer-Role': Failed to assume role: User: arn:aws:sts::<id>:assumed-role/RH-Managed-OpenShift-Installer/OCM is not authorized to perform: sts:AssumeRole on resource: arn:aws:iam::<customer_aws_id>:role/ManagedOpenShift-Installer-Role
+	{errorClassTrustPolicyChanged, regexp.MustCompile(`RH-Managed-OpenShift-Installer/OCM is not authorized to perform: sts:AssumeRole on resource`)},
+
+	// This error is the response from backplane calls when:
+	// trust policy of ManagedOpenShift-Support-Role is changed
+	{errorClassTrustPolicyChanged, regexp.MustCompile(`could not assume support role in customer's account: .*AccessDenied:`)},
+
+	// Customer removed the `GetRole` permission from the Installer role.
+	// Failed to get role: User: arn:aws:sts::<id>:assumed-role/ManagedOpenShift-Installer-Role/OCM is not authorized to perform: iam:GetRole on resource: role ManagedOpenShift-Support-Role because no identity-based policy allows the iam:GetRole action
+	{errorClassInstallerRoleGetRole, regexp.MustCompile(`is not authorized to perform: iam:GetRole on resource: role`)},
+}
+
+// throttlingErrorCodes are the error codes AWS APIs return when rate limiting requests
+var throttlingErrorCodes = []string{"Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequestsException"}
+
+// classifiedError is an error getting credentials to the cloud account of a cluster along with its class
+type classifiedError struct {
+	Class errorClass
+	// Status, Code and OperationID identify the OCM error in the chain, if any, e.g. 404, CLUSTERS-MGMT-404
+	Status      int
+	Code        string
+	OperationID string
+	// AWSErrorCode is the code of the AWS API error in the chain, if any, e.g. AccessDenied
+	AWSErrorCode string
+	Err          error
+}
+
+// String describes the error for PagerDuty notes
+func (e classifiedError) String() string {
+	s := fmt.Sprintf("class %s", e.Class)
+	if e.Code != "" {
+		s += fmt.Sprintf(", OCM status %d, code %s, operation ID %s", e.Status, e.Code, e.OperationID)
+	}
+	if e.AWSErrorCode != "" {
+		s += fmt.Sprintf(", AWS error code %s", e.AWSErrorCode)
+	}
+	return s + ": " + e.Err.Error()
+}
+
+// classifyError classifies an error getting credentials to the cloud account of a cluster.
+// The OCM and AWS API errors in the chain take precedence over the message of the error,
+// as only errors the customer caused should put the cluster in limited support.
+func classifyError(err error) classifiedError {
+	classified := classifiedError{Class: errorClassUnknown, Err: err}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		classified.AWSErrorCode = apiErr.ErrorCode()
+	}
+	var ocmErr *ocmerrors.Error
+	if errors.As(err, &ocmErr) {
+		classified.Status = ocmErr.Status()
+		classified.Code = ocmErr.Code()
+		classified.OperationID = ocmErr.OperationID()
+	}
+
+	switch {
+	case classified.Status == http.StatusTooManyRequests || slices.Contains(throttlingErrorCodes, classified.AWSErrorCode):
+		classified.Class = errorClassThrottling
+		return classified
+	case classified.Status >= http.StatusInternalServerError:
+		classified.Class = errorClassOCMOutage
+		return classified
+	}
+
+	message := err.Error()
+	for _, p := range errorPatterns {
+		if p.pattern.MatchString(message) {
+			classified.Class = p.class
+			break
+		}
+	}
+	return classified
+}
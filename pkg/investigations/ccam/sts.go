@@ -0,0 +1,236 @@
+package ccam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+// stsProblemKind is the kind of a misconfiguration of the roles or the OIDC provider of an STS cluster
+type stsProblemKind string
+
+const (
+	// stsOIDCProviderMissing: the customer deleted the IAM OIDC provider of the cluster
+	stsOIDCProviderMissing stsProblemKind = "OIDCProviderMissing"
+	// stsOperatorRoleMissing: the customer deleted an operator role
+	stsOperatorRoleMissing stsProblemKind = "OperatorRoleMissing"
+	// stsOperatorRoleTrustChanged: the trust policy of an operator role doesn't allow the cluster's service account anymore
+	stsOperatorRoleTrustChanged stsProblemKind = "OperatorRoleTrustPolicyChanged"
+	// stsOperatorRolePolicyDetached: the customer detached the permissions policies of an operator role
+	stsOperatorRolePolicyDetached stsProblemKind = "OperatorRolePolicyDetached"
+)
+
+// blocksOperators reports whether operators can't get any credentials because of problems of the kind,
+// as opposed to getting credentials with missing permissions
+func (k stsProblemKind) blocksOperators() bool {
+	return k != stsOperatorRolePolicyDetached
+}
+
+// managedPolicyPrefix is the prefix of the ARNs of AWS managed policies, used by clusters with managed policies
+const managedPolicyPrefix = "arn:aws:iam::aws:policy/"
+
+var stsLimitedSupport = ocm.LimitedSupportReason{
+	Summary: "Restore missing cloud credentials",
+	Details: "Your cluster requires you to take action because its operators are not able to get credentials for the infrastructure: %s. Please restore the OIDC provider and the operator roles as they were provided during install",
+}
+
+var stsServiceLog = ocm.ServiceLog{
+	Severity:     "Warning",
+	Summary:      "Action required: Restore operator role policies",
+	ServiceName:  "SREManualAction",
+	Description:  "Your cluster's operators are missing permissions on the infrastructure: %s. Please re-attach the policies to the operator roles as they were provided during install, otherwise the cluster may not be able to operate as expected.",
+	InternalOnly: false,
+}
+
+// stsProblem is a misconfiguration of the roles or the OIDC provider of an STS cluster
+type stsProblem struct {
+	Kind stsProblemKind
+	// Resource is the ARN of the role or provider
+	Resource string
+	Detail   string
+}
+
+func (p stsProblem) String() string {
+	return fmt.Sprintf("%s %s", p.Resource, p.Detail)
+}
+
+// trustPolicy is the part of an IAM trust policy needed to validate operator roles
+type trustPolicy struct {
+	Statement []struct {
+		Effect    string
+		Action    stringOrSlice
+		Principal struct {
+			Federated stringOrSlice
+		}
+		Condition map[string]map[string]stringOrSlice
+	}
+}
+
+// stringOrSlice is an IAM policy element that is either a string or a list of strings
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = []string{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// validateSTS checks the OIDC provider and the operator roles OCM expects for an STS cluster through IAM,
+// and returns their problems. It returns nothing for clusters that don't use STS.
+func validateSTS(ctx context.Context, cluster *cmv1.Cluster, awsCli aws.Client) ([]stsProblem, error) {
+	sts := cluster.AWS().STS()
+	if sts.Empty() || !sts.Enabled() {
+		return nil, nil
+	}
+
+	providerARN, err := oidcProviderARN(sts)
+	if err != nil {
+		return nil, err
+	}
+	var problems []stsProblem
+	exists, err := awsCli.OIDCProviderExists(ctx, providerARN)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		problems = append(problems, stsProblem{Kind: stsOIDCProviderMissing, Resource: providerARN, Detail: "does not exist"})
+	}
+
+	for _, operatorRole := range sts.OperatorIAMRoles() {
+		problem, err := validateOperatorRole(ctx, awsCli, operatorRole, providerARN, sts.ManagedPolicies())
+		if err != nil {
+			return nil, err
+		}
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	return problems, nil
+}
+
+// oidcProviderARN returns the ARN of the IAM OIDC provider of the cluster, which is in the account of the installer role
+func oidcProviderARN(sts *cmv1.STS) (string, error) {
+	installerRole, err := arn.Parse(sts.RoleARN())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse installer role ARN %q: %w", sts.RoleARN(), err)
+	}
+	issuer := sts.OIDCEndpointURL()
+	if issuer == "" {
+		issuer = sts.OidcConfig().IssuerUrl()
+	}
+	if issuer == "" {
+		return "", fmt.Errorf("cluster has no OIDC endpoint URL")
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", installerRole.Partition, installerRole.AccountID, strings.TrimPrefix(issuer, "https://")), nil
+}
+
+// validateOperatorRole returns the problem of the operator role, if any
+func validateOperatorRole(ctx context.Context, awsCli aws.Client, operatorRole *cmv1.OperatorIAMRole, providerARN string, managedPolicies bool) (*stsProblem, error) {
+	roleARN := operatorRole.RoleARN()
+	// Roles can have a path, the name is the last segment of the ARN
+	name := roleARN[strings.LastIndex(roleARN, "/")+1:]
+	role, err := awsCli.GetIAMRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return &stsProblem{Kind: stsOperatorRoleMissing, Resource: roleARN, Detail: "does not exist"}, nil
+	}
+
+	var policy trustPolicy
+	if err := json.Unmarshal([]byte(role.TrustPolicy), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse the trust policy of role %s: %w", roleARN, err)
+	}
+	if !policy.trusts(providerARN, serviceAccountSubject(operatorRole)) {
+		return &stsProblem{
+			Kind:     stsOperatorRoleTrustChanged,
+			Resource: roleARN,
+			Detail:   fmt.Sprintf("does not trust the service account %s of the OIDC provider", serviceAccountSubject(operatorRole)),
+		}, nil
+	}
+
+	attached := len(role.AttachedPolicyARNs) > 0
+	if managedPolicies {
+		attached = slices.ContainsFunc(role.AttachedPolicyARNs, func(a string) bool { return strings.HasPrefix(a, managedPolicyPrefix) })
+	}
+	if !attached {
+		return &stsProblem{Kind: stsOperatorRolePolicyDetached, Resource: roleARN, Detail: "has no permissions policy attached"}, nil
+	}
+	return nil, nil
+}
+
+// serviceAccountSubject returns the subject of the service account tokens the operator role is assumed with
+func serviceAccountSubject(operatorRole *cmv1.OperatorIAMRole) string {
+	serviceAccount := operatorRole.ServiceAccount()
+	if serviceAccount == "" {
+		serviceAccount = operatorRole.Name()
+	}
+	return fmt.Sprintf("system:serviceaccount:%s:%s", operatorRole.Namespace(), serviceAccount)
+}
+
+// trusts reports whether the policy allows the service account to assume the role with tokens of the OIDC provider
+func (p trustPolicy) trusts(providerARN, subject string) bool {
+	for _, s := range p.Statement {
+		if s.Effect != "Allow" || !slices.Contains(s.Action, "sts:AssumeRoleWithWebIdentity") || !slices.Contains(s.Principal.Federated, providerARN) {
+			continue
+		}
+		// The subject condition is keyed by the provider's issuer, e.g. oidc.example.com/abc:sub
+		issuer := providerARN[strings.Index(providerARN, "oidc-provider/")+len("oidc-provider/"):]
+		subjects, restricted := conditionValues(s.Condition, issuer+":sub")
+		if !restricted || slices.ContainsFunc(subjects, func(pattern string) bool { return matchesIAMPattern(pattern, subject) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionValues returns the values of the key in the string conditions of a statement
+func conditionValues(condition map[string]map[string]stringOrSlice, key string) ([]string, bool) {
+	var values []string
+	found := false
+	for operator, keys := range condition {
+		if !strings.HasPrefix(operator, "String") {
+			continue
+		}
+		if v, ok := keys[key]; ok {
+			values = append(values, v...)
+			found = true
+		}
+	}
+	return values, found
+}
+
+// matchesIAMPattern matches a value against an IAM condition value, which may contain * wildcards
+func matchesIAMPattern(pattern, value string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == value
+	}
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
@@ -2,14 +2,18 @@
 package chgm
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
 	ec2v2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
@@ -31,19 +35,44 @@ var (
 	}
 )
 
-type Investigation struct{}
+type Investigation struct {
+	// ActorPolicy decides whether the principals that stopped instances are authorized. Its rules are
+	// evaluated before the built-in defaultActorPolicy on AWS, and before gcpActorPolicy's defaults on GCP.
+	ActorPolicy *config.ActorPolicy
+}
 
 // Run runs the investigation for a triggered chgm pagerduty event
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation. On GCP clusters, the instances and network are checked
+// with the GCP client, whose requests are cancelled with ctx.
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
-	r, err := rb.WithClusterDeployment().WithAwsClient().Build()
+	r, err := rb.WithClusterDeployment().Build()
+	if err != nil {
+		return result, err
+	}
+	_, cloud := investigation.ClusterPlatforms(r.Cluster)
+	isGCP := cloud == investigation.PlatformGCP
+	if isGCP {
+		r, err = rb.WithGcpClient().Build()
+	} else {
+		r, err = rb.WithAwsClient().Build()
+	}
 	if err != nil {
 		return result, err
 	}
 	r.Notes = notewriter.New("CHGM", logging.RawLogger)
 
 	// 1. Check if the user stopped instances
-	res, err := investigateStoppedInstances(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	var res investigateInstancesOutput
+	if isGCP {
+		res, err = investigateStoppedInstancesGCP(ctx, r.Cluster, r.ClusterDeployment, r.GcpClient, r.OcmClient, i.ActorPolicy)
+	} else {
+		res, err = investigateStoppedInstances(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient, i.actorPolicy())
+	}
 	if err != nil {
 		// Check if this is a transient infrastructure error (AWS/OCM API failures)
 		// These should trigger a retry of the entire investigation (runInvestigationWithRetry)
@@ -71,9 +100,22 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	}
 	logging.Debugf("the investigation returned: [infras running: %d] - [masters running: %d]", res.RunningInstances.Infra, res.RunningInstances.Master)
 
+	if len(res.AWSInitiated) != 0 {
+		r.Notes.AppendSuccess("Instances were stopped or terminated by AWS, not the customer:\n%s", strings.Join(res.AWSInitiated, "\n"))
+	}
+	if len(res.InstanceHealth) != 0 {
+		r.Notes.AppendWarning("AWS reports scheduled events or failed status checks for instances:\n%s", strings.Join(res.InstanceHealth, "\n"))
+	}
+	if res.InstanceHealthError != "" {
+		r.Notes.AppendWarning("Could not check the status of instances: %s", res.InstanceHealthError)
+	}
+
 	if !res.UserAuthorized {
 		logging.Infof("Instances were stopped by unauthorized user: %s / arn: %s", res.User.UserName, res.User.IssuerUserName)
 		r.Notes.AppendWarning("Customer stopped instances.")
+		if res.Classification != "" {
+			r.Notes.AppendWarning("Actor classified as customer by the actor policy: %s", res.Classification)
+		}
 
 		result.Actions = append(
 			executor.NoteAndReportFrom(r.Notes, r.Cluster.ID(), i.Name()),
@@ -84,6 +126,9 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 		return result, nil
 	}
 	r.Notes.AppendSuccess("Customer did not stop nodes.")
+	if res.Classification != "" {
+		r.Notes.AppendSuccess("Actor authorized by the actor policy: %s", res.Classification)
+	}
 	logging.Info("The customer has not stopped/terminated any nodes.")
 
 	// 2. Check if the cluster was hibernated and has recently resumed.
@@ -99,8 +144,49 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 		logging.Info("The cluster was not hibernated for too long.")
 	}
 
-	// 3. Check if the customer blocked egresses
-	verifierResult, failureReason, err := networkverifier.Run(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	// 3. Check who changed the network of the cluster's VPC recently
+	var network networkChanges
+	if !isGCP {
+		network, err = investigateNetworkChanges(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, i.actorPolicy(), time.Now().Add(-networkChangesLookback))
+		if err != nil {
+			logging.Warnf("could not investigate network changes: %v", err)
+			r.Notes.AppendWarning("Could not check CloudTrail for network changes: %s", err.Error())
+		} else if len(network.Changes) != 0 {
+			lines := make([]string, 0, len(network.Changes))
+			for _, change := range network.Changes {
+				lines = append(lines, change.String())
+			}
+			r.Notes.AppendWarning("Network changes to the cluster's VPC in the last %.0f hours:\n%s", networkChangesLookback.Hours(), strings.Join(lines, "\n"))
+		}
+		if network.Truncated {
+			r.Notes.AppendWarning("CloudTrail had more events of the cluster's network resources than were looked up, older network changes may be missing")
+		}
+	}
+	if removed := customerRemovedEgress(network.Changes); len(removed) != 0 {
+		if network.egressStillRemoved() {
+			logging.Infof("Customer removed the egress of the cluster: %v", removed)
+			r.Notes.AppendWarning("Customer removed the cluster's egress, route tables without an active default route: %s", strings.Join(network.Resources.RouteTablesWithoutEgress, ", "))
+
+			result.Actions = append(
+				executor.NoteAndReportFrom(r.Notes, r.Cluster.ID(), i.Name()),
+				executor.NewLimitedSupportAction(networkChangeLS.Summary, fmt.Sprintf(networkChangeLS.Details, strings.Join(removed, ", ")), "NetworkEgressRemoved").
+					Build(),
+				executor.Silence("Customer removed the cluster's egress - cluster in limited support"),
+			)
+			return result, nil
+		}
+		logging.Infof("Customer removed the egress of the cluster, but all route tables have an active default route again: %v", removed)
+		r.Notes.AppendWarning("Customer removed the cluster's egress (%s), but all route tables have an active default route again - checking egress with the network verifier", strings.Join(removed, ", "))
+	}
+
+	// 4. Check if the customer blocked egresses
+	var verifierResult networkverifier.VerifierResult
+	var failureReason string
+	if isGCP {
+		verifierResult, failureReason, err = networkverifier.RunGCP(ctx, r.Cluster, r.ClusterDeployment, r.GcpClient)
+	} else {
+		verifierResult, failureReason, err = networkverifier.Run(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	}
 	if err != nil {
 		logging.Errorf("Network verifier ran into an error: %s", err.Error())
 		r.Notes.AppendWarning("NetworkVerifier failed to run:\n %s", err.Error())
@@ -125,7 +211,7 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 		}
 
 		docLink := ocm.DocumentationLink(product, ocm.DocumentationTopicPrivatelinkFirewall)
-		egressSL := createEgressSL(failureReason, docLink)
+		egressSL := createEgressSL(failureReason, docLink, customerNetworkChanges(network.Changes))
 
 		r.Notes.AppendWarning("NetworkVerifier found unreachable targets, but deadmanssnitch is not blocked! \n⚠️ Please investigate this cluster.\nUnreachable: \n%s", failureReason)
 
@@ -156,6 +242,14 @@ func (i *Investigation) Name() string {
 	return "chgm"
 }
 
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Investigates clusters that have gone missing (ClusterHasGoneMissing) by looking for stopped instances and egress failures",
+		Platforms:         []investigation.Platform{investigation.PlatformClassic, investigation.PlatformAWS, investigation.PlatformGCP},
+		RequiredResources: []investigation.Resource{investigation.ResourceAWS, investigation.ResourceGCP},
+	}
+}
+
 // hasRecentlyResumed checks if the cluster was woken up from
 // hibernation within the last 2h. In that case, the internal
 // certificates of the kubelets could have expired and CSRs need to be approved
@@ -169,50 +263,49 @@ func hasRecentlyResumed(hibernationPeriods []*hibernationPeriod, now time.Time)
 	return now.Sub(latestHibernation.DehibernationTime) <= recentWakeupTime
 }
 
-// isUserAllowedToStop verifies if a user is allowed to stop/terminate instances
-// For this, we use a whitelist of partial strings that can be SRE
-// based on findings in https://issues.redhat.com/browse/OSD-16042
-func isUserAllowedToStop(username, issuerUsername string, ccsEnabled bool) bool {
-	// Users are represented by Username in cloudtrail events
-	allowedUsersPartialStrings := []string{
-		// 'openshift-machine-api-aws' is a role for STS, and a user for ROSA non-STS and non-CCS
-		"openshift-machine-api-aws", // Infra nodes/Autoscaling
-
-		"osdCcsAdmin",     // ROSA-STS - doesn't start/stop instances but is our user
-		"osdManagedAdmin", // ROSA non-STS, OSD non-CCS - install/uninstall node run/terminate
-
-		// Might not exist - better safe than sorry
-		"RH-SRE-",
-	}
-
-	for _, partialUserString := range allowedUsersPartialStrings {
-		if strings.Contains(username, partialUserString) {
-			return true
-		}
-	}
-
-	// Roles are represented by issuerUsername in cloudtrail events
-	allowedRolesPartialStrings := []string{
-		// 'openshift-machine-api-aws' is a role for STS, and a user for ROSA non-STS and non-CCS
-		"openshift-machine-api-aws", // Infra nodes/Autoscaling
-
-		"-Installer-Role",           // ROSA-STS - install/uninstall node run/terminate
-		"-Support-Role",             // ROSA-STS - SRE work
-		"ManagedOpenShift-Support-", // ROSA- non-STS - SRE work
-	}
-
-	// Check cluster flavor, as 'OrganizationAccountAccessRole' is SRE for non-CCS and the user for ROSA
-	if !ccsEnabled {
-		allowedRolesPartialStrings = append(allowedRolesPartialStrings, "OrganizationAccountAccessRole")
+// defaultActorPolicy classifies the principals that stopped or terminated instances, based on findings in
+// https://issues.redhat.com/browse/OSD-16042. Users are the Username of CloudTrail events, and roles
+// the issuer of the session. Rules configured in the actor policy take precedence.
+var defaultActorPolicy = mustActorPolicy([]config.ActorRule{
+	// 'openshift-machine-api-aws' is a role for STS, and a user for ROSA non-STS and non-CCS
+	{Name: "machine-api-user", UserNames: []string{"openshift-machine-api-aws"}, Verdict: config.ActorVerdictAllow},
+	{Name: "machine-api-role", IssuerNames: []string{"openshift-machine-api-aws"}, Verdict: config.ActorVerdictAllow},
+
+	// ROSA-STS - doesn't start/stop instances but is our user
+	{Name: "osd-ccs-admin", UserNames: []string{"osdCcsAdmin"}, Verdict: config.ActorVerdictAllow},
+	// ROSA non-STS, OSD non-CCS - install/uninstall node run/terminate
+	{Name: "osd-managed-admin", UserNames: []string{"osdManagedAdmin"}, Verdict: config.ActorVerdictAllow},
+	// Might not exist - better safe than sorry
+	{Name: "sre-user", UserNames: []string{"RH-SRE-"}, Verdict: config.ActorVerdictAllow},
+
+	// ROSA-STS - install/uninstall node run/terminate
+	{Name: "installer-role", IssuerNames: []string{"-Installer-Role"}, Verdict: config.ActorVerdictAllow},
+	// ROSA-STS - SRE work
+	{Name: "support-role", IssuerNames: []string{"-Support-Role"}, Verdict: config.ActorVerdictAllow},
+	// ROSA non-STS - SRE work
+	{Name: "managed-openshift-support-role", IssuerNames: []string{"ManagedOpenShift-Support-"}, Verdict: config.ActorVerdictAllow},
+	// 'OrganizationAccountAccessRole' is SRE for non-CCS and the customer for CCS
+	{Name: "non-ccs-organization-access-role", IssuerNames: []string{"OrganizationAccountAccessRole"}, CCS: new(false), Verdict: config.ActorVerdictAllow},
+}...)
+
+// mustActorPolicy creates a policy from built-in rules, which are valid unless the code is wrong
+func mustActorPolicy(rules ...config.ActorRule) *config.ActorPolicy {
+	policy, err := config.NewActorPolicy(rules...)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in actor policy: %v", err))
 	}
+	return policy
+}
 
-	for _, partialRoleString := range allowedRolesPartialStrings {
-		if strings.Contains(issuerUsername, partialRoleString) {
-			return true
-		}
+// actorPolicy returns the configured actor policy rules followed by the default rules. Both are already
+// compiled: the configured policy when the config was loaded, and the defaults at package initialisation.
+func (i *Investigation) actorPolicy() *config.ActorPolicy {
+	policy := &config.ActorPolicy{}
+	if i.ActorPolicy != nil {
+		policy.Rules = append(policy.Rules, i.ActorPolicy.Rules...)
 	}
-
-	return false
+	policy.Rules = append(policy.Rules, defaultActorPolicy.Rules...)
+	return policy
 }
 
 // userInfo will hold the extracted user details
@@ -238,18 +331,27 @@ type expectedNodesCount struct {
 
 // investigateInstancesOutput is the result of the InvestigateInstances command
 type investigateInstancesOutput struct {
-	NonRunningInstances  []ec2v2types.Instance
-	RunningInstances     runningNodesCount
-	ExpectedInstances    expectedNodesCount
-	User                 userInfo
-	UserAuthorized       bool
+	NonRunningInstances []ec2v2types.Instance
+	RunningInstances    runningNodesCount
+	ExpectedInstances   expectedNodesCount
+	User                userInfo
+	UserAuthorized      bool
+	// Classification explains why the actor policy authorized User or not
+	Classification string
+	// AWSInitiated is the evidence of instances that AWS stopped or terminated, e.g. spot interruptions,
+	// scheduled retirements or auto scaling
+	AWSInitiated []string
+	// InstanceHealth lists the scheduled events and impaired status checks of instances
+	InstanceHealth []string
+	// InstanceHealthError is set if the status of instances couldn't be checked
+	InstanceHealthError  string
 	ClusterState         string
 	ClusterNotEvaluated  bool
 	LimitedSupportReason ocm.LimitedSupportReason
 	Error                string
 }
 
-func investigateStoppedInstances(cluster *cmv1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsCli aws.Client, ocmCli ocm.Client) (investigateInstancesOutput, error) {
+func investigateStoppedInstances(ctx context.Context, cluster *cmv1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsCli aws.Client, ocmCli ocm.Client, policy *config.ActorPolicy) (investigateInstancesOutput, error) {
 	if clusterDeployment == nil {
 		return investigateInstancesOutput{}, investigation.WrapFinding(
 			fmt.Errorf("clusterdeployment is empty when investigating stopped instances, did not populate the instance before"),
@@ -258,14 +360,14 @@ func investigateStoppedInstances(cluster *cmv1.Cluster, clusterDeployment *hivev
 
 	infraID := clusterDeployment.Spec.ClusterMetadata.InfraID
 
-	stoppedInstances, err := awsCli.ListNonRunningInstances(infraID)
+	stoppedInstances, err := awsCli.ListNonRunningInstances(ctx, infraID)
 	if err != nil {
 		return investigateInstancesOutput{}, investigation.WrapInfrastructure(
 			fmt.Errorf("could not retrieve non running instances while investigating stopped instances for %s: %w", infraID, err),
 			"AWS API failure retrieving non-running instances")
 	}
 
-	runningNodesCount, err := getRunningNodesCount(infraID, awsCli)
+	runningInstances, err := awsCli.ListRunningInstances(ctx, infraID)
 	if err != nil {
 		return investigateInstancesOutput{}, investigation.WrapInfrastructure(
 			fmt.Errorf("could not retrieve running cluster nodes while investigating stopped instances for %s: %w", infraID, err),
@@ -280,15 +382,34 @@ func investigateStoppedInstances(cluster *cmv1.Cluster, clusterDeployment *hivev
 			"OCM API failure retrieving expected node count")
 	}
 
+	output := investigateInstancesOutput{
+		NonRunningInstances: stoppedInstances,
+		UserAuthorized:      true,
+		RunningInstances:    getRunningNodesCount(runningInstances),
+		ExpectedInstances:   *expectedNodesCount,
+	}
+
+	// Status checks and scheduled events are evidence only, failing to get them doesn't fail the investigation
+	output.InstanceHealth, err = instanceHealthEvidence(ctx, awsCli, slices.Concat(runningInstances, stoppedInstances))
+	if err != nil {
+		logging.Warnf("could not describe the status of instances: %v", err)
+		output.InstanceHealthError = err.Error()
+	}
+
 	if len(stoppedInstances) == 0 {
 		// UserAuthorized: true so SRE will still be alerted for manual investigation
-		return investigateInstancesOutput{
-			UserAuthorized: true, RunningInstances: *runningNodesCount,
-			ExpectedInstances: *expectedNodesCount, Error: "no non running instances found, terminated instances may have already expired",
-		}, nil
+		output.Error = "no non running instances found, terminated instances may have already expired"
+		return output, nil
+	}
+
+	// Instances AWS stopped or terminated have no StopInstances/TerminateInstances event of a principal
+	requestedInstances, awsInitiated := splitAWSInitiated(stoppedInstances)
+	output.AWSInitiated = awsInitiated
+	if len(requestedInstances) == 0 {
+		return output, nil
 	}
 
-	stoppedInstancesEvents, err := awsCli.PollInstanceStopEventsFor(stoppedInstances, 15)
+	stoppedInstancesEvents, err := awsCli.PollInstanceStopEventsFor(ctx, requestedInstances, 15)
 	if err != nil {
 		return investigateInstancesOutput{}, investigation.WrapInfrastructure(
 			fmt.Errorf("could not PollStopEventsFor stoppedInstances: %w", err),
@@ -301,12 +422,6 @@ func investigateStoppedInstances(cluster *cmv1.Cluster, clusterDeployment *hivev
 			"CloudTrail data too old - instances were stopped too long ago or CloudTrail is not up to date")
 	}
 
-	output := investigateInstancesOutput{
-		NonRunningInstances: stoppedInstances,
-		UserAuthorized:      true,
-		RunningInstances:    *runningNodesCount,
-		ExpectedInstances:   *expectedNodesCount,
-	}
 	for _, event := range stoppedInstancesEvents {
 		userDetails, err := extractUserDetails(event.CloudTrailEvent)
 		if err != nil {
@@ -321,12 +436,27 @@ func investigateStoppedInstances(cluster *cmv1.Cluster, clusterDeployment *hivev
 				"invalid CloudTrail event data format")
 		}
 
+		if evidence, ok := awsServiceEvidence(event, userDetails); ok {
+			output.AWSInitiated = append(output.AWSInitiated, evidence)
+			continue
+		}
+
 		output.User = userInfo{
-			UserName:       *event.Username,
+			UserName:       awsv2.ToString(event.Username),
 			IssuerUserName: userDetails.UserIdentity.SessionContext.SessionIssuer.UserName,
 		}
 
-		if !isUserAllowedToStop(*event.Username, output.User.IssuerUserName, cluster.CCS().Enabled()) {
+		classification := policy.Classify(config.Actor{
+			UserName:   output.User.UserName,
+			IssuerName: output.User.IssuerUserName,
+			ARN:        userDetails.UserIdentity.ARN,
+			IssuerARN:  userDetails.UserIdentity.SessionContext.SessionIssuer.ARN,
+			CCS:        cluster.CCS().Enabled(),
+			STS:        cluster.AWS().STS().Enabled(),
+		})
+		output.Classification = classification.Reason
+
+		if !classification.Allowed {
 			output.UserAuthorized = false
 
 			// Return early with `output` containing the first unauthorized user.
@@ -341,14 +471,9 @@ func investigateStoppedInstances(cluster *cmv1.Cluster, clusterDeployment *hivev
 	return output, nil
 }
 
-// GetRunningNodesCount return the number of nodes that are currently running in the cluster
-func getRunningNodesCount(infraID string, awsCli aws.Client) (*runningNodesCount, error) {
-	instances, err := awsCli.ListRunningInstances(infraID)
-	if err != nil {
-		return nil, err
-	}
-
-	runningNodesCount := &runningNodesCount{
+// getRunningNodesCount returns the number of nodes of each type among the running instances
+func getRunningNodesCount(instances []ec2v2types.Instance) runningNodesCount {
+	runningNodesCount := runningNodesCount{
 		Master: 0,
 		Infra:  0,
 		Worker: 0,
@@ -357,21 +482,24 @@ func getRunningNodesCount(infraID string, awsCli aws.Client) (*runningNodesCount
 	for _, instance := range instances {
 		for _, t := range instance.Tags {
 			if *t.Key == "Name" {
-				switch {
-				case strings.Contains(*t.Value, "master"):
-					runningNodesCount.Master++
-				case strings.Contains(*t.Value, "infra"):
-					runningNodesCount.Infra++
-				case strings.Contains(*t.Value, "worker"):
-					runningNodesCount.Worker++
-				default:
-					continue
-				}
+				runningNodesCount.add(*t.Value)
 			}
 		}
 	}
 
-	return runningNodesCount, nil
+	return runningNodesCount
+}
+
+// add counts a running node by its instance name
+func (c *runningNodesCount) add(name string) {
+	switch {
+	case strings.Contains(name, "master"):
+		c.Master++
+	case strings.Contains(name, "infra"):
+		c.Infra++
+	case strings.Contains(name, "worker"):
+		c.Worker++
+	}
 }
 
 // GetExpectedNodesCount returns the minimum number of nodes that are supposed to be in the cluster
@@ -458,14 +586,19 @@ func getExpectedNodesCount(cluster *cmv1.Cluster, ocmCli ocm.Client) (*expectedN
 type CloudTrailEventRaw struct {
 	EventVersion string `json:"eventVersion"`
 	UserIdentity struct {
-		Type           string `json:"type"`
+		Type string `json:"type"`
+		ARN  string `json:"arn"`
+		// InvokedBy is the AWS service that made the request on behalf of the principal, if any
+		InvokedBy      string `json:"invokedBy"`
 		SessionContext struct {
 			SessionIssuer struct {
 				Type     string `json:"type"`
+				ARN      string `json:"arn"`
 				UserName string `json:"userName"`
 			} `json:"sessionIssuer"`
 		} `json:"sessionContext"`
 	} `json:"userIdentity"`
+	RequestParameters json.RawMessage `json:"requestParameters"`
 }
 
 // extractUserDetails will take an event and
@@ -0,0 +1,133 @@
+package chgm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	cloudtrailv2types "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	ec2v2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+)
+
+// awsInitiatedStateReasons are the state reason codes of instances that AWS stopped or terminated,
+// as opposed to the Client.* codes of stops requested through the API or from within the instance.
+// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_StateReason.html
+var awsInitiatedStateReasons = map[string]string{
+	"Server.SpotInstanceTermination":      "spot instance interrupted by AWS",
+	"Server.SpotInstanceShutdown":         "spot instance stopped by AWS",
+	"Server.ScheduledStop":                "stopped by AWS for a scheduled retirement or maintenance",
+	"Server.InternalError":                "AWS internal error",
+	"Server.InsufficientInstanceCapacity": "AWS had insufficient capacity to start the instance",
+}
+
+// awsServiceInvokers are the AWS services whose CloudTrail events on instances aren't customer actions:
+// auto scaling group scaling and capacity rebalancing, spot interruptions and EC2 fleet replacements
+var awsServiceInvokers = []string{
+	"autoscaling.amazonaws.com",
+	"ec2.amazonaws.com",
+	"spot.amazonaws.com",
+	"ec2fleet.amazonaws.com",
+}
+
+// splitAWSInitiated separates the instances AWS stopped or terminated from those that someone stopped
+// through the API, and returns the evidence for the former
+func splitAWSInitiated(instances []ec2v2types.Instance) (requested []ec2v2types.Instance, evidence []string) {
+	for _, instance := range instances {
+		if instance.StateReason == nil {
+			requested = append(requested, instance)
+			continue
+		}
+		code := awsv2.ToString(instance.StateReason.Code)
+		reason, ok := awsInitiatedStateReasons[code]
+		if !ok {
+			requested = append(requested, instance)
+			continue
+		}
+		evidence = append(evidence, fmt.Sprintf("%s: %s (%s: %s)", instanceName(instance), reason, code, awsv2.ToString(instance.StateReason.Message)))
+	}
+	return requested, evidence
+}
+
+// awsServiceInvoker returns the AWS service that invoked a CloudTrail event on behalf of the account,
+// or false if the event is a call of a principal that needs to be classified
+func awsServiceInvoker(raw CloudTrailEventRaw) (string, bool) {
+	service := raw.UserIdentity.InvokedBy
+	if raw.UserIdentity.Type != "AWSService" && !slices.Contains(awsServiceInvokers, service) {
+		return "", false
+	}
+	if service == "" {
+		service = "an AWS service"
+	}
+	return service, true
+}
+
+// awsServiceEvidence returns the evidence of a CloudTrail event that an AWS service invoked,
+// or false if the event is a call of a principal
+func awsServiceEvidence(event cloudtrailv2types.Event, raw CloudTrailEventRaw) (string, bool) {
+	service, ok := awsServiceInvoker(raw)
+	if !ok {
+		return "", false
+	}
+
+	resources := make([]string, 0, len(event.Resources))
+	for _, resource := range event.Resources {
+		resources = append(resources, awsv2.ToString(resource.ResourceName))
+	}
+	return fmt.Sprintf("%s of %s invoked by %s", awsv2.ToString(event.EventName), strings.Join(resources, ", "), service), true
+}
+
+// instanceHealthEvidence returns the scheduled events and impaired status checks of the instances
+// that still exist. Status checks of terminated instances aren't available anymore.
+func instanceHealthEvidence(ctx context.Context, awsCli aws.Client, instances []ec2v2types.Instance) ([]string, error) {
+	names := make(map[string]string)
+	var ids []string
+	for _, instance := range instances {
+		if instance.State != nil && (instance.State.Name == ec2v2types.InstanceStateNameTerminated || instance.State.Name == ec2v2types.InstanceStateNameShuttingDown) {
+			continue
+		}
+		id := awsv2.ToString(instance.InstanceId)
+		names[id] = instanceName(instance)
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	statuses, err := awsCli.DescribeInstanceStatus(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var evidence []string
+	for _, status := range statuses {
+		name := names[status.InstanceID]
+		if name == "" {
+			name = status.InstanceID
+		}
+		for _, event := range status.Events {
+			evidence = append(evidence, fmt.Sprintf("%s: scheduled event %s not before %s: %s", name, event.Code, event.NotBefore.UTC().Format(time.RFC3339), event.Description))
+		}
+		if status.SystemStatus == string(ec2v2types.SummaryStatusImpaired) {
+			evidence = append(evidence, fmt.Sprintf("%s: system status check impaired, the AWS host or network has an issue", name))
+		}
+		if status.InstanceStatus == string(ec2v2types.SummaryStatusImpaired) {
+			evidence = append(evidence, fmt.Sprintf("%s: instance status check impaired", name))
+		}
+	}
+	return evidence, nil
+}
+
+// instanceName returns the instance ID followed by its Name tag, if set
+func instanceName(instance ec2v2types.Instance) string {
+	id := awsv2.ToString(instance.InstanceId)
+	for _, t := range instance.Tags {
+		if awsv2.ToString(t.Key) == "Name" {
+			return fmt.Sprintf("%s (%s)", id, awsv2.ToString(t.Value))
+		}
+	}
+	return id
+}
@@ -0,0 +1,147 @@
+package chgm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
+	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// gcpStopEventsLookback limits the audit logs searched for instance stops.
+// Admin activity audit logs are kept for 400 days, but older stops are unlikely to be the cause.
+const gcpStopEventsLookback = 30 * 24 * time.Hour
+
+// investigateStoppedInstancesGCP is the GCP variant of investigateStoppedInstances: instead of
+// CloudTrail, it looks for the principals that stopped instances in the admin activity audit logs
+func investigateStoppedInstancesGCP(ctx context.Context, cluster *cmv1.Cluster, clusterDeployment *hivev1.ClusterDeployment, gcpCli gcp.Client, ocmCli ocm.Client, configuredPolicy *config.ActorPolicy) (investigateInstancesOutput, error) {
+	if clusterDeployment == nil {
+		return investigateInstancesOutput{}, investigation.WrapFinding(
+			fmt.Errorf("clusterdeployment is empty when investigating stopped instances, did not populate the instance before"),
+			"clusterdeployment data missing")
+	}
+
+	infraID := clusterDeployment.Spec.ClusterMetadata.InfraID
+	policy := gcpActorPolicy(configuredPolicy, infraID)
+
+	instances, err := gcpCli.ListInstances(ctx, infraID)
+	if err != nil {
+		return investigateInstancesOutput{}, investigation.WrapInfrastructure(
+			fmt.Errorf("could not retrieve instances while investigating stopped instances for %s: %w", infraID, err),
+			"GCP API failure retrieving instances")
+	}
+
+	runningNodesCount := runningNodesCount{}
+	var stoppedInstances []gcp.Instance
+	for _, instance := range instances {
+		if instance.IsRunning() {
+			runningNodesCount.add(strings.TrimPrefix(instance.Name, infraID))
+		} else {
+			stoppedInstances = append(stoppedInstances, instance)
+		}
+	}
+
+	expectedNodesCount, err := getExpectedNodesCount(cluster, ocmCli)
+	if err != nil {
+		return investigateInstancesOutput{}, investigation.WrapInfrastructure(
+			fmt.Errorf("could not retrieve expected cluster nodes while investigating stopped instances for %s: %w", infraID, err),
+			"OCM API failure retrieving expected node count")
+	}
+
+	if len(stoppedInstances) == 0 {
+		// UserAuthorized: true so SRE will still be alerted for manual investigation
+		return investigateInstancesOutput{
+			UserAuthorized: true, RunningInstances: runningNodesCount,
+			ExpectedInstances: *expectedNodesCount, Error: "no non running instances found, deleted instances don't show up anymore",
+		}, nil
+	}
+
+	stopEvents, err := gcpCli.ListInstanceStopEvents(ctx, infraID, time.Now().Add(-gcpStopEventsLookback))
+	if err != nil {
+		return investigateInstancesOutput{}, investigation.WrapInfrastructure(
+			fmt.Errorf("could not list stop events of instances: %w", err),
+			"GCP Cloud Logging API failure listing stop events")
+	}
+
+	// The audit logs cover every instance of the cluster, including instances that were stopped and restarted since
+	stopEvents = stopEventsOf(stopEvents, stoppedInstances)
+	if len(stopEvents) == 0 {
+		return investigateInstancesOutput{}, investigation.WrapFinding(
+			fmt.Errorf("there are stopped instances but no stop events in the audit logs"),
+			"audit logs too old - instances were stopped more than 30 days ago")
+	}
+
+	output := investigateInstancesOutput{
+		UserAuthorized:    true,
+		RunningInstances:  runningNodesCount,
+		ExpectedInstances: *expectedNodesCount,
+	}
+	for _, event := range stopEvents {
+		output.User = userInfo{UserName: event.PrincipalEmail}
+
+		classification := policy.Classify(config.Actor{
+			UserName: event.PrincipalEmail,
+			CCS:      cluster.CCS().Enabled(),
+		})
+		output.Classification = classification.Reason
+
+		if !classification.Allowed {
+			output.UserAuthorized = false
+
+			// Return early with `output` containing the first unauthorized principal.
+			return output, nil
+		}
+	}
+
+	return output, nil
+}
+
+// stopEventsOf returns the stop events of the given instances
+func stopEventsOf(events []gcp.AuditLogEntry, instances []gcp.Instance) []gcp.AuditLogEntry {
+	names := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		names[instance.Name] = true
+	}
+	var filtered []gcp.AuditLogEntry
+	for _, event := range events {
+		if names[gcp.ResourceName(event.ResourceName)] {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+// gcpActorPolicy returns the configured actor policy rules followed by the default rules for GCP principals,
+// which are the emails of users and service accounts in the audit logs
+func gcpActorPolicy(configured *config.ActorPolicy, infraID string) *config.ActorPolicy {
+	// The machine-api service account is minted by the cloud credential operator. Service account IDs are
+	// limited to 30 characters, so the infra ID is truncated to 12 and the credentials request name to 11
+	// characters, e.g. <infra-id>-openshift-m-<suffix>.
+	infraPrefix := infraID
+	if len(infraPrefix) > 12 {
+		infraPrefix = infraPrefix[:12]
+	}
+	defaults := mustActorPolicy([]config.ActorRule{
+		{Name: "gcp-machine-api", UserNames: []string{"^" + regexp.QuoteMeta(infraPrefix) + `-openshift-m-[a-z0-9]+@[a-z0-9-]+\.iam\.gserviceaccount\.com$`}, Verdict: config.ActorVerdictAllow},
+		// OSD non-CCS - install/uninstall instances
+		{Name: "gcp-osd-managed-admin", UserNames: []string{`^osd-managed-admin@[a-z0-9-]+\.iam\.gserviceaccount\.com$`}, Verdict: config.ActorVerdictAllow},
+		// OSD CCS - install/uninstall instances
+		{Name: "gcp-osd-ccs-admin", UserNames: []string{`^osd-ccs-admin@[a-z0-9-]+\.iam\.gserviceaccount\.com$`}, Verdict: config.ActorVerdictAllow},
+		// Google stops instances for host maintenance and preempts spot instances
+		{Name: "gcp-system", UserNames: []string{`^system@google\.com$`, `@compute-system\.iam\.gserviceaccount\.com$`}, Verdict: config.ActorVerdictAllow},
+	}...)
+
+	policy := &config.ActorPolicy{}
+	if configured != nil {
+		policy.Rules = append(policy.Rules, configured.Rules...)
+	}
+	policy.Rules = append(policy.Rules, defaults.Rules...)
+	return policy
+}
@@ -0,0 +1,186 @@
+package chgm
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	servicelogsv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	backplanemock "github.com/openshift/configuration-anomaly-detection/pkg/backplane/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
+	gcpmock "github.com/openshift/configuration-anomaly-detection/pkg/gcp/mock"
+	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("chgm on GCP", func() {
+	var (
+		r            *investigation.ResourceBuilderMock
+		mockCtrl     *gomock.Controller
+		gcpCli       *gcpmock.MockClient
+		ocmCli       *ocmmock.MockClient
+		cluster      *cmv1.Cluster
+		instances    []gcp.Instance
+		defaultRoute gcp.Route
+	)
+	BeforeEach(func() {
+		logging.InitLogger("fatal", "", "") // Mute logger for the tests
+		mockCtrl = gomock.NewController(GinkgoT())
+		gcpCli = gcpmock.NewMockClient(mockCtrl)
+		ocmCli = ocmmock.NewMockClient(mockCtrl)
+
+		var err error
+		cluster, err = cmv1.NewCluster().
+			Nodes(cmv1.NewClusterNodes().Compute(0).Master(1).Infra(1)).
+			State(cmv1.ClusterStateReady).
+			CloudProvider(cmv1.NewCloudProvider().ID("gcp")).
+			GCP(cmv1.NewGCP().ProjectID("project-1")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		instances = []gcp.Instance{
+			{Name: "infra-1-master-0", Status: gcp.InstanceRunning},
+			{Name: "infra-1-infra-a-abcde", Status: gcp.InstanceTerminated},
+		}
+		defaultRoute = gcp.Route{Name: "default-route", DestRange: "0.0.0.0/0", NextHopGateway: "default-internet-gateway"}
+
+		// The AWS client is left unset, as GCP clusters must not use it
+		r = &investigation.ResourceBuilderMock{
+			Resources: &investigation.Resources{
+				Name:    "Test",
+				Cluster: cluster,
+				ClusterDeployment: &hivev1.ClusterDeployment{
+					Spec: hivev1.ClusterDeploymentSpec{
+						ClusterMetadata: &hivev1.ClusterMetadata{InfraID: "infra-1"},
+					},
+				},
+				GcpClient: gcpCli,
+				BpClient:  &backplanemock.MockClient{},
+				OcmClient: ocmCli,
+			},
+		}
+	})
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	inv := Investigation{}
+
+	stoppedBy := func(principal string) {
+		gcpCli.EXPECT().ListInstances(gomock.Any(), "infra-1").Return(instances, nil)
+		ocmCli.EXPECT().GetClusterMachinePools(gomock.Any()).Return(nil, nil)
+		gcpCli.EXPECT().ListInstanceStopEvents(gomock.Any(), "infra-1", gomock.Any()).Return([]gcp.AuditLogEntry{
+			{MethodName: "v1.compute.instances.stop", ResourceName: "projects/project-1/zones/us-east1-b/instances/infra-1-infra-a-abcde", PrincipalEmail: principal},
+		}, nil)
+	}
+
+	When("a customer stopped instances", func() {
+		It("should put the cluster on limited support", func() {
+			stoppedBy("someone@customer.example.com")
+
+			result, err := inv.Run(r)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasLimitedSupportAction(result.Actions)).To(BeTrue())
+			Expect(hasSilenceAction(result.Actions)).To(BeTrue())
+			Expect(invtesting.NoteContent(result)).To(ContainSubstring("unknown actors are not authorized"))
+		})
+	})
+
+	When("a customer service account's name contains the machine-api prefix", func() {
+		It("should put the cluster on limited support", func() {
+			stoppedBy("foo-openshift-mgmt@project-1.iam.gserviceaccount.com")
+
+			result, err := inv.Run(r)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasLimitedSupportAction(result.Actions)).To(BeTrue())
+		})
+	})
+
+	When("a customer stopped an instance that was restarted since", func() {
+		It("should only judge the stop events of the instances that are stopped now", func() {
+			gcpCli.EXPECT().ListInstances(gomock.Any(), "infra-1").Return(instances, nil)
+			ocmCli.EXPECT().GetClusterMachinePools(gomock.Any()).Return(nil, nil)
+			gcpCli.EXPECT().ListInstanceStopEvents(gomock.Any(), "infra-1", gomock.Any()).Return([]gcp.AuditLogEntry{
+				{MethodName: "v1.compute.instances.stop", ResourceName: "projects/project-1/zones/us-east1-b/instances/infra-1-master-0", PrincipalEmail: "someone@customer.example.com"},
+				{MethodName: "v1.compute.instances.stop", ResourceName: "projects/project-1/zones/us-east1-b/instances/infra-1-infra-a-abcde", PrincipalEmail: "system@google.com"},
+			}, nil)
+			ocmCli.EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Any()).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return(nil, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "infra-1-network").Return([]gcp.Route{defaultRoute}, nil)
+
+			result, err := inv.Run(r)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+			Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+			Expect(invtesting.NoteContent(result)).To(ContainSubstring(`rule "gcp-system" (allow)`))
+		})
+	})
+
+	When("the machine-api stopped instances and the network allows egress", func() {
+		It("should escalate without limited support", func() {
+			stoppedBy("infra-1-openshift-m-abcde@project-1.iam.gserviceaccount.com")
+			ocmCli.EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Any()).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return(nil, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "infra-1-network").Return([]gcp.Route{defaultRoute}, nil)
+
+			result, err := inv.Run(r)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+			Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+			Expect(hasNoteAction(result.Actions)).To(BeTrue())
+			Expect(invtesting.NoteContent(result)).To(ContainSubstring(`rule "gcp-machine-api" (allow)`))
+		})
+	})
+
+	When("a firewall rule blocks egress", func() {
+		It("should send a service log and escalate", func() {
+			stoppedBy("system@google.com")
+			ocmCli.EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Any()).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return([]gcp.FirewallRule{
+				{Name: "deny-all", Direction: "EGRESS", Priority: 100, Denied: []gcp.FirewallProtocol{{IPProtocol: "all"}}},
+			}, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "infra-1-network").Return([]gcp.Route{defaultRoute}, nil)
+
+			result, err := inv.Run(r)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasActionType(result.Actions, string(executor.ActionTypeServiceLog))).To(BeTrue())
+			Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+		})
+	})
+
+	When("the audit logs have no stop events", func() {
+		It("should escalate for manual review", func() {
+			gcpCli.EXPECT().ListInstances(gomock.Any(), "infra-1").Return(instances, nil)
+			ocmCli.EXPECT().GetClusterMachinePools(gomock.Any()).Return(nil, nil)
+			gcpCli.EXPECT().ListInstanceStopEvents(gomock.Any(), "infra-1", gomock.Any()).Return(nil, nil)
+
+			result, err := inv.Run(r)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+			Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+		})
+	})
+
+	When("the GCP API fails", func() {
+		It("should return an infrastructure error for retry", func() {
+			gcpCli.EXPECT().ListInstances(gomock.Any(), "infra-1").Return(nil, fmt.Errorf("permission denied"))
+
+			_, err := inv.Run(r)
+
+			Expect(err).To(HaveOccurred())
+			Expect(investigation.IsInfrastructureError(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,247 @@
+package chgm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// networkChangesLookback limits the CloudTrail events searched for network changes.
+// Changes that removed egress longer ago would have made the cluster go missing earlier.
+const networkChangesLookback = 72 * time.Hour
+
+// removesEgress describes a change that removes the egress of a cluster on its own, regardless of
+// what else is configured. The description is formatted with the ID of the resource with the prefix.
+type removesEgress struct {
+	description    string
+	resourcePrefix string
+}
+
+// networkChangeEvents are the CloudTrail events that change the network of a VPC in a way that can
+// block the egress of a cluster. Changes that only might, e.g. revoked rules, don't remove egress.
+var networkChangeEvents = map[string]*removesEgress{
+	"DeleteNatGateway":             {"NAT gateway %s was deleted", "nat-"},
+	"DetachInternetGateway":        {"internet gateway %s was detached", "igw-"},
+	"DeleteInternetGateway":        {"internet gateway %s was deleted", "igw-"},
+	"DeleteRoute":                  {"the default route of route table %s was deleted", "rtb-"},
+	"ReplaceRoute":                 nil,
+	"DisassociateRouteTable":       nil,
+	"ReplaceRouteTableAssociation": nil,
+	"RevokeSecurityGroupEgress":    nil,
+	"RevokeSecurityGroupIngress":   nil,
+	"CreateNetworkAclEntry":        nil,
+	"ReplaceNetworkAclEntry":       nil,
+	"DeleteNetworkAclEntry":        nil,
+	"ReplaceNetworkAclAssociation": nil,
+	"DeleteVpcEndpoints":           nil,
+}
+
+// networkResourcePrefixes are the prefixes of the IDs of VPC network resources in CloudTrail request parameters
+var networkResourcePrefixes = []string{"vpc-", "rtb-", "nat-", "igw-", "acl-", "sg-", "vpce-"}
+
+var networkChangeLS = ocm.LimitedSupportReason{
+	Summary: "Cluster is in Limited Support due to unsupported cloud provider configuration",
+	Details: "Your cluster is no longer checking in with Red Hat OpenShift Cluster Manager because changes to its VPC removed the cluster's internet egress: %s. Please restore the removed network resources, as the cluster requires internet egress for operation and support.",
+}
+
+// networkChange is a change to the network of the cluster's VPC found in CloudTrail
+type networkChange struct {
+	EventName   string
+	Time        time.Time
+	ResourceIDs []string
+	// Actor is the principal or the AWS service that made the change
+	Actor string
+	// Customer is set if the actor policy didn't authorize the principal
+	Customer       bool
+	Classification string
+	// RemovedEgress describes the change if it removed the egress of the cluster on its own
+	RemovedEgress string
+}
+
+func (c networkChange) String() string {
+	verdict := "authorized"
+	if c.Customer {
+		verdict = "customer"
+	}
+	line := fmt.Sprintf("%s %s of %s by %s (%s)", c.Time.UTC().Format(time.RFC3339), c.EventName, strings.Join(c.ResourceIDs, ", "), c.Actor, verdict)
+	if c.Classification != "" {
+		line += ": " + c.Classification
+	}
+	return line
+}
+
+// networkChanges are the changes to the network of the cluster's VPC, and the current state of the network
+type networkChanges struct {
+	// Changes are the changes found in CloudTrail, newest first
+	Changes []networkChange
+	// Truncated is set if CloudTrail had more events than were looked up, older changes may be missing then
+	Truncated bool
+	Resources aws.VpcNetworkResources
+}
+
+// investigateNetworkChanges looks up the changes to the network of the cluster's VPC made since the given time,
+// and classifies who made them with the actor policy
+func investigateNetworkChanges(ctx context.Context, cluster *cmv1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsCli aws.Client, policy *config.ActorPolicy, since time.Time) (networkChanges, error) {
+	result := networkChanges{}
+	if clusterDeployment == nil {
+		return result, fmt.Errorf("clusterdeployment is empty when investigating network changes")
+	}
+	resources, err := awsCli.GetVpcNetworkResources(ctx, clusterDeployment.Spec.ClusterMetadata.InfraID)
+	if err != nil {
+		return result, fmt.Errorf("could not get the network resources of the cluster's VPC: %w", err)
+	}
+	result.Resources = resources
+
+	eventNames := make([]string, 0, len(networkChangeEvents))
+	for name := range networkChangeEvents {
+		eventNames = append(eventNames, name)
+	}
+	slices.Sort(eventNames)
+	events, truncated, err := awsCli.LookupResourceEvents(ctx, resources.IDs(), eventNames, since)
+	if err != nil {
+		return result, fmt.Errorf("could not look up network changes in CloudTrail: %w", err)
+	}
+	result.Truncated = truncated
+
+	var changes []networkChange
+	for _, event := range events {
+		raw, err := extractUserDetails(event.CloudTrailEvent)
+		if err != nil {
+			return result, fmt.Errorf("could not parse the CloudTrail event %s: %w", awsv2.ToString(event.EventId), err)
+		}
+		ids := networkResourceIDs(raw.RequestParameters)
+		if !slices.ContainsFunc(ids, resources.Contains) {
+			continue
+		}
+
+		change := networkChange{
+			EventName:   awsv2.ToString(event.EventName),
+			Time:        awsv2.ToTime(event.EventTime),
+			ResourceIDs: ids,
+		}
+		change.RemovedEgress = removedEgress(change.EventName, ids, raw.RequestParameters)
+
+		if service, ok := awsServiceInvoker(raw); ok {
+			change.Actor = service
+			changes = append(changes, change)
+			continue
+		}
+
+		user := userInfo{
+			UserName:       awsv2.ToString(event.Username),
+			IssuerUserName: raw.UserIdentity.SessionContext.SessionIssuer.UserName,
+		}
+		change.Actor = fmt.Sprintf("user %q", user.UserName)
+		if user.IssuerUserName != "" {
+			change.Actor += fmt.Sprintf(" (issuer %q)", user.IssuerUserName)
+		}
+		classification := policy.Classify(config.Actor{
+			UserName:   user.UserName,
+			IssuerName: user.IssuerUserName,
+			ARN:        raw.UserIdentity.ARN,
+			IssuerARN:  raw.UserIdentity.SessionContext.SessionIssuer.ARN,
+			CCS:        cluster.CCS().Enabled(),
+			STS:        cluster.AWS().STS().Enabled(),
+		})
+		change.Customer = !classification.Allowed
+		change.Classification = classification.Reason
+		changes = append(changes, change)
+	}
+
+	slices.SortFunc(changes, func(a, b networkChange) int { return b.Time.Compare(a.Time) })
+	result.Changes = changes
+	return result, nil
+}
+
+// egressStillRemoved reports whether the current route tables confirm the egress removed by the changes is
+// still missing. Gateways and routes may have been replaced since, so the changes alone don't prove it.
+func (n networkChanges) egressStillRemoved() bool {
+	return len(n.Resources.RouteTablesWithoutEgress) != 0
+}
+
+// removedEgress describes the change if it removed the cluster's egress on its own
+func removedEgress(eventName string, ids []string, requestParameters json.RawMessage) string {
+	removes := networkChangeEvents[eventName]
+	if removes == nil {
+		return ""
+	}
+	if eventName == "DeleteRoute" {
+		// Only the default route carries the egress, other routes are e.g. peerings
+		var params struct {
+			DestinationCidrBlock string `json:"destinationCidrBlock"`
+		}
+		if err := json.Unmarshal(requestParameters, &params); err != nil || params.DestinationCidrBlock != "0.0.0.0/0" {
+			return ""
+		}
+	}
+	for _, id := range ids {
+		if strings.HasPrefix(id, removes.resourcePrefix) {
+			return fmt.Sprintf(removes.description, id)
+		}
+	}
+	return ""
+}
+
+// networkResourceIDs returns the IDs of VPC network resources in the request parameters of a CloudTrail event.
+// The parameters are searched recursively, as their structure differs between events.
+func networkResourceIDs(requestParameters json.RawMessage) []string {
+	var params any
+	if len(requestParameters) == 0 || json.Unmarshal(requestParameters, &params) != nil {
+		return nil
+	}
+	var ids []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for _, value := range v {
+				walk(value)
+			}
+		case []any:
+			for _, value := range v {
+				walk(value)
+			}
+		case string:
+			for _, prefix := range networkResourcePrefixes {
+				if strings.HasPrefix(v, prefix) && !slices.Contains(ids, v) {
+					ids = append(ids, v)
+				}
+			}
+		}
+	}
+	walk(params)
+	slices.Sort(ids)
+	return ids
+}
+
+// customerRemovedEgress returns the descriptions of the changes the customer made that removed egress
+func customerRemovedEgress(changes []networkChange) []string {
+	var removed []string
+	for _, c := range changes {
+		if c.Customer && c.RemovedEgress != "" {
+			removed = append(removed, c.RemovedEgress)
+		}
+	}
+	return removed
+}
+
+// customerNetworkChanges returns the changes the customer made, for the egress service log
+func customerNetworkChanges(changes []networkChange) []string {
+	var lines []string
+	for _, c := range changes {
+		if c.Customer {
+			lines = append(lines, fmt.Sprintf("%s of %s at %s", c.EventName, strings.Join(c.ResourceIDs, ", "), c.Time.UTC().Format(time.RFC3339)))
+		}
+	}
+	return lines
+}
@@ -1,7 +1,9 @@
 package chgm
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
 	cloudtrailv2types "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
@@ -10,10 +12,13 @@ import (
 	. "github.com/onsi/gomega"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	servicelogsv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
 	awsmock "github.com/openshift/configuration-anomaly-detection/pkg/aws/mock"
 	backplanemock "github.com/openshift/configuration-anomaly-detection/pkg/backplane/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
 	pdmock "github.com/openshift/configuration-anomaly-detection/pkg/pagerduty/mock"
@@ -64,6 +69,10 @@ var _ = Describe("chgm", func() {
 		infraInstance     ec2v2types.Instance
 		infraInstanceTag  ec2v2types.Tag
 		event             cloudtrailv2types.Event
+		instanceStatuses  []aws.InstanceStatus
+		networkEvents     []cloudtrailv2types.Event
+		networkResources  aws.VpcNetworkResources
+		networkTruncated  bool
 	)
 	BeforeEach(func() {
 		logging.InitLogger("fatal", "", "") // Mute logger for the tests
@@ -114,11 +123,30 @@ var _ = Describe("chgm", func() {
 				AwsClient:         awsmock.NewMockClient(mockCtrl),
 				BpClient:          &backplanemock.MockClient{},
 				OcmClient:         ocmmock.NewMockClient(mockCtrl),
-				PdClient:          pdmock.NewMockClient(mockCtrl),
+				Incident:          pdmock.NewMockClient(mockCtrl),
 				Notes:             nil,
 			},
 		}
 
+		// Instance status checks are evidence only, tests set instanceStatuses to report issues
+		instanceStatuses = nil
+		r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().DescribeInstanceStatus(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ []string) ([]aws.InstanceStatus, error) {
+				return instanceStatuses, nil
+			}).AnyTimes()
+		// Network changes are looked up in CloudTrail, tests set networkEvents to report changes
+		networkEvents = nil
+		networkTruncated = false
+		networkResources = aws.VpcNetworkResources{VpcID: "vpc-1", RouteTableIDs: []string{"rtb-1"}, NatGatewayIDs: []string{"nat-1"}, SecurityGroupIDs: []string{"sg-1"}}
+		r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetVpcNetworkResources(gomock.Any(), gomock.Eq(infraID)).
+			DoAndReturn(func(_ context.Context, _ string) (aws.VpcNetworkResources, error) {
+				return networkResources, nil
+			}).AnyTimes()
+		r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().LookupResourceEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ []string, _ []string, _ time.Time) ([]cloudtrailv2types.Event, bool, error) {
+				return networkEvents, networkTruncated, nil
+			}).AnyTimes()
+
 		fmt.Println(instance, event)
 	})
 	AfterEach(func() {
@@ -135,10 +163,10 @@ var _ = Describe("chgm", func() {
 					CloudTrailEvent: awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"Role", "userName": "654321"}}}}`),
 				}
 				// We need to cast it to the mock client, as the investigationResources are unaware the underlying functions are mocks
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{infraInstance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance, infraInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{infraInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance, infraInstance}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 
@@ -156,10 +184,10 @@ var _ = Describe("chgm", func() {
 					CloudTrailEvent: awsv2.String(`{"eventVersion":"1.99", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"Role", "userName": "654321"}}}}`),
 				}
 				// We need to cast it to the mock client, as the investigationResources are unaware the underlying functions are mocks
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{infraInstance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance, infraInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{infraInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance, infraInstance}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 
@@ -172,7 +200,7 @@ var _ = Describe("chgm", func() {
 		})
 		When("Triggered errors", func() {
 			It("should return infrastructure error for retry", func() {
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any()).Return(nil, fmt.Errorf("could not retrieve non running instances: %w", fakeErr))
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("could not retrieve non running instances: %w", fakeErr))
 
 				_, gotErr := inv.Run(r)
 
@@ -182,12 +210,12 @@ var _ = Describe("chgm", func() {
 		})
 		When("there were no stopped instances", func() {
 			It("should update and escalate to primary", func() {
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance, infraInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance, infraInstance}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 				result, gotErr := inv.Run(r)
 				// Assert
@@ -199,10 +227,10 @@ var _ = Describe("chgm", func() {
 		})
 		When("there was an error getting StopInstancesEvents", func() {
 			It("should return infrastructure error for retry", func() {
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("could not PollStopEventsFor: %w", fakeErr))
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("could not PollStopEventsFor: %w", fakeErr))
 
 				_, gotErr := inv.Run(r)
 				Expect(gotErr).To(HaveOccurred())
@@ -212,10 +240,10 @@ var _ = Describe("chgm", func() {
 		When("there were no StopInstancesEvents", func() {
 			It("should update and escalate to primary", func() {
 				// Arrange
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{}, nil)
 
 				// Act
 				result, gotErr := inv.Run(r)
@@ -228,10 +256,10 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEventRaw base data is correct, but the sessionissue's username is not an authorized user", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"Role", "userName": "654321"}}}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 				// Act
 				result, gotErr := inv.Run(r)
 				// Assert
@@ -245,14 +273,14 @@ var _ = Describe("chgm", func() {
 		When("issuer user is authorized (openshift-machine-api-aws)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","principalId":"PRINCIPALID_REDACTED:1234567789","arn":"arn:aws:sts::1234:assumed-role/cluster-name-n9o7-openshift-machine-api-aws-cloud-credentials/1234567789","accountId":"1234","accessKeyId":"REDACTED","sessionContext":{"sessionIssuer":{"type":"Role","principalId":"PRINCIPALID_REDACTED","arn":"arn:aws:iam::1234:role/cluster-name-n9o7-openshift-machine-api-aws-cloud-credentials","accountId":"1234","userName":"cluster-name-n9o7-openshift-machine-api-aws-cloud-credentials"},"webIdFederationData":{"federatedProvider":"arn:aws:iam::1234:oidc-provider/rh-oidc.s3.us-east-1.amazonawsv2.com/redacted","attributes":{}},"attributes":{"creationDate":"2023-02-21T04:54:56Z","mfaAuthenticated":"false"}}},"eventTime":"2023-02-21T04:54:56Z","eventSource":"ec2v2types.amazonawsv2.com","eventName":"TerminateInstances","awsRegion":"ap-southeast-1","sourceIPAddress":"192.168.0.0","userAgent":"aws-sdk-go/1.43.20 (go1.18.7; linux; amd64) openshift.io cluster-api-provider-aws/4.11.0-202301051515.p0.ga796a77.assembly.stream","requestParameters":{"instancesSet":{"items":[{"instanceId":"i-08020c19123456789"}]}},"responseElements":{"requestId":"b8c78d9a-51de-4910-123456789","instancesSet":{"items":[{"instanceId":"i-08020c19123456789","currentState":{"code":32,"name":"shutting-down"},"previousState":{"code":32,"name":"shutting-down"}}]}},"requestID":"b8c78d9a-51de-4910-123456789","eventID":"5455f882-a4db-4505-bea6-123456789","readOnly":false,"eventType":"AwsApiCall","managementEvent":true,"recipientAccountId":"1234","eventCategory":"Management","tlsDetails":{"tlsVersion":"TLSv1.2","cipherSuite":"ECDHE-RSA-AES128-GCM-SHA256","clientProvidedHostHeader":"ec2v2types.ap-southeast-1.amazonawsv2.com"}}`)
 				event.Username = awsv2.String("1234567789") // ID of the initial jumprole account
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -265,13 +293,13 @@ var _ = Describe("chgm", func() {
 		When("username role is OrganizationAccountAccessRole on a non CCS cluster", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"Role", "userName": "OrganizationAccountAccessRole"}}}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -285,13 +313,13 @@ var _ = Describe("chgm", func() {
 		When("username role is OrganizationAccountAccessRole on a CCS cluster", func() {
 			It("should send a service log and silence the alert", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"Role", "userName": "OrganizationAccountAccessRole"}}}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Any(), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -305,13 +333,13 @@ var _ = Describe("chgm", func() {
 		When("issuer user is authorized (ManagedOpenShift-Installer-Role)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","principalId":"redacted:1234567","arn":"arn:aws:sts::1234567:assumed-role/ManagedOpenShift-Installer-Role/1234567","accountId":"1234567","accessKeyId":"redacted","sessionContext":{"sessionIssuer":{"type":"Role","principalId":"redacted","arn":"arn:aws:iam::1234567:role/ManagedOpenShift-Installer-Role","accountId":"1234567","userName":"ManagedOpenShift-Installer-Role"},"webIdFederationData":{},"attributes":{"creationDate":"2023-02-21T04:33:06Z","mfaAuthenticated":"false"}}},"eventTime":"2023-02-21T04:33:09Z","eventSource":"ec2v2types.amazonawsv2.com","eventName":"TerminateInstances","awsRegion":"ap-southeast-1","sourceIPAddress":"192.0.0.1","userAgent":"APN/1.0 HashiCorp/1.0 Terraform/1.0.11 (+https://www.terraform.io) terraform-provider-aws/dev (+https://registry.terraform.io/providers/hashicorp/aws) aws-sdk-go/1.43.9 (go1.18.7; linux; amd64) HashiCorp-terraform-exec/0.16.1","requestParameters":{"instancesSet":{"items":[{"instanceId":"i-0c123456"}]}},"responseElements":{"requestId":"bd3900cb-1234567","instancesSet":{"items":[{"instanceId":"i-0c123456","currentState":{"code":32,"name":"shutting-down"},"previousState":{"code":16,"name":"running"}}]}},"requestID":"bd3900cb-1234567","eventID":"7064eae0-1234567","readOnly":false,"eventType":"AwsApiCall","managementEvent":true,"recipientAccountId":"1234","eventCategory":"Management","tlsDetails":{"tlsVersion":"TLSv1.2","cipherSuite":"ECDHE-RSA-AES128-GCM-SHA256","clientProvidedHostHeader":"ec2v2types.ap-southeast-1.amazonawsv2.com"}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -324,13 +352,13 @@ var _ = Describe("chgm", func() {
 		When("issuer user is authorized (customprefix-Installer-Role)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","principalId":"redacted:1234567","arn":"arn:aws:sts::1234567:assumed-role/customprefix-Installer-Role/1234567","accountId":"1234567","accessKeyId":"redacted","sessionContext":{"sessionIssuer":{"type":"Role","principalId":"redacted","arn":"arn:aws:iam::1234567:role/customprefix-Installer-Role","accountId":"1234567","userName":"customprefix-Installer-Role"},"webIdFederationData":{},"attributes":{"creationDate":"2023-02-21T04:33:06Z","mfaAuthenticated":"false"}}},"eventTime":"2023-02-21T04:33:09Z","eventSource":"ec2v2types.amazonawsv2.com","eventName":"TerminateInstances","awsRegion":"ap-southeast-1","sourceIPAddress":"192.0.0.1","userAgent":"APN/1.0 HashiCorp/1.0 Terraform/1.0.11 (+https://www.terraform.io) terraform-provider-aws/dev (+https://registry.terraform.io/providers/hashicorp/aws) aws-sdk-go/1.43.9 (go1.18.7; linux; amd64) HashiCorp-terraform-exec/0.16.1","requestParameters":{"instancesSet":{"items":[{"instanceId":"i-0c123456"}]}},"responseElements":{"requestId":"bd3900cb-1234567","instancesSet":{"items":[{"instanceId":"i-0c123456","currentState":{"code":32,"name":"shutting-down"},"previousState":{"code":16,"name":"running"}}]}},"requestID":"bd3900cb-1234567","eventID":"7064eae0-1234567","readOnly":false,"eventType":"AwsApiCall","managementEvent":true,"recipientAccountId":"1234","eventCategory":"Management","tlsDetails":{"tlsVersion":"TLSv1.2","cipherSuite":"ECDHE-RSA-AES128-GCM-SHA256","clientProvidedHostHeader":"ec2v2types.ap-southeast-1.amazonawsv2.com"}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -343,13 +371,13 @@ var _ = Describe("chgm", func() {
 		When("issuer user is authorized (ManagedOpenShift-Support-.*)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","principalId":"redacted:1234567","arn":"arn:aws:sts::1234567:assumed-role/ManagedOpenShift-Support-v3218/1234567","accountId":"1234567","accessKeyId":"redacted","sessionContext":{"sessionIssuer":{"type":"Role","principalId":"redacted","arn":"arn:aws:iam::1234567:role/ManagedOpenShift-Support-v3218","accountId":"1234567","userName":"ManagedOpenShift-Support-v3218"},"webIdFederationData":{},"attributes":{"creationDate":"2023-02-21T04:33:06Z","mfaAuthenticated":"false"}}},"eventTime":"2023-02-21T04:33:09Z","eventSource":"ec2v2types.amazonawsv2.com","eventName":"TerminateInstances","awsRegion":"ap-southeast-1","sourceIPAddress":"192.0.0.1","userAgent":"APN/1.0 HashiCorp/1.0 Terraform/1.0.11 (+https://www.terraform.io) terraform-provider-aws/dev (+https://registry.terraform.io/providers/hashicorp/aws) aws-sdk-go/1.43.9 (go1.18.7; linux; amd64) HashiCorp-terraform-exec/0.16.1","requestParameters":{"instancesSet":{"items":[{"instanceId":"i-0c123456"}]}},"responseElements":{"requestId":"bd3900cb-1234567","instancesSet":{"items":[{"instanceId":"i-0c123456","currentState":{"code":32,"name":"shutting-down"},"previousState":{"code":16,"name":"running"}}]}},"requestID":"bd3900cb-1234567","eventID":"7064eae0-1234567","readOnly":false,"eventType":"AwsApiCall","managementEvent":true,"recipientAccountId":"1234","eventCategory":"Management","tlsDetails":{"tlsVersion":"TLSv1.2","cipherSuite":"ECDHE-RSA-AES128-GCM-SHA256","clientProvidedHostHeader":"ec2v2types.ap-southeast-1.amazonawsv2.com"}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -362,13 +390,13 @@ var _ = Describe("chgm", func() {
 		When("issuer user is authorized (.*-Support-Role)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","principalId":"redacted:1234567","arn":"arn:aws:sts::1234567:assumed-role/johnnycustom-Support-Role/1234567","accountId":"1234567","accessKeyId":"redacted","sessionContext":{"sessionIssuer":{"type":"Role","principalId":"redacted","arn":"arn:aws:iam::1234567:role/johnnycustom-Support-Role","accountId":"1234567","userName":"johnnycustom-Support-Role"},"webIdFederationData":{},"attributes":{"creationDate":"2023-02-21T04:33:06Z","mfaAuthenticated":"false"}}},"eventTime":"2023-02-21T04:33:09Z","eventSource":"ec2v2types.amazonawsv2.com","eventName":"TerminateInstances","awsRegion":"ap-southeast-1","sourceIPAddress":"192.0.0.1","userAgent":"APN/1.0 HashiCorp/1.0 Terraform/1.0.11 (+https://www.terraform.io) terraform-provider-aws/dev (+https://registry.terraform.io/providers/hashicorp/aws) aws-sdk-go/1.43.9 (go1.18.7; linux; amd64) HashiCorp-terraform-exec/0.16.1","requestParameters":{"instancesSet":{"items":[{"instanceId":"i-0c123456"}]}},"responseElements":{"requestId":"bd3900cb-1234567","instancesSet":{"items":[{"instanceId":"i-0c123456","currentState":{"code":32,"name":"shutting-down"},"previousState":{"code":16,"name":"running"}}]}},"requestID":"bd3900cb-1234567","eventID":"7064eae0-1234567","readOnly":false,"eventType":"AwsApiCall","managementEvent":true,"recipientAccountId":"1234","eventCategory":"Management","tlsDetails":{"tlsVersion":"TLSv1.2","cipherSuite":"ECDHE-RSA-AES128-GCM-SHA256","clientProvidedHostHeader":"ec2v2types.ap-southeast-1.amazonawsv2.com"}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -381,13 +409,13 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent has a matching whitelisted user (osdManagedAdmin-.*)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.Username = awsv2.String("osdManagedAdmin-abcd")
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -400,13 +428,13 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent has a matching whitelisted user (osdCcsAdmin)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.Username = awsv2.String("osdCcsAdmin")
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -419,13 +447,13 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent has a matching whitelisted user (.*openshift-machine-api-awsv2.*)", func() {
 			It("should update and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.Username = awsv2.String("test-openshift-machine-api-aws-test")
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
 				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
 
 				result, gotErr := inv.Run(r)
@@ -438,10 +466,10 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEventRaw has an empty userIdentity", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -454,10 +482,10 @@ var _ = Describe("chgm", func() {
 		When("issuer user is unauthorized (testuser assumed role)", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","principalId":"REDACTED:OCM","arn":"arn:aws:sts::1234:assumed-role/testuser/OCM","accountId":"1234","accessKeyId":"REDACTED","sessionContext":{"sessionIssuer":{"type":"Role","principalId":"REDACTED","arn":"arn:aws:iam::1234:role/testuser","accountId":"1234","userName":"testuser"},"webIdFederationData":{},"attributes":{"creationDate":"2023-02-21T04:08:01Z","mfaAuthenticated":"false"}}},"eventTime":"2023-02-21T04:10:40Z","eventSource":"ec2v2types.amazonawsv2.com","eventName":"TerminateInstances","awsRegion":"ap-southeast-1","sourceIPAddress":"192.168.0.0","userAgent":"aws-sdk-go-v2/1.17.3 os/linux lang/go/1.19.5 md/GOOS/linux md/GOARCH/amd64 api/ec2/1.25.0","requestParameters":{"instancesSet":{"items":[{"instanceId":"i-00c1f1234567"}]}},"responseElements":{"requestId":"credacted","instancesSet":{"items":[{"instanceId":"i-00c1f1234567","currentState":{"code":32,"name":"shutting-down"},"previousState":{"code":16,"name":"running"}}]}},"requestID":"credacted","eventID":"e55a8a64-9949-47a9-9fff-12345678","readOnly":false,"eventType":"AwsApiCall","managementEvent":true,"recipientAccountId":"1234","eventCategory":"Management","tlsDetails":{"tlsVersion":"TLSv1.2","cipherSuite":"ECDHE-RSA-AES128-GCM-SHA256","clientProvidedHostHeader":"ec2v2types.ap-southeast-1.amazonawsv2.com"}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -470,10 +498,10 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEventRaw base data is correct, but the sessionissue's role is not role", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"test"}}}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -486,9 +514,9 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEventRaw has no data", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -502,10 +530,10 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEventRaw has an empty userIdentity", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -519,10 +547,10 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEventRaw has a userIdentity is an iam user", func() {
 			It("should put the cluster on limited support", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"IAMUser"}}`)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -535,12 +563,12 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent has more than one resource", func() {
 			It("it should fail, add notes to the incident and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{}`)
 				cloudTrailResource := cloudtrailv2types.Resource{ResourceName: awsv2.String("123456")}
 				event.Resources = []cloudtrailv2types.Resource{cloudTrailResource}
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -552,12 +580,12 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent is empty", func() {
 			It("it should fail, add notes to the incident and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{}`)
 				cloudTrailResource := cloudtrailv2types.Resource{ResourceName: awsv2.String("123456")}
 				event.Resources = []cloudtrailv2types.Resource{cloudTrailResource}
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -569,12 +597,12 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent is an empty string", func() {
 			It("it should fail, add notes to the incident and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(``)
 				cloudTrailResource := cloudtrailv2types.Resource{ResourceName: awsv2.String("123456")}
 				event.Resources = []cloudtrailv2types.Resource{cloudTrailResource}
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -586,12 +614,12 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent is an empty json", func() {
 			It("it should fail, add notes to the incident and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{}`)
 				cloudTrailResource := cloudtrailv2types.Resource{ResourceName: awsv2.String("123456")}
 				event.Resources = []cloudtrailv2types.Resource{cloudTrailResource}
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -603,12 +631,12 @@ var _ = Describe("chgm", func() {
 		When("the returned CloudTrailEvent is an invalid json", func() {
 			It("it should fail, add notes to the incident and escalate to primary", func() {
 				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
 				event.CloudTrailEvent = awsv2.String(`{`)
 				cloudTrailResource := cloudtrailv2types.Resource{ResourceName: awsv2.String("123456")}
 				event.Resources = []cloudtrailv2types.Resource{cloudTrailResource}
-				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
 
 				result, gotErr := inv.Run(r)
 				Expect(gotErr).NotTo(HaveOccurred())
@@ -617,5 +645,250 @@ var _ = Describe("chgm", func() {
 				Expect(hasNoteAction(result.Actions)).To(BeTrue())
 			})
 		})
+		When("a configured actor policy rule denies a user allowed by default", func() {
+			It("should put the cluster on limited support and note the rule", func() {
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				event.Username = awsv2.String("osdManagedAdmin-abcd")
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+
+				policyInv := Investigation{ActorPolicy: mustActorPolicy(config.ActorRule{Name: "customer-admin", UserNames: []string{"^osdManagedAdmin-abcd$"}, Verdict: config.ActorVerdictDeny})}
+				result, gotErr := policyInv.Run(r)
+
+				Expect(gotErr).NotTo(HaveOccurred())
+				Expect(hasLimitedSupportAction(result.Actions)).To(BeTrue())
+				Expect(invtesting.NoteContent(result)).To(ContainSubstring(`rule "customer-admin" (deny): user name "osdManagedAdmin-abcd" matches "^osdManagedAdmin-abcd$"`))
+			})
+		})
+		When("a configured actor policy rule is restricted to non-STS clusters and the STS settings are disabled", func() {
+			It("should treat the cluster as non-STS", func() {
+				stsDisabled, err := cmv1.NewCluster().Nodes(cmv1.NewClusterNodes().Compute(0).Master(1).Infra(1)).State(cmv1.ClusterStateReady).
+					Region(cmv1.NewCloudRegion().Name("us-east-1")).AWS(cmv1.NewAWS().STS(cmv1.NewSTS().Enabled(false))).Build()
+				Expect(err).NotTo(HaveOccurred())
+				r.Resources.Cluster = stsDisabled
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				event.Username = awsv2.String("osdManagedAdmin-abcd")
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+
+				policyInv := Investigation{ActorPolicy: mustActorPolicy(config.ActorRule{Name: "non-sts-admin", UserNames: []string{"^osdManagedAdmin-abcd$"}, STS: new(false), Verdict: config.ActorVerdictDeny})}
+				result, gotErr := policyInv.Run(r)
+
+				Expect(gotErr).NotTo(HaveOccurred())
+				Expect(hasLimitedSupportAction(result.Actions)).To(BeTrue())
+				Expect(invtesting.NoteContent(result)).To(ContainSubstring(`rule "non-sts-admin" (deny)`))
+			})
+		})
+		When("AWS interrupted a spot instance", func() {
+			It("should not look for stop events and escalate with the evidence", func() {
+				spotInstance := infraInstance
+				spotInstance.State = &ec2v2types.InstanceState{Name: ec2v2types.InstanceStateNameTerminated}
+				spotInstance.StateReason = &ec2v2types.StateReason{
+					Code:    awsv2.String("Server.SpotInstanceTermination"),
+					Message: awsv2.String("Server.SpotInstanceTermination: Spot instance termination"),
+				}
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{spotInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
+
+				result, gotErr := inv.Run(r)
+
+				Expect(gotErr).NotTo(HaveOccurred())
+				Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+				Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+				Expect(invtesting.NoteContent(result)).To(ContainSubstring("67890 (cluster-test-gzq47-infra-0): spot instance interrupted by AWS (Server.SpotInstanceTermination"))
+			})
+		})
+		When("an auto scaling group terminated instances", func() {
+			It("should not classify the service as the customer", func() {
+				event.EventName = awsv2.String("TerminateInstances")
+				event.Resources = []cloudtrailv2types.Resource{{ResourceName: awsv2.String("i-0c123456")}}
+				event.Username = nil
+				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"AssumedRole","arn":"arn:aws:sts::1234:assumed-role/AWSServiceRoleForAutoScaling/AutoScaling","invokedBy":"autoscaling.amazonaws.com","sessionContext":{"sessionIssuer":{"type":"Role","userName":"AWSServiceRoleForAutoScaling"}}}}`)
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
+
+				result, gotErr := inv.Run(r)
+
+				Expect(gotErr).NotTo(HaveOccurred())
+				Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+				Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+				Expect(invtesting.NoteContent(result)).To(ContainSubstring("TerminateInstances of i-0c123456 invoked by autoscaling.amazonaws.com"))
+			})
+		})
+		When("AWS scheduled the retirement of an instance the customer stopped", func() {
+			It("should still put the cluster on limited support and note the scheduled event", func() {
+				instanceStatuses = []aws.InstanceStatus{{
+					InstanceID:   "67890",
+					State:        "stopped",
+					SystemStatus: "impaired",
+					Events: []aws.InstanceEvent{{
+						Code:        "instance-retirement",
+						Description: "The instance is running on degraded hardware",
+						NotBefore:   time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+					}},
+				}}
+				event.CloudTrailEvent = awsv2.String(`{"eventVersion":"1.08", "userIdentity":{"type":"AssumedRole", "sessionContext":{"sessionIssuer":{"type":"Role", "userName": "654321"}}}}`)
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{infraInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{masterInstance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+
+				result, gotErr := inv.Run(r)
+
+				Expect(gotErr).NotTo(HaveOccurred())
+				Expect(hasLimitedSupportAction(result.Actions)).To(BeTrue())
+				notes := invtesting.NoteContent(result)
+				Expect(notes).To(ContainSubstring("67890 (cluster-test-gzq47-infra-0): scheduled event instance-retirement not before 2026-10-01T12:00:00Z: The instance is running on degraded hardware"))
+				Expect(notes).To(ContainSubstring("67890 (cluster-test-gzq47-infra-0): system status check impaired"))
+			})
+		})
+		Describe("network changes", func() {
+			networkEvent := func(name, username, requestParameters string) cloudtrailv2types.Event {
+				return cloudtrailv2types.Event{
+					EventId:         awsv2.String("event-" + name),
+					EventName:       awsv2.String(name),
+					EventTime:       awsv2.Time(time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)),
+					Username:        awsv2.String(username),
+					CloudTrailEvent: awsv2.String(`{"eventVersion":"1.08","userIdentity":{"type":"IAMUser"},"requestParameters":` + requestParameters + `}`),
+				}
+			}
+			BeforeEach(func() {
+				// The instances were stopped by an authorized user
+				event.Username = awsv2.String("osdManagedAdmin-abcd")
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetClusterMachinePools(gomock.Any()).Return(machinePools, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListNonRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().ListRunningInstances(gomock.Any(), gomock.Eq(infraID)).Return([]ec2v2types.Instance{instance}, nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().PollInstanceStopEventsFor(gomock.Any(), gomock.Any(), gomock.Any()).Return([]cloudtrailv2types.Event{event}, nil)
+				r.Resources.OcmClient.(*ocmmock.MockClient).EXPECT().GetServiceLog(gomock.Eq(cluster), gomock.Eq("log_type='cluster-state-updates'")).Return(&servicelogsv1.ClusterLogsUUIDListResponse{}, nil)
+			})
+			expectNetworkVerifier := func() {
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(infraID)).Return(gomock.Any().String(), nil)
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetBaseConfig().Return(&awsv2.Config{})
+				r.Resources.AwsClient.(*awsmock.MockClient).EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(infraID)).Return([]string{"string1", "string2"}, nil)
+			}
+
+			When("the customer deleted the NAT gateway of the cluster", func() {
+				It("should put the cluster on limited support without running the network verifier", func() {
+					networkEvents = []cloudtrailv2types.Event{networkEvent("DeleteNatGateway", "customer", `{"DeleteNatGatewayRequest":{"NatGatewayId":"nat-1"}}`)}
+					networkResources.RouteTablesWithoutEgress = []string{"rtb-1"}
+
+					result, gotErr := inv.Run(r)
+
+					Expect(gotErr).NotTo(HaveOccurred())
+					Expect(hasLimitedSupportAction(result.Actions)).To(BeTrue())
+					Expect(hasSilenceAction(result.Actions)).To(BeTrue())
+					notes := invtesting.NoteContent(result)
+					Expect(notes).To(ContainSubstring(`2026-10-18T10:00:00Z DeleteNatGateway of nat-1 by user "customer" (customer): no rule matched user "customer"`))
+					Expect(notes).To(ContainSubstring("Customer removed the cluster's egress, route tables without an active default route: rtb-1"))
+				})
+			})
+			When("the customer deleted the NAT gateway of the cluster but egress was restored since", func() {
+				It("should note the change and run the network verifier", func() {
+					networkEvents = []cloudtrailv2types.Event{networkEvent("DeleteNatGateway", "customer", `{"DeleteNatGatewayRequest":{"NatGatewayId":"nat-1"}}`)}
+					expectNetworkVerifier()
+
+					result, gotErr := inv.Run(r)
+
+					Expect(gotErr).NotTo(HaveOccurred())
+					Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+					Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+					Expect(invtesting.NoteContent(result)).To(ContainSubstring("Customer removed the cluster's egress (NAT gateway nat-1 was deleted), but all route tables have an active default route again"))
+				})
+			})
+			When("the CloudTrail lookup was truncated", func() {
+				It("should note that older changes may be missing", func() {
+					networkTruncated = true
+					expectNetworkVerifier()
+
+					result, gotErr := inv.Run(r)
+
+					Expect(gotErr).NotTo(HaveOccurred())
+					Expect(invtesting.NoteContent(result)).To(ContainSubstring("older network changes may be missing"))
+				})
+			})
+			When("an authorized user deleted the NAT gateway of the cluster", func() {
+				It("should note the change and run the network verifier", func() {
+					networkEvents = []cloudtrailv2types.Event{networkEvent("DeleteNatGateway", "osdManagedAdmin-abcd", `{"DeleteNatGatewayRequest":{"NatGatewayId":"nat-1"}}`)}
+					expectNetworkVerifier()
+
+					result, gotErr := inv.Run(r)
+
+					Expect(gotErr).NotTo(HaveOccurred())
+					Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+					Expect(hasEscalateAction(result.Actions)).To(BeTrue())
+					Expect(invtesting.NoteContent(result)).To(ContainSubstring(`DeleteNatGateway of nat-1 by user "osdManagedAdmin-abcd" (authorized): rule "osd-managed-admin"`))
+				})
+			})
+			When("the customer changed the network without removing egress on its own", func() {
+				It("should note the change and run the network verifier", func() {
+					networkEvents = []cloudtrailv2types.Event{
+						networkEvent("DeleteRoute", "customer", `{"routeTableId":"rtb-1","destinationCidrBlock":"10.1.0.0/16"}`),
+						networkEvent("RevokeSecurityGroupEgress", "customer", `{"groupId":"sg-1","ipPermissions":{"items":[{"ipProtocol":"-1"}]}}`),
+					}
+					expectNetworkVerifier()
+
+					result, gotErr := inv.Run(r)
+
+					Expect(gotErr).NotTo(HaveOccurred())
+					Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+					notes := invtesting.NoteContent(result)
+					Expect(notes).To(ContainSubstring(`DeleteRoute of rtb-1 by user "customer" (customer)`))
+					Expect(notes).To(ContainSubstring(`RevokeSecurityGroupEgress of sg-1 by user "customer" (customer)`))
+				})
+			})
+			When("the changes are to another VPC", func() {
+				It("should ignore them", func() {
+					networkEvents = []cloudtrailv2types.Event{networkEvent("DeleteNatGateway", "customer", `{"DeleteNatGatewayRequest":{"NatGatewayId":"nat-other"}}`)}
+					expectNetworkVerifier()
+
+					result, gotErr := inv.Run(r)
+
+					Expect(gotErr).NotTo(HaveOccurred())
+					Expect(hasLimitedSupportAction(result.Actions)).To(BeFalse())
+					Expect(invtesting.NoteContent(result)).NotTo(ContainSubstring("nat-other"))
+				})
+			})
+		})
+	})
+
+	DescribeTable("the network resource IDs of CloudTrail events",
+		func(requestParameters string, ids []string) {
+			Expect(networkResourceIDs([]byte(requestParameters))).To(Equal(ids))
+		},
+		Entry("nested parameters", `{"DeleteVpcEndpointsRequest":{"VpcEndpointId":{"tag":1,"content":"vpce-2"}}}`, []string{"vpce-2"}),
+		Entry("several resources", `{"routeTableId":"rtb-1","natGatewayId":"nat-1","destinationCidrBlock":"0.0.0.0/0"}`, []string{"nat-1", "rtb-1"}),
+		Entry("no resources", `{"destinationCidrBlock":"0.0.0.0/0"}`, []string(nil)),
+		Entry("no parameters", ``, []string(nil)),
+	)
+
+	DescribeTable("the default actor rules",
+		func(actor config.Actor, allowed bool, rule string) {
+			classification := inv.actorPolicy().Classify(actor)
+
+			Expect(classification.Allowed).To(Equal(allowed))
+			Expect(classification.Rule).To(Equal(rule))
+		},
+		Entry("allow the machine-api user", config.Actor{UserName: "openshift-machine-api-aws-abcde"}, true, "machine-api-user"),
+		Entry("allow the installer role", config.Actor{UserName: "12345", IssuerName: "ManagedOpenShift-Installer-Role", STS: true, CCS: true}, true, "installer-role"),
+		Entry("allow OrganizationAccountAccessRole on non-CCS clusters", config.Actor{UserName: "12345", IssuerName: "OrganizationAccountAccessRole"}, true, "non-ccs-organization-access-role"),
+		Entry("deny OrganizationAccountAccessRole on CCS clusters", config.Actor{UserName: "12345", IssuerName: "OrganizationAccountAccessRole", CCS: true}, false, ""),
+		Entry("deny unknown users", config.Actor{UserName: "customer", IssuerName: "customer-role"}, false, ""),
+	)
+
+	It("should have valid default actor rules", func() {
+		Expect(inv.actorPolicy().Validate()).To(Succeed())
 	})
 })
@@ -2,16 +2,22 @@ package chgm
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 )
 
-func createEgressSL(blockedUrls, docLink string) *ocm.ServiceLog {
+// createEgressSL creates the service log for blocked egress. changes are the customer's network changes
+// found in CloudTrail that may have blocked it.
+func createEgressSL(blockedUrls, docLink string, changes []string) *ocm.ServiceLog {
 	if docLink == "" {
 		docLink = ocm.DocumentationLink(ocm.ProductROSA, ocm.DocumentationTopicPrivatelinkFirewall)
 	}
 
 	description := fmt.Sprintf("Your cluster requires you to take action. SRE has observed that there have been changes made to the network configuration which impacts normal working of the cluster, including lack of network egress to these internet-based resources which are required for the cluster operation and support: %s. Please revert changes, and refer to documentation regarding firewall requirements for PrivateLink clusters: %s.", blockedUrls, docLink)
+	if len(changes) != 0 {
+		description += fmt.Sprintf(" The following recent changes to the cluster's VPC may have blocked the egress: %s.", strings.Join(changes, "; "))
+	}
 
 	egressSL := ocm.ServiceLog{
 		Severity:     "Critical",
@@ -2,6 +2,7 @@ package chgm
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
@@ -28,6 +29,14 @@ func TestCreateEgressSL(t *testing.T) {
 		InternalOnly: false,
 	}
 
-	result := createEgressSL(blockedUrls, docLink)
+	result := createEgressSL(blockedUrls, docLink, nil)
 	assert.Equal(t, *expected, *result)
 }
+
+// TestCreateEgressSLWithChanges tests that createEgressSL names the customer's network changes
+func TestCreateEgressSLWithChanges(t *testing.T) {
+	changes := []string{"RevokeSecurityGroupEgress of sg-1 at 2026-10-18T10:00:00Z", "DeleteVpcEndpoints of vpce-1 at 2026-10-18T09:00:00Z"}
+
+	result := createEgressSL(blockedUrls, "https://docs.example.com", changes)
+	assert.Assert(t, strings.HasSuffix(result.Description, " The following recent changes to the cluster's VPC may have blocked the egress: RevokeSecurityGroupEgress of sg-1 at 2026-10-18T10:00:00Z; DeleteVpcEndpoints of vpce-1 at 2026-10-18T09:00:00Z."))
+}
@@ -0,0 +1,169 @@
+// Package cloudquota checks whether the AWS account of a cluster ran out of the service quotas the cluster needs
+package cloudquota
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/utils/quota"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+)
+
+const (
+	// nearLimitUtilization is the share of a quota in use from which it is noted as near its limit
+	nearLimitUtilization = 0.9
+	// sendServiceLogParam enables sending the service log for exhausted quotas
+	sendServiceLogParam  = "SEND_SERVICE_LOG"
+	quotaIncreaseDocLink = "https://docs.aws.amazon.com/servicequotas/latest/userguide/request-quota-increase.html"
+)
+
+type Investigation struct{}
+
+func newQuotaExhaustedSL(exhausted []quota.Usage) *ocm.ServiceLog {
+	lines := make([]string, 0, len(exhausted))
+	for _, usage := range exhausted {
+		lines = append(lines, usage.String())
+	}
+	return &ocm.ServiceLog{
+		Severity: "Warning",
+		Summary:  "Action required: Increase AWS service quotas",
+		Description: fmt.Sprintf("Your cluster can't create the AWS resources it needs because the following service quotas of your AWS account are exhausted: %s. "+
+			"Please request a quota increase from AWS, or free up resources within the quotas: %s.", strings.Join(lines, "; "), quotaIncreaseDocLink),
+		InternalOnly: false,
+		ServiceName:  "SREManualAction",
+	}
+}
+
+// Run compares the usage of the EC2 vCPU, Elastic IP and network interface quotas of the AWS account of the cluster
+// with their limits. The vCPU quotas are checked for the instance families the cluster uses.
+// - no quota near its limit: no actions, so the chain continues
+// - quotas near their limit: a note, the chain continues
+// - exhausted quotas: a note recommending the quota service log, escalate and stop the chain
+// - exhausted quotas and SEND_SERVICE_LOG: send the service log, silence and stop the chain
+func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests of the AWS client with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	result := investigation.InvestigationResult{}
+	r, err := rb.WithAwsClient().Build()
+	if err != nil {
+		return result, err
+	}
+
+	families, err := instanceFamilies(ctx, r.Cluster, r.OcmClient, r.AwsClient)
+	if err != nil {
+		return result, err
+	}
+	usages, err := quota.Check(ctx, r.AwsClient, families)
+	if err != nil {
+		return result, investigation.WrapInfrastructure(err, "failed to check the AWS service quotas")
+	}
+
+	notes := notewriter.New("cloudquota", logging.RawLogger)
+	var exhausted, nearLimit []quota.Usage
+	for _, usage := range usages {
+		switch {
+		case usage.Exhausted():
+			exhausted = append(exhausted, usage)
+			notes.AppendWarning("Quota exhausted: %s", usage)
+		case usage.Utilization() >= nearLimitUtilization:
+			nearLimit = append(nearLimit, usage)
+			notes.AppendWarning("Quota near its limit: %s", usage)
+		}
+	}
+
+	if len(exhausted) == 0 {
+		if len(nearLimit) == 0 {
+			logging.Infof("No AWS service quota is near its limit for instance families %v", families)
+			return result, nil
+		}
+		result.Actions = []types.Action{executor.NoteFrom(notes)}
+		return result, nil
+	}
+
+	result.StopInvestigations = fmt.Errorf("%d AWS service quotas are exhausted", len(exhausted))
+	sl := newQuotaExhaustedSL(exhausted)
+	if r.Params[sendServiceLogParam] == "true" {
+		notes.AppendAutomation("Sent a service log asking the customer to increase the exhausted quotas")
+		result.Actions = append(
+			executor.NoteAndReportFrom(notes, r.Cluster.ID(), c.Name()),
+			executor.NewServiceLogAction(sl.Severity, sl.Summary).
+				WithDescription(sl.Description).
+				WithServiceName(sl.ServiceName).
+				Build(),
+			executor.Silence("AWS service quotas exhausted - service log sent"),
+		)
+		return result, nil
+	}
+
+	notes.AppendWarning("Please send a service log asking the customer to increase the exhausted quotas:\nSummary: %s\nDescription: %s", sl.Summary, sl.Description)
+	result.Actions = append(
+		executor.NoteAndReportFrom(notes, r.Cluster.ID(), c.Name()),
+		executor.Escalate("AWS service quotas exhausted - manual service log required"),
+	)
+	return result, nil
+}
+
+// instanceFamilies returns the instance families of the compute nodes, the machine pools and the running
+// instances of the cluster, so quotas are also checked for families that failed to scale up.
+func instanceFamilies(ctx context.Context, cluster *cmv1.Cluster, ocmCli ocm.Client, awsCli aws.Client) ([]string, error) {
+	var instanceTypes []string
+	if machineType := cluster.Nodes().ComputeMachineType().ID(); machineType != "" {
+		instanceTypes = append(instanceTypes, machineType)
+	}
+
+	if ocmCli != nil {
+		machinePools, err := ocmCli.GetClusterMachinePools(cluster.ID())
+		if err != nil {
+			return nil, investigation.WrapInfrastructure(fmt.Errorf("failed to get the machine pools: %w", err), "OCM failure")
+		}
+		for _, pool := range machinePools {
+			instanceTypes = append(instanceTypes, pool.InstanceType())
+		}
+	}
+
+	if infraID := cluster.InfraID(); infraID != "" {
+		instances, err := awsCli.ListRunningInstances(ctx, infraID)
+		if err != nil {
+			return nil, investigation.WrapInfrastructure(fmt.Errorf("failed to list the running instances: %w", err), "AWS failure")
+		}
+		for _, instance := range instances {
+			instanceTypes = append(instanceTypes, string(instance.InstanceType))
+		}
+	}
+
+	var families []string
+	for _, instanceType := range instanceTypes {
+		if family := quota.InstanceFamily(instanceType); family != "" && !slices.Contains(families, family) {
+			families = append(families, family)
+		}
+	}
+	slices.Sort(families)
+	return families, nil
+}
+
+func (c *Investigation) Name() string {
+	return "cloudquota"
+}
+
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks whether the AWS account of the cluster exhausted the EC2 vCPU, Elastic IP or network interface quotas",
+		Platforms:         []investigation.Platform{investigation.PlatformAWS},
+		RequiredResources: []investigation.Resource{investigation.ResourceAWS},
+		Params: []investigation.Param{
+			{Name: sendServiceLogParam, Type: investigation.ParamBool, Default: "false", Description: "Send a service log and silence when quotas are exhausted, instead of escalating"},
+		},
+	}
+}
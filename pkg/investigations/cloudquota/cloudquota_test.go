@@ -0,0 +1,120 @@
+package cloudquota
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ec2v2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"go.uber.org/mock/gomock"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
+	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
+)
+
+func testQuotas(standardVCPUs, elasticIPs float64) map[string]aws.ServiceQuota {
+	return map[string]aws.ServiceQuota{
+		"ec2/L-1216C47A": {ServiceCode: "ec2", QuotaCode: "L-1216C47A", Name: "Running On-Demand Standard instances", Value: standardVCPUs},
+		"ec2/L-DB2E81BA": {ServiceCode: "ec2", QuotaCode: "L-DB2E81BA", Name: "Running On-Demand G and VT instances", Value: 64},
+		"ec2/L-0263D0A3": {ServiceCode: "ec2", QuotaCode: "L-0263D0A3", Name: "EC2-VPC Elastic IPs", Value: elasticIPs},
+		"vpc/L-DF5E4CA3": {ServiceCode: "vpc", QuotaCode: "L-DF5E4CA3", Name: "Network interfaces per Region", Value: 5000},
+	}
+}
+
+func testCluster() *cmv1.Cluster {
+	return invtesting.NewCluster(cmv1.NewCluster().
+		InfraID("infra-abc").
+		Nodes(cmv1.NewClusterNodes().ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge"))))
+}
+
+func TestRun(t *testing.T) {
+	usage := aws.EC2ResourceUsage{VCPUsByFamily: map[string]int32{"m5": 24, "r5": 8}, ElasticIPs: 3, NetworkInterfaces: 40}
+	running := []ec2v2types.Instance{{InstanceType: "m5.xlarge"}, {InstanceType: "r5.large"}}
+
+	tests := []struct {
+		name        string
+		data        invtesting.AWSData
+		params      map[string]string
+		wantActions []executor.ActionType
+		wantStop    bool
+		wantNote    string
+	}{
+		{
+			name:        "quotas within their limits",
+			data:        invtesting.AWSData{RunningInstances: running, EC2ResourceUsage: usage, ServiceQuotas: testQuotas(64, 5)},
+			wantActions: []executor.ActionType{},
+		},
+		{
+			name:        "quota near its limit",
+			data:        invtesting.AWSData{RunningInstances: running, EC2ResourceUsage: usage, ServiceQuotas: testQuotas(34, 5)},
+			wantActions: []executor.ActionType{executor.ActionTypePagerDutyNote},
+			wantNote:    "Quota near its limit: Running On-Demand Standard instances (L-1216C47A): 32 of 34 vCPUs",
+		},
+		{
+			name:        "exhausted quota escalates",
+			data:        invtesting.AWSData{RunningInstances: running, EC2ResourceUsage: usage, ServiceQuotas: testQuotas(32, 5)},
+			wantActions: []executor.ActionType{executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote, executor.ActionTypeEscalateIncident},
+			wantStop:    true,
+			wantNote:    "Please send a service log",
+		},
+		{
+			name:        "exhausted quota sends a service log",
+			data:        invtesting.AWSData{RunningInstances: running, EC2ResourceUsage: usage, ServiceQuotas: testQuotas(64, 3)},
+			params:      map[string]string{sendServiceLogParam: "true"},
+			wantActions: []executor.ActionType{executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote, executor.ActionTypeServiceLog, executor.ActionTypeSilenceIncident},
+			wantStop:    true,
+			wantNote:    "Quota exhausted: EC2-VPC Elastic IPs (L-0263D0A3): 3 of 3 Elastic IPs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := invtesting.Run(t, &Investigation{}, invtesting.Scenario{
+				Name:    "cloudquota",
+				Cluster: testCluster(),
+				AWS:     &tt.data,
+				Params:  tt.params,
+			})
+			invtesting.ExpectActions(t, result, tt.wantActions...)
+			if (result.StopInvestigations != nil) != tt.wantStop {
+				t.Errorf("expected StopInvestigations %v, got %v", tt.wantStop, result.StopInvestigations)
+			}
+			if note := invtesting.NoteContent(result); !strings.Contains(note, tt.wantNote) {
+				t.Errorf("expected the note to contain %q, got %q", tt.wantNote, note)
+			}
+		})
+	}
+}
+
+func TestRunAWSError(t *testing.T) {
+	_, err := (&Investigation{}).Run(invtesting.NewResourceBuilder(t, invtesting.Scenario{
+		Cluster: testCluster(),
+		AWS:     &invtesting.AWSData{Err: errors.New("throttled")},
+	}))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestInstanceFamilies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	ocmClient := ocmmock.NewMockClient(ctrl)
+	pool, err := cmv1.NewMachinePool().InstanceType("g4dn.xlarge").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ocmClient.EXPECT().GetClusterMachinePools(invtesting.DefaultClusterID).Return([]*cmv1.MachinePool{pool}, nil)
+	awsClient := &invtesting.FakeAWSClient{Data: invtesting.AWSData{RunningInstances: []ec2v2types.Instance{{InstanceType: "m5.xlarge"}, {InstanceType: "m5.2xlarge"}}}}
+
+	families, err := instanceFamilies(context.Background(), testCluster(), ocmClient, awsClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(families, ",") != "g4dn,m5" {
+		t.Errorf("instanceFamilies() = %v, want [g4dn m5]", families)
+	}
+}
@@ -71,7 +71,12 @@ type Investigation struct {
 }
 
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
-	ctx := context.Background()
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the health checks. Checks that fail once ctx is done, e.g. a hung exec into the
+// etcd or alertmanager pods, are noted like any other failed check.
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithCluster().WithK8sClient().Build()
@@ -1155,6 +1160,14 @@ func (i *Investigation) Name() string {
 	return "clusterhealthcheck"
 }
 
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       i.Description(),
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+		NeedsCustomerData: true,
+	}
+}
+
 func (i *Investigation) AlertTitle() string {
 	return ""
 }
@@ -63,7 +63,12 @@ const available = "Available"
 
 type Investigation struct{}
 
-func (c *Investigation) Run(rb investigation.ResourceBuilder) (result investigation.InvestigationResult, err error) {
+func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (result investigation.InvestigationResult, err error) {
 	r, err := rb.WithK8sClient().Build()
 	if err != nil {
 		if msg, ok := investigation.ClusterAccessErrorMessage(err); ok {
@@ -82,7 +87,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (result investigat
 	// List the monitoring cluster operator
 	coList := &configv1.ClusterOperatorList{}
 	listOptions := &client.ListOptions{FieldSelector: fields.SelectorFromSet(fields.Set{"metadata.name": "monitoring"})}
-	err = r.K8sClient.List(context.TODO(), coList, listOptions)
+	err = r.K8sClient.List(ctx, coList, listOptions)
 	if err != nil {
 		return result, investigation.WrapInfrastructure(
 			fmt.Errorf("unable to list monitoring clusteroperator: %w", err),
@@ -164,6 +169,13 @@ func (c *Investigation) Name() string {
 	return "clustermonitoringerrorbudgetburn"
 }
 
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks for a broken user workload monitoring config causing the monitoring error budget burn",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+	}
+}
+
 // Check if the `Available` status condition reports a broken UWM config
 func isUWMConfigInvalid(monitoringCo *configv1.ClusterOperator) bool {
 	symptomStatusString := `the User Workload Configuration from "config.yaml" key in the "openshift-user-workload-monitoring/user-workload-monitoring-config" ConfigMap could not be parsed`
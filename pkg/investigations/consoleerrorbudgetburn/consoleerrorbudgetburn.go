@@ -49,13 +49,13 @@ type probeHistoryChecker interface {
 }
 
 type egressVerifier interface {
-	run(r *investigation.Resources) (networkverifier.VerifierResult, string, error)
+	run(ctx context.Context, r *investigation.Resources) (networkverifier.VerifierResult, string, error)
 }
 
 type defaultEgressVerifier struct{}
 
-func (d *defaultEgressVerifier) run(r *investigation.Resources) (networkverifier.VerifierResult, string, error) {
-	return networkverifier.Run(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+func (d *defaultEgressVerifier) run(ctx context.Context, r *investigation.Resources) (networkverifier.VerifierResult, string, error) {
+	return networkverifier.Run(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
 }
 
 type Investigation struct {
@@ -66,6 +66,11 @@ type Investigation struct {
 }
 
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests of the k8s and AWS clients with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	if i.consoleChecker == nil {
 		i.consoleChecker = &defaultConsoleServiceChecker{}
 	}
@@ -79,7 +84,6 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 		i.egressVerifier = &defaultEgressVerifier{}
 	}
 
-	ctx := context.Background()
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithCluster().WithK8sClient().Build()
@@ -186,7 +190,7 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 			if err != nil {
 				notes.AppendWarning("VPC Egress: unable to fetch ClusterDeployment — %v", err)
 			} else {
-				i.checkVPCEgress(r, notes)
+				i.checkVPCEgress(ctx, r, notes)
 			}
 		}
 	}
@@ -702,8 +706,8 @@ func reportCLBInstanceHealth(lbName string, instances []aws.CLBInstanceHealth, n
 // to test outbound connectivity to required endpoints.
 //
 // Classic only, public only (not PrivateLink). Informational-only check.
-func (i *Investigation) checkVPCEgress(r *investigation.Resources, notes *notewriter.NoteWriter) {
-	verifierResult, failureReason, err := i.egressVerifier.run(r)
+func (i *Investigation) checkVPCEgress(ctx context.Context, r *investigation.Resources, notes *notewriter.NoteWriter) {
+	verifierResult, failureReason, err := i.egressVerifier.run(ctx, r)
 	if err != nil {
 		notes.AppendWarning("VPC Egress: network verifier error — %v", err)
 		return
@@ -1373,6 +1377,13 @@ func (i *Investigation) Name() string {
 	return "consoleerrorbudgetburn"
 }
 
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       i.Description(),
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+	}
+}
+
 func (i *Investigation) AlertTitle() string {
 	return "console-ErrorBudgetBurn"
 }
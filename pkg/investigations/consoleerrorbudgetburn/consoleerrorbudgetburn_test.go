@@ -2773,7 +2773,7 @@ type mockEgressVerifier struct {
 	called  bool
 }
 
-func (m *mockEgressVerifier) run(r *investigation.Resources) (networkverifier.VerifierResult, string, error) {
+func (m *mockEgressVerifier) run(_ context.Context, r *investigation.Resources) (networkverifier.VerifierResult, string, error) {
 	m.called = true
 	return m.result, m.failure, m.err
 }
@@ -2793,7 +2793,7 @@ func TestCheckVPCEgress_Success(t *testing.T) {
 		Cluster:           cluster,
 		ClusterDeployment: &hivev1.ClusterDeployment{},
 	}
-	inv.checkVPCEgress(r, notes)
+	inv.checkVPCEgress(context.Background(), r, notes)
 
 	output := notes.String()
 	if !strings.Contains(output, "network verifier passed") {
@@ -2813,7 +2813,7 @@ func TestCheckVPCEgress_Failure(t *testing.T) {
 		Cluster:           cluster,
 		ClusterDeployment: &hivev1.ClusterDeployment{},
 	}
-	inv.checkVPCEgress(r, notes)
+	inv.checkVPCEgress(context.Background(), r, notes)
 
 	output := notes.String()
 	if !strings.Contains(output, "blocked egress") {
@@ -2835,7 +2835,7 @@ func TestCheckVPCEgress_Error(t *testing.T) {
 		Cluster:           cluster,
 		ClusterDeployment: &hivev1.ClusterDeployment{},
 	}
-	inv.checkVPCEgress(r, notes)
+	inv.checkVPCEgress(context.Background(), r, notes)
 
 	output := notes.String()
 	if !strings.Contains(output, "network verifier error") {
@@ -2857,7 +2857,7 @@ func TestCheckVPCEgress_Undefined(t *testing.T) {
 		Cluster:           cluster,
 		ClusterDeployment: &hivev1.ClusterDeployment{},
 	}
-	inv.checkVPCEgress(r, notes)
+	inv.checkVPCEgress(context.Background(), r, notes)
 
 	output := notes.String()
 	if !strings.Contains(output, "undefined result") {
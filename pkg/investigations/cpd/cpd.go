@@ -2,16 +2,20 @@
 package cpd
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
 	"github.com/openshift/configuration-anomaly-detection/pkg/networkverifier"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
 )
 
 type Investigation struct{}
@@ -35,15 +39,31 @@ func newBYOVPCRoutingSL(docLink string) *ocm.ServiceLog {
 // Currently what this investigation does is:
 // - check cluster state
 // - check clusterDeployment state
+// - analyze the provision failure conditions and the install log, and note the matching install failure signature
 // - check DNS
-// - check subnet routes
-// - run network verifier and add the output as pagerduty note
-// - always escalate the alert to primary
-// The reasoning for this is that we don't fully trust network verifier yet.
-// In the future, we want to automate service logs based on the network verifier output.
+// - check subnet routes, or on GCP that the BYOVPC network exists
+// - run network verifier and add the output as pagerduty note, and its raw output to the backplane report
+// - classify the verifier failures and send the matching install failure service log, if the class is automated
+// - otherwise escalate the alert to primary
+// Classes are automated one by one through the investigation parameters, as we don't fully trust network verifier yet.
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests of the GCP client with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
-	r, err := rb.WithClusterDeployment().WithAwsClient().Build()
+	r, err := rb.WithClusterDeployment().Build()
+	if err != nil {
+		return result, err
+	}
+	_, cloud := investigation.ClusterPlatforms(r.Cluster)
+	isGCP := cloud == investigation.PlatformGCP
+	if isGCP {
+		r, err = rb.WithGcpClient().Build()
+	} else {
+		r, err = rb.WithAwsClient().Build()
+	}
 	if err != nil {
 		return result, err
 	}
@@ -63,6 +83,8 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	}
 	notes.AppendSuccess("Cluster installation did not yet finish")
 
+	noteProvisionAnalysis(r.OcmClient, r.Cluster.ID(), r.ClusterDeployment, notes)
+
 	if r.ClusterDeployment.Spec.ClusterMetadata == nil {
 		// This sometimes happens on staging when QE tests new unstable versions
 		// In case this happens on production, we want to raise this to OCM/CS.
@@ -79,6 +101,9 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	// Check if DNS is ready, exit out if not
 	if !r.Cluster.Status().DNSReady() {
 		notes.AppendWarning("DNS not ready.\nInvestigate reasons using the dnszones CR in the cluster namespace:\noc get dnszones -n uhc-production-%s -o yaml --as backplane-cluster-admin", r.Cluster.ID())
+		if isGCP {
+			noteCloudDNSZones(ctx, notes, r.GcpClient, r.Cluster.DNS().BaseDomain())
+		}
 		result.Actions = append(
 			executor.NoteAndReportFrom(notes, r.Cluster.ID(), c.Name()),
 			executor.Escalate("Cluster DNS not ready"),
@@ -90,10 +115,27 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	product := ocm.GetClusterProduct(r.Cluster)
 	docLink := ocm.DocumentationLink(product, ocm.DocumentationTopicAwsCustomVPC)
 
-	if r.Cluster.AWS().SubnetIDs() != nil && len(r.Cluster.AWS().SubnetIDs()) > 0 {
+	if isGCP {
+		if vpcName := r.Cluster.GCPNetwork().VPCName(); vpcName != "" {
+			logging.Info("Checking BYOVPC to ensure the network exists...")
+			_, err := r.GcpClient.GetNetwork(ctx, vpcName)
+			if gcp.IsNotFound(err) {
+				notes.AppendWarning("BYOVPC network %s does not exist or CAD can't access it", vpcName)
+				result.Actions = append(
+					executor.NoteAndReportFrom(notes, r.Cluster.ID(), c.Name()),
+					executor.Escalate("BYOVPC network not found"),
+				)
+				return result, nil
+			}
+			if err != nil {
+				return result, investigation.WrapInfrastructure(err, "GCP API failure getting the BYOVPC network")
+			}
+			notes.AppendSuccess("BYOVPC network %s exists", vpcName)
+		}
+	} else if r.Cluster.AWS().SubnetIDs() != nil && len(r.Cluster.AWS().SubnetIDs()) > 0 {
 		logging.Info("Checking BYOVPC to ensure subnets have valid routing...")
 		for _, subnet := range r.Cluster.AWS().SubnetIDs() {
-			isValid, err := isSubnetRouteValid(r.AwsClient, subnet)
+			isValid, err := isSubnetRouteValid(ctx, r.AwsClient, subnet)
 			if err != nil {
 				return result, investigation.WrapInfrastructure(err, "AWS API failure checking subnet route tables")
 			}
@@ -113,9 +155,25 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 			}
 		}
 	}
-	notes.AppendSuccess("BYOVPC has valid routing")
+	if !isGCP {
+		// On GCP, routes are checked along with the firewall rules below
+		notes.AppendSuccess("BYOVPC has valid routing")
+	}
 
-	verifierResult, failureReason, err := networkverifier.Run(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	var verifierResult networkverifier.VerifierResult
+	var failures []string
+	var verifierOutput string
+	if isGCP {
+		var failureReason string
+		verifierResult, failureReason, err = networkverifier.RunGCP(ctx, r.Cluster, r.ClusterDeployment, r.GcpClient)
+		if failureReason != "" {
+			failures = strings.Split(failureReason, ",")
+		}
+	} else {
+		var out networkverifier.Output
+		out, err = networkverifier.Verify(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+		verifierResult, failures, verifierOutput = out.Result, out.Failures, out.Raw
+	}
 	if err != nil {
 		logging.Errorf("Network verifier ran into an error: %s", err.Error())
 		notes.AppendWarning("NetworkVerifier failed to run:\n\t %s", err.Error())
@@ -124,17 +182,34 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 
 	switch verifierResult {
 	case networkverifier.Failure:
+		failureReason := strings.Join(failures, ",")
 		logging.Infof("Network verifier reported failure: %s", failureReason)
 		metrics.Inc(metrics.ServicelogPrepared, c.Name())
+		if !isGCP {
+			// On GCP, the failures are firewall rules and routes instead of probe errors
+			class := classifyFailures(failures, !r.Cluster.Proxy().Empty())
+			notes.AppendWarning("NetworkVerifier failures classified as %s", class)
+			if sl := newInstallFailureSL(class, failures, product); sl != nil && r.Params[failureClassParams[class]] == "true" {
+				notes.AppendAutomation("Sent the %s install failure service log for: %s", class, failureReason)
+				result.Actions = append(
+					executor.NoteAndReportWithAttachment(notes, r.Cluster.ID(), c.Name(), "Network verifier output", verifierOutput),
+					executor.NewServiceLogAction(sl.Severity, sl.Summary).
+						WithDescription(sl.Description).
+						WithServiceName(sl.ServiceName).
+						Build(),
+					executor.Silence(fmt.Sprintf("Network verifier found %s - install failure service log sent", class)),
+				)
+				return result, nil
+			}
+		}
 		notes.AppendWarning("NetworkVerifier found unreachable targets. \n \n Verify and send service log if necessary: \n osdctl servicelog post --cluster-id %s -t https://raw.githubusercontent.com/openshift/managed-notifications/master/osd/required_network_egresses_are_blocked.json -p URLS=\"%s\"", r.Cluster.ID(), failureReason)
 	case networkverifier.Success:
 		notes.AppendSuccess("Network verifier passed")
 	}
 
-	// We currently always escalate, in the future, when network verifier is reliable,
-	// we would silence the alert when we had a service log in the case of network verifier detecting failures.
+	// Failures are escalated unless their class is automated, see failureClassParams
 	result.Actions = append(
-		executor.NoteAndReportFrom(notes, r.Cluster.ID(), c.Name()),
+		executor.NoteAndReportWithAttachment(notes, r.Cluster.ID(), c.Name(), "Network verifier output", verifierOutput),
 		executor.Escalate("ClusterProvisioningDelay - manual investigation required"),
 	)
 	return result, nil
@@ -144,8 +219,48 @@ func (c *Investigation) Name() string {
 	return "cpd"
 }
 
-func isSubnetRouteValid(awsClient aws.Client, subnetID string) (bool, error) {
-	routeTable, err := awsClient.GetRouteTableForSubnet(subnetID)
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Investigates clusters whose provisioning is delayed (ClusterProvisioningDelay)",
+		Platforms:         []investigation.Platform{investigation.PlatformClassic, investigation.PlatformAWS, investigation.PlatformGCP},
+		RequiredResources: []investigation.Resource{investigation.ResourceAWS, investigation.ResourceGCP},
+		Params: []investigation.Param{
+			{Name: failureClassParams[failureClassEgressBlocked], Type: investigation.ParamBool, Default: "false", Description: "Send a service log and silence when the network verifier only finds blocked egress"},
+			{Name: failureClassParams[failureClassDNS], Type: investigation.ParamBool, Default: "false", Description: "Send a service log and silence when the network verifier only finds DNS failures"},
+			{Name: failureClassParams[failureClassMissingRoute], Type: investigation.ParamBool, Default: "false", Description: "Send a service log and silence when the network verifier only finds missing routes"},
+			{Name: failureClassParams[failureClassProxy], Type: investigation.ParamBool, Default: "false", Description: "Send a service log and silence when the network verifier only finds proxy failures"},
+		},
+	}
+}
+
+// noteProvisionAnalysis adds notes on the current install attempt.
+// Failures to get the install log are only noted, as the other checks don't depend on it.
+func noteProvisionAnalysis(ocmClient ocm.Client, clusterID string, cd *hivev1.ClusterDeployment, notes *notewriter.NoteWriter) {
+	analysis, err := analyzeProvision(ocmClient, clusterID, cd)
+	if err != nil {
+		notes.AppendWarning("Could not analyze the install log: %s", err.Error())
+		return
+	}
+	if analysis == nil {
+		notes.AppendWarning("Hive did not start provisioning the cluster yet")
+		return
+	}
+
+	notes.AppendSuccess("Current ClusterProvision: %s", analysis.Provision)
+	for _, condition := range analysis.FailureConditions {
+		notes.AppendWarning("ClusterDeployment condition %s", condition)
+	}
+	if analysis.Match != nil {
+		notes.AppendWarning("Install failure signature %s: %s\n\t%s", analysis.Match.Signature.Name, analysis.Match.Signature.Description, analysis.Match.Line)
+		return
+	}
+	if len(analysis.LogTail) > 0 {
+		notes.AppendSuccess("Install log matches no known failure signature")
+	}
+}
+
+func isSubnetRouteValid(ctx context.Context, awsClient aws.Client, subnetID string) (bool, error) {
+	routeTable, err := awsClient.GetRouteTableForSubnet(ctx, subnetID)
 	if err != nil {
 		return false, err
 	}
@@ -160,3 +275,19 @@ func isSubnetRouteValid(awsClient aws.Client, subnetID string) (bool, error) {
 	// We haven't found a default route to the internet, so this subnet has an invalid route table
 	return false, nil
 }
+
+// noteCloudDNSZones adds a note listing the Cloud DNS zones serving the base domain of a GCP cluster
+func noteCloudDNSZones(ctx context.Context, notes *notewriter.NoteWriter, gcpClient gcp.Client, baseDomain string) {
+	zones, err := gcpClient.ListManagedZones(ctx, baseDomain+".")
+	if err != nil {
+		notes.AppendWarning("Could not list Cloud DNS zones for %s: %s", baseDomain, err.Error())
+		return
+	}
+	if len(zones) == 0 {
+		notes.AppendWarning("No Cloud DNS zone for the base domain %s in project %s", baseDomain, gcpClient.ProjectID())
+		return
+	}
+	for _, zone := range zones {
+		notes.AppendSuccess("Cloud DNS zone %s (%s) serves %s", zone.Name, zone.Visibility, zone.DNSName)
+	}
+}
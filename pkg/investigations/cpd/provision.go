@@ -0,0 +1,144 @@
+package cpd
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+// installLogTailLines is the number of install log lines matched against the signatures
+const installLogTailLines = 50
+
+// failureConditions are the ClusterDeployment conditions reporting a failed or stuck install attempt.
+// Hive copies the failure of the current ClusterProvision into ProvisionFailed.
+var failureConditions = []hivev1.ClusterDeploymentConditionType{
+	hivev1.ProvisionFailedCondition,
+	hivev1.ProvisionStoppedCondition,
+	hivev1.InstallLaunchErrorCondition,
+	hivev1.InstallImagesNotResolvedCondition,
+}
+
+//go:embed install_signatures.yaml
+var installSignaturesData []byte
+
+// installSignatures are the known install failures, see install_signatures.yaml
+var installSignatures = mustParseInstallSignatures(installSignaturesData)
+
+// installSignature is a known install failure
+type installSignature struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Patterns    []string `yaml:"patterns"`
+
+	patterns []*regexp.Regexp
+}
+
+// parseInstallSignatures parses and compiles the signatures of a data file
+func parseInstallSignatures(data []byte) ([]installSignature, error) {
+	var signatures []installSignature
+	if err := yaml.Unmarshal(data, &signatures); err != nil {
+		return nil, fmt.Errorf("failed to parse install signatures: %w", err)
+	}
+	for i := range signatures {
+		s := &signatures[i]
+		if s.Name == "" || len(s.Patterns) == 0 {
+			return nil, fmt.Errorf("install signature %d needs a name and patterns", i)
+		}
+		for _, pattern := range s.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern of install signature %s: %w", s.Name, err)
+			}
+			s.patterns = append(s.patterns, re)
+		}
+	}
+	return signatures, nil
+}
+
+// mustParseInstallSignatures parses the embedded signatures, which are validated by the tests
+func mustParseInstallSignatures(data []byte) []installSignature {
+	signatures, err := parseInstallSignatures(data)
+	if err != nil {
+		panic(err)
+	}
+	return signatures
+}
+
+// signatureMatch is a signature along with the line that matched it
+type signatureMatch struct {
+	Signature *installSignature
+	Line      string
+}
+
+// matchInstallSignature returns the first signature matching one of the lines, or nil
+func matchInstallSignature(signatures []installSignature, lines []string) *signatureMatch {
+	for i := range signatures {
+		for _, line := range lines {
+			if slices.ContainsFunc(signatures[i].patterns, func(re *regexp.Regexp) bool { return re.MatchString(line) }) {
+				return &signatureMatch{Signature: &signatures[i], Line: strings.TrimSpace(line)}
+			}
+		}
+	}
+	return nil
+}
+
+// provisionAnalysis is what hive reports about the current install attempt of a cluster
+type provisionAnalysis struct {
+	// Provision is the name of the current ClusterProvision
+	Provision string
+	// FailureConditions are the failure conditions of the ClusterDeployment that are true, formatted for notes
+	FailureConditions []string
+	// LogTail is the end of the install log, empty if there is none yet
+	LogTail []string
+	Match   *signatureMatch
+}
+
+// analyzeProvision reads the failure conditions of the ClusterDeployment and the end of the install log hive
+// collected, and matches them against the install signatures. Both come from OCM, as CAD can't access the
+// hive shard. It returns nil if hive didn't start provisioning yet.
+func analyzeProvision(ocmClient ocm.Client, clusterID string, cd *hivev1.ClusterDeployment) (*provisionAnalysis, error) {
+	if cd.Status.ProvisionRef == nil || cd.Status.ProvisionRef.Name == "" {
+		return nil, nil
+	}
+	analysis := &provisionAnalysis{Provision: cd.Status.ProvisionRef.Name}
+
+	var lines []string
+	for _, condition := range cd.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue || !slices.Contains(failureConditions, condition.Type) {
+			continue
+		}
+		analysis.FailureConditions = append(analysis.FailureConditions, fmt.Sprintf("%s (%s): %s", condition.Type, condition.Reason, condition.Message))
+		lines = append(lines, condition.Reason, condition.Message)
+	}
+
+	installLog, err := ocmClient.GetInstallLog(clusterID, installLogTailLines)
+	if err != nil {
+		return nil, err
+	}
+	analysis.LogTail = tailLines(installLog, installLogTailLines)
+
+	analysis.Match = matchInstallSignature(installSignatures, append(lines, analysis.LogTail...))
+	return analysis, nil
+}
+
+// tailLines returns the last n non-empty lines of s
+func tailLines(s string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,190 @@
+package cpd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
+)
+
+func TestInstallSignatures(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{
+			line: `level=error msg=Error: creating EC2 Instance: VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit of 32 allows`,
+			want: "QuotaExceeded",
+		},
+		{
+			line: `level=error msg=Error: creating EC2 EIP: AddressLimitExceeded: The maximum number of addresses has been reached.`,
+			want: "QuotaExceeded",
+		},
+		{
+			line: `level=error msg=Error: Quota 'CPUS' exceeded. Limit: 24.0 in region us-east1.`,
+			want: "QuotaExceeded",
+		},
+		{
+			line: `level=error msg=Error: creating EC2 VPC: UnauthorizedOperation: You are not authorized to perform this operation. User: arn:aws:sts::123456789012:assumed-role/ManagedOpenShift-Installer-Role/123 is not authorized to perform: ec2:CreateVpc with an explicit deny in a service control policy`,
+			want: "SCPDeny",
+		},
+		{
+			line: `level=error msg=Error: waiting for EC2 Instance create: Client.InvalidKMSKey.InvalidState: The KMS key provided is in an incorrect state`,
+			want: "KMSKeyAccess",
+		},
+		{
+			line: `level=error msg=Bootstrap failed to complete: timed out waiting for the condition`,
+			want: "BootstrapTimeout",
+		},
+		{
+			line: `level=info msg=Waiting up to 20m0s for the Kubernetes API at https://api.example.com:6443...`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			match := matchInstallSignature(installSignatures, []string{tt.line})
+			got := ""
+			if match != nil {
+				got = match.Signature.Name
+			}
+			if got != tt.want {
+				t.Errorf("matchInstallSignature(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInstallSignaturesInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"not a list":      "name: x",
+		"missing pattern": "- name: x",
+		"invalid regexp":  "- name: x\n  patterns: ['(']",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseInstallSignatures([]byte(data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestAnalyzeProvision(t *testing.T) {
+	const clusterID = "abc"
+	installLog := "level=info msg=Creating infrastructure resources...\n" +
+		"level=error msg=Error: creating EC2 Instance: VcpuLimitExceeded: You have requested more vCPU capacity than your current vCPU limit of 32 allows\n"
+
+	tests := []struct {
+		name           string
+		provisionRef   string
+		conditions     []hivev1.ClusterDeploymentCondition
+		installLog     string
+		logErr         error
+		wantNil        bool
+		wantErr        bool
+		wantSignature  string
+		wantConditions int
+	}{
+		{
+			name:    "hive did not start provisioning",
+			wantNil: true,
+		},
+		{
+			name:         "install log not available",
+			provisionRef: "abc-0-xyz",
+			logErr:       errors.New("forbidden"),
+			wantErr:      true,
+		},
+		{
+			name:         "failed install with a known signature in the install log",
+			provisionRef: "abc-0-xyz",
+			conditions: []hivev1.ClusterDeploymentCondition{
+				{Type: hivev1.ProvisionFailedCondition, Status: corev1.ConditionTrue, Reason: "FailureReasonNotListed", Message: "install failed"},
+				{Type: hivev1.InstallLaunchErrorCondition, Status: corev1.ConditionFalse},
+			},
+			installLog:     installLog,
+			wantSignature:  "QuotaExceeded",
+			wantConditions: 1,
+		},
+		{
+			name:         "known signature in a failure condition before the installer logged",
+			provisionRef: "abc-0-xyz",
+			conditions: []hivev1.ClusterDeploymentCondition{
+				{Type: hivev1.ProvisionFailedCondition, Status: corev1.ConditionTrue, Reason: "KMSAccessDenied", Message: "KMSAccessDeniedException: the installer can't use the key"},
+			},
+			wantSignature:  "KMSKeyAccess",
+			wantConditions: 1,
+		},
+		{
+			name:         "running install",
+			provisionRef: "abc-0-xyz",
+			installLog:   "level=info msg=Creating infrastructure resources...\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ocmClient := ocmmock.NewMockClient(gomock.NewController(t))
+			if tt.provisionRef != "" {
+				ocmClient.EXPECT().GetInstallLog(clusterID, installLogTailLines).Return(tt.installLog, tt.logErr)
+			}
+
+			cd := &hivev1.ClusterDeployment{Status: hivev1.ClusterDeploymentStatus{Conditions: tt.conditions}}
+			if tt.provisionRef != "" {
+				cd.Status.ProvisionRef = &corev1.LocalObjectReference{Name: tt.provisionRef}
+			}
+			analysis, err := analyzeProvision(ocmClient, clusterID, cd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("analyzeProvision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (analysis == nil) != tt.wantNil {
+				t.Fatalf("analyzeProvision() = %v, wantNil %v", analysis, tt.wantNil)
+			}
+			if analysis == nil {
+				return
+			}
+
+			gotSignature := ""
+			if analysis.Match != nil {
+				gotSignature = analysis.Match.Signature.Name
+			}
+			if gotSignature != tt.wantSignature {
+				t.Errorf("expected signature %q, got %q", tt.wantSignature, gotSignature)
+			}
+			if len(analysis.FailureConditions) != tt.wantConditions {
+				t.Errorf("expected %d failure conditions, got %v", tt.wantConditions, analysis.FailureConditions)
+			}
+		})
+	}
+}
+
+func TestNoteProvisionAnalysis(t *testing.T) {
+	ocmClient := ocmmock.NewMockClient(gomock.NewController(t))
+	ocmClient.EXPECT().GetInstallLog("abc", installLogTailLines).Return("level=error msg=Bootstrap failed to complete: timed out waiting for the condition\n", nil)
+	cd := &hivev1.ClusterDeployment{Status: hivev1.ClusterDeploymentStatus{ProvisionRef: &corev1.LocalObjectReference{Name: "abc-0-xyz"}}}
+	notes := notewriter.New("CPD", logging.RawLogger)
+
+	noteProvisionAnalysis(ocmClient, "abc", cd, notes)
+	for _, want := range []string{"Current ClusterProvision: abc-0-xyz", "Install failure signature BootstrapTimeout", "Bootstrap failed to complete"} {
+		if !strings.Contains(notes.String(), want) {
+			t.Errorf("expected the notes to contain %q, got %q", want, notes.String())
+		}
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	got := tailLines("a\n\nb\nc\n", 2)
+	if len(got) != 2 || got[0] != "b" || got[1] != "c" {
+		t.Errorf("tailLines() = %q, want [b c]", got)
+	}
+}
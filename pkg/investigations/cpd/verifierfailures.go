@@ -0,0 +1,126 @@
+package cpd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+// failureClass is the class of the failures the network verifier found
+type failureClass string
+
+const (
+	// failureClassEgressBlocked: connections to required endpoints time out or are refused, e.g. by a firewall
+	failureClassEgressBlocked failureClass = "EgressBlocked"
+	// failureClassDNS: the hostnames of required endpoints can't be resolved
+	failureClassDNS failureClass = "DNSFailure"
+	// failureClassMissingRoute: the subnet has no route to the required endpoints
+	failureClassMissingRoute failureClass = "MissingRoute"
+	// failureClassProxy: the cluster-wide proxy refuses or breaks the connections
+	failureClassProxy failureClass = "ProxyMisconfigured"
+	// failureClassUnknown: failures of different classes, or that aren't understood
+	failureClassUnknown failureClass = "Unknown"
+)
+
+// failureClassParams are the investigation parameters that enable sending the service log of a class.
+// Classes are only automated once we trust the classification, until then they are escalated.
+var failureClassParams = map[failureClass]string{
+	failureClassEgressBlocked: "AUTOMATE_EGRESS_BLOCKED",
+	failureClassDNS:           "AUTOMATE_DNS_FAILURE",
+	failureClassMissingRoute:  "AUTOMATE_MISSING_ROUTE",
+	failureClassProxy:         "AUTOMATE_PROXY_MISCONFIGURED",
+}
+
+// failurePatterns map the curl errors of failed probes to their class, the first matching pattern wins.
+// Proxy errors come first, as curl reports errors of the proxy connection with the same wording.
+var failurePatterns = []struct {
+	class   failureClass
+	pattern string
+}{
+	{failureClassProxy, "Could not resolve proxy"},
+	{failureClassProxy, "from proxy after CONNECT"},
+	{failureClassProxy, "Proxy CONNECT aborted"},
+	{failureClassDNS, "Could not resolve host"},
+	{failureClassMissingRoute, "No route to host"},
+	{failureClassMissingRoute, "Network is unreachable"},
+	{failureClassEgressBlocked, "Connection timed out"},
+	{failureClassEgressBlocked, "Connection refused"},
+	{failureClassEgressBlocked, "Operation timed out"},
+	{failureClassEgressBlocked, "Connection reset by peer"},
+}
+
+// classifyFailure returns the class of a single verifier failure. On clusters with a proxy,
+// certificate errors mean the proxy intercepts TLS with a CA the cluster doesn't trust.
+func classifyFailure(failure string, proxied bool) failureClass {
+	for _, p := range failurePatterns {
+		if strings.Contains(failure, p.pattern) {
+			return p.class
+		}
+	}
+	if proxied && strings.Contains(failure, "SSL certificate problem") {
+		return failureClassProxy
+	}
+	return failureClassUnknown
+}
+
+// classifyFailures returns the class all failures share, or failureClassUnknown if they don't share one.
+// Mixed failures point to a cause we can't tell the customer with confidence.
+func classifyFailures(failures []string, proxied bool) failureClass {
+	if len(failures) == 0 {
+		return failureClassUnknown
+	}
+	class := classifyFailure(failures[0], proxied)
+	for _, failure := range failures[1:] {
+		if classifyFailure(failure, proxied) != class {
+			return failureClassUnknown
+		}
+	}
+	return class
+}
+
+// failedTargets returns the targets of the failures without the probe errors, for service logs
+func failedTargets(failures []string) string {
+	targets := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		target, _, _ := strings.Cut(failure, " (")
+		targets = append(targets, target)
+	}
+	return strings.Join(targets, ", ")
+}
+
+// newInstallFailureSL returns the service log for verifier failures of the class, or nil if the class has none
+func newInstallFailureSL(class failureClass, failures []string, product ocm.Product) *ocm.ServiceLog {
+	targets := failedTargets(failures)
+	firewallDocLink := ocm.DocumentationLink(product, ocm.DocumentationTopicPrivatelinkFirewall)
+
+	switch class {
+	case failureClassEgressBlocked:
+		return &ocm.ServiceLog{
+			Severity:     "Major",
+			Summary:      "Installation blocked: Required network egress is blocked",
+			Description:  fmt.Sprintf("Your cluster's installation is blocked because the cluster can't reach these internet-based resources which are required for the installation: %s. Please allow the egress to them in your firewall or security groups by following the documentation and re-install the cluster: %s.", targets, firewallDocLink),
+			InternalOnly: false,
+			ServiceName:  "SREManualAction",
+		}
+	case failureClassDNS:
+		return &ocm.ServiceLog{
+			Severity:     "Major",
+			Summary:      "Installation blocked: DNS resolution failure",
+			Description:  fmt.Sprintf("Your cluster's installation is blocked because the DNS resolver of the cluster's VPC can't resolve the hostnames of these resources which are required for the installation: %s. Please verify the DHCP options and DNS configuration of the VPC, and re-install the cluster.", targets),
+			InternalOnly: false,
+			ServiceName:  "SREManualAction",
+		}
+	case failureClassMissingRoute:
+		return newBYOVPCRoutingSL(ocm.DocumentationLink(product, ocm.DocumentationTopicAwsCustomVPC))
+	case failureClassProxy:
+		return &ocm.ServiceLog{
+			Severity:     "Major",
+			Summary:      "Installation blocked: Cluster-wide proxy misconfiguration",
+			Description:  fmt.Sprintf("Your cluster's installation is blocked because the cluster-wide proxy refused or failed the connections to these resources which are required for the installation: %s. Please verify that the proxy allows the connections, and that the additional trust bundle of the cluster contains the CA of the proxy if it intercepts TLS, and re-install the cluster.", targets),
+			InternalOnly: false,
+			ServiceName:  "SREManualAction",
+		}
+	}
+	return nil
+}
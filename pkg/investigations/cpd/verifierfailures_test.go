@@ -0,0 +1,85 @@
+package cpd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+func TestClassifyFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures []string
+		proxied  bool
+		want     failureClass
+	}{
+		{
+			name:     "blocked egress",
+			failures: []string{"https://quay.io:443 (Failed to connect to quay.io port 443 after 2000 ms: Connection timed out)", "tcp://inputs1.osdsecuritylogs.splunkcloud.com:9997 (Connection refused)"},
+			want:     failureClassEgressBlocked,
+		},
+		{
+			name:     "DNS failure",
+			failures: []string{"https://quay.io:443 (Could not resolve host: quay.io)"},
+			want:     failureClassDNS,
+		},
+		{
+			name:     "missing route",
+			failures: []string{"https://quay.io:443 (Failed to connect to quay.io port 443: No route to host)"},
+			want:     failureClassMissingRoute,
+		},
+		{
+			name:     "proxy refuses CONNECT",
+			failures: []string{"https://quay.io:443 (Received HTTP code 403 from proxy after CONNECT)"},
+			proxied:  true,
+			want:     failureClassProxy,
+		},
+		{
+			name:     "certificate error behind a proxy",
+			failures: []string{"https://quay.io:443 (SSL certificate problem: unable to get local issuer certificate)"},
+			proxied:  true,
+			want:     failureClassProxy,
+		},
+		{
+			name:     "certificate error without a proxy",
+			failures: []string{"https://quay.io:443 (SSL certificate problem: unable to get local issuer certificate)"},
+			want:     failureClassUnknown,
+		},
+		{
+			name:     "mixed classes",
+			failures: []string{"https://quay.io:443 (Could not resolve host: quay.io)", "https://api.openshift.com:443 (Connection timed out)"},
+			want:     failureClassUnknown,
+		},
+		{
+			name: "no failures",
+			want: failureClassUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyFailures(tt.failures, tt.proxied); got != tt.want {
+				t.Errorf("classifyFailures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInstallFailureSL(t *testing.T) {
+	failures := []string{"https://quay.io:443 (Could not resolve host: quay.io)", "https://api.openshift.com:443 (Could not resolve host: api.openshift.com)"}
+	sl := newInstallFailureSL(failureClassDNS, failures, ocm.ProductROSA)
+	if sl == nil {
+		t.Fatal("expected a service log for DNS failures")
+	}
+	if !strings.Contains(sl.Description, "https://quay.io:443, https://api.openshift.com:443.") {
+		t.Errorf("expected the service log to list the targets without the probe errors, got %q", sl.Description)
+	}
+	if sl := newInstallFailureSL(failureClassUnknown, failures, ocm.ProductROSA); sl != nil {
+		t.Errorf("expected no service log for unknown failures, got %+v", sl)
+	}
+	for class := range failureClassParams {
+		if newInstallFailureSL(class, failures, ocm.ProductROSA) == nil {
+			t.Errorf("expected a service log for automatable class %s", class)
+		}
+	}
+}
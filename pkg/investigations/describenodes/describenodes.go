@@ -34,7 +34,11 @@ type Investigation struct {
 // flags (MASTER, INFRA, WORKER) can be combined. If no params are provided,
 // all nodes are described.
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
-	ctx := context.Background()
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithCluster().WithK8sClient().Build()
@@ -223,3 +227,18 @@ func newNodeDescriber(k8sClient k8sclient.Client) (nodeDescriber, error) {
 func (i *Investigation) Name() string {
 	return "describenodes"
 }
+
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Describes the nodes of the cluster, including the pods scheduled on them",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+		NeedsCustomerData: true,
+		Params: []investigation.Param{
+			{Name: "NODES", Type: investigation.ParamString, Description: "Comma-separated names of the nodes to describe"},
+			{Name: "SELECTOR", Type: investigation.ParamString, Description: "Label selector of the nodes to describe, ignored if NODES is set"},
+			{Name: "MASTER", Type: investigation.ParamBool, Default: "false", Description: "Describe the master nodes"},
+			{Name: "INFRA", Type: investigation.ParamBool, Default: "false", Description: "Describe the infra nodes"},
+			{Name: "WORKER", Type: investigation.ParamBool, Default: "false", Description: "Describe the worker nodes"},
+		},
+	}
+}
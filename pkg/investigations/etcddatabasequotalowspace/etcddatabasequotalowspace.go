@@ -13,22 +13,29 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
-	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 )
 
 const (
 	etcdNamespace        = "openshift-etcd"
 	etcdctlInitContainer = "reset-member"
+	// snapshotCleanupTimeout bounds the removal of the snapshot, which isn't cancelled with the investigation
+	snapshotCleanupTimeout = 2 * time.Minute
 )
 
-type Investigation struct{}
+type Investigation struct {
+	// CleanupPolicy lists the known offenders the remediation mode may prune, none if nil
+	CleanupPolicy *config.EtcdCleanupPolicy
+}
 
 // SnapshotResult contains information about the etcd snapshot that was taken
 type SnapshotResult struct {
@@ -40,7 +47,11 @@ type SnapshotResult struct {
 }
 
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
-	ctx := context.TODO()
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the snapshot, the analysis and the requests to the cluster with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.
@@ -113,7 +124,12 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 
 	logging.Infof("etcd snapshot taken successfully from pod %s on node %s", snapshotResult.PodName, snapshotResult.NodeName)
 
-	defer handleSnapshotCleanup(ctx, r.K8sClient, r.Notes, snapshotResult)
+	// The snapshot is removed from the node even once ctx is done, e.g. after the investigation timed out
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), snapshotCleanupTimeout)
+		defer cancel()
+		handleSnapshotCleanup(cleanupCtx, r.K8sClient, r.Notes, snapshotResult)
+	}()
 
 	timestamp := extractTimestampFromPath(snapshotResult.SnapshotPath)
 
@@ -179,23 +195,30 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	}
 
 	formattedResults := formatAnalysisResults(analysisResult)
+	record := compareWithPreviousAnalysis(ctx, r.K8sClient, r.BpClient, r.Notes, r.Cluster.ExternalID(), snapshotResult, analysisResult)
+
+	var remediation types.Action
+	if r.Params[remediateParam] == "true" {
+		remediation = i.planClassicRemediation(ctx, r.K8sClient, r.Notes, snapshotResult.PodName)
+	}
 
 	logging.Info("etcd snapshot analysis completed successfully")
 
 	// Create backplane report action
 	backplaneReportAction := &executor.BackplaneReportAction{
 		ClusterID: r.Cluster.ExternalID(),
-		Summary:   "CAD Investigation: Analysis of etcd storage utilization",
-		Data:      formattedResults,
+		Summary:   analysisReportSummary,
+		Data:      formatAnalysisReport(formattedResults, record),
 	}
 
 	metrics.Inc(metrics.EtcdDatabaseAnalysis, i.Name(), "success", "completed")
 
-	result.Actions = append(
-		executor.NoteAndReportFrom(r.Notes, r.Cluster.ID(), i.Name()),
-		backplaneReportAction,
-	)
-	if isWarningAlert(r.PdClient) {
+	if remediation != nil {
+		result.Actions = append(result.Actions, remediation)
+	}
+	result.Actions = append(result.Actions, executor.NoteAndReportFrom(r.Notes, r.Cluster.ID(), i.Name())...)
+	result.Actions = append(result.Actions, backplaneReportAction)
+	if isWarningAlert(r.Incident) {
 		result.Actions = append(result.Actions, executor.Silence("etcd warning alert - investigation and analysis complete, see report for details"))
 	} else {
 		result.Actions = append(result.Actions, executor.Escalate("etcd critical alert - analysis complete, see report for details"))
@@ -222,6 +245,10 @@ func (i *Investigation) runHCPEtcdAnalysis(ctx context.Context, rb investigation
 		return result, err
 	}
 
+	if r.Params[remediateParam] == "true" {
+		r.Notes.AppendWarning("Remediation mode is not supported on HCP clusters, etcd is not defragmented or pruned")
+	}
+
 	etcdPod, err := getEtcdPod(ctx, r.ManagementK8sClient, r.HCPNamespace)
 	if err != nil {
 		if investigation.IsInfrastructureError(err) {
@@ -315,7 +342,7 @@ func (i *Investigation) runHCPEtcdAnalysis(ctx context.Context, rb investigation
 	metrics.Inc(metrics.EtcdDatabaseAnalysis, i.Name(), "success", "completed")
 
 	result.Actions = executor.NoteAndReportFrom(r.Notes, r.Cluster.ID(), i.Name())
-	if isWarningAlert(r.PdClient) {
+	if isWarningAlert(r.Incident) {
 		result.Actions = append(result.Actions, executor.Silence("HCP etcd warning alert - investigation and analysis complete, see report for details"))
 	} else {
 		result.Actions = append(result.Actions, executor.Escalate("HCP etcd critical alert - analysis complete, see dynatrace logs for details"))
@@ -327,14 +354,93 @@ func (i *Investigation) Name() string {
 	return "etcddatabasequotalowspace"
 }
 
-// isWarningAlert checks if the PagerDuty incident title indicates a warning-severity alert.
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Takes an etcd snapshot and analyzes the database for etcd quota issues",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s, investigation.ResourceManagementCluster},
+		Params: []investigation.Param{
+			{Name: remediateParam, Type: investigation.ParamBool, Default: "false", Description: "Defragment fragmented etcd members and prune the known offenders of the etcd_cleanup config on classic clusters"},
+		},
+	}
+}
+
+// planClassicRemediation plans the remediation of the etcd cluster of a classic cluster through etcdctl in the
+// etcd pod, and returns the action executing it, or nil if there is nothing to do.
+// Failures are only noted, as the analysis results are reported regardless.
+func (i *Investigation) planClassicRemediation(ctx context.Context, k8sClient k8sclient.Client, notes *notewriter.NoteWriter, podName string) types.Action {
+	pod := &corev1.Pod{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: etcdNamespace, Name: podName}, pod); err != nil {
+		notes.AppendWarning("Failed to get etcd pod %s to plan the remediation: %v", podName, err)
+		return nil
+	}
+	restConfig, err := k8sclient.GetRestConfig(k8sClient)
+	if err != nil {
+		notes.AppendWarning("Failed to get REST config to plan the remediation: %v", err)
+		return nil
+	}
+
+	run := podEtcdctl(restConfig, pod)
+	plan, err := planRemediation(ctx, run, k8sClient, i.CleanupPolicy, quotaBackendBytes(pod), notes)
+	if err != nil {
+		notes.AppendWarning("Failed to plan the etcd remediation: %v", err)
+		logging.Errorf("failed to plan etcd remediation: %v", err)
+		return nil
+	}
+	if plan.empty() {
+		return nil
+	}
+	notes.AppendSuccess("Planned etcd remediation:\n%s", plan)
+	return remediationAction(ctx, plan, run, k8sClient)
+}
+
+// compareWithPreviousAnalysis notes the growth of etcd since the previous analysis of the cluster, and returns
+// the record of this analysis for its report. Failures are only noted, as the analysis results are reported regardless.
+func compareWithPreviousAnalysis(ctx context.Context, k8sClient k8sclient.Client, bpClient backplane.Client, notes *notewriter.NoteWriter,
+	reportClusterID string, snapshotResult *SnapshotResult, analysisResult *AnalysisResult,
+) analysisRecord {
+	quota := int64(defaultQuotaBackendBytes)
+	var run etcdctl
+	pod := &corev1.Pod{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: snapshotResult.Namespace, Name: snapshotResult.PodName}, pod); err != nil {
+		logging.Warnf("failed to get etcd pod %s to read the quota, assuming the default: %v", snapshotResult.PodName, err)
+	} else {
+		quota = quotaBackendBytes(pod)
+		if restConfig, err := k8sclient.GetRestConfig(k8sClient); err != nil {
+			logging.Warnf("failed to get REST config to read the etcd database size: %v", err)
+		} else {
+			run = podEtcdctl(restConfig, pod)
+		}
+	}
+	record := newAnalysisRecord(analysisResult, databaseSize(ctx, run, snapshotResult.SnapshotSize), quota, time.Now())
+
+	previous, err := previousAnalysis(ctx, bpClient, reportClusterID)
+	if err != nil {
+		notes.AppendWarning("Failed to get the previous etcd analysis, growth is not reported: %v", err)
+		logging.Errorf("failed to get previous etcd analysis: %v", err)
+		return record
+	}
+	if previous == nil {
+		notes.AppendSuccess("No previous etcd analysis found, growth is reported from the next analysis on")
+		return record
+	}
+
+	trend := compareAnalyses(*previous, record)
+	if trend.QuotaReached || (trend.TimeToQuota > 0 && trend.TimeToQuota < trendQuotaWarning) {
+		notes.AppendWarning("%s", formatAnalysisTrend(trend))
+	} else {
+		notes.AppendSuccess("%s", formatAnalysisTrend(trend))
+	}
+	return record
+}
+
+// isWarningAlert checks if the incident title indicates a warning-severity alert.
 // PD event severity field. Warning alerts are silenced after investigation; critical alerts
 // are still escalated to SRE.
-func isWarningAlert(pdClient pagerduty.Client) bool {
-	if pdClient == nil {
+func isWarningAlert(backend incident.Backend) bool {
+	if backend == nil {
 		return false
 	}
-	return strings.Contains(strings.ToUpper(pdClient.GetTitle()), "WARNING")
+	return strings.Contains(strings.ToUpper(backend.GetTitle()), "WARNING")
 }
 
 // isHCPCluster checks if the cluster is a Hosted Control Plane (HCP) cluster
@@ -301,7 +301,7 @@ func TestRunHCPEtcdAnalysis_WarningAlertSilenced(t *testing.T) {
 			ManagementClusterName:         "test-management-cluster",
 			DynatraceManagementClusterURL: "https://hrm15629.apps.dynatrace.com/",
 			Notes:                         notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger),
-			PdClient:                      mockPD,
+			Incident:                      mockPD,
 		},
 	}
 
@@ -338,7 +338,7 @@ func TestRunHCPEtcdAnalysis_CriticalAlertEscalated(t *testing.T) {
 			ManagementClusterName:         "test-management-cluster",
 			DynatraceManagementClusterURL: "https://hrm15629.apps.dynatrace.com/",
 			Notes:                         notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger),
-			PdClient:                      mockPD,
+			Incident:                      mockPD,
 		},
 	}
 
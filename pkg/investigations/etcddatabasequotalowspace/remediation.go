@@ -0,0 +1,442 @@
+package etcddatabasequotalowspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/metrics"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+)
+
+const (
+	// remediateParam enables the remediation mode of the investigation
+	remediateParam = "REMEDIATE"
+
+	// defragMinFragmentation and defragMinReclaimBytes are the thresholds from which a member is defragmented,
+	// they follow the defrag controller of the cluster-etcd-operator
+	defragMinFragmentation = 0.45
+	defragMinReclaimBytes  = 100 * 1024 * 1024
+
+	defragCommandTimeout = "--command-timeout=120s"
+	healthCheckAttempts  = 8
+	pruneListPageSize    = 500
+)
+
+// healthCheckInterval is the time between health checks while waiting for the etcd cluster to become healthy
+var healthCheckInterval = 15 * time.Second
+
+// etcdctl runs etcdctl with args in an etcd pod and returns its output
+type etcdctl func(ctx context.Context, args ...string) (string, error)
+
+// podEtcdctl runs etcdctl in the etcdctl container of pod
+func podEtcdctl(restConfig *rest.Config, pod *corev1.Pod) etcdctl {
+	return func(ctx context.Context, args ...string) (string, error) {
+		return k8sclient.ExecInPod(ctx, restConfig, pod, "etcdctl", append([]string{"etcdctl"}, args...))
+	}
+}
+
+// etcdMember is the status of an etcd member, as reported by etcdctl endpoint status
+type etcdMember struct {
+	Endpoint    string
+	ID          uint64
+	Leader      bool
+	DBSize      int64
+	DBSizeInUse int64
+}
+
+// Fragmentation returns the share of the database file that is not in use and can be reclaimed by defragmentation
+func (m etcdMember) Fragmentation() float64 {
+	if m.DBSize <= 0 {
+		return 0
+	}
+	return 1 - float64(m.DBSizeInUse)/float64(m.DBSize)
+}
+
+func (m etcdMember) String() string {
+	role := "follower"
+	if m.Leader {
+		role = "leader"
+	}
+	return fmt.Sprintf("%s (%s): %.2f MB, %.2f MB in use, %.0f%% fragmented",
+		m.Endpoint, role, float64(m.DBSize)/(1024*1024), float64(m.DBSizeInUse)/(1024*1024), m.Fragmentation()*100)
+}
+
+// endpointStatus is an entry of the JSON output of etcdctl endpoint status
+type endpointStatus struct {
+	Endpoint string `json:"Endpoint"`
+	Status   struct {
+		Header struct {
+			MemberID uint64 `json:"member_id"`
+		} `json:"header"`
+		Leader      uint64 `json:"leader"`
+		DBSize      int64  `json:"dbSize"`
+		DBSizeInUse int64  `json:"dbSizeInUse"`
+	} `json:"Status"`
+}
+
+// endpointHealth is an entry of the JSON output of etcdctl endpoint health
+type endpointHealth struct {
+	Endpoint string `json:"endpoint"`
+	Health   bool   `json:"health"`
+	Error    string `json:"error"`
+}
+
+// parseEndpointStatus parses the JSON output of etcdctl endpoint status
+func parseEndpointStatus(output string) ([]etcdMember, error) {
+	var statuses []endpointStatus
+	if err := json.Unmarshal([]byte(output), &statuses); err != nil {
+		return nil, fmt.Errorf("failed to parse etcd endpoint status: %w", err)
+	}
+	members := make([]etcdMember, 0, len(statuses))
+	for _, s := range statuses {
+		members = append(members, etcdMember{
+			Endpoint:    s.Endpoint,
+			ID:          s.Status.Header.MemberID,
+			Leader:      s.Status.Header.MemberID == s.Status.Leader,
+			DBSize:      s.Status.DBSize,
+			DBSizeInUse: s.Status.DBSizeInUse,
+		})
+	}
+	return members, nil
+}
+
+// getMembers returns the status of all members of the etcd cluster
+func getMembers(ctx context.Context, run etcdctl) ([]etcdMember, error) {
+	output, err := run(ctx, "endpoint", "status", "--cluster", "-w", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get etcd endpoint status: %w", err)
+	}
+	return parseEndpointStatus(output)
+}
+
+// checkHealth returns an error naming the unhealthy members, if any.
+// etcdctl exits with an error if a member is unhealthy, so its output is parsed regardless.
+func checkHealth(ctx context.Context, run etcdctl) error {
+	output, runErr := run(ctx, "endpoint", "health", "--cluster", "-w", "json")
+	var health []endpointHealth
+	if err := json.Unmarshal([]byte(output), &health); err != nil || len(health) == 0 {
+		if runErr != nil {
+			return fmt.Errorf("failed to check etcd health: %w", runErr)
+		}
+		return fmt.Errorf("failed to parse etcd endpoint health: %w", err)
+	}
+	var unhealthy []string
+	for _, h := range health {
+		if !h.Health {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", h.Endpoint, h.Error))
+		}
+	}
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("unhealthy etcd members: %s", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
+
+// waitForHealthy checks the health of the etcd cluster until all members are healthy or the attempts are used up
+func waitForHealthy(ctx context.Context, run etcdctl) error {
+	var err error
+	for attempt := 0; attempt < healthCheckAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(healthCheckInterval):
+			}
+		}
+		if err = checkHealth(ctx, run); err == nil {
+			return nil
+		}
+		logging.Infof("waiting for etcd to become healthy: %v", err)
+	}
+	return err
+}
+
+// planDefrag returns the members worth defragmenting, followers first and the leader last,
+// so the cluster only has to elect a new leader if it becomes unavailable while defragmenting the last member.
+func planDefrag(members []etcdMember) []etcdMember {
+	var planned []etcdMember
+	for _, m := range members {
+		if m.Fragmentation() >= defragMinFragmentation && m.DBSize-m.DBSizeInUse >= defragMinReclaimBytes {
+			planned = append(planned, m)
+		}
+	}
+	slices.SortStableFunc(planned, func(a, b etcdMember) int {
+		switch {
+		case a.Leader == b.Leader:
+			return 0
+		case a.Leader:
+			return 1
+		default:
+			return -1
+		}
+	})
+	return planned
+}
+
+// defragMembers defragments the members one at a time. It checks that all members are healthy before each
+// member and after the last one, and stops at the first unhealthy cluster or failed defragmentation.
+// Once done, it disarms the NOSPACE alarm if every member is below quotaBytes again.
+func defragMembers(ctx context.Context, run etcdctl, members []etcdMember, quotaBytes int64, notes *notewriter.NoteWriter) error {
+	if len(members) == 0 {
+		return nil
+	}
+	for _, m := range members {
+		if err := waitForHealthy(ctx, run); err != nil {
+			metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "defrag", "unhealthy")
+			return fmt.Errorf("stopped before defragmenting %s: %w", m.Endpoint, err)
+		}
+
+		logging.Infof("defragmenting etcd member %s", m)
+		if _, err := run(ctx, "defrag", "--endpoints="+m.Endpoint, defragCommandTimeout); err != nil {
+			metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "defrag", "failure")
+			return fmt.Errorf("failed to defragment %s: %w", m.Endpoint, err)
+		}
+		metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "defrag", "success")
+
+		size := "unknown"
+		if output, err := run(ctx, "endpoint", "status", "--endpoints="+m.Endpoint, "-w", "json"); err == nil {
+			if after, err := parseEndpointStatus(output); err == nil && len(after) == 1 {
+				size = fmt.Sprintf("%.2f MB", float64(after[0].DBSize)/(1024*1024))
+			}
+		}
+		notes.AppendAutomation("Defragmented etcd member %s: %.2f MB -> %s", m.Endpoint, float64(m.DBSize)/(1024*1024), size)
+	}
+
+	if err := waitForHealthy(ctx, run); err != nil {
+		metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "defrag", "unhealthy")
+		return fmt.Errorf("etcd is unhealthy after defragmentation: %w", err)
+	}
+	return disarmNoSpaceAlarm(ctx, run, quotaBytes, notes)
+}
+
+// disarmNoSpaceAlarm disarms the NOSPACE alarm etcd raises when a database exceeds the quota, as etcd keeps
+// rejecting writes until it is disarmed. The alarm is only disarmed once the database of every member is below
+// the quota again, and only if no other alarm is raised, as etcdctl alarm disarm disarms all alarms.
+func disarmNoSpaceAlarm(ctx context.Context, run etcdctl, quotaBytes int64, notes *notewriter.NoteWriter) error {
+	output, err := run(ctx, "alarm", "list")
+	if err != nil {
+		return fmt.Errorf("failed to list etcd alarms: %w", err)
+	}
+	noSpace := false
+	var others []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		// Alarms are listed as "memberID:<id> alarm:<type>"
+		_, alarm, found := strings.Cut(line, "alarm:")
+		if !found {
+			continue
+		}
+		if alarm = strings.TrimSpace(alarm); alarm == "NOSPACE" {
+			noSpace = true
+		} else {
+			others = append(others, alarm)
+		}
+	}
+	if !noSpace {
+		return nil
+	}
+	if len(others) > 0 {
+		notes.AppendWarning("etcd NOSPACE alarm is not disarmed, as other alarms are raised as well: %s", strings.Join(others, ", "))
+		return nil
+	}
+
+	members, err := getMembers(ctx, run)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.DBSize >= quotaBytes {
+			notes.AppendWarning("etcd NOSPACE alarm is not disarmed, member %s is still at %.2f MB of the %.2f MB quota",
+				m.Endpoint, bytesToMB(m.DBSize), bytesToMB(quotaBytes))
+			return nil
+		}
+	}
+
+	if _, err := run(ctx, "alarm", "disarm"); err != nil {
+		metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "disarm", "failure")
+		return fmt.Errorf("failed to disarm the etcd NOSPACE alarm: %w", err)
+	}
+	metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "disarm", "success")
+	notes.AppendAutomation("Disarmed the etcd NOSPACE alarm, every member is below the %.2f MB quota", bytesToMB(quotaBytes))
+	return nil
+}
+
+// pruneCandidates are the objects of a known offender that can be pruned
+type pruneCandidates struct {
+	Offender config.EtcdOffender
+	Objects  []*unstructured.Unstructured
+}
+
+// String summarizes the candidates by namespace for notes
+func (c pruneCandidates) String() string {
+	byNamespace := map[string]int{}
+	for _, obj := range c.Objects {
+		byNamespace[obj.GetNamespace()]++
+	}
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns := range byNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	counts := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		counts = append(counts, fmt.Sprintf("%s: %d", ns, byNamespace[ns]))
+	}
+	return fmt.Sprintf("%d %s objects older than %s (offender %s) - %s",
+		len(c.Objects), c.Offender.Kind, c.Offender.GetMinAge(), c.Offender.Name, strings.Join(counts, ", "))
+}
+
+// findPruneCandidates lists the objects of the known offenders that are old enough to be pruned,
+// up to the maximum number of deletions of each offender
+func findPruneCandidates(ctx context.Context, k8sClient k8sclient.Client, policy *config.EtcdCleanupPolicy, now time.Time) ([]pruneCandidates, error) {
+	if policy == nil {
+		return nil, nil
+	}
+
+	var candidates []pruneCandidates
+	for _, offender := range policy.Offenders {
+		found := pruneCandidates{Offender: offender}
+		gvk := schema.GroupVersionKind{Group: offender.Group, Version: offender.Version, Kind: offender.Kind + "List"}
+		opts := []client.ListOption{client.Limit(pruneListPageSize)}
+		for {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk)
+			if err := k8sClient.List(ctx, list, opts...); err != nil {
+				return nil, fmt.Errorf("failed to list %s of offender %s: %w", offender.Kind, offender.Name, err)
+			}
+			for i := range list.Items {
+				obj := &list.Items[i]
+				if len(found.Objects) >= offender.GetMaxDeletions() {
+					break
+				}
+				if now.Sub(obj.GetCreationTimestamp().Time) >= offender.GetMinAge() && offender.MatchesNamespace(obj.GetNamespace()) {
+					found.Objects = append(found.Objects, obj)
+				}
+			}
+			if list.GetContinue() == "" || len(found.Objects) >= offender.GetMaxDeletions() {
+				break
+			}
+			opts = []client.ListOption{client.Limit(pruneListPageSize), client.Continue(list.GetContinue())}
+		}
+		if len(found.Objects) > 0 {
+			candidates = append(candidates, found)
+		}
+	}
+	return candidates, nil
+}
+
+// pruneObjects deletes the candidates. Every deletion is logged as the audit trail of the cleanup,
+// objects that were replaced since they were listed are left alone.
+func pruneObjects(ctx context.Context, k8sClient k8sclient.Client, candidates []pruneCandidates, notes *notewriter.NoteWriter) error {
+	for _, c := range candidates {
+		pruned := 0
+		for _, obj := range c.Objects {
+			uid := obj.GetUID()
+			err := k8sClient.Delete(ctx, obj, client.Preconditions{UID: &uid})
+			if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				continue
+			}
+			if err != nil {
+				metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "prune", "failure")
+				notes.AppendAutomation("Pruned %d of %s before failing", pruned, c)
+				return fmt.Errorf("failed to prune %s %s/%s: %w", c.Offender.Kind, obj.GetNamespace(), obj.GetName(), err)
+			}
+			logging.Infof("pruned %s %s/%s created %s (offender %s)",
+				c.Offender.Kind, obj.GetNamespace(), obj.GetName(), obj.GetCreationTimestamp().UTC().Format(time.RFC3339), c.Offender.Name)
+			pruned++
+		}
+		metrics.Inc(metrics.EtcdRemediation, "etcddatabasequotalowspace", "prune", "success")
+		notes.AppendAutomation("Pruned %d of %s", pruned, c)
+	}
+	return nil
+}
+
+// remediationPlan is what the remediation mode found to do on a cluster
+type remediationPlan struct {
+	Defrag []etcdMember
+	Prune  []pruneCandidates
+	// QuotaBytes is the etcd quota, the NOSPACE alarm is disarmed once every member is below it
+	QuotaBytes int64
+}
+
+func (p remediationPlan) empty() bool {
+	return len(p.Defrag) == 0 && len(p.Prune) == 0
+}
+
+func (p remediationPlan) String() string {
+	var lines []string
+	for _, m := range p.Defrag {
+		lines = append(lines, "Defragment "+m.String())
+	}
+	for _, c := range p.Prune {
+		lines = append(lines, "Prune "+c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// planRemediation checks the fragmentation of the members and looks for objects of the known offenders,
+// and adds its findings to the notes. It doesn't change the cluster.
+func planRemediation(ctx context.Context, run etcdctl, k8sClient k8sclient.Client, policy *config.EtcdCleanupPolicy, quotaBytes int64, notes *notewriter.NoteWriter) (remediationPlan, error) {
+	plan := remediationPlan{QuotaBytes: quotaBytes}
+
+	members, err := getMembers(ctx, run)
+	if err != nil {
+		return plan, err
+	}
+	for _, m := range members {
+		notes.AppendSuccess("etcd member %s", m)
+	}
+	plan.Defrag = planDefrag(members)
+	if len(plan.Defrag) == 0 {
+		notes.AppendSuccess("No etcd member is fragmented enough to be defragmented (%.0f%% and %d MB)", defragMinFragmentation*100, defragMinReclaimBytes/(1024*1024))
+	}
+
+	plan.Prune, err = findPruneCandidates(ctx, k8sClient, policy, time.Now())
+	if err != nil {
+		return plan, err
+	}
+	if policy == nil || len(policy.Offenders) == 0 {
+		notes.AppendSuccess("No known offenders are configured for pruning")
+	} else if len(plan.Prune) == 0 {
+		notes.AppendSuccess("No objects of the known offenders are old enough to be pruned")
+	}
+	return plan, nil
+}
+
+// remediationAction executes the plan: it defragments the members one at a time first, and then prunes the known
+// offenders. Deleting objects doesn't shrink the database: the space of the deleted revisions only becomes free once
+// the API server compacted etcd, and is only returned by a defragmentation. Defragmenting first returns the space
+// that is already free, which brings the members below the quota so the NOSPACE alarm can be disarmed right away.
+// The space of the pruned objects is reclaimed by a later defragmentation.
+// The action runs after the investigation returned, so it is cancelled at the deadline of ctx, if any, instead.
+func remediationAction(ctx context.Context, plan remediationPlan, run etcdctl, k8sClient k8sclient.Client) types.Action {
+	deadline, hasDeadline := ctx.Deadline()
+	return executor.Remediation("Defragment etcd and prune known offenders", plan.String(),
+		func(ctx context.Context, notes *notewriter.NoteWriter) error {
+			if hasDeadline {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, deadline)
+				defer cancel()
+			}
+			if err := defragMembers(ctx, run, plan.Defrag, plan.QuotaBytes, notes); err != nil {
+				return err
+			}
+			return pruneObjects(ctx, k8sClient, plan.Prune, notes)
+		})
+}
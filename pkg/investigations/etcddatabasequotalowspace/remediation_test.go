@@ -0,0 +1,356 @@
+package etcddatabasequotalowspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+)
+
+const (
+	testStatusOutput = `[
+{"Endpoint":"https://10.0.0.1:2379","Status":{"header":{"member_id":1},"leader":2,"dbSize":2147483648,"dbSizeInUse":536870912}},
+{"Endpoint":"https://10.0.0.2:2379","Status":{"header":{"member_id":2},"leader":2,"dbSize":2147483648,"dbSizeInUse":536870912}},
+{"Endpoint":"https://10.0.0.3:2379","Status":{"header":{"member_id":3},"leader":2,"dbSize":2147483648,"dbSizeInUse":2040109465}}
+]`
+	testHealthyOutput   = `[{"endpoint":"https://10.0.0.1:2379","health":true},{"endpoint":"https://10.0.0.2:2379","health":true}]`
+	testUnhealthyOutput = `[{"endpoint":"https://10.0.0.1:2379","health":true},{"endpoint":"https://10.0.0.2:2379","health":false,"error":"context deadline exceeded"}]`
+)
+
+// fakeEtcdctl answers etcdctl commands from canned outputs and records the commands it ran
+type fakeEtcdctl struct {
+	health   []string // Outputs of the health checks in order, the last one is repeated
+	defragFn func(endpoint string) error
+	alarms   string // Output of etcdctl alarm list
+	commands []string
+}
+
+func (f *fakeEtcdctl) run(_ context.Context, args ...string) (string, error) {
+	command := strings.Join(args, " ")
+	f.commands = append(f.commands, command)
+	switch {
+	case strings.HasPrefix(command, "endpoint health"):
+		output := f.health[0]
+		if len(f.health) > 1 {
+			f.health = f.health[1:]
+		}
+		if strings.Contains(output, `"health":false`) {
+			return output, errors.New("command terminated with exit code 1")
+		}
+		return output, nil
+	case strings.HasPrefix(command, "endpoint status --cluster"):
+		return testStatusOutput, nil
+	case strings.HasPrefix(command, "endpoint status --endpoints="):
+		endpoint := strings.TrimPrefix(args[2], "--endpoints=")
+		return fmt.Sprintf(`[{"Endpoint":%q,"Status":{"header":{"member_id":1},"leader":2,"dbSize":536870912,"dbSizeInUse":536870912}}]`, endpoint), nil
+	case command == "alarm list":
+		return f.alarms, nil
+	case command == "alarm disarm":
+		return f.alarms, nil
+	case args[0] == "defrag":
+		if f.defragFn != nil {
+			return "", f.defragFn(strings.TrimPrefix(args[1], "--endpoints="))
+		}
+		return "Finished defragmenting etcd member", nil
+	}
+	return "", fmt.Errorf("unexpected command %q", command)
+}
+
+func (f *fakeEtcdctl) ran(command string) bool {
+	for _, c := range f.commands {
+		if c == command {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeEtcdctl) defragged() []string {
+	var endpoints []string
+	for _, c := range f.commands {
+		if strings.HasPrefix(c, "defrag ") {
+			endpoints = append(endpoints, strings.TrimPrefix(strings.Fields(c)[1], "--endpoints="))
+		}
+	}
+	return endpoints
+}
+
+func TestParseEndpointStatus(t *testing.T) {
+	members, err := parseEndpointStatus(testStatusOutput)
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+	assert.Equal(t, "https://10.0.0.1:2379", members[0].Endpoint)
+	assert.False(t, members[0].Leader)
+	assert.True(t, members[1].Leader)
+	assert.InDelta(t, 0.75, members[0].Fragmentation(), 0.001)
+
+	_, err = parseEndpointStatus("Error: context deadline exceeded")
+	assert.Error(t, err)
+}
+
+func TestPlanDefrag(t *testing.T) {
+	members, err := parseEndpointStatus(testStatusOutput)
+	assert.NoError(t, err)
+
+	planned := planDefrag(members)
+	assert.Len(t, planned, 2, "The member with little fragmentation should not be defragmented")
+	assert.Equal(t, "https://10.0.0.1:2379", planned[0].Endpoint)
+	assert.Equal(t, "https://10.0.0.2:2379", planned[1].Endpoint, "The leader should be defragmented last")
+
+	small := []etcdMember{{Endpoint: "https://10.0.0.1:2379", DBSize: 100 * 1024 * 1024, DBSizeInUse: 10 * 1024 * 1024}}
+	assert.Empty(t, planDefrag(small), "Members with less than the minimum reclaimable space should not be defragmented")
+}
+
+func TestCheckHealth(t *testing.T) {
+	healthy := &fakeEtcdctl{health: []string{testHealthyOutput}}
+	assert.NoError(t, checkHealth(context.Background(), healthy.run))
+
+	unhealthy := &fakeEtcdctl{health: []string{testUnhealthyOutput}}
+	err := checkHealth(context.Background(), unhealthy.run)
+	assert.ErrorContains(t, err, "https://10.0.0.2:2379 (context deadline exceeded)", "The output should be parsed although etcdctl failed")
+}
+
+func TestDefragMembers(t *testing.T) {
+	healthCheckInterval = 0
+	defer func() { healthCheckInterval = 15 * time.Second }()
+
+	members, err := parseEndpointStatus(testStatusOutput)
+	assert.NoError(t, err)
+	planned := planDefrag(members)
+
+	t.Run("defragments the followers before the leader", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput}}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		assert.NoError(t, defragMembers(context.Background(), run.run, planned, defaultQuotaBackendBytes, notes))
+		assert.Equal(t, []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379"}, run.defragged())
+		assert.True(t, run.ran("alarm list"), "The alarms should be checked after defragmenting")
+		assert.False(t, run.ran("alarm disarm"), "Nothing should be disarmed without a NOSPACE alarm")
+		assert.Contains(t, notes.String(), "Defragmented etcd member https://10.0.0.1:2379: 2048.00 MB -> 512.00 MB")
+	})
+
+	t.Run("waits for the cluster to recover", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput, testUnhealthyOutput, testHealthyOutput}}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		assert.NoError(t, defragMembers(context.Background(), run.run, planned, defaultQuotaBackendBytes, notes))
+		assert.Len(t, run.defragged(), 2)
+	})
+
+	t.Run("stops when the cluster stays unhealthy", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput, testUnhealthyOutput}}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		err := defragMembers(context.Background(), run.run, planned, defaultQuotaBackendBytes, notes)
+		assert.ErrorContains(t, err, "stopped before defragmenting https://10.0.0.2:2379")
+		assert.Equal(t, []string{"https://10.0.0.1:2379"}, run.defragged(), "The leader should not be defragmented")
+	})
+
+	t.Run("disarms the NOSPACE alarm below the quota", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput}, alarms: "memberID:1 alarm:NOSPACE\nmemberID:2 alarm:NOSPACE\n"}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		assert.NoError(t, defragMembers(context.Background(), run.run, planned, defaultQuotaBackendBytes, notes))
+		assert.True(t, run.ran("alarm disarm"))
+		assert.Contains(t, notes.String(), "Disarmed the etcd NOSPACE alarm")
+	})
+
+	t.Run("keeps the NOSPACE alarm above the quota", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput}, alarms: "memberID:1 alarm:NOSPACE\n"}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		assert.NoError(t, defragMembers(context.Background(), run.run, planned, 2*1024*1024*1024, notes))
+		assert.False(t, run.ran("alarm disarm"), "The alarm should not be disarmed while a member is at the quota")
+		assert.Contains(t, notes.String(), "member https://10.0.0.1:2379 is still at 2048.00 MB of the 2048.00 MB quota")
+	})
+
+	t.Run("keeps the alarms if another alarm is raised", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput}, alarms: "memberID:1 alarm:NOSPACE\nmemberID:2 alarm:CORRUPT\n"}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		assert.NoError(t, defragMembers(context.Background(), run.run, planned, defaultQuotaBackendBytes, notes))
+		assert.False(t, run.ran("alarm disarm"), "Disarming would disarm the CORRUPT alarm as well")
+		assert.Contains(t, notes.String(), "other alarms are raised as well: CORRUPT")
+	})
+
+	t.Run("stops at a failed defragmentation", func(t *testing.T) {
+		run := &fakeEtcdctl{health: []string{testHealthyOutput}, defragFn: func(string) error { return errors.New("timeout") }}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		err := defragMembers(context.Background(), run.run, planned, defaultQuotaBackendBytes, notes)
+		assert.ErrorContains(t, err, "failed to defragment https://10.0.0.1:2379")
+		assert.Len(t, run.defragged(), 1)
+	})
+}
+
+func testEvent(namespace, name string, age time.Duration, now time.Time) *corev1.Event {
+	return &corev1.Event{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         namespace,
+		Name:              name,
+		UID:               k8stypes.UID("uid-" + namespace + "-" + name),
+		CreationTimestamp: metav1.NewTime(now.Add(-age)),
+	}}
+}
+
+func TestPruneKnownOffenders(t *testing.T) {
+	now := time.Now()
+	fakeK8s := fake.NewClientBuilder().WithObjects(
+		testEvent("ci-builds", "old-1", 48*time.Hour, now),
+		testEvent("ci-builds", "old-2", 48*time.Hour, now),
+		testEvent("ci-builds", "old-3", 48*time.Hour, now),
+		testEvent("ci-builds", "new", time.Hour, now),
+		testEvent("openshift-etcd", "old", 48*time.Hour, now),
+	).Build()
+
+	policy, err := config.NewEtcdCleanupPolicy(config.EtcdOffender{
+		Name:          "stale-ci-events",
+		Version:       "v1",
+		Kind:          "Event",
+		Namespaces:    []string{"^ci-"},
+		MinAgeMinutes: 24 * 60,
+		MaxDeletions:  2,
+	})
+	assert.NoError(t, err)
+
+	candidates, err := findPruneCandidates(context.Background(), fakeK8s, policy, now)
+	assert.NoError(t, err)
+	assert.Len(t, candidates, 1)
+	assert.Len(t, candidates[0].Objects, 2, "The candidates should be capped at the maximum deletions")
+	for _, obj := range candidates[0].Objects {
+		assert.Equal(t, "ci-builds", obj.GetNamespace())
+		assert.True(t, strings.HasPrefix(obj.GetName(), "old-"))
+	}
+	assert.Contains(t, candidates[0].String(), "2 Event objects older than 24h0m0s (offender stale-ci-events) - ci-builds: 2")
+
+	notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+	assert.NoError(t, pruneObjects(context.Background(), fakeK8s, candidates, notes))
+	assert.Contains(t, notes.String(), "Pruned 2 of 2 Event objects")
+
+	remaining := &corev1.EventList{}
+	assert.NoError(t, fakeK8s.List(context.Background(), remaining))
+	assert.Len(t, remaining.Items, 3)
+
+	kept := &corev1.EventList{}
+	assert.NoError(t, fakeK8s.List(context.Background(), kept, client.InNamespace("openshift-etcd")))
+	assert.Len(t, kept.Items, 1, "Objects in namespaces that are not selected should not be pruned")
+
+	none, err := findPruneCandidates(context.Background(), fakeK8s, nil, now)
+	assert.NoError(t, err)
+	assert.Empty(t, none, "Nothing should be pruned without a policy")
+}
+
+func TestRemediationAction(t *testing.T) {
+	healthCheckInterval = 0
+	defer func() { healthCheckInterval = 15 * time.Second }()
+
+	now := time.Now()
+	fakeK8s := fake.NewClientBuilder().WithObjects(testEvent("ci-builds", "old", 48*time.Hour, now)).Build()
+	policy, err := config.NewEtcdCleanupPolicy(config.EtcdOffender{Name: "stale-events", Version: "v1", Kind: "Event", Namespaces: []string{"^ci-"}, MinAgeMinutes: 60})
+	assert.NoError(t, err)
+	run := &fakeEtcdctl{health: []string{testHealthyOutput}}
+	notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+	plan, err := planRemediation(context.Background(), run.run, fakeK8s, policy, defaultQuotaBackendBytes, notes)
+	assert.NoError(t, err)
+	assert.Len(t, plan.Defrag, 2)
+	assert.Len(t, plan.Prune, 1)
+	assert.Empty(t, run.defragged(), "Planning should not change the cluster")
+	assert.Contains(t, plan.String(), "Defragment https://10.0.0.1:2379")
+	assert.Contains(t, plan.String(), "Prune 1 Event objects")
+
+	action := remediationAction(context.Background(), plan, run.run, fakeK8s)
+	assert.NoError(t, action.Validate())
+}
+
+func TestRemediationActionDeadline(t *testing.T) {
+	healthCheckInterval = 0
+	defer func() { healthCheckInterval = 15 * time.Second }()
+
+	now := time.Now()
+	fakeK8s := fake.NewClientBuilder().WithObjects(testEvent("ci-builds", "old", 48*time.Hour, now)).Build()
+	etcd := &fakeEtcdctl{health: []string{testHealthyOutput}}
+	var deadlines []time.Time
+	run := func(ctx context.Context, args ...string) (string, error) {
+		deadline, _ := ctx.Deadline()
+		deadlines = append(deadlines, deadline)
+		return etcd.run(ctx, args...)
+	}
+	plan := remediationPlan{
+		Defrag:     []etcdMember{{Endpoint: "https://10.0.0.1:2379", DBSize: 600 * 1024 * 1024}},
+		QuotaBytes: defaultQuotaBackendBytes,
+	}
+
+	// The investigation's context is cancelled once it returned, before the action is executed
+	deadline := now.Add(time.Hour)
+	invCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	action := remediationAction(invCtx, plan, run, fakeK8s)
+	cancel()
+
+	notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+	err := action.(*executor.ClusterRemediationAction).Remediate(context.Background(), notes)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://10.0.0.1:2379"}, etcd.defragged())
+	assert.NotEmpty(t, deadlines)
+	for _, d := range deadlines {
+		assert.True(t, d.Equal(deadline), "etcdctl ran with deadline %v, want the investigation's %v", d, deadline)
+	}
+
+	t.Run("expired deadline stops the remediation", func(t *testing.T) {
+		etcd := &fakeEtcdctl{health: []string{testHealthyOutput}}
+		invCtx, cancel := context.WithDeadline(context.Background(), now.Add(-time.Minute))
+		defer cancel()
+		action := remediationAction(invCtx, plan, func(ctx context.Context, args ...string) (string, error) {
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+			return etcd.run(ctx, args...)
+		}, fakeK8s)
+
+		err := action.(*executor.ClusterRemediationAction).Remediate(context.Background(), notes)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, etcd.defragged())
+	})
+}
+
+// TestPrunableKindsAreGranted ensures the metadata.yaml grants listing and deleting every kind the
+// etcd_cleanup config accepts, so a valid offender can't fail on missing permissions.
+func TestPrunableKindsAreGranted(t *testing.T) {
+	metadata, err := invtesting.LoadMetadata("metadata.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	granted := func(verb, group, resource string) bool {
+		for _, rule := range metadata.RBAC.ClusterRoleRules {
+			if slices.Contains(rule.Verbs, verb) && slices.Contains(rule.APIGroups, group) && slices.Contains(rule.Resources, resource) {
+				return true
+			}
+		}
+		return false
+	}
+	for group, kinds := range config.EtcdPrunableKinds {
+		for _, kind := range kinds {
+			resource, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Group: group, Kind: kind})
+			for _, verb := range []string{"list", "delete"} {
+				assert.True(t, granted(verb, group, resource.Resource), "metadata.yaml doesn't grant %s %s in group %q", verb, resource.Resource, group)
+			}
+		}
+	}
+}
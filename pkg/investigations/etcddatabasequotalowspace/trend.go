@@ -0,0 +1,284 @@
+package etcddatabasequotalowspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+)
+
+const (
+	analysisReportSummary = "CAD Investigation: Analysis of etcd storage utilization"
+	// analysisDataTitle precedes the JSON record of the analysis in the report data
+	analysisDataTitle = "Analysis data (JSON)"
+	// analysisReportLookback is the number of recent reports of a cluster searched for the previous analysis
+	analysisReportLookback = 20
+	// defaultQuotaBackendBytes is the etcd quota of OpenShift if the etcd pod doesn't set one
+	defaultQuotaBackendBytes = 8 * 1024 * 1024 * 1024
+	// trendMaxDeltas is the number of namespaces and resource types listed in the trend
+	trendMaxDeltas = 10
+	// trendQuotaWarning is the projected time-to-quota below which the trend is noted as a warning
+	trendQuotaWarning = 7 * 24 * time.Hour
+)
+
+// analysisRecord is the part of an analysis that is kept in the backplane report of the run,
+// so the next run can compare its results against it
+type analysisRecord struct {
+	Timestamp     time.Time          `json:"timestamp"`
+	DBSizeBytes   int64              `json:"db_size_bytes"` // Physical size of the largest member database, which the quota applies to
+	QuotaBytes    int64              `json:"quota_bytes"`
+	Namespaces    map[string]float64 `json:"namespaces_mb"`
+	ResourceTypes map[string]float64 `json:"resource_types_mb"`
+}
+
+func newAnalysisRecord(result *AnalysisResult, dbSizeBytes, quotaBytes int64, now time.Time) analysisRecord {
+	record := analysisRecord{
+		Timestamp:     now.UTC(),
+		DBSizeBytes:   dbSizeBytes,
+		QuotaBytes:    quotaBytes,
+		Namespaces:    make(map[string]float64, len(result.TopNamespaces)),
+		ResourceTypes: make(map[string]float64, len(result.TopResourceTypes)),
+	}
+	for _, ns := range result.TopNamespaces {
+		record.Namespaces[ns.Namespace] = ns.SizeMB
+	}
+	for _, rt := range result.TopResourceTypes {
+		record.ResourceTypes[rt.ResourceType] = rt.SizeMB
+	}
+	return record
+}
+
+// databaseSize returns the physical size of the largest member database, as the quota applies to the size on disk
+// including fragmentation. It falls back to snapshotSize, the logical size, if the members can't be queried.
+func databaseSize(ctx context.Context, run etcdctl, snapshotSize int64) int64 {
+	if run == nil {
+		return snapshotSize
+	}
+	members, err := getMembers(ctx, run)
+	if err != nil {
+		logging.Warnf("failed to get the etcd member status, using the snapshot size as database size: %v", err)
+		return snapshotSize
+	}
+	var size int64
+	for _, m := range members {
+		size = max(size, m.DBSize)
+	}
+	if size == 0 {
+		return snapshotSize
+	}
+	return size
+}
+
+// formatAnalysisReport appends the record to the formatted results, in the layout of report attachments
+func formatAnalysisReport(formattedResults string, record analysisRecord) string {
+	data, err := json.Marshal(record)
+	if err != nil {
+		logging.Warnf("failed to marshal etcd analysis record: %v", err)
+		return formattedResults
+	}
+	return fmt.Sprintf("%s\n%s:\n%s", formattedResults, analysisDataTitle, data)
+}
+
+// parseAnalysisReport returns the record of an analysis report, or nil if the report has none,
+// e.g. because it was created before the record was added
+func parseAnalysisReport(data string) (*analysisRecord, error) {
+	_, recordData, found := strings.Cut(data, "\n"+analysisDataTitle+":\n")
+	if !found {
+		return nil, nil
+	}
+	record := &analysisRecord{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(recordData)), record); err != nil {
+		return nil, fmt.Errorf("failed to parse etcd analysis record: %w", err)
+	}
+	return record, nil
+}
+
+// previousAnalysis returns the record of the latest analysis report of the cluster, or nil if there is none
+func previousAnalysis(ctx context.Context, bpClient backplane.Client, clusterID string) (*analysisRecord, error) {
+	reports, err := bpClient.ListReports(ctx, clusterID, analysisReportLookback)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := reports.Reports
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].CreatedAt == nil || candidates[j].CreatedAt == nil {
+			return candidates[j].CreatedAt == nil && candidates[i].CreatedAt != nil
+		}
+		return candidates[i].CreatedAt.After(*candidates[j].CreatedAt)
+	})
+
+	for _, candidate := range candidates {
+		if candidate.ReportId == nil || candidate.Summary == nil || *candidate.Summary != analysisReportSummary {
+			continue
+		}
+		report, err := bpClient.GetReport(ctx, clusterID, *candidate.ReportId)
+		if err != nil {
+			return nil, err
+		}
+		record, err := parseAnalysisReport(report.Data)
+		if err != nil {
+			logging.Warnf("skipping etcd analysis report %s: %v", *candidate.ReportId, err)
+			continue
+		}
+		if record != nil {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+// quotaBackendBytes returns the etcd quota set on the etcd pod through ETCD_QUOTA_BACKEND_BYTES or
+// --quota-backend-bytes, falling back to the OpenShift default
+func quotaBackendBytes(pod *corev1.Pod) int64 {
+	for _, container := range pod.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == "ETCD_QUOTA_BACKEND_BYTES" {
+				if quota, err := strconv.ParseInt(env.Value, 10, 64); err == nil && quota > 0 {
+					return quota
+				}
+			}
+		}
+		for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+			for _, field := range strings.Fields(arg) {
+				value, found := strings.CutPrefix(field, "--quota-backend-bytes=")
+				if !found {
+					continue
+				}
+				if quota, err := strconv.ParseInt(value, 10, 64); err == nil && quota > 0 {
+					return quota
+				}
+			}
+		}
+	}
+	return defaultQuotaBackendBytes
+}
+
+// sizeDelta is the change in size of a namespace or resource type between two analyses
+type sizeDelta struct {
+	Name     string
+	Previous float64
+	Current  float64
+}
+
+func (d sizeDelta) DeltaMB() float64 {
+	return d.Current - d.Previous
+}
+
+// analysisTrend compares an analysis with the previous one of the cluster
+type analysisTrend struct {
+	Previous           analysisRecord
+	Current            analysisRecord
+	NamespaceDeltas    []sizeDelta
+	ResourceTypeDeltas []sizeDelta
+	// GrowthBytesPerHour is the growth of the database since the previous analysis, negative if it shrank
+	GrowthBytesPerHour float64
+	// TimeToQuota is the projected time until the database reaches its quota, 0 if it doesn't grow
+	// or QuotaReached
+	TimeToQuota time.Duration
+	// QuotaReached is set if the database is already at or above its quota
+	QuotaReached bool
+}
+
+// compareAnalyses computes the trend between two analyses. Namespaces and resource types are only
+// compared if both analyses list them, as the analysis only lists the largest ones.
+func compareAnalyses(previous, current analysisRecord) analysisTrend {
+	trend := analysisTrend{
+		Previous:           previous,
+		Current:            current,
+		NamespaceDeltas:    sizeDeltas(previous.Namespaces, current.Namespaces),
+		ResourceTypeDeltas: sizeDeltas(previous.ResourceTypes, current.ResourceTypes),
+	}
+
+	remaining := current.QuotaBytes - current.DBSizeBytes
+	trend.QuotaReached = remaining <= 0
+
+	elapsed := current.Timestamp.Sub(previous.Timestamp)
+	if elapsed <= 0 {
+		return trend
+	}
+	trend.GrowthBytesPerHour = float64(current.DBSizeBytes-previous.DBSizeBytes) / elapsed.Hours()
+	if trend.GrowthBytesPerHour > 0 && !trend.QuotaReached {
+		trend.TimeToQuota = time.Duration(float64(remaining) / trend.GrowthBytesPerHour * float64(time.Hour))
+	}
+	return trend
+}
+
+// sizeDeltas returns the changed sizes present in both maps, largest change first
+func sizeDeltas(previous, current map[string]float64) []sizeDelta {
+	var deltas []sizeDelta
+	for name, size := range current {
+		previousSize, ok := previous[name]
+		if !ok || size == previousSize {
+			continue
+		}
+		deltas = append(deltas, sizeDelta{Name: name, Previous: previousSize, Current: size})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if math.Abs(deltas[i].DeltaMB()) != math.Abs(deltas[j].DeltaMB()) {
+			return math.Abs(deltas[i].DeltaMB()) > math.Abs(deltas[j].DeltaMB())
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+	if len(deltas) > trendMaxDeltas {
+		deltas = deltas[:trendMaxDeltas]
+	}
+	return deltas
+}
+
+// formatAnalysisTrend formats the trend for the PagerDuty note
+func formatAnalysisTrend(trend analysisTrend) string {
+	var builder strings.Builder
+
+	elapsed := trend.Current.Timestamp.Sub(trend.Previous.Timestamp).Round(time.Minute)
+	fmt.Fprintf(&builder, "etcd growth since the previous analysis (%s, %s ago):\n",
+		trend.Previous.Timestamp.Format(time.RFC3339), elapsed)
+	fmt.Fprintf(&builder, "  - Database: %.2f MB -> %.2f MB (%+.2f MB/day) of a %.2f MB quota\n",
+		bytesToMB(trend.Previous.DBSizeBytes), bytesToMB(trend.Current.DBSizeBytes),
+		bytesToMB(int64(trend.GrowthBytesPerHour*24)), bytesToMB(trend.Current.QuotaBytes))
+	switch {
+	case trend.QuotaReached:
+		builder.WriteString("  - Projected time to quota: quota reached\n")
+	case trend.TimeToQuota > 0:
+		fmt.Fprintf(&builder, "  - Projected time to quota: %s\n", formatDuration(trend.TimeToQuota))
+	default:
+		builder.WriteString("  - Projected time to quota: not growing\n")
+	}
+
+	writeDeltas := func(title string, deltas []sizeDelta) {
+		if len(deltas) == 0 {
+			return
+		}
+		fmt.Fprintf(&builder, "%s:\n", title)
+		for _, d := range deltas {
+			fmt.Fprintf(&builder, "  - %s: %.2f MB -> %.2f MB (%+.2f MB)\n", d.Name, d.Previous, d.Current, d.DeltaMB())
+		}
+	}
+	writeDeltas("Growth by namespace", trend.NamespaceDeltas)
+	writeDeltas("Growth by resource type", trend.ResourceTypeDeltas)
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func bytesToMB(b int64) float64 {
+	return float64(b) / (1024 * 1024)
+}
+
+// formatDuration formats a projection in days and hours, as minutes are not meaningful for it
+func formatDuration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", days, hours)
+}
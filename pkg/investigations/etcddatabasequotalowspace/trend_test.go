@@ -0,0 +1,246 @@
+package etcddatabasequotalowspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	bpapi "github.com/openshift/backplane-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	bpmock "github.com/openshift/configuration-anomaly-detection/pkg/backplane/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+)
+
+// fakeReports serves the reports of a cluster from memory
+type fakeReports struct {
+	bpmock.MockClient
+	reports []bpapi.Report
+	listErr error
+}
+
+func (f *fakeReports) ListReports(_ context.Context, clusterId string, _ int) (*bpapi.ListReports, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	list := &bpapi.ListReports{ClusterId: clusterId}
+	for i := range f.reports {
+		report := &f.reports[i]
+		list.Reports = append(list.Reports, struct {
+			CreatedAt *time.Time `json:"created_at,omitempty"`
+			ReportId  *string    `json:"report_id,omitempty"`
+			Summary   *string    `json:"summary,omitempty"`
+		}{CreatedAt: &report.CreatedAt, ReportId: &report.ReportId, Summary: &report.Summary})
+	}
+	return list, nil
+}
+
+func (f *fakeReports) GetReport(_ context.Context, _ string, reportId string) (*bpapi.Report, error) {
+	for _, report := range f.reports {
+		if report.ReportId == reportId {
+			return &report, nil
+		}
+	}
+	return nil, errors.New("report not found")
+}
+
+func testAnalysisResult(openshiftEtcdMB, ciMB, eventsMB float64) *AnalysisResult {
+	return &AnalysisResult{
+		TopNamespaces: []NamespaceSize{
+			{Namespace: "openshift-etcd", SizeMB: openshiftEtcdMB},
+			{Namespace: "ci-builds", SizeMB: ciMB},
+		},
+		TopResourceTypes: []ResourceTypeSize{
+			{ResourceType: "events", SizeMB: eventsMB},
+			{ResourceType: "secrets", SizeMB: 50},
+		},
+	}
+}
+
+func TestAnalysisReportRoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := newAnalysisRecord(testAnalysisResult(10, 200, 300), 4*1024*1024*1024, defaultQuotaBackendBytes, now)
+
+	data := formatAnalysisReport("etcd Database Space Analysis\n", record)
+	assert.Contains(t, data, "etcd Database Space Analysis", "The report should still contain the formatted results")
+
+	parsed, err := parseAnalysisReport(data)
+	assert.NoError(t, err)
+	assert.Equal(t, &record, parsed)
+
+	parsed, err = parseAnalysisReport("etcd Database Space Analysis\n")
+	assert.NoError(t, err)
+	assert.Nil(t, parsed, "Reports without a record should be skipped")
+
+	_, err = parseAnalysisReport("results\n" + analysisDataTitle + ":\n{")
+	assert.Error(t, err)
+}
+
+func TestPreviousAnalysis(t *testing.T) {
+	now := time.Now()
+	older := newAnalysisRecord(testAnalysisResult(10, 100, 200), 3*1024*1024*1024, defaultQuotaBackendBytes, now.Add(-48*time.Hour))
+	latest := newAnalysisRecord(testAnalysisResult(10, 150, 250), 3500*1024*1024, defaultQuotaBackendBytes, now.Add(-24*time.Hour))
+
+	reports := &fakeReports{reports: []bpapi.Report{
+		{ReportId: "older", Summary: analysisReportSummary, CreatedAt: now.Add(-48 * time.Hour), Data: formatAnalysisReport("results", older)},
+		{ReportId: "other", Summary: "2026-01-01T00:00:00Z : etcddatabasequotalowspace", CreatedAt: now.Add(-time.Hour), Data: "notes"},
+		{ReportId: "legacy", Summary: analysisReportSummary, CreatedAt: now.Add(-12 * time.Hour), Data: "results without a record"},
+		{ReportId: "latest", Summary: analysisReportSummary, CreatedAt: now.Add(-24 * time.Hour), Data: formatAnalysisReport("results", latest)},
+	}}
+
+	previous, err := previousAnalysis(context.Background(), reports, "external-cluster-id")
+	assert.NoError(t, err)
+	if assert.NotNil(t, previous) {
+		assert.Equal(t, latest.DBSizeBytes, previous.DBSizeBytes, "The latest report with a record should be used")
+	}
+
+	previous, err = previousAnalysis(context.Background(), &fakeReports{}, "external-cluster-id")
+	assert.NoError(t, err)
+	assert.Nil(t, previous)
+
+	_, err = previousAnalysis(context.Background(), &fakeReports{listErr: errors.New("unauthorized")}, "external-cluster-id")
+	assert.Error(t, err)
+}
+
+func TestCompareAnalyses(t *testing.T) {
+	now := time.Now()
+	previous := newAnalysisRecord(testAnalysisResult(10, 100, 200), 6*1024*1024*1024, defaultQuotaBackendBytes, now.Add(-24*time.Hour))
+
+	t.Run("growing database", func(t *testing.T) {
+		current := newAnalysisRecord(testAnalysisResult(10, 400, 250), 7*1024*1024*1024, defaultQuotaBackendBytes, now)
+		current.Namespaces["new-namespace"] = 500
+
+		trend := compareAnalyses(previous, current)
+		assert.InDelta(t, float64(1024*1024*1024)/24, trend.GrowthBytesPerHour, 1)
+		assert.InDelta(t, (24 * time.Hour).Hours(), trend.TimeToQuota.Hours(), 0.01, "1 GB left at 1 GB a day")
+
+		assert.Equal(t, []sizeDelta{{Name: "ci-builds", Previous: 100, Current: 400}}, trend.NamespaceDeltas,
+			"Unchanged namespaces and namespaces missing from the previous analysis should not be listed")
+		assert.Equal(t, []sizeDelta{{Name: "events", Previous: 200, Current: 250}}, trend.ResourceTypeDeltas)
+
+		formatted := formatAnalysisTrend(trend)
+		assert.Contains(t, formatted, "Database: 6144.00 MB -> 7168.00 MB (+1024.00 MB/day) of a 8192.00 MB quota")
+		assert.Contains(t, formatted, "Projected time to quota: 1d 0h")
+		assert.Contains(t, formatted, "ci-builds: 100.00 MB -> 400.00 MB (+300.00 MB)")
+		assert.Contains(t, formatted, "events: 200.00 MB -> 250.00 MB (+50.00 MB)")
+	})
+
+	t.Run("shrinking database", func(t *testing.T) {
+		current := newAnalysisRecord(testAnalysisResult(10, 50, 100), 5*1024*1024*1024, defaultQuotaBackendBytes, now)
+
+		trend := compareAnalyses(previous, current)
+		assert.Less(t, trend.GrowthBytesPerHour, 0.0)
+		assert.Zero(t, trend.TimeToQuota)
+		assert.Contains(t, formatAnalysisTrend(trend), "Projected time to quota: not growing")
+		assert.Contains(t, formatAnalysisTrend(trend), "ci-builds: 100.00 MB -> 50.00 MB (-50.00 MB)")
+	})
+
+	t.Run("quota reached", func(t *testing.T) {
+		current := newAnalysisRecord(testAnalysisResult(10, 400, 250), defaultQuotaBackendBytes, defaultQuotaBackendBytes, now)
+
+		trend := compareAnalyses(previous, current)
+		assert.True(t, trend.QuotaReached)
+		assert.Zero(t, trend.TimeToQuota)
+		assert.Contains(t, formatAnalysisTrend(trend), "Projected time to quota: quota reached")
+		assert.NotContains(t, formatAnalysisTrend(trend), "not growing")
+	})
+}
+
+func TestQuotaBackendBytes(t *testing.T) {
+	tests := []struct {
+		name      string
+		container corev1.Container
+		want      int64
+	}{
+		{
+			name:      "environment",
+			container: corev1.Container{Name: "etcd", Env: []corev1.EnvVar{{Name: "ETCD_QUOTA_BACKEND_BYTES", Value: "17179869184"}}},
+			want:      16 * 1024 * 1024 * 1024,
+		},
+		{
+			name:      "flag in a shell script",
+			container: corev1.Container{Name: "etcd", Command: []string{"/bin/sh", "-c"}, Args: []string{"exec etcd \\\n --quota-backend-bytes=4294967296 \\\n --logger=zap"}},
+			want:      4 * 1024 * 1024 * 1024,
+		},
+		{
+			name:      "default",
+			container: corev1.Container{Name: "etcd"},
+			want:      defaultQuotaBackendBytes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{tt.container}}}
+			assert.Equal(t, tt.want, quotaBackendBytes(pod))
+		})
+	}
+}
+
+func TestDatabaseSize(t *testing.T) {
+	snapshotSize := int64(500 * 1024 * 1024)
+	run := &fakeEtcdctl{}
+	assert.Equal(t, int64(2147483648), databaseSize(context.Background(), run.run, snapshotSize), "The physical size of the largest member should be used")
+
+	failing := func(context.Context, ...string) (string, error) { return "", errors.New("connection refused") }
+	assert.Equal(t, snapshotSize, databaseSize(context.Background(), failing, snapshotSize))
+	assert.Equal(t, snapshotSize, databaseSize(context.Background(), nil, snapshotSize))
+}
+
+func TestCompareWithPreviousAnalysis(t *testing.T) {
+	etcdPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-master-0", Namespace: etcdNamespace},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "etcd",
+			Env:  []corev1.EnvVar{{Name: "ETCD_QUOTA_BACKEND_BYTES", Value: "8589934592"}},
+		}}},
+	}
+	fakeK8s := fake.NewClientBuilder().WithObjects(etcdPod).Build()
+	snapshot := &SnapshotResult{PodName: "etcd-master-0", Namespace: etcdNamespace, SnapshotSize: 8000 * 1024 * 1024}
+
+	t.Run("first analysis", func(t *testing.T) {
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		record := compareWithPreviousAnalysis(context.Background(), fakeK8s, &fakeReports{}, notes, "external-cluster-id", snapshot, testAnalysisResult(10, 100, 200))
+		assert.Equal(t, int64(defaultQuotaBackendBytes), record.QuotaBytes)
+		assert.Equal(t, snapshot.SnapshotSize, record.DBSizeBytes, "The fake client can't exec into the pod, so the snapshot size is used")
+		assert.Contains(t, notes.String(), "No previous etcd analysis found")
+	})
+
+	t.Run("quota reached soon", func(t *testing.T) {
+		previous := newAnalysisRecord(testAnalysisResult(10, 100, 200), 7000*1024*1024, defaultQuotaBackendBytes, time.Now().Add(-24*time.Hour))
+		reports := &fakeReports{reports: []bpapi.Report{
+			{ReportId: "previous", Summary: analysisReportSummary, CreatedAt: previous.Timestamp, Data: formatAnalysisReport("results", previous)},
+		}}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		compareWithPreviousAnalysis(context.Background(), fakeK8s, reports, notes, "external-cluster-id", snapshot, testAnalysisResult(10, 400, 200))
+		assert.Contains(t, notes.String(), "⚠️ etcd growth since the previous analysis")
+		assert.Contains(t, notes.String(), "Projected time to quota: 4h")
+	})
+
+	t.Run("quota reached", func(t *testing.T) {
+		previous := newAnalysisRecord(testAnalysisResult(10, 100, 200), 9000*1024*1024, defaultQuotaBackendBytes, time.Now().Add(-24*time.Hour))
+		reports := &fakeReports{reports: []bpapi.Report{
+			{ReportId: "previous", Summary: analysisReportSummary, CreatedAt: previous.Timestamp, Data: formatAnalysisReport("results", previous)},
+		}}
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+		full := &SnapshotResult{PodName: "etcd-master-0", Namespace: etcdNamespace, SnapshotSize: defaultQuotaBackendBytes}
+
+		compareWithPreviousAnalysis(context.Background(), fakeK8s, reports, notes, "external-cluster-id", full, testAnalysisResult(10, 400, 200))
+		assert.Contains(t, notes.String(), "⚠️ etcd growth since the previous analysis")
+		assert.Contains(t, notes.String(), "Projected time to quota: quota reached")
+	})
+
+	t.Run("backplane failure", func(t *testing.T) {
+		notes := notewriter.New("etcddatabasequotalowspace_test", logging.RawLogger)
+
+		record := compareWithPreviousAnalysis(context.Background(), fakeK8s, &fakeReports{listErr: errors.New("unauthorized")}, notes, "external-cluster-id", snapshot, testAnalysisResult(10, 100, 200))
+		assert.Contains(t, notes.String(), "Failed to get the previous etcd analysis")
+		assert.Equal(t, snapshot.SnapshotSize, record.DBSizeBytes, "The analysis should still be recorded")
+	})
+}
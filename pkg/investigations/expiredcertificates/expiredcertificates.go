@@ -70,7 +70,11 @@ func (c *certInfo) statusString() string {
 type Investigation struct{}
 
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
-	ctx := context.Background()
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithCluster().WithK8sClient().WithNotes().Build()
@@ -316,6 +320,14 @@ func (i *Investigation) Name() string {
 	return "expiredcertificates"
 }
 
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       i.Description(),
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+		NeedsCustomerData: true,
+	}
+}
+
 func (i *Investigation) AlertTitle() string {
 	return "expiredcertificates"
 }
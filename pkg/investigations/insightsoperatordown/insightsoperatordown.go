@@ -19,6 +19,11 @@ import (
 type Investigation struct{}
 
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 	r, err := rb.WithClusterDeployment().WithAwsClient().Build()
 	if err != nil {
@@ -62,7 +67,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 
 	coList := &configv1.ClusterOperatorList{}
 	listOptions := &client.ListOptions{FieldSelector: fields.SelectorFromSet(fields.Set{"metadata.name": "insights"})}
-	err = r.K8sClient.List(context.TODO(), coList, listOptions)
+	err = r.K8sClient.List(ctx, coList, listOptions)
 	if err != nil {
 		return result, investigation.WrapInfrastructure(
 			fmt.Errorf("unable to list insights clusteroperator: %w", err),
@@ -90,7 +95,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 		notes.AppendSuccess("Ruled out OCPBUGS-22226")
 	}
 
-	verifierResult, failureReason, err := networkverifier.Run(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	verifierResult, failureReason, err := networkverifier.Run(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
 	if err != nil {
 		logging.Errorf("Network verifier ran into an error: %s", err.Error())
 		notes.AppendWarning("NetworkVerifier failed to run:\n\t %s", err.Error())
@@ -126,3 +131,11 @@ func isOCPBUG22226(co *configv1.ClusterOperator) bool {
 func (c *Investigation) Name() string {
 	return "insightsoperatordown"
 }
+
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks why the insights operator is down, e.g. a banned cluster owner or blocked egress",
+		Platforms:         []investigation.Platform{investigation.PlatformClassic, investigation.PlatformAWS},
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s, investigation.ResourceAWS},
+	}
+}
@@ -0,0 +1,135 @@
+package investigation
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+// ChainCache shares read-only resources between the investigations of a chain, so every step
+// doesn't fetch the cluster, cluster deployment, hypershift config or cloud clients again.
+// Resources scoped to an investigation, like backplane rest configs and the k8s and oc clients
+// built from them, are never cached, so the remediation RBAC stays per investigation.
+type ChainCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	fetches int
+	hits    int
+}
+
+// cacheEntry is a cached value, or a fetch in progress until done is closed
+type cacheEntry struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// NewChainCache creates an empty cache, to be shared by the builders of a single chain run
+func NewChainCache() *ChainCache {
+	return &ChainCache{entries: map[string]*cacheEntry{}}
+}
+
+// Stats returns how many resources were fetched and how many were served from the cache
+func (c *ChainCache) Stats() (fetches int, hits int) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fetches, c.hits
+}
+
+// cached returns the value cached under key, calling fetch with ctx on first use. Concurrent callers
+// of the same key wait for the fetch in progress instead of fetching again, or until their ctx is done.
+// Errors are shared with the waiting callers but not cached, so a later step fetches again.
+// A nil cache always fetches.
+func cached[T any](ctx context.Context, c *ChainCache, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return fetch(ctx)
+	}
+
+	for {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &cacheEntry{done: make(chan struct{})}
+			c.entries[key] = entry
+			c.fetches++
+			c.mu.Unlock()
+			return fillEntry(ctx, c, key, entry, fetch)
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		// The fetch of another caller was canceled, fetch again with this caller's context
+		if isContextError(entry.err) && ctx.Err() == nil {
+			continue
+		}
+		if entry.err != nil {
+			var zero T
+			return zero, entry.err
+		}
+		c.mu.Lock()
+		c.hits++
+		c.mu.Unlock()
+		// A nil interface value was cached, e.g. a nil client without an error
+		value, _ := entry.value.(T)
+		return value, nil
+	}
+}
+
+// fillEntry fetches the value of entry and wakes up the callers waiting for it. Failed entries are
+// removed, so the next caller fetches again.
+func fillEntry[T any](ctx context.Context, c *ChainCache, key string, entry *cacheEntry, fetch func(ctx context.Context) (T, error)) (value T, err error) {
+	// Also wake up the waiting callers if fetch panics
+	entry.err = errors.New("fetch did not complete")
+	defer func() {
+		c.mu.Lock()
+		if entry.err != nil {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		close(entry.done)
+	}()
+
+	value, err = fetch(ctx)
+	entry.value, entry.err = value, err
+	return value, err
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// OCMClient wraps client so the subscription lookups of alert filters, the organization ID and
+// the creator of a cluster, are only done once per chain
+func (c *ChainCache) OCMClient(client ocm.Client) ocm.Client {
+	return &cachingOCMClient{Client: client, cache: c}
+}
+
+type cachingOCMClient struct {
+	ocm.Client
+	cache *ChainCache
+}
+
+func (o *cachingOCMClient) GetOrganizationID(clusterID string) (string, error) {
+	return cached(context.Background(), o.cache, "organization/"+clusterID, func(context.Context) (string, error) {
+		return o.Client.GetOrganizationID(clusterID)
+	})
+}
+
+func (o *cachingOCMClient) GetCreatorFromCluster(cluster *cmv1.Cluster) (*amv1.Account, error) {
+	return cached(context.Background(), o.cache, "creator/"+cluster.ID(), func(context.Context) (*amv1.Account, error) {
+		return o.Client.GetCreatorFromCluster(cluster)
+	})
+}
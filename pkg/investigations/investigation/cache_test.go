@@ -0,0 +1,119 @@
+package investigation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
+)
+
+func TestCached(t *testing.T) {
+	cache := NewChainCache()
+	calls := 0
+	fetch := func(context.Context) (string, error) {
+		calls++
+		return "value", nil
+	}
+
+	for range 3 {
+		value, err := cached(context.Background(), cache, "key", fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "value", value)
+	}
+	assert.Equal(t, 1, calls)
+	fetches, hits := cache.Stats()
+	assert.Equal(t, 1, fetches)
+	assert.Equal(t, 2, hits)
+
+	// Errors aren't cached, the next builder fetches again
+	failing := func(context.Context) (string, error) {
+		calls++
+		return "", errors.New("ocm unavailable")
+	}
+	_, err := cached(context.Background(), cache, "other", failing)
+	require.Error(t, err)
+	_, err = cached(context.Background(), cache, "other", failing)
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+
+	// A nil cache always fetches
+	_, err = cached(context.Background(), nil, "key", fetch)
+	require.NoError(t, err)
+	assert.Equal(t, 4, calls)
+
+	// Cached nil interface values are returned as nil
+	for range 2 {
+		value, err := cached(context.Background(), cache, "nil", func(context.Context) (ocm.Client, error) { return nil, nil })
+		require.NoError(t, err)
+		assert.Nil(t, value)
+	}
+}
+
+func TestCached_Concurrent(t *testing.T) {
+	cache := NewChainCache()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	fetch := func(context.Context) (string, error) {
+		calls++
+		close(started)
+		<-release
+		return "value", nil
+	}
+
+	results := make(chan string, 3)
+	for range 3 {
+		go func() {
+			value, _ := cached(context.Background(), cache, "key", fetch)
+			results <- value
+		}()
+	}
+	<-started
+
+	// The fetch in progress doesn't block other keys
+	other, err := cached(context.Background(), cache, "other", func(context.Context) (string, error) { return "other", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "other", other)
+
+	// A waiting caller gives up when its context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cached(ctx, cache, "key", fetch)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	for range 3 {
+		assert.Equal(t, "value", <-results)
+	}
+	assert.Equal(t, 1, calls, "Concurrent callers should share a single fetch")
+}
+
+func TestChainCache_OCMClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockOCM := ocmmock.NewMockClient(ctrl)
+	cluster, err := cmv1.NewCluster().ID("cluster-1").Build()
+	require.NoError(t, err)
+
+	mockOCM.EXPECT().GetOrganizationID("cluster-1").Return("org-1", nil).Times(1)
+	mockOCM.EXPECT().GetCreatorFromCluster(cluster).Return(nil, nil).Times(1)
+	mockOCM.EXPECT().GetClusterInfo("cluster-1").Return(cluster, nil).Times(2)
+
+	client := NewChainCache().OCMClient(mockOCM)
+	for range 2 {
+		orgID, err := client.GetOrganizationID("cluster-1")
+		require.NoError(t, err)
+		assert.Equal(t, "org-1", orgID)
+		_, err = client.GetCreatorFromCluster(cluster)
+		require.NoError(t, err)
+		// Other calls are passed through
+		_, err = client.GetClusterInfo("cluster-1")
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,60 @@
+package investigation
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextInvestigation is an Investigation that can be cancelled. RunContext should pass ctx on
+// to every k8s, AWS and OCM call, and return the notes gathered so far once ctx is done.
+// Run is kept for callers without a context and usually calls RunContext with context.Background().
+type ContextInvestigation interface {
+	Investigation
+	RunContext(ctx context.Context, builder ResourceBuilder) (InvestigationResult, error)
+}
+
+// AbandonedError is returned by RunWithContext for an investigation that was still running when its
+// context was done. The investigation keeps using its builder until Done is closed, so the builder must
+// not be used, e.g. built or cleaned up, before that.
+type AbandonedError struct {
+	Name string
+	Err  error
+	Done <-chan struct{}
+}
+
+func (e *AbandonedError) Error() string {
+	return fmt.Sprintf("investigation %s did not finish: %v", e.Name, e.Err)
+}
+
+func (e *AbandonedError) Unwrap() error {
+	return e.Err
+}
+
+// RunWithContext runs inv with ctx, passing ctx on to the builder.
+// Investigations that don't implement ContextInvestigation can't be cancelled: they run in a goroutine
+// that is abandoned when ctx is done, in which case an *AbandonedError is returned.
+func RunWithContext(ctx context.Context, inv Investigation, builder ResourceBuilder) (InvestigationResult, error) {
+	builder.WithContext(ctx)
+	if ci, ok := inv.(ContextInvestigation); ok {
+		return ci.RunContext(ctx, builder)
+	}
+
+	type runResult struct {
+		result InvestigationResult
+		err    error
+	}
+	done := make(chan runResult, 1)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		result, err := inv.Run(builder)
+		done <- runResult{result: result, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.result, r.err
+	case <-ctx.Done():
+		return InvestigationResult{}, &AbandonedError{Name: inv.Name(), Err: ctx.Err(), Done: finished}
+	}
+}
@@ -0,0 +1,69 @@
+package investigation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingInvestigation is a legacy investigation that runs until release is closed
+type blockingInvestigation struct {
+	plainInvestigation
+	release chan struct{}
+}
+
+func (b blockingInvestigation) Run(ResourceBuilder) (InvestigationResult, error) {
+	<-b.release
+	return InvestigationResult{}, nil
+}
+
+// contextInvestigation returns the error of its context
+type contextInvestigation struct{ plainInvestigation }
+
+func (contextInvestigation) RunContext(ctx context.Context, _ ResourceBuilder) (InvestigationResult, error) {
+	<-ctx.Done()
+	return InvestigationResult{}, ctx.Err()
+}
+
+func TestRunWithContext(t *testing.T) {
+	t.Run("legacy investigation finishes", func(t *testing.T) {
+		_, err := RunWithContext(context.Background(), plainInvestigation{}, &ResourceBuilderMock{})
+		require.NoError(t, err)
+	})
+
+	t.Run("legacy investigation is abandoned on timeout", func(t *testing.T) {
+		inv := blockingInvestigation{release: make(chan struct{})}
+		defer close(inv.release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := RunWithContext(ctx, inv, &ResourceBuilderMock{})
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected deadline exceeded, got %v", err)
+		assert.ErrorContains(t, err, "investigation plain did not finish")
+
+		var abandoned *AbandonedError
+		require.ErrorAs(t, err, &abandoned)
+		select {
+		case <-abandoned.Done:
+			t.Fatal("abandoned investigation reported as done while it is still running")
+		default:
+		}
+		inv.release <- struct{}{}
+		select {
+		case <-abandoned.Done:
+		case <-time.After(time.Second):
+			t.Fatal("abandoned investigation not reported as done after it returned")
+		}
+	})
+
+	t.Run("context investigation gets the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := RunWithContext(ctx, contextInvestigation{}, &ResourceBuilderMock{})
+		assert.Equal(t, context.Canceled, err)
+	})
+}
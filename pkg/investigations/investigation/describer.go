@@ -0,0 +1,116 @@
+package investigation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Platform is a cluster topology or cloud provider an investigation can run against
+type Platform string
+
+const (
+	PlatformClassic Platform = "classic"
+	PlatformHCP     Platform = "hcp"
+	PlatformAWS     Platform = "aws"
+	PlatformGCP     Platform = "gcp"
+)
+
+// Resource is a class of access an investigation needs to do its work
+type Resource string
+
+const (
+	ResourceK8s               Resource = "k8s"
+	ResourceManagementCluster Resource = "management-cluster"
+	ResourceAWS               Resource = "aws"
+	ResourceGCP               Resource = "gcp"
+)
+
+// Capabilities describes what an investigation does and what it needs to run
+type Capabilities struct {
+	Description string
+	// Platforms lists the topologies (classic, hcp) and cloud providers (aws, gcp) the investigation supports.
+	// An empty list of either kind means the investigation supports all of them.
+	Platforms         []Platform
+	RequiredResources []Resource
+	// MutatesCluster is set for investigations that change the cluster, e.g. restart the control plane
+	MutatesCluster bool
+	// NeedsCustomerData is set for investigations that collect customer data, e.g. a must-gather
+	NeedsCustomerData bool
+	// Params is the schema of the parameters the investigation accepts. Runs with parameters
+	// outside of it are rejected, see ResolveParams.
+	Params []Param
+}
+
+// Describer is implemented by investigations that declare their capabilities.
+// Investigations that don't implement it are assumed to support every cluster.
+type Describer interface {
+	Describe() Capabilities
+}
+
+// Describe returns the capabilities of inv. For investigations that don't implement Describer,
+// only the description is filled in, if available.
+func Describe(inv Investigation) Capabilities {
+	if d, ok := inv.(Describer); ok {
+		return d.Describe()
+	}
+	if d, ok := inv.(interface{ Description() string }); ok {
+		return Capabilities{Description: d.Description()}
+	}
+	return Capabilities{}
+}
+
+// Supports reports whether cluster is on one of the declared platforms.
+// If not, the returned reason explains why, e.g. "supports hcp clusters only, cluster is classic".
+func (c Capabilities) Supports(cluster *cmv1.Cluster) (bool, string) {
+	topology, cloud := ClusterPlatforms(cluster)
+
+	var topologies, clouds []Platform
+	for _, p := range c.Platforms {
+		switch p {
+		case PlatformClassic, PlatformHCP:
+			topologies = append(topologies, p)
+		case PlatformAWS, PlatformGCP:
+			clouds = append(clouds, p)
+		}
+	}
+
+	if len(topologies) > 0 && !slices.Contains(topologies, topology) {
+		return false, fmt.Sprintf("supports %s clusters only, cluster is %s", joinPlatforms(topologies), topology)
+	}
+	if len(clouds) > 0 && !slices.Contains(clouds, cloud) {
+		if cloud == "" {
+			cloud = "unknown"
+		}
+		return false, fmt.Sprintf("supports %s clusters only, cluster cloud provider is %s", joinPlatforms(clouds), cloud)
+	}
+	return true, ""
+}
+
+// ClusterPlatforms returns the topology and the cloud provider of cluster.
+// The cloud provider is empty if OCM doesn't report one.
+func ClusterPlatforms(cluster *cmv1.Cluster) (topology Platform, cloud Platform) {
+	topology = PlatformClassic
+	if hypershift := cluster.Hypershift(); hypershift != nil && hypershift.Enabled() {
+		topology = PlatformHCP
+	}
+	switch {
+	case cluster.CloudProvider().ID() != "":
+		cloud = Platform(cluster.CloudProvider().ID())
+	case cluster.AWS() != nil:
+		cloud = PlatformAWS
+	case cluster.GCP() != nil:
+		cloud = PlatformGCP
+	}
+	return topology, cloud
+}
+
+func joinPlatforms(platforms []Platform) string {
+	names := make([]string, 0, len(platforms))
+	for _, p := range platforms {
+		names = append(names, string(p))
+	}
+	return strings.Join(names, " or ")
+}
@@ -0,0 +1,68 @@
+package investigation
+
+import (
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCluster(t *testing.T, hcp bool, cloud string) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().
+		Hypershift(cmv1.NewHypershift().Enabled(hcp)).
+		CloudProvider(cmv1.NewCloudProvider().ID(cloud)).
+		Build()
+	require.NoError(t, err)
+	return cluster
+}
+
+func TestCapabilities_Supports(t *testing.T) {
+	tests := []struct {
+		name       string
+		platforms  []Platform
+		hcp        bool
+		cloud      string
+		supported  bool
+		wantReason string
+	}{
+		{name: "no platforms supports everything", hcp: true, cloud: "gcp", supported: true},
+		{name: "classic on classic", platforms: []Platform{PlatformClassic}, cloud: "aws", supported: true},
+		{name: "hcp only on classic", platforms: []Platform{PlatformHCP}, cloud: "aws", wantReason: "supports hcp clusters only, cluster is classic"},
+		{name: "classic aws on hcp", platforms: []Platform{PlatformClassic, PlatformAWS}, hcp: true, cloud: "aws", wantReason: "supports classic clusters only, cluster is hcp"},
+		{name: "aws only on gcp", platforms: []Platform{PlatformClassic, PlatformAWS}, cloud: "gcp", wantReason: "supports aws clusters only, cluster cloud provider is gcp"},
+		{name: "aws or gcp on gcp", platforms: []Platform{PlatformAWS, PlatformGCP}, hcp: true, cloud: "gcp", supported: true},
+		{name: "unknown cloud provider", platforms: []Platform{PlatformAWS}, wantReason: "supports aws clusters only, cluster cloud provider is unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supported, reason := Capabilities{Platforms: tt.platforms}.Supports(newTestCluster(t, tt.hcp, tt.cloud))
+			assert.Equal(t, tt.supported, supported)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+type describedInvestigation struct{ plainInvestigation }
+
+func (describedInvestigation) Describe() Capabilities {
+	return Capabilities{Description: "described", MutatesCluster: true}
+}
+
+type plainInvestigation struct{}
+
+func (plainInvestigation) Run(ResourceBuilder) (InvestigationResult, error) {
+	return InvestigationResult{}, nil
+}
+func (plainInvestigation) Name() string { return "plain" }
+
+type legacyInvestigation struct{ plainInvestigation }
+
+func (legacyInvestigation) Description() string { return "legacy" }
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, Capabilities{Description: "described", MutatesCluster: true}, Describe(describedInvestigation{}))
+	assert.Equal(t, Capabilities{Description: "legacy"}, Describe(legacyInvestigation{}))
+	assert.Equal(t, Capabilities{}, Describe(plainInvestigation{}))
+}
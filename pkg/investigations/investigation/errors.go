@@ -40,6 +40,17 @@ func (e AWSClientError) Error() string {
 	return fmt.Sprintf("could not retrieve aws credentials for %s: %s", e.ClusterID, e.Err.Error())
 }
 
+type GCPClientError struct {
+	ClusterID string
+	Err       error
+}
+
+func (e GCPClientError) Unwrap() error { return e.Err }
+
+func (e GCPClientError) Error() string {
+	return fmt.Sprintf("could not create gcp client for %s: %s", e.ClusterID, e.Err.Error())
+}
+
 type RestConfigError struct {
 	ClusterID string
 	Err       error
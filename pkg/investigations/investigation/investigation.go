@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
@@ -11,13 +12,14 @@ import (
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
 	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
 	"github.com/openshift/configuration-anomaly-detection/pkg/managedcloud"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 	"github.com/openshift/configuration-anomaly-detection/pkg/oc"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
-	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 )
 
@@ -36,6 +38,7 @@ func NewResourceBuilder(
 	name string,
 	backplaneUrl string,
 	params map[string]string,
+	cache *ChainCache,
 ) (ResourceBuilder, error) {
 	if params == nil {
 		params = make(map[string]string)
@@ -45,6 +48,8 @@ func NewResourceBuilder(
 		name:         name,
 		ocmClient:    ocmClient,
 		backplaneUrl: backplaneUrl,
+		ctx:          context.Background(),
+		cache:        cache,
 		builtResources: &Resources{
 			BpClient:  bpClient,
 			OcmClient: ocmClient,
@@ -68,11 +73,12 @@ type Resources struct {
 	Cluster                          *cmv1.Cluster
 	ClusterDeployment                *hivev1.ClusterDeployment
 	AwsClient                        aws.Client
+	GcpClient                        gcp.Client
 	BpClient                         backplane.Client
 	RestConfig                       *backplane.RestConfig
 	K8sClient                        k8sclient.Client
 	OcmClient                        ocm.Client
-	PdClient                         pagerduty.Client
+	Incident                         incident.Backend
 	Notes                            *notewriter.NoteWriter
 	OCClient                         oc.Client
 	ManagementRestConfig             *backplane.RestConfig
@@ -92,14 +98,17 @@ type ResourceBuilder interface {
 	WithCluster() ResourceBuilder
 	WithClusterDeployment() ResourceBuilder
 	WithAwsClient() ResourceBuilder
+	WithGcpClient() ResourceBuilder
 	WithRestConfig() ResourceBuilder
 	WithK8sClient() ResourceBuilder
-	WithPdClient(pdClient pagerduty.Client) ResourceBuilder
+	WithIncident(backend incident.Backend) ResourceBuilder
 	WithOC() ResourceBuilder
 	WithNotes() ResourceBuilder
 	WithManagementRestConfig() ResourceBuilder
 	WithManagementK8sClient() ResourceBuilder
 	WithManagementOCClient() ResourceBuilder
+	// WithContext sets the context of the requests Build makes, e.g. to get the rest config
+	WithContext(ctx context.Context) ResourceBuilder
 	Build() (*Resources, error)
 }
 
@@ -107,6 +116,7 @@ type ResourceBuilderT struct {
 	buildCluster              bool
 	buildClusterDeployment    bool
 	buildAwsClient            bool
+	buildGcpClient            bool
 	buildRestConfig           bool
 	buildK8sClient            bool
 	buildOC                   bool
@@ -122,7 +132,12 @@ type ResourceBuilderT struct {
 	backplaneUrl string
 
 	ocmClient *ocm.SdkClient
+	ctx       context.Context
+	// cache is shared with the other builders of the chain, nil disables caching
+	cache *ChainCache
 
+	// Build can be called by the controller while an abandoned investigation is still building
+	mu sync.Mutex
 	// cache
 	builtResources *Resources
 	buildErr       error
@@ -151,6 +166,12 @@ func (r *ResourceBuilderT) WithAwsClient() ResourceBuilder {
 	return r
 }
 
+func (r *ResourceBuilderT) WithGcpClient() ResourceBuilder {
+	r.WithCluster()
+	r.buildGcpClient = true
+	return r
+}
+
 func (r *ResourceBuilderT) WithOC() ResourceBuilder {
 	r.WithRestConfig()
 	r.buildOC = true
@@ -168,8 +189,8 @@ func (r *ResourceBuilderT) WithNotes() ResourceBuilder {
 	return r
 }
 
-func (r *ResourceBuilderT) WithPdClient(pdClient pagerduty.Client) ResourceBuilder {
-	r.builtResources.PdClient = pdClient
+func (r *ResourceBuilderT) WithIncident(backend incident.Backend) ResourceBuilder {
+	r.builtResources.Incident = backend
 	return r
 }
 
@@ -191,7 +212,18 @@ func (r *ResourceBuilderT) WithManagementOCClient() ResourceBuilder {
 	return r
 }
 
+func (r *ResourceBuilderT) WithContext(ctx context.Context) ResourceBuilder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ctx = ctx
+	r.builtResources.OcmClient = r.ocmClient.WithContext(ctx)
+	return r
+}
+
 func (r *ResourceBuilderT) Build() (*Resources, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.buildErr != nil {
 		// Return whatever managed to build + an error. this might allow some subset of checks to proceed.
 		return r.builtResources, r.buildErr
@@ -203,7 +235,9 @@ func (r *ResourceBuilderT) Build() (*Resources, error) {
 	var err error
 
 	if r.buildCluster && r.builtResources.Cluster == nil {
-		r.builtResources.Cluster, err = r.ocmClient.GetClusterInfo(r.clusterId)
+		r.builtResources.Cluster, err = cached(r.ctx, r.cache, "cluster/"+r.clusterId, func(ctx context.Context) (*cmv1.Cluster, error) {
+			return r.ocmClient.WithContext(ctx).GetClusterInfo(r.clusterId)
+		})
 		if err != nil {
 			// Let the caller handle how to respond to this error.
 			r.buildErr = ClusterNotFoundError{ClusterID: r.clusterId, Err: err}
@@ -220,15 +254,27 @@ func (r *ResourceBuilderT) Build() (*Resources, error) {
 	internalClusterId := r.builtResources.Cluster.ID()
 
 	if r.buildAwsClient && r.builtResources.AwsClient == nil {
-		r.builtResources.AwsClient, err = managedcloud.CreateCustomerAWSClient(r.builtResources.Cluster, r.ocmClient)
+		r.builtResources.AwsClient, err = cached(r.ctx, r.cache, "aws/"+internalClusterId, func(ctx context.Context) (aws.Client, error) {
+			return managedcloud.CreateCustomerAWSClient(r.builtResources.Cluster, r.ocmClient.WithContext(ctx))
+		})
 		if err != nil {
 			r.buildErr = AWSClientError{ClusterID: r.clusterId, Err: err}
 			return r.builtResources, r.buildErr
 		}
 	}
 
+	if r.buildGcpClient && r.builtResources.GcpClient == nil {
+		r.builtResources.GcpClient, err = cached(r.ctx, r.cache, "gcp/"+internalClusterId, func(context.Context) (gcp.Client, error) {
+			return managedcloud.CreateCustomerGCPClient(r.builtResources.Cluster)
+		})
+		if err != nil {
+			r.buildErr = GCPClientError{ClusterID: r.clusterId, Err: err}
+			return r.builtResources, r.buildErr
+		}
+	}
+
 	if r.buildRestConfig && r.builtResources.RestConfig == nil {
-		r.builtResources.RestConfig, err = r.builtResources.BpClient.GetRestConfig(context.Background(), internalClusterId, r.name, false)
+		r.builtResources.RestConfig, err = r.builtResources.BpClient.GetRestConfig(r.ctx, internalClusterId, r.name, false)
 		if err != nil {
 			r.buildErr = RestConfigError{ClusterID: r.clusterId, Err: err}
 			return r.builtResources, r.buildErr
@@ -245,7 +291,7 @@ func (r *ResourceBuilderT) Build() (*Resources, error) {
 	}
 
 	if r.buildOC && r.builtResources.OCClient == nil {
-		r.builtResources.OCClient, err = oc.New(context.Background(), &r.builtResources.RestConfig.Config)
+		r.builtResources.OCClient, err = oc.New(r.ctx, &r.builtResources.RestConfig.Config)
 		if err != nil {
 			r.buildErr = OCClientError{ClusterID: r.clusterId, Err: err}
 			return r.builtResources, r.buildErr
@@ -253,7 +299,9 @@ func (r *ResourceBuilderT) Build() (*Resources, error) {
 	}
 
 	if r.buildClusterDeployment && r.builtResources.ClusterDeployment == nil {
-		r.builtResources.ClusterDeployment, err = r.ocmClient.GetClusterDeployment(internalClusterId)
+		r.builtResources.ClusterDeployment, err = cached(r.ctx, r.cache, "clusterdeployment/"+internalClusterId, func(ctx context.Context) (*hivev1.ClusterDeployment, error) {
+			return r.ocmClient.WithContext(ctx).GetClusterDeployment(internalClusterId)
+		})
 		if err != nil {
 			r.buildErr = ClusterDeploymentNotFoundError{ClusterID: r.clusterId, Err: err}
 			return r.builtResources, r.buildErr
@@ -285,7 +333,9 @@ func (r *ResourceBuilderT) buildManagementClusterResources() error {
 	r.builtResources.IsHCP = true
 	logging.Infof("Cluster %s is an HCP cluster, retrieving management cluster information", r.clusterId)
 
-	hypershiftConfig, err := r.ocmClient.GetClusterHypershiftConfig(r.builtResources.Cluster)
+	hypershiftConfig, err := cached(r.ctx, r.cache, "hypershiftconfig/"+r.builtResources.Cluster.ID(), func(ctx context.Context) (*cmv1.HypershiftConfig, error) {
+		return r.ocmClient.WithContext(ctx).GetClusterHypershiftConfig(r.builtResources.Cluster)
+	})
 	if err != nil {
 		return ManagementClusterNotFoundError{ClusterID: r.clusterId, Err: err}
 	}
@@ -314,7 +364,7 @@ func (r *ResourceBuilderT) buildManagementClusterResources() error {
 
 	if r.buildManagementRestConfig && r.builtResources.ManagementRestConfig == nil {
 		logging.Infof("Creating RestConfig for management cluster")
-		r.builtResources.ManagementRestConfig, err = r.builtResources.BpClient.GetRestConfig(context.Background(), r.builtResources.Cluster.ID(), r.name, true)
+		r.builtResources.ManagementRestConfig, err = r.builtResources.BpClient.GetRestConfig(r.ctx, r.builtResources.Cluster.ID(), r.name, true)
 		if err != nil {
 			return ManagementRestConfigError{
 				ClusterID: r.clusterId,
@@ -336,7 +386,7 @@ func (r *ResourceBuilderT) buildManagementClusterResources() error {
 
 	if r.buildManagementOCClient && r.builtResources.ManagementOCClient == nil {
 		logging.Infof("Creating OC client for management cluster of %s", r.clusterId)
-		r.builtResources.ManagementOCClient, err = oc.New(context.Background(), &r.builtResources.ManagementRestConfig.Config)
+		r.builtResources.ManagementOCClient, err = oc.New(r.ctx, &r.builtResources.ManagementRestConfig.Config)
 		if err != nil {
 			return ManagementOCClientError{
 				ClusterID: r.clusterId,
@@ -353,13 +403,17 @@ func (r *ResourceBuilderT) buildManagementClusterResources() error {
 
 	r.builtResources.ManagementClusterName = managementClusterName
 
-	managementCluster, err := r.ocmClient.GetClusterInfo(managementClusterName)
+	managementCluster, err := cached(r.ctx, r.cache, "cluster/"+managementClusterName, func(ctx context.Context) (*cmv1.Cluster, error) {
+		return r.ocmClient.WithContext(ctx).GetClusterInfo(managementClusterName)
+	})
 	if err != nil {
 		logging.Warnf("Failed to get management cluster info for Dynatrace URL: %v", err)
 		return nil
 	}
 
-	dynatraceURL, err := r.ocmClient.GetDynatraceURL(managementCluster)
+	dynatraceURL, err := cached(r.ctx, r.cache, "dynatrace/"+managementClusterName, func(ctx context.Context) (string, error) {
+		return r.ocmClient.WithContext(ctx).GetDynatraceURL(managementCluster)
+	})
 	if err != nil {
 		logging.Warnf("Failed to get Dynatrace URL: %v", err)
 		return nil
@@ -387,6 +441,10 @@ func (r *ResourceBuilderMock) WithAwsClient() ResourceBuilder {
 	return r
 }
 
+func (r *ResourceBuilderMock) WithGcpClient() ResourceBuilder {
+	return r
+}
+
 func (r *ResourceBuilderMock) WithRestConfig() ResourceBuilder {
 	return r
 }
@@ -403,8 +461,8 @@ func (r *ResourceBuilderMock) WithK8sClient() ResourceBuilder {
 	return r
 }
 
-func (r *ResourceBuilderMock) WithPdClient(client pagerduty.Client) ResourceBuilder {
-	r.Resources.PdClient = client
+func (r *ResourceBuilderMock) WithIncident(backend incident.Backend) ResourceBuilder {
+	r.Resources.Incident = backend
 	return r
 }
 
@@ -420,6 +478,10 @@ func (r *ResourceBuilderMock) WithManagementOCClient() ResourceBuilder {
 	return r
 }
 
+func (r *ResourceBuilderMock) WithContext(context.Context) ResourceBuilder {
+	return r
+}
+
 func (r *ResourceBuilderMock) Build() (*Resources, error) {
 	if r.BuildError != nil {
 		return r.Resources, r.BuildError
@@ -444,7 +506,9 @@ func (r *ResourceBuilderT) detectClusterType() {
 	}
 
 	// For non-HCP clusters, check if it's a management/service/hive cluster
-	isManaging, err := r.ocmClient.IsManagingCluster(internalID)
+	isManaging, err := cached(r.ctx, r.cache, "managing/"+internalID, func(ctx context.Context) (bool, error) {
+		return r.ocmClient.WithContext(ctx).IsManagingCluster(internalID)
+	})
 	if err != nil {
 		logging.Warnf("Failed to check if cluster %s is a managing cluster: %v. Assuming it IS a managing cluster (fail-closed).", internalID, err)
 		r.builtResources.IsInfrastructureCluster = true
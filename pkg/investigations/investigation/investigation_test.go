@@ -125,7 +125,7 @@ func TestResourceBuilder_ErrorCaching(t *testing.T) {
 		pipelineName: "test-pipeline",
 		ocmClient:    nil,
 		builtResources: &Resources{
-			PdClient:  mockPDClient,
+			Incident:  mockPDClient,
 			OcmClient: nil,
 		},
 		buildErr: ClusterNotFoundError{
@@ -136,7 +136,7 @@ func TestResourceBuilder_ErrorCaching(t *testing.T) {
 
 	// First Build() should return the cached error without attempting any operations
 	resources1, err1 := rb.Build()
-	assert.Equal(t, &Resources{PdClient: mockPDClient}, resources1)
+	assert.Equal(t, &Resources{Incident: mockPDClient}, resources1)
 	assert.Error(t, err1)
 
 	var clusterNotFoundErr ClusterNotFoundError
@@ -146,7 +146,7 @@ func TestResourceBuilder_ErrorCaching(t *testing.T) {
 
 	// Second Build() should also return the cached error
 	resources2, err2 := rb.Build()
-	assert.Equal(t, &Resources{PdClient: mockPDClient}, resources2)
+	assert.Equal(t, &Resources{Incident: mockPDClient}, resources2)
 	assert.Error(t, err2)
 	assert.ErrorAs(t, err2, &clusterNotFoundErr)
 
@@ -178,14 +178,14 @@ func TestResourceBuilder_Build_ReturnsClusterNotFoundError(t *testing.T) {
 		pipelineName: "test-pipeline",
 		ocmClient:    nil, // This would normally be set
 		builtResources: &Resources{
-			PdClient:  mockPDClient,
+			Incident:  mockPDClient,
 			OcmClient: nil,
 		},
 		buildErr: testErr,
 	}
 
 	resources, err := rb.Build()
-	assert.Equal(t, &Resources{PdClient: mockPDClient}, resources)
+	assert.Equal(t, &Resources{Incident: mockPDClient}, resources)
 	assert.Error(t, err)
 
 	// Verify the error is of the correct type
@@ -251,7 +251,7 @@ func TestResourceBuilder_Build_NameIsSet(t *testing.T) {
 		pipelineName: "test-pipeline",
 		ocmClient:    nil,
 		builtResources: &Resources{
-			PdClient:  mockPDClient,
+			Incident:  mockPDClient,
 			OcmClient: nil,
 		},
 	}
@@ -341,7 +341,7 @@ func TestWithManagementRestConfig_SetsFlags(t *testing.T) {
 		logLevel:     "info",
 		pipelineName: "test-pipeline",
 		builtResources: &Resources{
-			PdClient: mockPDClient,
+			Incident: mockPDClient,
 		},
 	}
 
@@ -369,7 +369,7 @@ func TestWithManagementOCClient_SetsFlags(t *testing.T) {
 		logLevel:     "info",
 		pipelineName: "test-pipeline",
 		builtResources: &Resources{
-			PdClient: mockPDClient,
+			Incident: mockPDClient,
 		},
 	}
 
@@ -398,7 +398,7 @@ func TestWithManagementOCClient_Chaining(t *testing.T) {
 		logLevel:     "info",
 		pipelineName: "test-pipeline",
 		builtResources: &Resources{
-			PdClient: mockPDClient,
+			Incident: mockPDClient,
 		},
 	}
 
@@ -0,0 +1,97 @@
+package investigation
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParamType is the type of an investigation parameter's value
+type ParamType string
+
+const (
+	ParamString ParamType = "string"
+	ParamBool   ParamType = "bool"
+	ParamInt    ParamType = "int"
+)
+
+// Param declares a parameter an investigation accepts through Resources.Params
+type Param struct {
+	Name        string
+	Type        ParamType
+	Default     string // Empty if the parameter is unset by default
+	Description string
+	// Allowed restricts the values of the parameter. Any value of the type is allowed if empty.
+	Allowed []string
+}
+
+// ResolveParams validates params against the schema of an investigation and returns them
+// with defaults filled in. Unknown keys, values that don't parse as the declared type and
+// values that aren't allowed are errors. Bool values are normalized to "true" or "false".
+func ResolveParams(schema []Param, params map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(schema))
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		index := slices.IndexFunc(schema, func(p Param) bool { return p.Name == key })
+		if index < 0 {
+			errs = append(errs, unknownParamError(schema, key))
+			continue
+		}
+		value, err := schema[index].parse(params[key])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		resolved[key] = value
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	for _, p := range schema {
+		if _, ok := resolved[p.Name]; !ok && p.Default != "" {
+			resolved[p.Name] = p.Default
+		}
+	}
+	return resolved, nil
+}
+
+func (p Param) parse(value string) (string, error) {
+	switch p.Type {
+	case ParamBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("parameter %s: %q is not a bool", p.Name, value)
+		}
+		value = strconv.FormatBool(b)
+	case ParamInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("parameter %s: %q is not an int", p.Name, value)
+		}
+	}
+	if len(p.Allowed) > 0 && !slices.Contains(p.Allowed, value) {
+		return "", fmt.Errorf("parameter %s: %q is not one of %s", p.Name, value, strings.Join(p.Allowed, ", "))
+	}
+	return value, nil
+}
+
+func unknownParamError(schema []Param, key string) error {
+	if len(schema) == 0 {
+		return fmt.Errorf("unknown parameter %s: the investigation takes no parameters", key)
+	}
+	names := make([]string, 0, len(schema))
+	for _, p := range schema {
+		names = append(names, p.Name)
+	}
+	return fmt.Errorf("unknown parameter %s, valid parameters: %s", key, strings.Join(names, ", "))
+}
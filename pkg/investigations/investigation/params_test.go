@@ -0,0 +1,71 @@
+package investigation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = []Param{
+	{Name: "NODES", Type: ParamString},
+	{Name: "MASTER", Type: ParamBool, Default: "false"},
+	{Name: "LIMIT", Type: ParamInt, Default: "10"},
+	{Name: "MODE", Type: ParamString, Default: "fast", Allowed: []string{"fast", "full"}},
+}
+
+func TestResolveParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  []Param
+		params  map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			schema: testSchema,
+			want:   map[string]string{"MASTER": "false", "LIMIT": "10", "MODE": "fast"},
+		},
+		{
+			name:   "values override defaults and bools are normalized",
+			schema: testSchema,
+			params: map[string]string{"NODES": "a,b", "MASTER": "1", "LIMIT": "3", "MODE": "full"},
+			want:   map[string]string{"NODES": "a,b", "MASTER": "true", "LIMIT": "3", "MODE": "full"},
+		},
+		{
+			name:    "unknown key",
+			schema:  testSchema,
+			params:  map[string]string{"NODE": "a"},
+			wantErr: "unknown parameter NODE, valid parameters: NODES, MASTER, LIMIT, MODE",
+		},
+		{
+			name:    "no schema",
+			params:  map[string]string{"NODES": "a"},
+			wantErr: "unknown parameter NODES: the investigation takes no parameters",
+		},
+		{
+			name:    "wrong types",
+			schema:  testSchema,
+			params:  map[string]string{"MASTER": "yes", "LIMIT": "many"},
+			wantErr: "parameter LIMIT: \"many\" is not an int\nparameter MASTER: \"yes\" is not a bool",
+		},
+		{
+			name:    "value not allowed",
+			schema:  testSchema,
+			params:  map[string]string{"MODE": "slow"},
+			wantErr: "parameter MODE: \"slow\" is not one of fast, full",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveParams(tt.schema, tt.params)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
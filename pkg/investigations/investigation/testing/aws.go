@@ -0,0 +1,262 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	cloudtrailv2types "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	ec2v2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+)
+
+// AWSData is the content of a customer AWS account served by FakeAWSClient.
+// Lookups of resources that aren't present return an error, like the real client.
+type AWSData struct {
+	Region string
+
+	RunningInstances    []ec2v2types.Instance
+	NonRunningInstances []ec2v2types.Instance
+	StopEvents          []cloudtrailv2types.Event
+	// Events are served by LookupResourceEvents, filtered by name and time
+	Events []cloudtrailv2types.Event
+	// EventsTruncated is returned by LookupResourceEvents
+	EventsTruncated bool
+
+	SecurityGroupID string
+	SubnetIDs       []string
+	PrivateSubnets  map[string]bool                  // subnet ID -> private
+	RouteTables     map[string]ec2v2types.RouteTable // subnet ID -> route table
+	DhcpDomainNames []string
+
+	HostedZones        map[string]string // DNS name -> hosted zone ID
+	ResourceRecordSets map[string]bool   // "<zone ID>/<name>/<type>" -> present
+
+	NLBs                   map[string]FakeLoadBalancer        // DNS name -> NLB
+	CLBs                   map[string]FakeLoadBalancer        // DNS name -> CLB
+	NLBTargetHealth        map[string][]aws.NLBTargetHealth   // NLB ARN -> targets
+	CLBInstanceHealth      map[string][]aws.CLBInstanceHealth // CLB name -> instances
+	SecurityGroups         []ec2v2types.SecurityGroup
+	InstanceSecurityGroups map[string][]string // instance ID -> security group IDs
+	InstanceStatuses       []aws.InstanceStatus
+	VpcNetworkResources    aws.VpcNetworkResources
+	IAMRoles               map[string]aws.IAMRole // role name -> role
+	OIDCProviderARNs       []string
+	ServiceQuotas          map[string]aws.ServiceQuota // "<service code>/<quota code>" -> quota
+	EC2ResourceUsage       aws.EC2ResourceUsage
+
+	// Err, if set, is returned by every call
+	Err error
+}
+
+// FakeLoadBalancer is a load balancer served by FakeAWSClient
+type FakeLoadBalancer struct {
+	ARN            string
+	Name           string
+	SecurityGroups []string
+}
+
+// FakeAWSClient implements aws.Client on top of AWSData
+type FakeAWSClient struct {
+	Data AWSData
+}
+
+var _ aws.Client = &FakeAWSClient{}
+
+func (c *FakeAWSClient) ListRunningInstances(_ context.Context, _ string) ([]ec2v2types.Instance, error) {
+	return c.Data.RunningInstances, c.Data.Err
+}
+
+func (c *FakeAWSClient) ListNonRunningInstances(_ context.Context, _ string) ([]ec2v2types.Instance, error) {
+	return c.Data.NonRunningInstances, c.Data.Err
+}
+
+func (c *FakeAWSClient) PollInstanceStopEventsFor(_ context.Context, _ []ec2v2types.Instance, _ int) ([]cloudtrailv2types.Event, error) {
+	return c.Data.StopEvents, c.Data.Err
+}
+
+func (c *FakeAWSClient) GetBaseConfig() *awsv2.Config {
+	return &awsv2.Config{Region: c.Data.Region}
+}
+
+func (c *FakeAWSClient) GetSecurityGroupID(_ context.Context, infraID string) (string, error) {
+	if c.Data.Err != nil {
+		return "", c.Data.Err
+	}
+	if c.Data.SecurityGroupID == "" {
+		return "", fmt.Errorf("no security group found for %s", infraID)
+	}
+	return c.Data.SecurityGroupID, nil
+}
+
+func (c *FakeAWSClient) GetSubnetID(_ context.Context, infraID string) ([]string, error) {
+	if c.Data.Err != nil {
+		return nil, c.Data.Err
+	}
+	if len(c.Data.SubnetIDs) == 0 {
+		return nil, fmt.Errorf("no subnets found for %s", infraID)
+	}
+	return c.Data.SubnetIDs, nil
+}
+
+func (c *FakeAWSClient) IsSubnetPrivate(_ context.Context, subnet string) (bool, error) {
+	return c.Data.PrivateSubnets[subnet], c.Data.Err
+}
+
+func (c *FakeAWSClient) GetRouteTableForSubnet(_ context.Context, subnetID string) (ec2v2types.RouteTable, error) {
+	if c.Data.Err != nil {
+		return ec2v2types.RouteTable{}, c.Data.Err
+	}
+	table, ok := c.Data.RouteTables[subnetID]
+	if !ok {
+		return ec2v2types.RouteTable{}, fmt.Errorf("no route table found for subnet %s", subnetID)
+	}
+	return table, nil
+}
+
+func (c *FakeAWSClient) FindHostedZone(_ context.Context, dnsName string, _ bool) (string, error) {
+	if c.Data.Err != nil {
+		return "", c.Data.Err
+	}
+	id, ok := c.Data.HostedZones[dnsName]
+	if !ok {
+		return "", fmt.Errorf("no hosted zone found for %s", dnsName)
+	}
+	return id, nil
+}
+
+func (c *FakeAWSClient) HasResourceRecordSet(_ context.Context, hostedZoneID, recordName, recordType string) (bool, error) {
+	return c.Data.ResourceRecordSets[hostedZoneID+"/"+recordName+"/"+recordType], c.Data.Err
+}
+
+func (c *FakeAWSClient) GetVpcDhcpConfiguration(_ context.Context, _ string) ([]string, error) {
+	return c.Data.DhcpDomainNames, c.Data.Err
+}
+
+func (c *FakeAWSClient) FindNLBByDNSName(_ context.Context, dnsName string) (string, string, []string, error) {
+	if c.Data.Err != nil {
+		return "", "", nil, c.Data.Err
+	}
+	lb, ok := c.Data.NLBs[dnsName]
+	if !ok {
+		return "", "", nil, fmt.Errorf("no NLB found for %s", dnsName)
+	}
+	return lb.ARN, lb.Name, lb.SecurityGroups, nil
+}
+
+func (c *FakeAWSClient) FindCLBByDNSName(_ context.Context, dnsName string) (string, []string, error) {
+	if c.Data.Err != nil {
+		return "", nil, c.Data.Err
+	}
+	lb, ok := c.Data.CLBs[dnsName]
+	if !ok {
+		return "", nil, fmt.Errorf("no CLB found for %s", dnsName)
+	}
+	return lb.Name, lb.SecurityGroups, nil
+}
+
+func (c *FakeAWSClient) GetNLBTargetHealth(_ context.Context, lbARN string) ([]aws.NLBTargetHealth, error) {
+	return c.Data.NLBTargetHealth[lbARN], c.Data.Err
+}
+
+func (c *FakeAWSClient) GetCLBInstanceHealth(_ context.Context, lbName string) ([]aws.CLBInstanceHealth, error) {
+	return c.Data.CLBInstanceHealth[lbName], c.Data.Err
+}
+
+func (c *FakeAWSClient) GetSecurityGroupRules(_ context.Context, sgIDs []string) ([]ec2v2types.SecurityGroup, error) {
+	if c.Data.Err != nil {
+		return nil, c.Data.Err
+	}
+	var groups []ec2v2types.SecurityGroup
+	for _, group := range c.Data.SecurityGroups {
+		for _, id := range sgIDs {
+			if awsv2.ToString(group.GroupId) == id {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups, nil
+}
+
+func (c *FakeAWSClient) GetInstanceSecurityGroupIDs(_ context.Context, instanceIDs []string) ([]string, error) {
+	if c.Data.Err != nil {
+		return nil, c.Data.Err
+	}
+	var ids []string
+	for _, instanceID := range instanceIDs {
+		ids = append(ids, c.Data.InstanceSecurityGroups[instanceID]...)
+	}
+	return ids, nil
+}
+
+func (c *FakeAWSClient) DescribeInstanceStatus(_ context.Context, instanceIDs []string) ([]aws.InstanceStatus, error) {
+	if c.Data.Err != nil {
+		return nil, c.Data.Err
+	}
+	var statuses []aws.InstanceStatus
+	for _, status := range c.Data.InstanceStatuses {
+		if slices.Contains(instanceIDs, status.InstanceID) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+func (c *FakeAWSClient) LookupResourceEvents(_ context.Context, _ []string, eventNames []string, since time.Time) ([]cloudtrailv2types.Event, bool, error) {
+	if c.Data.Err != nil {
+		return nil, false, c.Data.Err
+	}
+	var events []cloudtrailv2types.Event
+	for _, event := range c.Data.Events {
+		if slices.Contains(eventNames, awsv2.ToString(event.EventName)) && !awsv2.ToTime(event.EventTime).Before(since) {
+			events = append(events, event)
+		}
+	}
+	return events, c.Data.EventsTruncated, nil
+}
+
+func (c *FakeAWSClient) GetVpcNetworkResources(_ context.Context, infraID string) (aws.VpcNetworkResources, error) {
+	if c.Data.Err != nil {
+		return aws.VpcNetworkResources{}, c.Data.Err
+	}
+	if c.Data.VpcNetworkResources.VpcID == "" {
+		return aws.VpcNetworkResources{}, fmt.Errorf("no VPC found for %s", infraID)
+	}
+	return c.Data.VpcNetworkResources, nil
+}
+
+func (c *FakeAWSClient) GetIAMRole(_ context.Context, roleName string) (*aws.IAMRole, error) {
+	if c.Data.Err != nil {
+		return nil, c.Data.Err
+	}
+	role, ok := c.Data.IAMRoles[roleName]
+	if !ok {
+		return nil, nil
+	}
+	return &role, nil
+}
+
+func (c *FakeAWSClient) OIDCProviderExists(_ context.Context, providerARN string) (bool, error) {
+	if c.Data.Err != nil {
+		return false, c.Data.Err
+	}
+	return slices.Contains(c.Data.OIDCProviderARNs, providerARN), nil
+}
+
+func (c *FakeAWSClient) GetServiceQuota(_ context.Context, serviceCode, quotaCode string) (aws.ServiceQuota, error) {
+	if c.Data.Err != nil {
+		return aws.ServiceQuota{}, c.Data.Err
+	}
+	quota, ok := c.Data.ServiceQuotas[serviceCode+"/"+quotaCode]
+	if !ok {
+		return aws.ServiceQuota{}, fmt.Errorf("service quota %s/%s not found", serviceCode, quotaCode)
+	}
+	return quota, nil
+}
+
+func (c *FakeAWSClient) GetEC2ResourceUsage(_ context.Context) (aws.EC2ResourceUsage, error) {
+	return c.Data.EC2ResourceUsage, c.Data.Err
+}
@@ -0,0 +1,54 @@
+package testing
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LoadManifests decodes the objects of the given YAML files with scheme.
+// Files may contain several documents separated by "---"; empty documents are skipped.
+func LoadManifests(scheme *runtime.Scheme, paths ...string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	var objects []client.Object
+	for _, path := range paths {
+		data, err := os.ReadFile(path) //nolint:gosec // test manifests
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+
+		reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+		for i := 0; ; i++ {
+			doc, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: failed to read document %d: %w", path, i, err)
+			}
+			if len(bytes.TrimSpace(doc)) == 0 {
+				continue
+			}
+
+			obj, _, err := decoder.Decode(doc, nil, nil)
+			if err != nil {
+				return nil, fmt.Errorf("%s: failed to decode document %d: %w", path, i, err)
+			}
+			clientObj, ok := obj.(client.Object)
+			if !ok {
+				return nil, fmt.Errorf("%s: document %d is not a Kubernetes object", path, i)
+			}
+			objects = append(objects, clientObj)
+		}
+	}
+	return objects, nil
+}
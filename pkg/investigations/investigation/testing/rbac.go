@@ -0,0 +1,232 @@
+package testing
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	gotesting "testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
+)
+
+// Metadata is the RBAC part of an investigation's metadata.yaml, which backplane uses to
+// scope the remediation of the investigation
+type Metadata struct {
+	Name                  string         `yaml:"name"`
+	RBAC                  RBAC           `yaml:"rbac"`
+	ManagementClusterRBAC ManagementRBAC `yaml:"managementClusterRbac"`
+	CustomerDataAccess    bool           `yaml:"customerDataAccess"`
+}
+
+// RBAC holds the rules granted on a cluster
+type RBAC struct {
+	Roles            []Role       `yaml:"roles"`
+	ClusterRoleRules []PolicyRule `yaml:"clusterRoleRules"`
+}
+
+// Role holds rules granted in a single namespace
+type Role struct {
+	Namespace string       `yaml:"namespace"`
+	Rules     []PolicyRule `yaml:"rules"`
+}
+
+// ManagementRBAC holds the rules granted on the management cluster of HCP clusters.
+// HCPNamespace and HCNamespace rules are granted in the namespaces of the hosted cluster.
+type ManagementRBAC struct {
+	RBAC         RBAC         `yaml:"rbac"`
+	HCPNamespace []PolicyRule `yaml:"hcpNamespace"`
+	HCNamespace  []PolicyRule `yaml:"hcNamespace"`
+}
+
+// PolicyRule is a Kubernetes RBAC rule
+type PolicyRule struct {
+	Verbs         []string `yaml:"verbs"`
+	APIGroups     []string `yaml:"apiGroups"`
+	Resources     []string `yaml:"resources"`
+	ResourceNames []string `yaml:"resourceNames"`
+}
+
+// LoadMetadata reads an investigation's metadata.yaml
+func LoadMetadata(path string) (*Metadata, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // metadata paths are provided by tests
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	metadata := &Metadata{}
+	if err := yaml.Unmarshal(data, metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return metadata, nil
+}
+
+// RBACUsage collects the k8s requests of scenario runs. As a single scenario rarely covers
+// every path of an investigation, rules are only reported as unused across all runs.
+type RBACUsage struct {
+	mu         sync.Mutex
+	cluster    []k8sclient.Access
+	management []k8sclient.Access
+}
+
+// Run runs inv against the scenario like Run, and records the requests it makes
+func (u *RBACUsage) Run(t gotesting.TB, inv investigation.Investigation, scenario Scenario) investigation.InvestigationResult {
+	t.Helper()
+
+	builder := NewResourceBuilder(t, scenario)
+	result, err := inv.Run(builder)
+	u.Record(builder)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", inv.Name(), err)
+	}
+	return result
+}
+
+// Record adds the requests made through builder
+func (u *RBACUsage) Record(builder *ResourceBuilder) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.cluster = append(u.cluster, builder.Accesses()...)
+	u.management = append(u.management, builder.ManagementAccesses()...)
+}
+
+// RBACOptions tunes CheckRBAC
+type RBACOptions struct {
+	// Uncovered lists permissions, formatted like k8sclient.Access.String() (e.g. "create pods/exec"),
+	// that are used outside of the k8s client or on paths no scenario covers. They aren't reported as unused.
+	Uncovered []string
+}
+
+// CheckRBAC compares the recorded requests with the RBAC declared in the metadata.yaml at
+// metadataPath. It returns a problem for every request no rule allows, and for every
+// permission no request used. Resource names and wildcards aren't checked for use.
+func CheckRBAC(metadataPath string, usage *RBACUsage, opts RBACOptions) ([]string, error) {
+	metadata, err := LoadMetadata(metadataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+
+	var problems []string
+	problems = append(problems, checkScope("cluster", clusterGrants(metadata.RBAC), usage.cluster, opts)...)
+	management := clusterGrants(metadata.ManagementClusterRBAC.RBAC)
+	for _, rules := range [][]PolicyRule{metadata.ManagementClusterRBAC.HCPNamespace, metadata.ManagementClusterRBAC.HCNamespace} {
+		for _, rule := range rules {
+			management = append(management, grant{rule: rule, anyNamespace: true})
+		}
+	}
+	problems = append(problems, checkScope("management cluster", management, usage.management, opts)...)
+
+	sort.Strings(problems)
+	return slices.Compact(problems), nil
+}
+
+// ExpectRBAC fails the test for every problem CheckRBAC reports
+func ExpectRBAC(t gotesting.TB, metadataPath string, usage *RBACUsage, opts RBACOptions) {
+	t.Helper()
+
+	problems, err := CheckRBAC(metadataPath, usage, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range problems {
+		t.Errorf("%s: %s", metadataPath, problem)
+	}
+}
+
+// grant is a rule together with where it applies
+type grant struct {
+	rule PolicyRule
+	// namespace limits the rule to a namespace. Cluster role rules apply everywhere.
+	namespace   string
+	clusterWide bool
+	// anyNamespace is set for rules granted in namespaces only known at runtime, like the HCP namespace
+	anyNamespace bool
+}
+
+func clusterGrants(rbac RBAC) []grant {
+	var grants []grant
+	for _, rule := range rbac.ClusterRoleRules {
+		grants = append(grants, grant{rule: rule, clusterWide: true})
+	}
+	for _, role := range rbac.Roles {
+		for _, rule := range role.Rules {
+			grants = append(grants, grant{rule: rule, namespace: role.Namespace})
+		}
+	}
+	return grants
+}
+
+func (g grant) appliesTo(namespace string) bool {
+	switch {
+	case g.clusterWide:
+		return true
+	case g.anyNamespace:
+		return namespace != ""
+	default:
+		return namespace == g.namespace
+	}
+}
+
+func (g grant) allows(access k8sclient.Access) bool {
+	return g.appliesTo(access.Namespace) &&
+		matches(g.rule.Verbs, access.Verb) &&
+		matches(g.rule.APIGroups, access.Group) &&
+		matches(g.rule.Resources, access.Resource)
+}
+
+func (g grant) String() string {
+	switch {
+	case g.clusterWide:
+		return "cluster-wide"
+	case g.anyNamespace:
+		return "in the hosted cluster namespaces"
+	default:
+		return "in namespace " + g.namespace
+	}
+}
+
+func matches(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+func checkScope(scope string, grants []grant, accesses []k8sclient.Access, opts RBACOptions) []string {
+	var problems []string
+	for _, access := range accesses {
+		if !slices.ContainsFunc(grants, func(g grant) bool { return g.allows(access) }) {
+			where := "cluster-wide"
+			if access.Namespace != "" {
+				where = "in namespace " + access.Namespace
+			}
+			problems = append(problems, fmt.Sprintf("%s: %s %s is not allowed by any rule", scope, access, where))
+		}
+	}
+
+	for _, g := range grants {
+		for _, verb := range g.rule.Verbs {
+			for _, group := range g.rule.APIGroups {
+				for _, resource := range g.rule.Resources {
+					if verb == "*" || group == "*" || resource == "*" {
+						continue
+					}
+					permission := k8sclient.Access{Verb: verb, Group: group, Resource: resource}
+					if slices.Contains(opts.Uncovered, permission.String()) {
+						continue
+					}
+					used := slices.ContainsFunc(accesses, func(a k8sclient.Access) bool {
+						return a.Verb == verb && a.Group == group && a.Resource == resource && g.appliesTo(a.Namespace)
+					})
+					if !used {
+						problems = append(problems, fmt.Sprintf("%s: %s %s is granted but never used", scope, permission, g))
+					}
+				}
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,48 @@
+package testing
+
+import (
+	"slices"
+	gotesting "testing"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+)
+
+// Run runs inv against the scenario and fails the test if the investigation returns an error
+func Run(t gotesting.TB, inv investigation.Investigation, scenario Scenario) investigation.InvestigationResult {
+	t.Helper()
+
+	result, err := inv.Run(NewResourceBuilder(t, scenario))
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", inv.Name(), err)
+	}
+	return result
+}
+
+// ActionTypes returns the types of the actions of result, in order
+func ActionTypes(result investigation.InvestigationResult) []executor.ActionType {
+	types := make([]executor.ActionType, 0, len(result.Actions))
+	for _, action := range result.Actions {
+		types = append(types, executor.ActionType(action.Type()))
+	}
+	return types
+}
+
+// ExpectActions fails the test unless result has exactly the given action types, in order
+func ExpectActions(t gotesting.TB, result investigation.InvestigationResult, want ...executor.ActionType) {
+	t.Helper()
+
+	if got := ActionTypes(result); !slices.Equal(got, want) {
+		t.Errorf("expected actions %v, got %v", want, got)
+	}
+}
+
+// NoteContent returns the content of the first note action of result, or an empty string
+func NoteContent(result investigation.InvestigationResult) string {
+	for _, action := range result.Actions {
+		if note, ok := action.(*executor.PagerDutyNoteAction); ok {
+			return note.ResolvedContent()
+		}
+	}
+	return ""
+}
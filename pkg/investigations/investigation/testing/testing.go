@@ -0,0 +1,303 @@
+// Package testing provides a ResourceBuilder backed by fake cluster, OCM and AWS data,
+// so investigations can be tested as scenarios: input manifests in, expected actions out.
+//
+// Cluster objects are served by a controller-runtime fake client that uses the same scheme as
+// the real k8s client (k8sclient.NewScheme), so a type missing from that scheme fails here too.
+package testing
+
+import (
+	"context"
+	gotesting "testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+	"github.com/openshift/configuration-anomaly-detection/pkg/oc"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+)
+
+// DefaultClusterID is the ID of the cluster used when a Scenario doesn't set one
+const DefaultClusterID = "test-cluster"
+
+// Scenario describes the state an investigation runs against
+type Scenario struct {
+	// Name is the investigation name used for notes, defaults to "test"
+	Name string
+
+	// Cluster is the OCM cluster, defaults to a cluster with DefaultClusterID
+	Cluster           *cmv1.Cluster
+	ClusterDeployment *hivev1.ClusterDeployment
+
+	// Manifests are YAML files, possibly with several documents, loaded into the fake cluster
+	Manifests []string
+	// Objects are loaded into the fake cluster in addition to Manifests
+	Objects []client.Object
+
+	// ManagementManifests and ManagementObjects make up the fake management cluster of HCP clusters
+	ManagementManifests []string
+	ManagementObjects   []client.Object
+	HCPNamespace        string
+
+	// AWS is the data served by the fake AWS client. AwsClient takes precedence if set.
+	AWS       *AWSData
+	AwsClient aws.Client
+	// GcpClient serves GCP clusters, usually a gcpmock.MockClient
+	GcpClient gcp.Client
+
+	OcmClient ocm.Client
+	BpClient  backplane.Client
+	OCClient  oc.Client
+	Incident  incident.Backend
+	Params    map[string]string
+
+	// BuildError is returned by Build along with the resources
+	BuildError error
+}
+
+// ResourceBuilder is an investigation.ResourceBuilder serving a Scenario.
+// Like the real builder, it only populates the resources that were requested.
+type ResourceBuilder struct {
+	scenario   Scenario
+	client     client.Client
+	management client.Client
+
+	// Investigations get recording clients, so tests can check the RBAC they need
+	recorder           *k8sclient.RecordingClient
+	managementRecorder *k8sclient.RecordingClient
+
+	buildCluster           bool
+	buildClusterDeployment bool
+	buildAwsClient         bool
+	buildGcpClient         bool
+	buildRestConfig        bool
+	buildK8sClient         bool
+	buildOC                bool
+	buildNotes             bool
+	buildManagementConfig  bool
+	buildManagementClient  bool
+	buildManagementOC      bool
+
+	resources *investigation.Resources
+}
+
+var _ investigation.ResourceBuilder = &ResourceBuilder{}
+
+// NewResourceBuilder creates a ResourceBuilder for the scenario. Manifests that can't be loaded fail the test.
+func NewResourceBuilder(t gotesting.TB, scenario Scenario) *ResourceBuilder {
+	t.Helper()
+
+	if scenario.Name == "" {
+		scenario.Name = "test"
+	}
+	if scenario.Cluster == nil {
+		scenario.Cluster = NewCluster(cmv1.NewCluster())
+	}
+	if scenario.Params == nil {
+		scenario.Params = map[string]string{}
+	}
+	if scenario.AwsClient == nil && scenario.AWS != nil {
+		scenario.AwsClient = &FakeAWSClient{Data: *scenario.AWS}
+	}
+
+	cluster := newFakeClient(t, scenario.Manifests, scenario.Objects)
+	management := newFakeClient(t, scenario.ManagementManifests, scenario.ManagementObjects)
+	return &ResourceBuilder{
+		scenario:           scenario,
+		client:             cluster,
+		management:         management,
+		recorder:           k8sclient.NewRecordingClient(cluster),
+		managementRecorder: k8sclient.NewRecordingClient(management),
+		resources: &investigation.Resources{
+			Name:      scenario.Name,
+			OcmClient: scenario.OcmClient,
+			BpClient:  scenario.BpClient,
+			Incident:  scenario.Incident,
+			Params:    scenario.Params,
+		},
+	}
+}
+
+// NewCluster builds an OCM cluster from builder, setting DefaultClusterID if it has no ID.
+// It panics if the cluster can't be built, which only happens for invalid test input.
+func NewCluster(builder *cmv1.ClusterBuilder) *cmv1.Cluster {
+	cluster, err := builder.Build()
+	if err != nil {
+		panic(err)
+	}
+	if cluster.ID() == "" {
+		cluster, err = builder.ID(DefaultClusterID).Build()
+		if err != nil {
+			panic(err)
+		}
+	}
+	return cluster
+}
+
+func newFakeClient(t gotesting.TB, manifests []string, objects []client.Object) client.Client {
+	t.Helper()
+
+	scheme, err := k8sclient.NewScheme()
+	if err != nil {
+		t.Fatalf("failed to create scheme: %v", err)
+	}
+	loaded, err := LoadManifests(scheme, manifests...)
+	if err != nil {
+		t.Fatalf("failed to load manifests: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(loaded, objects...)...).Build()
+}
+
+// Client returns the fake cluster client, e.g. to assert on objects changed by an investigation.
+// Requests made through it aren't recorded.
+func (r *ResourceBuilder) Client() client.Client {
+	return r.client
+}
+
+// ManagementClient returns the fake management cluster client
+func (r *ResourceBuilder) ManagementClient() client.Client {
+	return r.management
+}
+
+// Accesses returns the cluster requests the investigation made through its k8s client
+func (r *ResourceBuilder) Accesses() []k8sclient.Access {
+	return r.recorder.Accesses()
+}
+
+// ManagementAccesses returns the management cluster requests the investigation made through its k8s client
+func (r *ResourceBuilder) ManagementAccesses() []k8sclient.Access {
+	return r.managementRecorder.Accesses()
+}
+
+func (r *ResourceBuilder) WithCluster() investigation.ResourceBuilder {
+	r.buildCluster = true
+	return r
+}
+
+func (r *ResourceBuilder) WithClusterDeployment() investigation.ResourceBuilder {
+	r.WithCluster()
+	r.buildClusterDeployment = true
+	return r
+}
+
+func (r *ResourceBuilder) WithAwsClient() investigation.ResourceBuilder {
+	r.WithCluster()
+	r.buildAwsClient = true
+	return r
+}
+
+func (r *ResourceBuilder) WithGcpClient() investigation.ResourceBuilder {
+	r.WithCluster()
+	r.buildGcpClient = true
+	return r
+}
+
+func (r *ResourceBuilder) WithRestConfig() investigation.ResourceBuilder {
+	r.WithCluster()
+	r.buildRestConfig = true
+	return r
+}
+
+func (r *ResourceBuilder) WithK8sClient() investigation.ResourceBuilder {
+	r.WithRestConfig()
+	r.buildK8sClient = true
+	return r
+}
+
+func (r *ResourceBuilder) WithOC() investigation.ResourceBuilder {
+	r.WithRestConfig()
+	r.buildOC = true
+	return r
+}
+
+func (r *ResourceBuilder) WithNotes() investigation.ResourceBuilder {
+	r.buildNotes = true
+	return r
+}
+
+func (r *ResourceBuilder) WithIncident(backend incident.Backend) investigation.ResourceBuilder {
+	r.resources.Incident = backend
+	return r
+}
+
+func (r *ResourceBuilder) WithManagementRestConfig() investigation.ResourceBuilder {
+	r.WithCluster()
+	r.buildManagementConfig = true
+	return r
+}
+
+func (r *ResourceBuilder) WithManagementK8sClient() investigation.ResourceBuilder {
+	r.WithManagementRestConfig()
+	r.buildManagementClient = true
+	return r
+}
+
+func (r *ResourceBuilder) WithManagementOCClient() investigation.ResourceBuilder {
+	r.WithManagementRestConfig()
+	r.buildManagementOC = true
+	return r
+}
+
+func (r *ResourceBuilder) WithContext(context.Context) investigation.ResourceBuilder {
+	return r
+}
+
+func (r *ResourceBuilder) Build() (*investigation.Resources, error) {
+	res := r.resources
+	s := r.scenario
+
+	if r.buildCluster && res.Cluster == nil {
+		res.Cluster = s.Cluster
+		hypershift := s.Cluster.Hypershift()
+		res.IsHCP = hypershift != nil && hypershift.Enabled()
+	}
+	if r.buildNotes && res.Notes == nil {
+		res.Notes = notewriter.New(s.Name, nil)
+	}
+	if r.buildClusterDeployment {
+		res.ClusterDeployment = s.ClusterDeployment
+	}
+	if r.buildAwsClient {
+		res.AwsClient = s.AwsClient
+	}
+	if r.buildGcpClient {
+		res.GcpClient = s.GcpClient
+	}
+	if r.buildRestConfig && res.RestConfig == nil {
+		res.RestConfig = fakeRestConfig()
+	}
+	if r.buildK8sClient {
+		res.K8sClient = r.recorder
+	}
+	if r.buildOC {
+		res.OCClient = s.OCClient
+	}
+	if r.buildManagementConfig && res.ManagementRestConfig == nil {
+		res.ManagementRestConfig = fakeRestConfig()
+		res.HCPNamespace = s.HCPNamespace
+	}
+	if r.buildManagementClient {
+		res.ManagementK8sClient = r.managementRecorder
+	}
+	if r.buildManagementOC {
+		res.ManagementOCClient = s.OCClient
+	}
+
+	return res, s.BuildError
+}
+
+func fakeRestConfig() *backplane.RestConfig {
+	return &backplane.RestConfig{
+		Config:  rest.Config{Host: "https://api.test-cluster.invalid:6443"},
+		Cleaner: backplane.CleanerFunc(func() error { return nil }),
+	}
+}
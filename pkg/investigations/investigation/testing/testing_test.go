@@ -0,0 +1,178 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	gotesting "testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
+)
+
+func TestLoadManifests(t *gotesting.T) {
+	scheme, err := k8sclient.NewScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects, err := LoadManifests(scheme, "testdata/cluster.yaml")
+	if err != nil {
+		t.Fatalf("LoadManifests() error = %v", err)
+	}
+	if len(objects) != 3 {
+		t.Fatalf("expected 3 objects, got %d", len(objects))
+	}
+	if _, ok := objects[1].(*appsv1.ReplicaSet); !ok {
+		t.Errorf("expected a typed ReplicaSet, got %T", objects[1])
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("apiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadManifests(scheme, invalid); err == nil {
+		t.Error("expected an error for a kind missing from the scheme")
+	}
+}
+
+func TestResourceBuilder(t *gotesting.T) {
+	buildErr := errors.New("build failed")
+	rb := NewResourceBuilder(t, Scenario{
+		Name:      "example",
+		Manifests: []string{"testdata/cluster.yaml"},
+		Objects:   []client.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer"}}},
+		AWS:       &AWSData{SecurityGroupID: "sg-1"},
+	})
+
+	r, err := rb.WithNotes().Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if r.Cluster != nil || r.K8sClient != nil || r.AwsClient != nil {
+		t.Error("expected only the requested resources to be built")
+	}
+	if r.Notes == nil {
+		t.Error("expected notes")
+	}
+
+	r, _ = rb.WithK8sClient().WithAwsClient().Build()
+	if r.Cluster == nil || r.Cluster.ID() != DefaultClusterID {
+		t.Errorf("expected the default cluster, got %v", r.Cluster)
+	}
+	if r.RestConfig == nil {
+		t.Error("WithK8sClient should imply a rest config")
+	}
+
+	cv := &configv1.ClusterVersion{}
+	if err := r.K8sClient.Get(context.Background(), client.ObjectKey{Name: "version"}, cv); err != nil {
+		t.Errorf("expected the ClusterVersion from the manifests: %v", err)
+	}
+	ns := &corev1.Namespace{}
+	if err := rb.Client().Get(context.Background(), client.ObjectKey{Name: "customer"}, ns); err != nil {
+		t.Errorf("expected the Namespace from the objects: %v", err)
+	}
+	if id, err := r.AwsClient.GetSecurityGroupID(context.Background(), "infra"); err != nil || id != "sg-1" {
+		t.Errorf("unexpected security group %q (err %v)", id, err)
+	}
+	if _, err := r.AwsClient.GetSubnetID(context.Background(), "infra"); err == nil {
+		t.Error("expected an error for missing AWS data")
+	}
+
+	failing := NewResourceBuilder(t, Scenario{BuildError: buildErr})
+	if _, err := failing.WithCluster().Build(); !errors.Is(err, buildErr) {
+		t.Errorf("expected the scenario build error, got %v", err)
+	}
+}
+
+type exampleInvestigation struct{}
+
+func (exampleInvestigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	r, err := rb.WithCluster().WithK8sClient().WithNotes().Build()
+	if err != nil {
+		return investigation.InvestigationResult{}, err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.K8sClient.List(context.Background(), nodes); err != nil {
+		return investigation.InvestigationResult{}, err
+	}
+	r.Notes.AppendSuccess("%d node(s) on %s", len(nodes.Items), r.Cluster.ID())
+	return investigation.InvestigationResult{
+		Actions: []executor.Action{executor.NoteFrom(r.Notes), executor.Silence("healthy")},
+	}, nil
+}
+
+func (exampleInvestigation) Name() string { return "example" }
+
+func TestRun(t *gotesting.T) {
+	result := Run(t, exampleInvestigation{}, Scenario{
+		Name:      "example",
+		Cluster:   NewCluster(cmv1.NewCluster().ID("abc")),
+		Manifests: []string{"testdata/cluster.yaml"},
+	})
+
+	ExpectActions(t, result, executor.ActionTypePagerDutyNote, executor.ActionTypeSilenceIncident)
+	if note := NoteContent(result); !strings.Contains(note, "1 node(s) on abc") {
+		t.Errorf("unexpected note %q", note)
+	}
+}
+
+func TestCheckRBAC(t *gotesting.T) {
+	metadata := filepath.Join(t.TempDir(), "metadata.yaml")
+	err := os.WriteFile(metadata, []byte(`name: test
+rbac:
+  roles:
+    - namespace: openshift-etcd
+      rules:
+        - verbs: ["get", "list"]
+          apiGroups: [""]
+          resources: ["pods"]
+        - verbs: ["create"]
+          apiGroups: [""]
+          resources: ["pods/exec"]
+  clusterRoleRules:
+    - verbs: ["list"]
+      apiGroups: ["config.openshift.io"]
+      resources: ["clusteroperators"]
+managementClusterRbac:
+  hcpNamespace:
+    - verbs: ["list"]
+      apiGroups: ["batch"]
+      resources: ["jobs"]
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usage := &RBACUsage{
+		cluster: []k8sclient.Access{
+			{Verb: "list", Resource: "pods", Namespace: "openshift-etcd"},
+			{Verb: "list", Group: "config.openshift.io", Resource: "clusteroperators"},
+			{Verb: "list", Resource: "pods", Namespace: "default"},
+		},
+		management: []k8sclient.Access{
+			{Verb: "list", Group: "batch", Resource: "jobs", Namespace: "ocm-test-abc"},
+		},
+	}
+	problems, err := CheckRBAC(metadata, usage, RBACOptions{Uncovered: []string{"create pods/exec"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"cluster: get pods in namespace openshift-etcd is granted but never used",
+		"cluster: list pods in namespace default is not allowed by any rule",
+	}
+	if strings.Join(problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected problems\n got: %q\nwant: %q", problems, want)
+	}
+}
@@ -44,7 +44,11 @@ type Investigation struct {
 // If the investigation determines that the breakage is occurring at the machine-level, the corresponding node is *not* investigated.
 // After investigating all affected machines, potentially affected nodes are investigated.
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
-	ctx := context.Background()
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 	r, err := rb.WithK8sClient().WithCluster().WithNotes().Build()
 	if err != nil {
@@ -318,3 +322,10 @@ func (i *Investigation) InvestigateNode(node corev1.Node) {
 func (i *Investigation) Name() string {
 	return strings.ToLower(alertname)
 }
+
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Recommends an action for each machine and node blocking a short-circuited MachineHealthCheck",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+	}
+}
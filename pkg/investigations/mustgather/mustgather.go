@@ -64,6 +64,12 @@ func getAcmHcpMustGatherImage() string {
 type Investigation struct{}
 
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext collects the must-gather. The wait for a previous must-gather, the collection and
+// the upload are all cancelled with ctx.
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithNotes().WithOC().WithManagementOCClient().WithManagementK8sClient().Build()
@@ -92,7 +98,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 
 	if r.IsHCP {
 		productName = productNameHCP
-		err = waitForMustGatherNamespaceDeletion(context.Background(), r.ManagementK8sClient, mustGatherWaitTimeout, mustGatherPollInterval)
+		err = waitForMustGatherNamespaceDeletion(ctx, r.ManagementK8sClient, mustGatherWaitTimeout, mustGatherPollInterval)
 		if err != nil {
 			logging.Errorf("CAD was unable to proceed with must-gather. Error: %v", err)
 			result.Actions = []types.Action{
@@ -106,9 +112,9 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 			fmt.Sprintf("--image=%s", getAcmHcpMustGatherImage()),
 			fmt.Sprintf(AcmHcpMustGatherCommandTemplate, r.HCPNamespace, r.Cluster.Name()),
 		)
-		err = r.ManagementOCClient.CreateMustGather(mustGatherCommandFlags)
+		err = r.ManagementOCClient.CreateMustGather(ctx, mustGatherCommandFlags)
 	} else {
-		err = r.OCClient.CreateMustGather(mustGatherCommandFlags)
+		err = r.OCClient.CreateMustGather(ctx, mustGatherCommandFlags)
 	}
 	if err != nil {
 		return result, investigation.WrapInfrastructure(
@@ -151,7 +157,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	// Get SFTP credentials with retry logic to avoid re-running the must-gather
 	// on transient failures.
 	var username, token string
-	credCtx, credCancel := context.WithTimeout(context.Background(), sftpCredentialOverallTimeout)
+	credCtx, credCancel := context.WithTimeout(ctx, sftpCredentialOverallTimeout)
 	defer credCancel()
 	err = utils.WithRetriesContext(credCtx, sftpRetryAttempts, sftpRetryInitialBackoff, func() error {
 		attemptCtx, attemptCancel := context.WithTimeout(credCtx, sftpCredentialAttemptTimeout)
@@ -171,7 +177,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	// Upload with extended timeout - during testing, uploading to the SFTP server was very slow at 10 MB/min
 	// retry logic shares the overall timeout (6 hours), as to avoid multiple such long timeouts
 	// FIXME: As in improvement, CAD could use its own service account to upload to the SFTP server.
-	uploadCtx, uploadCancel := context.WithTimeout(context.Background(), sftpUploadOverallTimeout)
+	uploadCtx, uploadCancel := context.WithTimeout(ctx, sftpUploadOverallTimeout)
 	defer uploadCancel()
 	err = utils.WithRetriesContext(uploadCtx, sftpRetryAttempts, sftpRetryInitialBackoff, func() error {
 		return sftpUpload(uploadCtx, tarfile.Name(), username, token)
@@ -192,6 +198,15 @@ func (c *Investigation) Name() string {
 	return "mustgather"
 }
 
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Collects a must-gather from the cluster and uploads it to the Red Hat SFTP server",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s, investigation.ResourceManagementCluster},
+		MutatesCluster:    true,
+		NeedsCustomerData: true,
+	}
+}
+
 // waitForMustGatherNamespaceDeletion waits for any existing openshift-must-gather-* namespace to be deleted
 // from the management cluster. This ensures that a previous must-gather job has completed before starting a new one.
 // Returns an error if the namespace still exists after the timeout period.
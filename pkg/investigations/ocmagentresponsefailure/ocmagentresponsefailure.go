@@ -3,6 +3,7 @@
 package ocmagentresponsefailure
 
 import (
+	"context"
 	"errors"
 	"os"
 	"strconv"
@@ -18,7 +19,7 @@ import (
 
 type Investigation struct{}
 
-type check func(*investigation.Resources) (checkResult, error)
+type check func(context.Context, *investigation.Resources) (checkResult, error)
 
 // checkResult is returned by individual checks. It contains the set of actions
 // as determined by the check, as well as a boolean indicating whether the
@@ -29,6 +30,11 @@ type checkResult struct {
 }
 
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the checks, cancelling the requests to the cluster, AWS and OCM with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	investigationResult := investigation.InvestigationResult{}
 	r, err := rb.WithNotes().WithAwsClient().WithK8sClient().WithClusterDeployment().Build()
 	if err != nil {
@@ -61,7 +67,7 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	// Run all checks and merge their resulting actions together into the investigation result.
 	// Continue until all checks are run or a check signals the investigation should stop.
 	for _, c := range checks {
-		result, err := c(r)
+		result, err := c(ctx, r)
 		if err != nil {
 			return investigationResult, err
 		}
@@ -90,9 +96,17 @@ func (i *Investigation) Name() string {
 	return "ocmagentresponsefailure"
 }
 
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks why OCM agent cannot send service logs, e.g. blocked egress, a banned owner or an invalid pull secret",
+		Platforms:         []investigation.Platform{investigation.PlatformClassic, investigation.PlatformAWS},
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s, investigation.ResourceAWS},
+	}
+}
+
 // checkUserBanStatus checks if the cluster owner is banned.
 // It returns a set of actions, and a boolean indicating whether the investigation should halt
-func checkUserBanStatus(r *investigation.Resources) (checkResult, error) {
+func checkUserBanStatus(_ context.Context, r *investigation.Resources) (checkResult, error) {
 	experimentalEnabled, _ := strconv.ParseBool(os.Getenv("CAD_EXPERIMENTAL_ENABLED"))
 	userBannedErr := ocm.UserBannedError{}
 	err := r.OcmClient.CheckIfUserBanned(r.Cluster)
@@ -158,9 +172,9 @@ func checkUserBanStatus(r *investigation.Resources) (checkResult, error) {
 
 // validateEgress checks the cluster can reach the required endpoints.
 // It returns a set of actions, and a boolean indicating whether the investigation should halt
-func validateEgress(r *investigation.Resources) (checkResult, error) {
+func validateEgress(ctx context.Context, r *investigation.Resources) (checkResult, error) {
 	actions := []types.Action{}
-	verifierResult, failureReason, err := networkverifier.Run(r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
+	verifierResult, failureReason, err := networkverifier.Run(ctx, r.Cluster, r.ClusterDeployment, r.AwsClient, r.OcmClient)
 	if err != nil {
 		logging.Errorf("Network verifier ran into an error: %s", err.Error())
 		r.Notes.AppendWarning("NetworkVerifier failed to run:\n %s", err.Error())
@@ -190,7 +204,7 @@ func validateEgress(r *investigation.Resources) (checkResult, error) {
 
 // validatePullSecret checks the cluster pull secret is valid.
 // It returns a set of actions, and a boolean indicating whether the investigation should halt
-func validatePullSecret(r *investigation.Resources) (checkResult, error) {
+func validatePullSecret(ctx context.Context, r *investigation.Resources) (checkResult, error) {
 	actions := []types.Action{}
 	user, err := r.OcmClient.GetCreatorFromCluster(r.Cluster)
 	if err != nil {
@@ -210,7 +224,7 @@ func validatePullSecret(r *investigation.Resources) (checkResult, error) {
 
 	// Pullsecret validation done via pullsecret package
 	ocmEmail := user.Email()
-	emailValidation := pullsecret.ValidateEmail(r.K8sClient, ocmEmail)
+	emailValidation := pullsecret.ValidateEmail(ctx, r.K8sClient, ocmEmail)
 
 	for _, warning := range emailValidation.Warnings {
 		r.Notes.AppendWarning("%s", warning)
@@ -221,7 +235,7 @@ func validatePullSecret(r *investigation.Resources) (checkResult, error) {
 	}
 
 	// Registry credentials validation
-	registryValidation, registryResults := pullsecret.ValidateRegistryCredentials(r.K8sClient, r.OcmClient.GetConnection(), user.ID(), ocmEmail)
+	registryValidation, registryResults := pullsecret.ValidateRegistryCredentials(ctx, r.K8sClient, r.OcmClient.GetConnection(), user.ID(), ocmEmail)
 
 	// INFO: per-registry validation results at debug level for troubleshooting
 	for _, regResult := range registryResults {
@@ -1,6 +1,7 @@
 package ocmagentresponsefailure
 
 import (
+	"context"
 	"os"
 	"reflect"
 	"slices"
@@ -134,7 +135,7 @@ func Test_checkUserBanStatus(t *testing.T) {
 			resources := &investigation.Resources{
 				Cluster:   m.cluster,
 				OcmClient: m.ocmClient,
-				PdClient:  m.pdClient,
+				Incident:  m.pdClient,
 				AwsClient: m.awsClient,
 				Notes:     notewriter.New(tt.name, logging.RawLogger),
 			}
@@ -149,7 +150,7 @@ func Test_checkUserBanStatus(t *testing.T) {
 				}
 			})
 
-			got, err := checkUserBanStatus(resources)
+			got, err := checkUserBanStatus(context.Background(), resources)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("wanted error = %v, got %v", tt.wantErr, err)
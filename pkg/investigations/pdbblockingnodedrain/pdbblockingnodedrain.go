@@ -49,6 +49,11 @@ type blockingPDB struct {
 type Investigation struct{}
 
 func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithCluster().WithK8sClient().WithNotes().Build()
@@ -64,8 +69,6 @@ func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 		return result, investigation.WrapInfrastructure(err, "failed to build resources for "+i.Name())
 	}
 
-	ctx := context.Background()
-
 	stalledNodes := i.checkDrainingNodes(ctx, r.K8sClient, r.Notes)
 
 	if len(stalledNodes) == 0 {
@@ -439,6 +442,13 @@ func (i *Investigation) Name() string {
 	return "pdbblockingnodedrain"
 }
 
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       i.Description(),
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+	}
+}
+
 func (i *Investigation) AlertTitle() string {
 	return "HCPNodepoolUpgradeDelay"
 }
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -138,6 +140,53 @@ func TestDescription(t *testing.T) {
 	}
 }
 
+// --- Scenario tests ---
+
+func TestRun_Scenarios(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifest  string
+		wantNotes []string
+	}{
+		{
+			name:      "no draining nodes",
+			manifest:  "testdata/no-draining-nodes.yaml",
+			wantNotes: []string{"No nodes are stalled in draining state"},
+		},
+		{
+			name:     "customer PDB blocks drain",
+			manifest: "testdata/customer-pdb-blocks-drain.yaml",
+			wantNotes: []string{
+				"1/1 draining node(s) stalled",
+				"customer/app",
+				"Deployment/app",
+				"1 customer-managed PDB(s)",
+			},
+		},
+	}
+
+	usage := &invtesting.RBACUsage{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := usage.Run(t, &Investigation{}, invtesting.Scenario{
+				Name:      "pdbblockingnodedrain",
+				Manifests: []string{tt.manifest},
+			})
+
+			invtesting.ExpectActions(t, result,
+				executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote, executor.ActionTypeEscalateIncident)
+			note := invtesting.NoteContent(result)
+			for _, want := range tt.wantNotes {
+				if !strings.Contains(note, want) {
+					t.Errorf("expected note to contain %q, got:\n%s", want, note)
+				}
+			}
+		})
+	}
+
+	invtesting.ExpectRBAC(t, "metadata.yaml", usage, invtesting.RBACOptions{})
+}
+
 // --- findUnschedulableTaint tests ---
 
 func TestFindUnschedulableTaint_Present(t *testing.T) {
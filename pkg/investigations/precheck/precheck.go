@@ -1,12 +1,14 @@
 package precheck
 
 import (
+	"context"
 	"errors"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/managedcloud"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
 )
 
@@ -14,9 +16,16 @@ type ClusterStatePrecheck struct{}
 
 // Checks pre-requisites for a cluster investigation:
 // - the cluster's state is supported by CAD for an investigation (= not uninstalling)
-// - the cloud provider is supported by CAD (cluster is AWS)
+// - the cloud provider is supported by CAD (cluster is AWS or GCP)
+// - CAD has credentials for the cloud provider (GCP needs CAD's own service account)
 // Performs according pagerduty actions and returns whether CAD needs to investigate the cluster
 func (c *ClusterStatePrecheck) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the precheck. Its only requests go through the OCM client of rb, which is cancelled
+// with ctx when run through investigation.RunWithContext.
+func (c *ClusterStatePrecheck) RunContext(_ context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 	r, err := rb.WithCluster().Build()
 	if err != nil {
@@ -44,9 +53,11 @@ func (c *ClusterStatePrecheck) Run(rb investigation.ResourceBuilder) (investigat
 		return result, nil
 	}
 
-	if cluster.AWS() == nil {
+	// Investigations that only support one of the cloud providers are skipped by the controller
+	_, cloud := investigation.ClusterPlatforms(cluster)
+	if cloud != investigation.PlatformAWS && cloud != investigation.PlatformGCP {
 		logging.Info("Cloud provider unsupported, forwarding to primary.")
-		result.StopInvestigations = errors.New("unsupported cloud provider (non-AWS)")
+		result.StopInvestigations = errors.New("unsupported cloud provider (neither AWS nor GCP)")
 		result.Actions = []types.Action{
 			executor.Note("CAD could not run an automated investigation on this cluster: unsupported cloud provider."),
 			executor.Escalate("CAD could not run an automated investigation on this cluster: unsupported cloud provider."),
@@ -54,6 +65,17 @@ func (c *ClusterStatePrecheck) Run(rb investigation.ResourceBuilder) (investigat
 		return result, nil
 	}
 
+	// Backplane doesn't hand out GCP credentials, so GCP investigations need CAD's own service account
+	if cloud == investigation.PlatformGCP && !managedcloud.GCPCredentialsConfigured() {
+		logging.Warn("GCP credentials are not configured, forwarding to primary.")
+		result.StopInvestigations = errors.New("gcp credentials not configured")
+		result.Actions = []types.Action{
+			executor.Note("CAD could not run an automated investigation on this GCP cluster: CAD has no GCP credentials configured. Please investigate manually."),
+			executor.Escalate("CAD could not run an automated investigation on this GCP cluster: CAD has no GCP credentials configured. Please investigate manually."),
+		}
+		return result, nil
+	}
+
 	isAccessProtected, err := ocmClient.IsAccessProtected(cluster)
 	if err != nil {
 		logging.Warnf("failed to get access protection status for cluster: %v. Escalating for manual handling.", err)
@@ -79,3 +101,9 @@ func (c *ClusterStatePrecheck) Run(rb investigation.ResourceBuilder) (investigat
 func (c *ClusterStatePrecheck) Name() string {
 	return "precheck"
 }
+
+func (c *ClusterStatePrecheck) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description: "Checks that the cluster state and cloud provider allow CAD to investigate the cluster",
+	}
+}
@@ -2,11 +2,14 @@ package precheck
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
 	investigation "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	"github.com/openshift/configuration-anomaly-detection/pkg/managedcloud"
 	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
 	pdmock "github.com/openshift/configuration-anomaly-detection/pkg/pagerduty/mock"
 	"github.com/openshift/configuration-anomaly-detection/pkg/types"
@@ -14,16 +17,23 @@ import (
 )
 
 func TestInvestigation_Run(t *testing.T) {
+	credentialsFile := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(credentialsFile, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer managedcloud.SetGCPCredentialsFile("")
+
 	type args struct {
 		rb *investigation.ResourceBuilderMock
 	}
 	tests := []struct {
-		name       string
-		c          *ClusterStatePrecheck
-		args       args
-		want       investigation.InvestigationResult
-		wantErr    bool
-		setupMocks func(*gomock.Controller) (*pdmock.MockClient, *ocmmock.MockClient, *cmv1.Cluster)
+		name           string
+		c              *ClusterStatePrecheck
+		args           args
+		gcpCredentials bool
+		want           investigation.InvestigationResult
+		wantErr        bool
+		setupMocks     func(*gomock.Controller) (*pdmock.MockClient, *ocmmock.MockClient, *cmv1.Cluster)
 	}{
 		{
 			name: "cloud provider unsupported stops investigation and escalates the alert",
@@ -33,7 +43,7 @@ func TestInvestigation_Run(t *testing.T) {
 					executor.Note("CAD could not run an automated investigation on this cluster: unsupported cloud provider."),
 					executor.Escalate("CAD could not run an automated investigation on this cluster: unsupported cloud provider."),
 				},
-				StopInvestigations: errors.New("unsupported cloud provider (non-AWS)"),
+				StopInvestigations: errors.New("unsupported cloud provider (neither AWS nor GCP)"),
 			},
 			wantErr: false,
 			setupMocks: func(ctrl *gomock.Controller) (*pdmock.MockClient, *ocmmock.MockClient, *cmv1.Cluster) {
@@ -41,7 +51,7 @@ func TestInvestigation_Run(t *testing.T) {
 				ocmClient := ocmmock.NewMockClient(ctrl)
 				builder := cmv1.NewCluster()
 				builder.State(cmv1.ClusterStateReady)
-				builder.GCP(cmv1.NewGCP())
+				builder.CloudProvider(cmv1.NewCloudProvider().ID("azure"))
 				cluster, _ := builder.Build()
 
 				return pdClient, ocmClient, cluster
@@ -119,6 +129,49 @@ func TestInvestigation_Run(t *testing.T) {
 				return pdClient, ocmClient, cluster
 			},
 		},
+		{
+			name: "GCP cluster without credentials escalates",
+			c:    &ClusterStatePrecheck{},
+			want: investigation.InvestigationResult{
+				StopInvestigations: errors.New("gcp credentials not configured"),
+				Actions: []types.Action{
+					executor.Note("CAD could not run an automated investigation on this GCP cluster: CAD has no GCP credentials configured. Please investigate manually."),
+					executor.Escalate("CAD could not run an automated investigation on this GCP cluster: CAD has no GCP credentials configured. Please investigate manually."),
+				},
+			},
+			wantErr: false,
+			setupMocks: func(ctrl *gomock.Controller) (*pdmock.MockClient, *ocmmock.MockClient, *cmv1.Cluster) {
+				pdClient := pdmock.NewMockClient(ctrl)
+				ocmClient := ocmmock.NewMockClient(ctrl)
+
+				builder := cmv1.NewCluster()
+				builder.State(cmv1.ClusterStateReady)
+				builder.GCP(cmv1.NewGCP())
+				cluster, _ := builder.Build()
+
+				return pdClient, ocmClient, cluster
+			},
+		},
+		{
+			name:           "GCP cluster continues investigation",
+			c:              &ClusterStatePrecheck{},
+			gcpCredentials: true,
+			want:           investigation.InvestigationResult{StopInvestigations: nil},
+			wantErr:        false,
+			setupMocks: func(ctrl *gomock.Controller) (*pdmock.MockClient, *ocmmock.MockClient, *cmv1.Cluster) {
+				pdClient := pdmock.NewMockClient(ctrl)
+				ocmClient := ocmmock.NewMockClient(ctrl)
+
+				builder := cmv1.NewCluster()
+				builder.State(cmv1.ClusterStateReady)
+				builder.GCP(cmv1.NewGCP())
+				cluster, _ := builder.Build()
+
+				ocmClient.EXPECT().IsAccessProtected(cluster).Return(false, nil)
+
+				return pdClient, ocmClient, cluster
+			},
+		},
 		{
 			name:    "access protection disabled continues investigation",
 			c:       &ClusterStatePrecheck{},
@@ -139,14 +192,14 @@ func TestInvestigation_Run(t *testing.T) {
 			},
 		},
 		{
-			name: "cloud provider unsupported stops investigation",
+			name: "unknown cloud provider stops investigation",
 			c:    &ClusterStatePrecheck{},
 			want: investigation.InvestigationResult{
 				Actions: []types.Action{
 					executor.Note("CAD could not run an automated investigation on this cluster: unsupported cloud provider."),
 					executor.Escalate("CAD could not run an automated investigation on this cluster: unsupported cloud provider."),
 				},
-				StopInvestigations: errors.New("unsupported cloud provider (non-AWS)"),
+				StopInvestigations: errors.New("unsupported cloud provider (neither AWS nor GCP)"),
 			},
 			setupMocks: func(ctrl *gomock.Controller) (*pdmock.MockClient, *ocmmock.MockClient, *cmv1.Cluster) {
 				pdClient := pdmock.NewMockClient(ctrl)
@@ -154,7 +207,6 @@ func TestInvestigation_Run(t *testing.T) {
 
 				builder := cmv1.NewCluster()
 				builder.State(cmv1.ClusterStateReady)
-				builder.GCP(cmv1.NewGCP())
 				cluster, _ := builder.Build()
 				return pdClient, ocmClient, cluster
 			},
@@ -167,12 +219,17 @@ func TestInvestigation_Run(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			pdClient, ocmClient, cluster := tt.setupMocks(mockCtrl)
+			if tt.gcpCredentials {
+				managedcloud.SetGCPCredentialsFile(credentialsFile)
+			} else {
+				managedcloud.SetGCPCredentialsFile("")
+			}
 
 			tt.args.rb = &investigation.ResourceBuilderMock{
 				Resources: &investigation.Resources{
 					Cluster:   cluster,
 					OcmClient: ocmClient,
-					PdClient:  pdClient,
+					Incident:  pdClient,
 				},
 			}
 
@@ -0,0 +1,376 @@
+// Package pruningcronjoberror investigates failures of the image pruner CronJob in the
+// openshift-image-registry namespace (PruningCronjobErrorSRE).
+package pruningcronjoberror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+	k8sclient "github.com/openshift/configuration-anomaly-detection/pkg/k8s"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+)
+
+const (
+	registryNamespace    = "openshift-image-registry"
+	prunerCronJobName    = "image-pruner"
+	registryOperatorName = "image-registry"
+
+	// prunerLogTailLines is how much of a failed pruner pod's log is searched for known errors
+	prunerLogTailLines  int64 = 200
+	invalidReference          = "invalid reference format"
+	maxReportedLogLines       = 3
+)
+
+// The registry operator reports these reasons on its conditions when it doesn't manage the registry
+const (
+	registryRemoved   = "Removed"
+	registryUnmanaged = "Unmanaged"
+)
+
+func newRegistryNotManagedSL(state string) *ocm.ServiceLog {
+	return &ocm.ServiceLog{
+		Severity:    "Warning",
+		Summary:     "Action required: image pruner is failing",
+		ServiceName: "SREManualAction",
+		Description: fmt.Sprintf("The image pruner CronJob in the openshift-image-registry namespace is failing because the "+
+			"image registry is set to %s. Please set the managementState of configs.imageregistry.operator.openshift.io/cluster "+
+			"to Managed, or suspend the image pruner by setting spec.suspend to true in imagepruners.imageregistry.operator.openshift.io/cluster.",
+			state),
+		InternalOnly: false,
+	}
+}
+
+func newInvalidImageReferencesSL() *ocm.ServiceLog {
+	return &ocm.ServiceLog{
+		Severity:    "Warning",
+		Summary:     "Action required: image pruner is failing",
+		ServiceName: "SREManualAction",
+		Description: "The image pruner CronJob in the openshift-image-registry namespace is failing because it found images " +
+			"with invalid references on the cluster. Please correct or remove the invalid image references, or set " +
+			"spec.ignoreInvalidImageReferences to true in imagepruners.imageregistry.operator.openshift.io/cluster so the pruner skips them.",
+		InternalOnly: false,
+	}
+}
+
+// logReader returns the tail of the logs of a pruner pod
+type logReader func(ctx context.Context, restConfig *rest.Config, pod *corev1.Pod) (string, error)
+
+type Investigation struct {
+	// readLogs defaults to reading logs through the kube-api, tests replace it
+	readLogs logReader
+}
+
+// prunerState is what the investigation found out about the image pruner
+type prunerState struct {
+	registryState     string // registryRemoved, registryUnmanaged or empty if the registry is managed
+	registryDegraded  string // Message of the Degraded condition, empty if not degraded
+	failedJobs        []batchv1.Job
+	oomKilledPods     []string
+	invalidReferences []string
+}
+
+func (i *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return i.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster with ctx
+func (i *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	result := investigation.InvestigationResult{}
+
+	r, err := rb.WithCluster().WithK8sClient().WithNotes().Build()
+	if err != nil {
+		if msg, ok := investigation.ClusterAccessErrorMessage(err); ok {
+			logging.Warnf("Cluster access error for %s: %v", i.Name(), err)
+			result.Actions = []types.Action{
+				executor.Note(msg),
+				executor.Escalate(msg),
+			}
+			return result, nil
+		}
+		return result, investigation.WrapInfrastructure(err, "failed to build resources for "+i.Name())
+	}
+
+	state := prunerState{}
+
+	if err := checkRegistryOperator(ctx, r.K8sClient, &state, r.Notes); err != nil {
+		return result, err
+	}
+	if err := checkCronJob(ctx, r.K8sClient, r.Notes); err != nil {
+		return result, err
+	}
+	if err := checkFailedJobs(ctx, r.K8sClient, &state, r.Notes); err != nil {
+		return result, err
+	}
+	if len(state.failedJobs) > 0 {
+		if err := i.checkPrunerPods(ctx, r.K8sClient, &r.RestConfig.Config, &state, r.Notes); err != nil {
+			return result, err
+		}
+	}
+
+	notesAndReport := executor.NoteAndReportFrom(r.Notes, r.Cluster.ID(), i.Name())
+	switch {
+	case state.registryState != "":
+		sl := newRegistryNotManagedSL(state.registryState)
+		result.Actions = append(notesAndReport,
+			serviceLogAction(sl),
+			executor.Silence("Image pruner fails because the image registry is "+state.registryState),
+		)
+	case len(state.invalidReferences) > 0:
+		result.Actions = append(notesAndReport,
+			serviceLogAction(newInvalidImageReferencesSL()),
+			executor.Silence("Image pruner fails on invalid image references"),
+		)
+	case len(state.oomKilledPods) > 0:
+		result.Actions = append(notesAndReport,
+			executor.Escalate("Image pruner pods were OOMKilled: manual review required"),
+		)
+	case state.registryDegraded != "":
+		result.Actions = append(notesAndReport,
+			executor.Escalate("Image registry ClusterOperator is degraded: manual review required"),
+		)
+	default:
+		result.Actions = append(notesAndReport,
+			executor.Escalate("No known image pruner failure detected: manual investigation required"),
+		)
+	}
+	return result, nil
+}
+
+func serviceLogAction(sl *ocm.ServiceLog) types.Action {
+	return executor.NewServiceLogAction(sl.Severity, sl.Summary).
+		WithDescription(sl.Description).
+		WithServiceName(sl.ServiceName).
+		Build()
+}
+
+// checkRegistryOperator records whether the image registry is managed and degraded,
+// based on the conditions of the image-registry ClusterOperator
+func checkRegistryOperator(ctx context.Context, c k8sclient.Client, state *prunerState, notes *notewriter.NoteWriter) error {
+	co := &configv1.ClusterOperator{}
+	err := c.Get(ctx, client.ObjectKey{Name: registryOperatorName}, co)
+	if apierrors.IsNotFound(err) {
+		notes.AppendWarning("ClusterOperator %s not found", registryOperatorName)
+		return nil
+	}
+	if err != nil {
+		return investigation.WrapInfrastructure(
+			fmt.Errorf("unable to get clusteroperator %s: %w", registryOperatorName, err),
+			"K8s API failure getting clusteroperators")
+	}
+
+	for _, condition := range co.Status.Conditions {
+		if condition.Reason == registryRemoved || condition.Reason == registryUnmanaged {
+			state.registryState = condition.Reason
+		}
+		if condition.Type == configv1.OperatorDegraded && condition.Status == configv1.ConditionTrue {
+			state.registryDegraded = condition.Message
+		}
+	}
+
+	if state.registryState != "" {
+		notes.AppendWarning("Image registry is %s, the image pruner can't prune it", state.registryState)
+	} else {
+		notes.AppendSuccess("Image registry is managed")
+	}
+	if state.registryDegraded != "" {
+		notes.AppendWarning("ClusterOperator %s is degraded: %s", registryOperatorName, state.registryDegraded)
+	}
+	return nil
+}
+
+// checkCronJob notes the schedule state of the image pruner CronJob
+func checkCronJob(ctx context.Context, c k8sclient.Client, notes *notewriter.NoteWriter) error {
+	cronJob := &batchv1.CronJob{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: registryNamespace, Name: prunerCronJobName}, cronJob)
+	if apierrors.IsNotFound(err) {
+		notes.AppendWarning("CronJob %s/%s not found", registryNamespace, prunerCronJobName)
+		return nil
+	}
+	if err != nil {
+		return investigation.WrapInfrastructure(
+			fmt.Errorf("unable to get cronjob %s/%s: %w", registryNamespace, prunerCronJobName, err),
+			"K8s API failure getting cronjobs")
+	}
+
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		notes.AppendWarning("CronJob %s is suspended", prunerCronJobName)
+	}
+	if cronJob.Status.LastSuccessfulTime != nil {
+		notes.AppendAutomation("Last successful image pruner run: %s", cronJob.Status.LastSuccessfulTime.UTC().Format("2006-01-02 15:04 MST"))
+	} else {
+		notes.AppendWarning("The image pruner has no successful run on record")
+	}
+	return nil
+}
+
+// checkFailedJobs records the failed Jobs of the image pruner CronJob, newest first
+func checkFailedJobs(ctx context.Context, c k8sclient.Client, state *prunerState, notes *notewriter.NoteWriter) error {
+	jobs := &batchv1.JobList{}
+	if err := c.List(ctx, jobs, client.InNamespace(registryNamespace)); err != nil {
+		return investigation.WrapInfrastructure(
+			fmt.Errorf("unable to list jobs in %s: %w", registryNamespace, err),
+			"K8s API failure listing jobs")
+	}
+
+	prunerJobs := 0
+	for _, job := range jobs.Items {
+		if !isPrunerJob(job) {
+			continue
+		}
+		prunerJobs++
+		if isFailed(job) {
+			state.failedJobs = append(state.failedJobs, job)
+		}
+	}
+	sort.Slice(state.failedJobs, func(a, b int) bool {
+		return state.failedJobs[b].CreationTimestamp.Before(&state.failedJobs[a].CreationTimestamp)
+	})
+
+	if len(state.failedJobs) == 0 {
+		notes.AppendSuccess("None of the %d image pruner job(s) on record failed", prunerJobs)
+		return nil
+	}
+	names := make([]string, 0, len(state.failedJobs))
+	for _, job := range state.failedJobs {
+		names = append(names, job.Name)
+	}
+	notes.AppendWarning("%d/%d image pruner job(s) failed: %s", len(state.failedJobs), prunerJobs, strings.Join(names, ", "))
+	return nil
+}
+
+func isPrunerJob(job batchv1.Job) bool {
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "CronJob" && owner.Name == prunerCronJobName {
+			return true
+		}
+	}
+	return false
+}
+
+func isFailed(job batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPrunerPods looks for OOMKilled containers in the pods of the failed jobs, and for
+// invalid image references in the logs of the newest failed job's pods
+func (i *Investigation) checkPrunerPods(ctx context.Context, c k8sclient.Client, restConfig *rest.Config, state *prunerState, notes *notewriter.NoteWriter) error {
+	readLogs := i.readLogs
+	if readLogs == nil {
+		readLogs = readPodLogs
+	}
+
+	for index, job := range state.failedJobs {
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(registryNamespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+			return investigation.WrapInfrastructure(
+				fmt.Errorf("unable to list pods of job %s: %w", job.Name, err),
+				"K8s API failure listing pods")
+		}
+
+		for podIndex := range pods.Items {
+			pod := &pods.Items[podIndex]
+			if isOOMKilled(pod) {
+				state.oomKilledPods = append(state.oomKilledPods, pod.Name)
+			}
+			// Older failures most likely have the same cause, only read the logs of the newest job
+			if index > 0 || len(state.invalidReferences) > 0 {
+				continue
+			}
+			logs, err := readLogs(ctx, restConfig, pod)
+			if err != nil {
+				notes.AppendWarning("Could not read the logs of pod %s: %v", pod.Name, err)
+				continue
+			}
+			state.invalidReferences = invalidReferenceLines(logs)
+		}
+	}
+
+	if len(state.oomKilledPods) > 0 {
+		notes.AppendWarning("%d image pruner pod(s) were OOMKilled: %s. The pruner may need more memory on clusters with many images.",
+			len(state.oomKilledPods), strings.Join(state.oomKilledPods, ", "))
+	}
+	if len(state.invalidReferences) > 0 {
+		notes.AppendWarning("The image pruner failed on invalid image references:\n%s", strings.Join(state.invalidReferences, "\n"))
+	}
+	return nil
+}
+
+func isOOMKilled(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+			if terminated != nil && terminated.Reason == "OOMKilled" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// invalidReferenceLines returns the first log lines reporting an invalid image reference
+func invalidReferenceLines(logs string) []string {
+	var lines []string
+	for _, line := range strings.Split(logs, "\n") {
+		if strings.Contains(line, invalidReference) {
+			lines = append(lines, strings.TrimSpace(line))
+			if len(lines) == maxReportedLogLines {
+				break
+			}
+		}
+	}
+	return lines
+}
+
+func readPodLogs(ctx context.Context, restConfig *rest.Config, pod *corev1.Pod) (string, error) {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+	tailLines := prunerLogTailLines
+	stream, err := clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tailLines}).Stream(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to open log stream: %w", err)
+	}
+	defer func() {
+		if closeErr := stream.Close(); closeErr != nil {
+			logging.Warnf("failed to close log stream: %v", closeErr)
+		}
+	}()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs: %w", err)
+	}
+	return string(logs), nil
+}
+
+func (i *Investigation) Name() string {
+	return "pruningcronjoberror"
+}
+
+func (i *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks why the image pruner CronJob fails, e.g. an unmanaged registry, OOMKilled pruner pods or invalid image references",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+	}
+}
@@ -0,0 +1,115 @@
+package pruningcronjoberror
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
+)
+
+func staticLogs(logs string, err error) logReader {
+	return func(context.Context, *rest.Config, *corev1.Pod) (string, error) {
+		return logs, err
+	}
+}
+
+const invalidReferenceLogs = `I1001 00:00:01.000000       1 prune.go:344] Creating image pruner with keepYoungerThan=1h0m0s
+error: failed to build graph - resolution of a reference failed: invalid reference format: "quay.io/example/app:v1 "
+error: failed to build graph - resolution of a reference failed: invalid reference format: "registry..example.com/app@sha256:"`
+
+func TestRun_Scenarios(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifests   []string
+		logs        logReader
+		wantActions []executor.ActionType
+		wantNotes   []string
+	}{
+		{
+			name:      "registry removed",
+			manifests: []string{"testdata/registry-removed.yaml", "testdata/cronjob.yaml"},
+			logs:      staticLogs("", nil),
+			wantActions: []executor.ActionType{
+				executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote,
+				executor.ActionTypeServiceLog, executor.ActionTypeSilenceIncident,
+			},
+			wantNotes: []string{"Image registry is Removed", "1/1 image pruner job(s) failed: image-pruner-29000000"},
+		},
+		{
+			name:      "invalid image references",
+			manifests: []string{"testdata/registry-managed.yaml", "testdata/cronjob.yaml"},
+			logs:      staticLogs(invalidReferenceLogs, nil),
+			wantActions: []executor.ActionType{
+				executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote,
+				executor.ActionTypeServiceLog, executor.ActionTypeSilenceIncident,
+			},
+			wantNotes: []string{"Image registry is managed", "invalid reference format: \"quay.io/example/app:v1 \""},
+		},
+		{
+			name:      "pruner OOMKilled",
+			manifests: []string{"testdata/registry-managed.yaml", "testdata/cronjob.yaml", "testdata/pruner-oomkilled.yaml"},
+			logs:      staticLogs("", nil),
+			wantActions: []executor.ActionType{
+				executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote, executor.ActionTypeEscalateIncident,
+			},
+			wantNotes: []string{"1 image pruner pod(s) were OOMKilled: image-pruner-29000000-fghij"},
+		},
+		{
+			name:      "registry degraded and logs unavailable",
+			manifests: []string{"testdata/registry-degraded.yaml", "testdata/cronjob.yaml"},
+			logs:      staticLogs("", errors.New("connection refused")),
+			wantActions: []executor.ActionType{
+				executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote, executor.ActionTypeEscalateIncident,
+			},
+			wantNotes: []string{
+				"ClusterOperator image-registry is degraded: Job has reached the specified backoff limit",
+				"Could not read the logs of pod image-pruner-29000000-abcde: connection refused",
+			},
+		},
+		{
+			name:      "no failed jobs",
+			manifests: []string{"testdata/registry-managed.yaml", "testdata/no-failed-jobs.yaml"},
+			wantActions: []executor.ActionType{
+				executor.ActionTypeBackplaneReport, executor.ActionTypePagerDutyNote, executor.ActionTypeEscalateIncident,
+			},
+			wantNotes: []string{"Last successful image pruner run: 2026-10-01 00:05 UTC", "None of the 1 image pruner job(s) on record failed"},
+		},
+	}
+
+	usage := &invtesting.RBACUsage{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := usage.Run(t, &Investigation{readLogs: tt.logs}, invtesting.Scenario{
+				Name:      "pruningcronjoberror",
+				Manifests: tt.manifests,
+			})
+
+			invtesting.ExpectActions(t, result, tt.wantActions...)
+			note := invtesting.NoteContent(result)
+			for _, want := range tt.wantNotes {
+				if !strings.Contains(note, want) {
+					t.Errorf("expected note to contain %q, got:\n%s", want, note)
+				}
+			}
+		})
+	}
+
+	// Pod logs are read through a clientset, not the recorded k8s client
+	invtesting.ExpectRBAC(t, "metadata.yaml", usage, invtesting.RBACOptions{Uncovered: []string{"get pods/log"}})
+}
+
+func TestInvalidReferenceLines(t *testing.T) {
+	logs := strings.Repeat("error: invalid reference format: \"x\"\n", maxReportedLogLines+2) + "done"
+	if got := invalidReferenceLines(logs); len(got) != maxReportedLogLines {
+		t.Errorf("expected %d lines, got %d: %v", maxReportedLogLines, len(got), got)
+	}
+	if got := invalidReferenceLines("pruning finished"); len(got) != 0 {
+		t.Errorf("expected no lines, got %v", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/cannotretrieveupdatessre"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/ccam"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/chgm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/cloudquota"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/clusterhealthcheck"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/clustermonitoringerrorbudgetburn"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/consoleerrorbudgetburn"
@@ -19,6 +20,7 @@ import (
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/ocmagentresponsefailure"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/pdbblockingnodedrain"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/precheck"
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/pruningcronjoberror"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/restartcontrolplane"
 	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/upgradeconfigsyncfailureover4hr"
 )
@@ -44,6 +46,8 @@ var availableInvestigations = []investigation.Investigation{
 	&consoleerrorbudgetburn.Investigation{},
 	&expiredcertificates.Investigation{},
 	&pdbblockingnodedrain.Investigation{},
+	&pruningcronjoberror.Investigation{},
+	&cloudquota.Investigation{},
 }
 
 // GetInvestigationByName returns the Investigation with the given name, or nil if not found.
@@ -65,3 +69,8 @@ func GetAvailableInvestigationsNames() []string {
 	}
 	return alertNames
 }
+
+// GetAvailableInvestigations returns all available investigations, in registration order.
+func GetAvailableInvestigations() []investigation.Investigation {
+	return append([]investigation.Investigation{}, availableInvestigations...)
+}
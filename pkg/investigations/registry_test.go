@@ -0,0 +1,54 @@
+package investigations
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation"
+)
+
+// metadataWithoutInvestigation lists metadata.yaml files that don't have an investigation yet
+var metadataWithoutInvestigation = map[string]bool{}
+
+// TestMetadataHasInvestigation ensures every metadata.yaml belongs to a registered investigation,
+// as backplane looks up the RBAC of an investigation by its name.
+func TestMetadataHasInvestigation(t *testing.T) {
+	paths, err := filepath.Glob("*/metadata.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no metadata.yaml files found")
+	}
+	for _, path := range paths {
+		name := filepath.Base(filepath.Dir(path))
+		registered := GetInvestigationByName(name) != nil
+		switch {
+		case !registered && !metadataWithoutInvestigation[name]:
+			t.Errorf("%s has no registered investigation named %q", path, name)
+		case registered && metadataWithoutInvestigation[name]:
+			t.Errorf("%q is registered now, remove it from metadataWithoutInvestigation", name)
+		}
+	}
+}
+
+// TestParamSchemas ensures the declared parameter defaults are valid values of their parameters
+func TestParamSchemas(t *testing.T) {
+	for _, inv := range GetAvailableInvestigations() {
+		schema := investigation.Describe(inv).Params
+		seen := map[string]bool{}
+		defaults := map[string]string{}
+		for _, p := range schema {
+			if seen[p.Name] {
+				t.Errorf("%s: parameter %s is declared twice", inv.Name(), p.Name)
+			}
+			seen[p.Name] = true
+			if p.Default != "" {
+				defaults[p.Name] = p.Default
+			}
+		}
+		if _, err := investigation.ResolveParams(schema, defaults); err != nil {
+			t.Errorf("%s: invalid parameter defaults: %v", inv.Name(), err)
+		}
+	}
+}
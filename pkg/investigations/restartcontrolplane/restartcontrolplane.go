@@ -16,9 +16,12 @@ import (
 type Investigation struct{}
 
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
-	result := investigation.InvestigationResult{}
+	return c.RunContext(context.Background(), rb)
+}
 
-	ctx := context.Background()
+// RunContext runs the investigation, cancelling the requests to the management cluster with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	result := investigation.InvestigationResult{}
 
 	r, err := rb.WithCluster().WithManagementK8sClient().WithNotes().Build()
 	if err != nil {
@@ -80,3 +83,12 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 func (c *Investigation) Name() string {
 	return "restartcontrolplane"
 }
+
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Restarts the control plane of an HCP cluster",
+		Platforms:         []investigation.Platform{investigation.PlatformHCP},
+		RequiredResources: []investigation.Resource{investigation.ResourceManagementCluster},
+		MutatesCluster:    true,
+	}
+}
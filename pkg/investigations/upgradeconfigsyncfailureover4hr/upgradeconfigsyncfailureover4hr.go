@@ -2,6 +2,7 @@
 package upgradeconfigsyncfailureover4hr
 
 import (
+	"context"
 	"errors"
 
 	"github.com/openshift/configuration-anomaly-detection/pkg/executor"
@@ -16,6 +17,11 @@ import (
 type Investigation struct{}
 
 func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
+	return c.RunContext(context.Background(), rb)
+}
+
+// RunContext runs the investigation, cancelling the requests to the cluster and OCM with ctx
+func (c *Investigation) RunContext(ctx context.Context, rb investigation.ResourceBuilder) (investigation.InvestigationResult, error) {
 	result := investigation.InvestigationResult{}
 	r, err := rb.WithCluster().Build()
 	if err != nil {
@@ -110,7 +116,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 
 	// Pullsecret validation done via pullsecret package
 	ocmEmail := user.Email()
-	emailValidation := pullsecret.ValidateEmail(r.K8sClient, ocmEmail)
+	emailValidation := pullsecret.ValidateEmail(ctx, r.K8sClient, ocmEmail)
 
 	for _, warning := range emailValidation.Warnings {
 		notes.AppendWarning("%s", warning)
@@ -121,7 +127,7 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 	}
 
 	// Registry credentials validation
-	registryValidation, registryResults := pullsecret.ValidateRegistryCredentials(r.K8sClient, r.OcmClient.GetConnection(), user.ID(), ocmEmail)
+	registryValidation, registryResults := pullsecret.ValidateRegistryCredentials(ctx, r.K8sClient, r.OcmClient.GetConnection(), user.ID(), ocmEmail)
 
 	// INFO: per-registry validation results at debug level for troubleshooting
 	for _, regResult := range registryResults {
@@ -146,3 +152,10 @@ func (c *Investigation) Run(rb investigation.ResourceBuilder) (investigation.Inv
 func (c *Investigation) Name() string {
 	return "upgradeconfigsyncfailureover4hr"
 }
+
+func (c *Investigation) Describe() investigation.Capabilities {
+	return investigation.Capabilities{
+		Description:       "Checks why the managed upgrade operator failed to sync the UpgradeConfig",
+		RequiredResources: []investigation.Resource{investigation.ResourceK8s},
+	}
+}
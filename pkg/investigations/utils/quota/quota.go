@@ -0,0 +1,145 @@
+/*
+quota defines investigation utility logic comparing the usage of the AWS account of a cluster with its service quotas
+*/
+package quota
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+)
+
+// quota identifies a service quota in the Service Quotas API
+type quota struct {
+	serviceCode string
+	quotaCode   string
+}
+
+// vcpuQuotas are the quotas on the vCPUs of running On-Demand instances, by the instance classes they cover.
+// The class of an instance family are its leading letters, e.g. m for m5 and inf for inf2.
+var vcpuQuotas = map[string]quota{
+	"standard": {"ec2", "L-1216C47A"},
+	"g":        {"ec2", "L-DB2E81BA"},
+	"p":        {"ec2", "L-417A185B"},
+	"x":        {"ec2", "L-7295265B"},
+	"f":        {"ec2", "L-74FC7D96"},
+	"inf":      {"ec2", "L-1945791B"},
+	"trn":      {"ec2", "L-2C3B7624"},
+	"dl":       {"ec2", "L-6E869C2A"},
+	"hpc":      {"ec2", "L-F7808C92"},
+}
+
+// instanceClassQuotas maps instance classes to the key of their vCPU quota in vcpuQuotas
+var instanceClassQuotas = map[string]string{
+	"a": "standard", "c": "standard", "d": "standard", "h": "standard", "i": "standard", "im": "standard",
+	"is": "standard", "m": "standard", "r": "standard", "t": "standard", "z": "standard",
+	"g": "g", "vt": "g",
+	"p": "p", "x": "x", "f": "f", "inf": "inf", "trn": "trn", "dl": "dl", "hpc": "hpc",
+}
+
+var (
+	elasticIPQuota        = quota{"ec2", "L-0263D0A3"}
+	networkInterfaceQuota = quota{"vpc", "L-DF5E4CA3"}
+)
+
+var instanceClassRegex = regexp.MustCompile(`^[a-z]+`)
+
+// Usage is the usage of a service quota of the account in the region of the cluster
+type Usage struct {
+	Name      string
+	QuotaCode string
+	Unit      string
+	Used      float64
+	Limit     float64
+}
+
+// Utilization returns the share of the quota in use, 1 meaning it is exhausted
+func (u Usage) Utilization() float64 {
+	if u.Limit <= 0 {
+		return 1
+	}
+	return u.Used / u.Limit
+}
+
+// Exhausted reports whether no more resources can be created within the quota
+func (u Usage) Exhausted() bool {
+	return u.Used >= u.Limit
+}
+
+func (u Usage) String() string {
+	return fmt.Sprintf("%s (%s): %.0f of %.0f %s", u.Name, u.QuotaCode, u.Used, u.Limit, u.Unit)
+}
+
+// InstanceFamily returns the family of an instance type, e.g. m5 for m5.xlarge
+func InstanceFamily(instanceType string) string {
+	family, _, _ := strings.Cut(instanceType, ".")
+	return family
+}
+
+// vcpuQuotaKey returns the key of the vCPU quota covering the instance family, or "" if none is known
+func vcpuQuotaKey(family string) string {
+	return instanceClassQuotas[instanceClassRegex.FindString(family)]
+}
+
+// Check returns the usage of the vCPU quotas covering the instance families, and of the Elastic IP and
+// network interface quotas. Usage is counted for the whole account in the region, as that is what quotas apply to.
+// Families without a known vCPU quota are skipped.
+func Check(ctx context.Context, awsCli aws.Client, families []string) ([]Usage, error) {
+	resources, err := awsCli.GetEC2ResourceUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []Usage
+	checked := map[string]bool{}
+	for _, family := range families {
+		key := vcpuQuotaKey(family)
+		if key == "" || checked[key] {
+			continue
+		}
+		checked[key] = true
+
+		var used int32
+		for runningFamily, vcpus := range resources.VCPUsByFamily {
+			if vcpuQuotaKey(runningFamily) == key {
+				used += vcpus
+			}
+		}
+		usage, err := usageOf(ctx, awsCli, vcpuQuotas[key], "vCPUs", float64(used))
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+
+	for _, q := range []struct {
+		quota
+		unit string
+		used int
+	}{
+		{elasticIPQuota, "Elastic IPs", resources.ElasticIPs},
+		{networkInterfaceQuota, "network interfaces", resources.NetworkInterfaces},
+	} {
+		usage, err := usageOf(ctx, awsCli, q.quota, q.unit, float64(q.used))
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+func usageOf(ctx context.Context, awsCli aws.Client, q quota, unit string, used float64) (Usage, error) {
+	sq, err := awsCli.GetServiceQuota(ctx, q.serviceCode, q.quotaCode)
+	if err != nil {
+		return Usage{}, err
+	}
+	name := sq.Name
+	if name == "" {
+		name = q.quotaCode
+	}
+	return Usage{Name: name, QuotaCode: q.quotaCode, Unit: unit, Used: used, Limit: sq.Value}, nil
+}
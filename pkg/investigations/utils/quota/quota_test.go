@@ -0,0 +1,78 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	invtesting "github.com/openshift/configuration-anomaly-detection/pkg/investigations/investigation/testing"
+)
+
+func TestVcpuQuotaKey(t *testing.T) {
+	tests := map[string]string{
+		"m5":    "standard",
+		"m6i":   "standard",
+		"t3a":   "standard",
+		"im4gn": "standard",
+		"g4dn":  "g",
+		"vt1":   "g",
+		"p4d":   "p",
+		"inf2":  "inf",
+		"trn1":  "trn",
+		"u":     "",
+	}
+	for family, want := range tests {
+		if got := vcpuQuotaKey(family); got != want {
+			t.Errorf("vcpuQuotaKey(%q) = %q, want %q", family, got, want)
+		}
+	}
+}
+
+func TestInstanceFamily(t *testing.T) {
+	if got := InstanceFamily("m5.xlarge"); got != "m5" {
+		t.Errorf("InstanceFamily() = %q, want m5", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	awsCli := &invtesting.FakeAWSClient{Data: invtesting.AWSData{
+		EC2ResourceUsage: aws.EC2ResourceUsage{
+			VCPUsByFamily:     map[string]int32{"m5": 16, "c5": 8, "g4dn": 4},
+			ElasticIPs:        5,
+			NetworkInterfaces: 12,
+		},
+		ServiceQuotas: map[string]aws.ServiceQuota{
+			"ec2/L-1216C47A": {Name: "Running On-Demand Standard instances", Value: 32},
+			"ec2/L-0263D0A3": {Name: "EC2-VPC Elastic IPs", Value: 5},
+			"vpc/L-DF5E4CA3": {Value: 5000},
+		},
+	}}
+
+	usages, err := Check(context.Background(), awsCli, []string{"m5", "r5", "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Usage{
+		{Name: "Running On-Demand Standard instances", QuotaCode: "L-1216C47A", Unit: "vCPUs", Used: 24, Limit: 32},
+		{Name: "EC2-VPC Elastic IPs", QuotaCode: "L-0263D0A3", Unit: "Elastic IPs", Used: 5, Limit: 5},
+		{Name: "L-DF5E4CA3", QuotaCode: "L-DF5E4CA3", Unit: "network interfaces", Used: 12, Limit: 5000},
+	}
+	if len(usages) != len(want) {
+		t.Fatalf("Check() = %v, want %v", usages, want)
+	}
+	for i := range want {
+		if usages[i] != want[i] {
+			t.Errorf("usage %d = %v, want %v", i, usages[i], want[i])
+		}
+	}
+	if !usages[1].Exhausted() || usages[0].Exhausted() {
+		t.Errorf("expected only the Elastic IP quota to be exhausted")
+	}
+}
+
+func TestCheckMissingQuota(t *testing.T) {
+	awsCli := &invtesting.FakeAWSClient{Data: invtesting.AWSData{}}
+	if _, err := Check(context.Background(), awsCli, []string{"m5"}); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -8,9 +8,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func GetClusterVersion(k8scli client.Client) (*configv1.ClusterVersion, error) {
+func GetClusterVersion(ctx context.Context, k8scli client.Client) (*configv1.ClusterVersion, error) {
 	clusterVersion := &configv1.ClusterVersion{}
-	err := k8scli.Get(context.TODO(), client.ObjectKey{Name: "version"}, clusterVersion)
+	err := k8scli.Get(ctx, client.ObjectKey{Name: "version"}, clusterVersion)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ClusterVersion: %w", err)
 	}
@@ -1,6 +1,7 @@
 package version
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -68,7 +69,7 @@ func TestGetClusterVersion(t *testing.T) {
 				t.Fatalf("failed to create a fake client: %v", err)
 			}
 
-			got, err := GetClusterVersion(k8scli)
+			got, err := GetClusterVersion(context.Background(), k8scli)
 
 			if tt.expectError && err == nil {
 				t.Errorf("Expected an error, got none")
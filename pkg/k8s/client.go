@@ -17,7 +17,7 @@ type clientImpl struct {
 
 // New returns a Kubernetes client for the given cluster scoped to a given remediation's permissions.
 func New(cfg *rest.Config) (k8scli Client, err error) {
-	scheme, err := initScheme()
+	scheme, err := NewScheme()
 	if err != nil {
 		return nil, err
 	}
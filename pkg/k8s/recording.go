@@ -0,0 +1,155 @@
+package k8sclient
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Access is a single kube-api request, in the terms RBAC rules are written in
+type Access struct {
+	Verb      string
+	Group     string
+	Resource  string // Includes the subresource, e.g. "pods/status"
+	Namespace string // Empty for cluster-scoped resources and requests across all namespaces
+}
+
+// String formats the access like "list apps/deployments", omitting the core group
+func (a Access) String() string {
+	if a.Group == "" {
+		return a.Verb + " " + a.Resource
+	}
+	return a.Verb + " " + a.Group + "/" + a.Resource
+}
+
+// RecordingClient is a Client that records every access it makes. It is used by tests to
+// compare the RBAC an investigation needs with the RBAC declared in its metadata.yaml.
+type RecordingClient struct {
+	client.Client
+	mu       sync.Mutex
+	accesses []Access
+}
+
+var _ Client = &RecordingClient{}
+
+// NewRecordingClient creates a RecordingClient passing all requests to inner
+func NewRecordingClient(inner client.Client) *RecordingClient {
+	return &RecordingClient{Client: inner}
+}
+
+// Accesses returns the accesses recorded so far, in request order
+func (c *RecordingClient) Accesses() []Access {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Access{}, c.accesses...)
+}
+
+func (c *RecordingClient) record(verb string, obj runtime.Object, namespace, subResource string) {
+	var access Access
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		// Lists are recorded as accesses to the listed kind
+		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+		access = Access{Verb: verb, Group: gvk.Group, Resource: c.resourceFor(gvk)}
+	} else {
+		access = Access{Verb: verb, Resource: "<unknown>"}
+	}
+	if subResource != "" {
+		access.Resource += "/" + subResource
+	}
+	access.Namespace = namespace
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accesses = append(c.accesses, access)
+}
+
+// resourceFor returns the plural resource name of gvk, preferring the client's REST mapper
+func (c *RecordingClient) resourceFor(gvk schema.GroupVersionKind) string {
+	if mapper := c.RESTMapper(); mapper != nil {
+		if mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+			return mapping.Resource.Resource
+		}
+	}
+	plural, _ := meta.UnsafeGuessKindToResource(gvk)
+	return plural.Resource
+}
+
+func (c *RecordingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.record("get", obj, key.Namespace, "")
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *RecordingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	c.record("list", list, listOpts.Namespace, "")
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *RecordingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.record("create", obj, obj.GetNamespace(), "")
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *RecordingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.record("delete", obj, obj.GetNamespace(), "")
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *RecordingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.record("update", obj, obj.GetNamespace(), "")
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *RecordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.record("patch", obj, obj.GetNamespace(), "")
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *RecordingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteOpts := &client.DeleteAllOfOptions{}
+	deleteOpts.ApplyOptions(opts)
+	c.record("deletecollection", obj, deleteOpts.Namespace, "")
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *RecordingClient) Status() client.SubResourceWriter {
+	return &recordingSubResourceClient{parent: c, SubResourceClient: c.Client.SubResource("status"), name: "status"}
+}
+
+func (c *RecordingClient) SubResource(subResource string) client.SubResourceClient {
+	return &recordingSubResourceClient{parent: c, SubResourceClient: c.Client.SubResource(subResource), name: subResource}
+}
+
+// recordingSubResourceClient records subresource accesses on its parent RecordingClient.
+// Apply is passed through unrecorded, as apply configurations don't carry a typed object.
+type recordingSubResourceClient struct {
+	client.SubResourceClient
+	parent *RecordingClient
+	name   string
+}
+
+func (s *recordingSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	s.parent.record("get", obj, obj.GetNamespace(), s.name)
+	return s.SubResourceClient.Get(ctx, obj, subResource, opts...)
+}
+
+func (s *recordingSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	s.parent.record("create", obj, obj.GetNamespace(), s.name)
+	return s.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (s *recordingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	s.parent.record("update", obj, obj.GetNamespace(), s.name)
+	return s.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (s *recordingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	s.parent.record("patch", obj, obj.GetNamespace(), s.name)
+	return s.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
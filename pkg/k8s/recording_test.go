@@ -0,0 +1,65 @@
+package k8sclient
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRecordingClient(t *testing.T) {
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "ns"}}
+	inner := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).WithStatusSubresource(pod).Build()
+	c := NewRecordingClient(inner)
+	ctx := context.Background()
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.List(ctx, &appsv1.DeploymentList{}, client.InNamespace("ns")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.List(ctx, &configv1.ClusterOperatorList{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "new"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Status().Update(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Delete(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Access{
+		{Verb: "get", Resource: "pods", Namespace: "ns"},
+		{Verb: "list", Group: "apps", Resource: "deployments", Namespace: "ns"},
+		{Verb: "list", Group: "config.openshift.io", Resource: "clusteroperators"},
+		{Verb: "create", Resource: "namespaces"},
+		{Verb: "update", Resource: "pods/status", Namespace: "ns"},
+		{Verb: "delete", Resource: "pods", Namespace: "ns"},
+	}
+	if got := c.Accesses(); !slices.Equal(got, want) {
+		t.Errorf("unexpected accesses\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestAccessString(t *testing.T) {
+	if got := (Access{Verb: "get", Resource: "pods/log"}).String(); got != "get pods/log" {
+		t.Errorf("unexpected core group format %q", got)
+	}
+	if got := (Access{Verb: "list", Group: "apps", Resource: "deployments"}).String(); got != "list apps/deployments" {
+		t.Errorf("unexpected group format %q", got)
+	}
+}
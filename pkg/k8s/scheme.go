@@ -7,6 +7,7 @@ import (
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	certsv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -14,14 +15,18 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-// initScheme initializes the runtime scheme with required APIs.
-func initScheme() (*runtime.Scheme, error) {
+// NewScheme returns a runtime scheme with all APIs investigations read from clusters.
+func NewScheme() (*runtime.Scheme, error) {
 	scheme := runtime.NewScheme()
 
 	if err := corev1.AddToScheme(scheme); err != nil {
 		return nil, fmt.Errorf("unable to add corev1 scheme: %w", err)
 	}
 
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("unable to add appsv1 scheme: %w", err)
+	}
+
 	if err := batchv1.AddToScheme(scheme); err != nil {
 		return nil, fmt.Errorf("unable to add batchv1 scheme: %w", err)
 	}
@@ -50,9 +55,5 @@ func initScheme() (*runtime.Scheme, error) {
 		return nil, fmt.Errorf("unable to add policy/v1 scheme: %w", err)
 	}
 
-	if err := operatorv1.Install(scheme); err != nil {
-		return nil, fmt.Errorf("unable to add operator.openshift.io/v1 scheme: %w", err)
-	}
-
 	return scheme, nil
 }
@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	bpcloud "github.com/openshift/backplane-cli/cmd/ocm-backplane/cloud"
 	"github.com/openshift/backplane-cli/pkg/cli/config"
 	"github.com/openshift/configuration-anomaly-detection/pkg/aws"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
 	ocm "github.com/openshift/configuration-anomaly-detection/pkg/ocm"
 )
 
@@ -18,6 +22,10 @@ var (
 	backplaneInitialARN string
 	backplaneProxy      string
 	awsProxy            string
+	transportWrapper    func(http.RoundTripper) http.RoundTripper
+	gcpCredentialsFile  string
+	gcpTransportWrapper func(http.RoundTripper) http.RoundTripper
+	offline             bool
 )
 
 // SetBackplaneURL sets the backplane URL to use for managed cloud connections
@@ -44,8 +52,47 @@ func SetAWSProxy(proxy string) {
 	awsProxy = proxy
 }
 
+// SetTransportWrapper sets a wrapper for the transport of customer AWS clients, e.g. to record their traffic
+func SetTransportWrapper(wrapper func(http.RoundTripper) http.RoundTripper) {
+	transportWrapper = wrapper
+}
+
+// SetGCPCredentialsFile sets the service account key file used to access the projects of GCP clusters
+// FIXME: Replace with proper config mechanism when implemented service
+func SetGCPCredentialsFile(path string) {
+	gcpCredentialsFile = path
+}
+
+// GCPCredentialsConfigured reports whether CreateCustomerGCPClient can authenticate, i.e. whether
+// the service account key file is configured and exists, or the clients are offline.
+func GCPCredentialsConfigured() bool {
+	if offline {
+		return true
+	}
+	if gcpCredentialsFile == "" {
+		return false
+	}
+	_, err := os.Stat(gcpCredentialsFile)
+	return err == nil
+}
+
+// SetGCPTransportWrapper sets a wrapper for the transport of customer GCP clients, e.g. to record their traffic
+func SetGCPTransportWrapper(wrapper func(http.RoundTripper) http.RoundTripper) {
+	gcpTransportWrapper = wrapper
+}
+
+// SetOffline makes CreateCustomerAWSClient and CreateCustomerGCPClient skip the backplane credentials exchange and use static
+// credentials instead. It requires a transport wrapper that serves requests without network access.
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
 // CreateCustomerAWSClient creates an aws.SdkClient to a cluster's AWS account
 func CreateCustomerAWSClient(cluster *cmv1.Cluster, ocmClient ocm.Client) (*aws.SdkClient, error) {
+	if offline {
+		return createOfflineAWSClient(cluster)
+	}
+
 	if backplaneURL == "" {
 		return nil, fmt.Errorf("could not create new aws client: backplane URL not configured, call SetBackplaneURL first")
 	}
@@ -64,15 +111,66 @@ func CreateCustomerAWSClient(cluster *cmv1.Cluster, ocmClient ocm.Client) (*aws.
 		return nil, fmt.Errorf("unable to query aws credentials from backplane: %w", err)
 	}
 
+	var transport http.RoundTripper
 	if awsProxy != "" {
-		config.HTTPClient = &http.Client{
-			Transport: &http.Transport{
-				Proxy: func(*http.Request) (*url.URL, error) {
-					return url.Parse(awsProxy)
-				},
+		transport = &http.Transport{
+			Proxy: func(*http.Request) (*url.URL, error) {
+				return url.Parse(awsProxy)
 			},
 		}
 	}
+	if transportWrapper != nil {
+		transport = transportWrapper(transport)
+	}
+	if transport != nil {
+		config.HTTPClient = &http.Client{Transport: transport}
+	}
 
 	return aws.NewClient(config)
 }
+
+// createOfflineAWSClient creates an aws.SdkClient with static credentials whose requests are
+// served by the transport wrapper
+func createOfflineAWSClient(cluster *cmv1.Cluster) (*aws.SdkClient, error) {
+	if transportWrapper == nil {
+		return nil, fmt.Errorf("could not create offline aws client: no transport wrapper configured, call SetTransportWrapper first")
+	}
+	return aws.NewClient(awsv2.Config{
+		Region:           cluster.Region().ID(),
+		Credentials:      credentialsv2.NewStaticCredentialsProvider("offline", "offline", ""),
+		HTTPClient:       &http.Client{Transport: transportWrapper(nil)},
+		RetryMaxAttempts: 1,
+	})
+}
+
+// CreateCustomerGCPClient creates a gcp.SdkClient to a cluster's GCP project.
+// Backplane only hands out the project ID of GCP clusters, so the client authenticates with
+// CAD's own service account, which needs read access to the project.
+func CreateCustomerGCPClient(cluster *cmv1.Cluster) (*gcp.SdkClient, error) {
+	projectID := cluster.GCP().ProjectID()
+	if projectID == "" {
+		return nil, fmt.Errorf("could not create new gcp client: cluster %s has no GCP project", cluster.ID())
+	}
+	networkProjectID := cluster.GCPNetwork().VPCProjectID()
+
+	if offline {
+		if gcpTransportWrapper == nil {
+			return nil, fmt.Errorf("could not create offline gcp client: no transport wrapper configured, call SetGCPTransportWrapper first")
+		}
+		return gcp.NewClient(&http.Client{Transport: gcpTransportWrapper(nil)}, projectID, networkProjectID), nil
+	}
+
+	if gcpCredentialsFile == "" {
+		return nil, fmt.Errorf("could not create new gcp client: credentials file not configured, call SetGCPCredentialsFile first")
+	}
+	key, err := os.ReadFile(gcpCredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read gcp credentials file: %w", err)
+	}
+
+	var transport http.RoundTripper
+	if gcpTransportWrapper != nil {
+		transport = gcpTransportWrapper(nil)
+	}
+	return gcp.NewClientFromServiceAccountKey(key, projectID, networkProjectID, transport)
+}
@@ -23,8 +23,11 @@ func Push() {
 		promPusher.Collector(MustGatherPerformed)
 		promPusher.Collector(EtcdDatabaseAnalysis)
 		promPusher.Collector(EtcdSnapshotCleanup)
+		promPusher.Collector(EtcdRemediation)
 		promPusher.Collector(ManualInvestigationStarted)
 		promPusher.Collector(ManualInvestigationCompleted)
+		promPusher.Collector(InvestigationTimeouts)
+		promPusher.Collector(CloudCredentialsErrors)
 		err := promPusher.Add()
 		if err != nil {
 			logging.Errorf("failed to push metrics: %w", err)
@@ -108,6 +111,13 @@ var (
 			Name: "etcd_snapshot_cleanup_total",
 			Help: "counts etcd snapshot cleanup attempts by alert type and status",
 		}, []string{alertTypeLabel, "status"})
+	// EtcdRemediation tracks the steps of the etcddatabasequotalowspace remediation mode
+	EtcdRemediation = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystemInvestigate,
+			Name: "etcd_remediation_total",
+			Help: "counts etcd remediation steps by alert type, step and status",
+		}, []string{alertTypeLabel, "step", "status"})
 	// ManualInvestigationStarted tracks when manual investigations are initiated
 	ManualInvestigationStarted = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -122,4 +132,18 @@ var (
 			Name: "manual_completed_total",
 			Help: "counts manually triggered investigation completions by name, status, and dry-run mode",
 		}, []string{alertTypeLabel, statusLabel, dryRunLabel})
+	// InvestigationTimeouts tracks investigations cancelled by their configured timeout
+	InvestigationTimeouts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystemInvestigate,
+			Name: "timeouts_total",
+			Help: "counts investigations that timed out by investigation name",
+		}, []string{alertTypeLabel})
+	// CloudCredentialsErrors tracks the classes of errors getting credentials to the cloud account of clusters
+	CloudCredentialsErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace, Subsystem: subsystemInvestigate,
+			Name: "cloud_credentials_errors_total",
+			Help: "counts errors getting cloud credentials by alert type and error class",
+		}, []string{alertTypeLabel, "class"})
 )
@@ -0,0 +1,143 @@
+package networkverifier
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+const anyDestination = "0.0.0.0/0"
+
+// RunGCP checks the VPC of a GCP cluster for configurations that block the egress the cluster needs:
+// egress firewall rules denying HTTPS to the internet, and a missing default route.
+// The network verifier can't launch probe instances on GCP from CAD, so unlike Run, RunGCP reports
+// the offending firewall rules and routes instead of unreachable URLs.
+func RunGCP(ctx context.Context, cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, gcpClient gcp.Client) (result VerifierResult, failures string, err error) {
+	if clusterDeployment == nil || clusterDeployment.Spec.ClusterMetadata == nil {
+		return Undefined, "", fmt.Errorf("nil clusterDeployment")
+	}
+	infraID := clusterDeployment.Spec.ClusterMetadata.InfraID
+	network := GCPNetworkName(cluster, infraID)
+
+	logging.Infof("Checking firewall rules and routes of GCP network '%s'...", network)
+
+	rules, err := gcpClient.ListFirewallRules(ctx, network)
+	if err != nil {
+		return Undefined, "", fmt.Errorf("failed to list firewall rules: %w", err)
+	}
+	found := blockingFirewallRules(rules, infraID)
+
+	// With a cluster-wide proxy, the nodes don't need a route to the internet themselves
+	if cluster.Proxy() == nil || cluster.Proxy().Empty() {
+		routes, err := gcpClient.ListRoutes(ctx, network)
+		if err != nil {
+			return Undefined, "", fmt.Errorf("failed to list routes: %w", err)
+		}
+		if !hasDefaultRoute(routes, infraID) {
+			found = append(found, fmt.Sprintf("network %s has no route to %s", network, anyDestination))
+		}
+	}
+
+	if len(found) > 0 {
+		return Failure, strings.Join(found, ","), nil
+	}
+	return Success, "", nil
+}
+
+// GCPNetworkName returns the VPC network of a GCP cluster: the customer's network for
+// BYOVPC clusters, else the network the installer created
+func GCPNetworkName(cluster *v1.Cluster, infraID string) string {
+	if name := cluster.GCPNetwork().VPCName(); name != "" {
+		return name
+	}
+	return infraID + "-network"
+}
+
+// blockingFirewallRules describes the enabled egress rules denying HTTPS to the internet to
+// the cluster's instances, that aren't overridden by a rule allowing it with a higher priority
+func blockingFirewallRules(rules []gcp.FirewallRule, infraID string) []string {
+	// Lower numbers take precedence
+	rules = slices.Clone(rules)
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Priority < rules[j].Priority })
+
+	var blocking []string
+	for _, rule := range rules {
+		if rule.Disabled || rule.Direction != "EGRESS" || !appliesToCluster(rule.TargetTags, infraID) {
+			continue
+		}
+		if len(rule.DestinationRanges) > 0 && !slices.Contains(rule.DestinationRanges, anyDestination) {
+			continue
+		}
+		if allowsHTTPS(rule.Allowed) {
+			// Rules of lower priority can't deny the traffic anymore
+			break
+		}
+		if allowsHTTPS(rule.Denied) {
+			blocking = append(blocking, fmt.Sprintf("firewall rule %s (priority %d) denies egress to %s", rule.Name, rule.Priority, anyDestination))
+		}
+	}
+	return blocking
+}
+
+// hasDefaultRoute reports whether the cluster's instances have a route to the internet,
+// through the default internet gateway or a customer managed next hop like a NAT instance
+func hasDefaultRoute(routes []gcp.Route, infraID string) bool {
+	for _, route := range routes {
+		if route.DestRange == anyDestination && appliesToCluster(route.Tags, infraID) {
+			return true
+		}
+	}
+	return false
+}
+
+// appliesToCluster reports whether a rule or route with the given network tags applies to the cluster's
+// instances. Untagged rules apply to all instances, and the installer tags instances with the infra ID.
+func appliesToCluster(tags []string, infraID string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(tags, func(tag string) bool { return strings.HasPrefix(tag, infraID) })
+}
+
+// allowsHTTPS reports whether the protocols of a firewall rule match TCP traffic to port 443
+func allowsHTTPS(protocols []gcp.FirewallProtocol) bool {
+	for _, p := range protocols {
+		if p.IPProtocol != "all" && p.IPProtocol != "tcp" && p.IPProtocol != "6" {
+			continue
+		}
+		if len(p.Ports) == 0 {
+			return true
+		}
+		for _, ports := range p.Ports {
+			if portInRange(443, ports) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// portInRange reports whether port matches a firewall port spec, either a single port or a range like 400-500
+func portInRange(port int, spec string) bool {
+	low, high, isRange := strings.Cut(spec, "-")
+	if !isRange {
+		high = low
+	}
+	from, err := strconv.Atoi(low)
+	if err != nil {
+		return false
+	}
+	to, err := strconv.Atoi(high)
+	if err != nil {
+		return false
+	}
+	return from <= port && port <= to
+}
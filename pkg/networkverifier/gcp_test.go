@@ -0,0 +1,118 @@
+package networkverifier_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/gcp"
+	gcpmock "github.com/openshift/configuration-anomaly-detection/pkg/gcp/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/networkverifier"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("RunGCP", func() {
+	var (
+		mockCtrl          *gomock.Controller
+		gcpCli            *gcpmock.MockClient
+		cluster           *v1.Cluster
+		clusterDeployment *hivev1.ClusterDeployment
+		defaultRoute      gcp.Route
+	)
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		gcpCli = gcpmock.NewMockClient(mockCtrl)
+
+		var err error
+		cluster, err = v1.NewCluster().ID("12345").GCP(v1.NewGCP().ProjectID("project-1")).Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		clusterDeployment = &hivev1.ClusterDeployment{
+			Spec: hivev1.ClusterDeploymentSpec{
+				ClusterMetadata: &hivev1.ClusterMetadata{InfraID: "infra-1"},
+			},
+		}
+		defaultRoute = gcp.Route{Name: "default-route", DestRange: "0.0.0.0/0", NextHopGateway: "default-internet-gateway"}
+	})
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	When("the network allows egress", func() {
+		It("should succeed", func() {
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return([]gcp.FirewallRule{
+				{Name: "infra-1-api", Direction: "INGRESS", Priority: 1000, Allowed: []gcp.FirewallProtocol{{IPProtocol: "tcp", Ports: []string{"6443"}}}},
+			}, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "infra-1-network").Return([]gcp.Route{defaultRoute}, nil)
+
+			result, failures, err := networkverifier.RunGCP(context.Background(), cluster, clusterDeployment, gcpCli)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(networkverifier.Success))
+			Expect(failures).To(BeEmpty())
+		})
+	})
+
+	When("a firewall rule denies egress", func() {
+		It("should report the rule unless a higher priority rule allows HTTPS", func() {
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return([]gcp.FirewallRule{
+				{Name: "deny-all", Direction: "EGRESS", Priority: 500, Denied: []gcp.FirewallProtocol{{IPProtocol: "all"}}},
+				{Name: "allow-https", Direction: "EGRESS", Priority: 900, Allowed: []gcp.FirewallProtocol{{IPProtocol: "tcp", Ports: []string{"443"}}}},
+				{Name: "deny-other-cluster", Direction: "EGRESS", Priority: 100, TargetTags: []string{"other-cluster-worker"}, Denied: []gcp.FirewallProtocol{{IPProtocol: "all"}}},
+				{Name: "deny-disabled", Direction: "EGRESS", Priority: 100, Disabled: true, Denied: []gcp.FirewallProtocol{{IPProtocol: "all"}}},
+				{Name: "deny-ssh", Direction: "EGRESS", Priority: 100, Denied: []gcp.FirewallProtocol{{IPProtocol: "tcp", Ports: []string{"22"}}}},
+			}, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "infra-1-network").Return([]gcp.Route{defaultRoute}, nil)
+
+			result, failures, err := networkverifier.RunGCP(context.Background(), cluster, clusterDeployment, gcpCli)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(networkverifier.Failure))
+			Expect(failures).To(Equal("firewall rule deny-all (priority 500) denies egress to 0.0.0.0/0"))
+		})
+
+		It("should ignore deny rules overridden by an allow rule", func() {
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return([]gcp.FirewallRule{
+				{Name: "allow-https", Direction: "EGRESS", Priority: 100, Allowed: []gcp.FirewallProtocol{{IPProtocol: "tcp", Ports: []string{"400-500"}}}},
+				{Name: "deny-all", Direction: "EGRESS", Priority: 500, Denied: []gcp.FirewallProtocol{{IPProtocol: "all"}}},
+			}, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "infra-1-network").Return([]gcp.Route{defaultRoute}, nil)
+
+			result, _, err := networkverifier.RunGCP(context.Background(), cluster, clusterDeployment, gcpCli)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(networkverifier.Success))
+		})
+	})
+
+	When("the BYOVPC network has no default route", func() {
+		It("should report the missing route", func() {
+			cluster, err := v1.NewCluster().ID("12345").
+				GCP(v1.NewGCP().ProjectID("project-1")).
+				GCPNetwork(v1.NewGCPNetwork().VPCName("customer-vpc")).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "customer-vpc").Return(nil, nil)
+			gcpCli.EXPECT().ListRoutes(gomock.Any(), "customer-vpc").Return([]gcp.Route{
+				{Name: "subnet-route", DestRange: "10.0.0.0/16"},
+				{Name: "other-cluster-egress", DestRange: "0.0.0.0/0", Tags: []string{"other-cluster-worker"}},
+			}, nil)
+
+			result, failures, err := networkverifier.RunGCP(context.Background(), cluster, clusterDeployment, gcpCli)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(networkverifier.Failure))
+			Expect(failures).To(Equal("network customer-vpc has no route to 0.0.0.0/0"))
+		})
+	})
+
+	When("the firewall rules can't be listed", func() {
+		It("should return the error", func() {
+			gcpCli.EXPECT().ListFirewallRules(gomock.Any(), "infra-1-network").Return(nil, errors.New("permission denied"))
+
+			result, _, err := networkverifier.RunGCP(context.Background(), cluster, clusterDeployment, gcpCli)
+			Expect(err).To(MatchError("failed to list firewall rules: permission denied"))
+			Expect(result).To(Equal(networkverifier.Undefined))
+		})
+	})
+})
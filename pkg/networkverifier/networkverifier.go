@@ -33,18 +33,18 @@ const (
 // InitializeValidateEgressInput computes the input to pass to the network verifier tool.
 // If the cluster has an additional trust bundle configured, additionalTrustBundle should
 // contain the PEM-encoded CA bundle retrieved from Hive.
-func InitializeValidateEgressInput(cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsClient aws.Client, additionalTrustBundle string) (*verifier.ValidateEgressInput, error) {
+func InitializeValidateEgressInput(ctx context.Context, cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsClient aws.Client, additionalTrustBundle string) (*verifier.ValidateEgressInput, error) {
 	if clusterDeployment == nil {
 		return nil, fmt.Errorf("nil clusterDeployment")
 	}
 
 	infraID := clusterDeployment.Spec.ClusterMetadata.InfraID
-	securityGroupID, err := awsClient.GetSecurityGroupID(infraID)
+	securityGroupID, err := awsClient.GetSecurityGroupID(ctx, infraID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get SecurityGroupId: %w", err)
 	}
 
-	subnets, err := getSubnets(infraID, cluster, awsClient)
+	subnets, err := getSubnets(ctx, infraID, cluster, awsClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Subnets: %w", err)
 	}
@@ -85,7 +85,7 @@ func InitializeValidateEgressInput(cluster *v1.Cluster, clusterDeployment *hivev
 	}
 
 	return &verifier.ValidateEgressInput{
-		Ctx:          context.Background(),
+		Ctx:          ctx,
 		SubnetID:     subnet,
 		InstanceType: "t3.micro",
 		Proxy:        proxy,
@@ -98,29 +98,47 @@ func InitializeValidateEgressInput(cluster *v1.Cluster, clusterDeployment *hivev
 	}, nil
 }
 
+// Output is the outcome of a network verifier run
+type Output struct {
+	Result VerifierResult
+	// Failures are the unreachable targets with the probe's error, e.g. "https://quay.io:443 (Could not resolve host: quay.io)"
+	Failures []string
+	// Raw is the output of the verifier, including the results of all probes
+	Raw string
+}
+
 // Run runs the network verifier tool to check for network misconfigurations.
 // If the cluster has an additional trust bundle configured, it is automatically
 // retrieved via the OCM cluster resources API.
-func Run(cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsClient aws.Client, ocmClient ocm.Client) (result VerifierResult, failures string, name error) {
+func Run(ctx context.Context, cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsClient aws.Client, ocmClient ocm.Client) (result VerifierResult, failures string, name error) {
+	out, err := Verify(ctx, cluster, clusterDeployment, awsClient, ocmClient)
+	if err != nil {
+		return Undefined, "", err
+	}
+	return out.Result, strings.Join(out.Failures, ","), nil
+}
+
+// Verify runs the network verifier tool like Run, and returns the failures along with the raw output of the verifier.
+func Verify(ctx context.Context, cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsClient aws.Client, ocmClient ocm.Client) (Output, error) {
 	trustBundle, err := GetAdditionalTrustBundle(ocmClient, cluster)
 	if err != nil {
-		return Undefined, "", fmt.Errorf("failed to retrieve additional trust bundle: %w", err)
+		return Output{}, fmt.Errorf("failed to retrieve additional trust bundle: %w", err)
 	}
 
-	validateEgressInput, err := InitializeValidateEgressInput(cluster, clusterDeployment, awsClient, trustBundle)
+	validateEgressInput, err := InitializeValidateEgressInput(ctx, cluster, clusterDeployment, awsClient, trustBundle)
 	if err != nil {
-		return Undefined, "", fmt.Errorf("failed to initialize validateEgressInput: %w", err)
+		return Output{}, fmt.Errorf("failed to initialize validateEgressInput: %w", err)
 	}
 
 	// The network verifier requires a very specific type of logger (ocm logger)
 	logger, err := ocmlog.NewStdLoggerBuilder().Build()
 	if err != nil {
-		return Undefined, "", fmt.Errorf("unable to build network verifier logger: %w", err)
+		return Output{}, fmt.Errorf("unable to build network verifier logger: %w", err)
 	}
 
 	awsVerifier, err := verifierAws.NewAwsVerifierFromConfig(*awsClient.GetBaseConfig(), logger)
 	if err != nil {
-		return Undefined, "", fmt.Errorf("could not build awsVerifier %w", err)
+		return Output{}, fmt.Errorf("could not build awsVerifier %w", err)
 	}
 
 	logging.Infof("Running Network Verifier with security group '%s' - subnet '%s' - region '%s'... ", validateEgressInput.AWS.SecurityGroupIDs, validateEgressInput.SubnetID, cluster.Region().ID())
@@ -146,26 +164,26 @@ func Run(cluster *v1.Cluster, clusterDeployment *hivev1.ClusterDeployment, awsCl
 			}
 			msg += errorsSummary
 		}
-		return Undefined, "", fmt.Errorf("%s", msg)
+		return Output{}, fmt.Errorf("%s", msg)
 	}
 
 	if !out.IsSuccessful() {
-		failureSummary := ""
+		failures := make([]string, 0, len(verifierFailures))
 		for _, failure := range verifierFailures {
-			failureSummary = failureSummary + failure.Error() + ","
+			failures = append(failures, strings.ReplaceAll(failure.Error(), "egressURL error: ", ""))
 		}
 
-		return Failure, strings.TrimSuffix(strings.ReplaceAll(failureSummary, "egressURL error: ", ""), ","), nil
+		return Output{Result: Failure, Failures: failures, Raw: out.Format(true)}, nil
 	}
 
-	return Success, "", nil
+	return Output{Result: Success, Raw: out.Format(true)}, nil
 }
 
 // GetSubnets gets the private subnets for the cluster based on cluster type
-func getSubnets(infraID string, cluster *v1.Cluster, awsClient aws.Client) ([]string, error) {
+func getSubnets(ctx context.Context, infraID string, cluster *v1.Cluster, awsClient aws.Client) ([]string, error) {
 	// For non-BYOVPC clusters, retrieve private subnets by tag
 	if len(cluster.AWS().SubnetIDs()) == 0 {
-		subnets, err := awsClient.GetSubnetID(infraID)
+		subnets, err := awsClient.GetSubnetID(ctx, infraID)
 		if err != nil {
 			return nil, fmt.Errorf("could not retrieve subnet for non-BYOVPC cluster: %w", err)
 		}
@@ -184,7 +202,7 @@ func getSubnets(infraID string, cluster *v1.Cluster, awsClient aws.Client) ([]st
 	if !cluster.AWS().PrivateLink() && len(cluster.AWS().SubnetIDs()) != 0 {
 		subnets := cluster.AWS().SubnetIDs()
 		for _, subnet := range subnets {
-			isPrivate, err := awsClient.IsSubnetPrivate(subnet)
+			isPrivate, err := awsClient.IsSubnetPrivate(ctx, subnet)
 			if err != nil {
 				return []string{}, err
 			}
@@ -1,6 +1,7 @@
 package networkverifier_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -55,10 +56,10 @@ var _ = Describe("RunVerifier", func() {
 				// Arrange
 				expectedError := errors.New("failed to get SecurityGroupId: errormessage")
 
-				awsCli.EXPECT().GetSecurityGroupID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return("", expectedError)
+				awsCli.EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return("", expectedError)
 
 				// Act
-				result, failures, gotErr := networkverifier.Run(cluster, clusterDeployment, awsCli, ocmCli)
+				result, failures, gotErr := networkverifier.Run(context.Background(), cluster, clusterDeployment, awsCli, ocmCli)
 				fmt.Printf("result %v, failures %v", result, failures)
 
 				// Assert
@@ -78,11 +79,11 @@ var _ = Describe("RunVerifier", func() {
 				Expect(err).ToNot(HaveOccurred())
 
 				// Arrange
-				awsCli.EXPECT().GetSecurityGroupID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return(gomock.Any().String(), nil)
-				awsCli.EXPECT().GetSubnetID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return([]string{"string1", "string2"}, nil)
+				awsCli.EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return(gomock.Any().String(), nil)
+				awsCli.EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return([]string{"string1", "string2"}, nil)
 
 				// Act
-				input, gotErr := networkverifier.InitializeValidateEgressInput(cluster, clusterDeployment, awsCli, "")
+				input, gotErr := networkverifier.InitializeValidateEgressInput(context.Background(), cluster, clusterDeployment, awsCli, "")
 				fmt.Printf("input %v", input)
 
 				// Assert
@@ -97,10 +98,10 @@ var _ = Describe("RunVerifier", func() {
 				cluster, err := clusterBuilder.Build()
 				Expect(err).ToNot(HaveOccurred())
 
-				awsCli.EXPECT().GetSecurityGroupID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return("sg-123", nil)
-				awsCli.EXPECT().GetSubnetID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return([]string{"subnet-1"}, nil)
+				awsCli.EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return("sg-123", nil)
+				awsCli.EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return([]string{"subnet-1"}, nil)
 
-				input, gotErr := networkverifier.InitializeValidateEgressInput(cluster, clusterDeployment, awsCli, trustBundle)
+				input, gotErr := networkverifier.InitializeValidateEgressInput(context.Background(), cluster, clusterDeployment, awsCli, trustBundle)
 
 				Expect(gotErr).ToNot(HaveOccurred())
 				Expect(input.Proxy.Cacert).To(Equal(trustBundle))
@@ -112,10 +113,10 @@ var _ = Describe("RunVerifier", func() {
 				cluster, err := clusterBuilder.Build()
 				Expect(err).ToNot(HaveOccurred())
 
-				awsCli.EXPECT().GetSecurityGroupID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return("sg-123", nil)
-				awsCli.EXPECT().GetSubnetID(gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return([]string{"subnet-1"}, nil)
+				awsCli.EXPECT().GetSecurityGroupID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return("sg-123", nil)
+				awsCli.EXPECT().GetSubnetID(gomock.Any(), gomock.Eq(clusterDeployment.Spec.ClusterMetadata.InfraID)).Return([]string{"subnet-1"}, nil)
 
-				_, gotErr := networkverifier.InitializeValidateEgressInput(cluster, clusterDeployment, awsCli, "")
+				_, gotErr := networkverifier.InitializeValidateEgressInput(context.Background(), cluster, clusterDeployment, awsCli, "")
 
 				Expect(gotErr).To(HaveOccurred())
 				Expect(gotErr.Error()).To(ContainSubstring("could not be retrieved"))
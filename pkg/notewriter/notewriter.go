@@ -3,12 +3,16 @@ package notewriter
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"go.uber.org/zap"
 )
 
+// NoteWriter is safe for concurrent use, as the controller reads the notes of investigations that
+// timed out while they may still be running.
 type NoteWriter struct {
 	investigationName string
+	mu                sync.Mutex
 	sb                strings.Builder
 	logger            *zap.SugaredLogger
 }
@@ -22,7 +26,7 @@ type NoteWriter struct {
 // 🤖 Automated CHGM pre-investigation 🤖
 // ===========================
 func New(investigationName string, logger *zap.SugaredLogger) *NoteWriter {
-	nw := &NoteWriter{investigationName, strings.Builder{}, logger}
+	nw := &NoteWriter{investigationName: investigationName, logger: logger}
 	fmt.Fprintf(&nw.sb, "🤖 Automated %s pre-investigation 🤖\n", investigationName)
 	nw.sb.WriteString("===========================\n")
 	return nw
@@ -30,6 +34,8 @@ func New(investigationName string, logger *zap.SugaredLogger) *NoteWriter {
 
 // String() returns the current full string format of the built note
 func (n *NoteWriter) String() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	return n.sb.String()
 }
 
@@ -38,6 +44,8 @@ func (n *NoteWriter) writeWithLog(format string, a ...any) {
 		n.logger.Infof(format, a...)
 	}
 
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	fmt.Fprintf(&n.sb, format, a...)
 }
 
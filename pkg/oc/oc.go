@@ -18,7 +18,7 @@ import (
 )
 
 type Client interface {
-	CreateMustGather(additionalFlags []string) error
+	CreateMustGather(ctx context.Context, additionalFlags []string) error
 	Clean() error
 }
 
@@ -43,9 +43,9 @@ func (c *clientImpl) Clean() error {
 	return c.cleanupKubeConfigFile()
 }
 
-func (c *clientImpl) CreateMustGather(additionalFlags []string) error {
+func (c *clientImpl) CreateMustGather(ctx context.Context, additionalFlags []string) error {
 	// Handle sigints and sigterms
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	signalChan := make(chan os.Signal, 1)
@@ -69,6 +69,9 @@ func (c *clientImpl) CreateMustGather(additionalFlags []string) error {
 
 	err := cmd.Run()
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("'oc adm must-gather' timed out: %w", ctx.Err())
+		}
 		if errors.Is(ctx.Err(), context.Canceled) {
 			return fmt.Errorf("command was canceled by user (e.g., Ctrl+C): %w", err)
 		}
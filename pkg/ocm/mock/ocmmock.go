@@ -163,6 +163,21 @@ func (mr *MockClientMockRecorder) GetDynatraceURL(cluster any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDynatraceURL", reflect.TypeOf((*MockClient)(nil).GetDynatraceURL), cluster)
 }
 
+// GetInstallLog mocks base method.
+func (m *MockClient) GetInstallLog(internalClusterID string, tailLines int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstallLog", internalClusterID, tailLines)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstallLog indicates an expected call of GetInstallLog.
+func (mr *MockClientMockRecorder) GetInstallLog(internalClusterID, tailLines any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstallLog", reflect.TypeOf((*MockClient)(nil).GetInstallLog), internalClusterID, tailLines)
+}
+
 // GetOrganizationID mocks base method.
 func (m *MockClient) GetOrganizationID(clusterID string) (string, error) {
 	m.ctrl.T.Helper()
@@ -2,6 +2,7 @@
 package ocm
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -59,20 +60,28 @@ type Client interface {
 	CheckIfUserBanned(cluster *cmv1.Cluster) error
 	GetCreatorFromCluster(cluster *cmv1.Cluster) (*amv1.Account, error)
 	GetSyncSets(internalClusterID string) ([]hivev1.SyncSet, error)
+	GetInstallLog(internalClusterID string, tailLines int) (string, error)
 }
 
 // SdkClient is the ocm client with which we can run the commands
 // currently we do not need to export the connection or the config, as we create the SdkClient using the New func
 type SdkClient struct {
 	conn *sdk.Connection
+	// ctx cancels the requests of the client, nil sends them with context.Background()
+	ctx context.Context
 }
 
-// New will create a new ocm client using the provided credentials
-func New(clientID, clientSecret, url string) (*SdkClient, error) {
+// offlineToken is an unsigned access token that never expires. The SDK does not verify token
+// signatures, so it lets an offline connection authenticate against replayed responses.
+const offlineToken = "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJ0eXAiOiJCZWFyZXIiLCJleHAiOjQxMDI0NDQ4MDB9."
+
+// New will create a new ocm client using the provided credentials.
+// Optional transport wrappers intercept the traffic of the connection, e.g. to record it.
+func New(clientID, clientSecret, url string, wrappers ...sdk.TransportWrapper) (*SdkClient, error) {
 	var err error
 	client := SdkClient{}
 
-	client.conn, err = newConnectionFromClientPair(clientID, clientSecret, url)
+	client.conn, err = newConnectionFromClientPair(clientID, clientSecret, url, wrappers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection from client key pair: %w", err)
 	}
@@ -80,13 +89,46 @@ func New(clientID, clientSecret, url string) (*SdkClient, error) {
 	return &client, nil
 }
 
+// NewOffline creates an ocm client that authenticates with a static token and sends all
+// requests through wrapper, which is expected to serve them without network access.
+func NewOffline(url string, wrapper sdk.TransportWrapper) (*SdkClient, error) {
+	if url == "" || wrapper == nil {
+		return nil, fmt.Errorf("missing required parameters: url or transport wrapper")
+	}
+	conn, err := sdk.NewConnectionBuilder().URL(url).Tokens(offlineToken).TransportWrapper(wrapper).RetryLimit(0).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create offline connection: %w", err)
+	}
+	return &SdkClient{conn: conn}, nil
+}
+
 // newConnectionFromClientPair creates a new connection via set of client ID, client secret
 // and the target OCM API URL.
-func newConnectionFromClientPair(clientID, clientSecret, url string) (*sdk.Connection, error) {
+func newConnectionFromClientPair(clientID, clientSecret, url string, wrappers []sdk.TransportWrapper) (*sdk.Connection, error) {
 	if clientID == "" || clientSecret == "" || url == "" {
 		return nil, fmt.Errorf("missing required parameters: clientID, clientSecret, or url")
 	}
-	return sdk.NewConnectionBuilder().URL(url).Client(clientID, clientSecret).Insecure(false).Build()
+	builder := sdk.NewConnectionBuilder().URL(url).Client(clientID, clientSecret).Insecure(false)
+	for _, wrapper := range wrappers {
+		builder = builder.TransportWrapper(wrapper)
+	}
+	return builder.Build()
+}
+
+// WithContext returns a copy of the client sharing its connection, whose requests are cancelled with ctx
+func (c *SdkClient) WithContext(ctx context.Context) *SdkClient {
+	if c == nil {
+		return nil
+	}
+	return &SdkClient{conn: c.conn, ctx: ctx}
+}
+
+// requestContext returns the context the requests of the client are sent with
+func (c *SdkClient) requestContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // GetSupportRoleARN returns the support role ARN that allows the access to the cluster from internal cluster ID
@@ -123,7 +165,7 @@ func (c *SdkClient) GetAWSAccountClaim(internalClusterID string) (*awsv1alpha1.A
 // Returns a v1.Cluster object or an error
 func (c *SdkClient) GetClusterInfo(identifier string) (*cmv1.Cluster, error) {
 	q := fmt.Sprintf("(id like '%[1]s' or external_id like '%[1]s' or display_name like '%[1]s')", identifier)
-	resp, err := c.conn.ClustersMgmt().V1().Clusters().List().Search(q).Send()
+	resp, err := c.conn.ClustersMgmt().V1().Clusters().List().Search(q).SendContext(c.requestContext())
 	if err != nil || resp.Error() != nil || resp.Status() != http.StatusOK {
 		return nil, fmt.Errorf("received error while fetch ClusterInfo from ocm: %w with resp %#v", err, resp)
 	}
@@ -149,7 +191,7 @@ func (c *SdkClient) GetOrganizationID(clusterID string) (string, error) {
 		return "", nil
 	}
 
-	subscriptionResponse, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(cmv1Subscription.ID()).Get().Send()
+	subscriptionResponse, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(cmv1Subscription.ID()).Get().SendContext(c.requestContext())
 	if err != nil {
 		return "", err
 	}
@@ -178,7 +220,7 @@ func (c *SdkClient) GetClusterDeployment(internalClusterID string) (*hivev1.Clus
 
 // GetClusterMachinePools gets the machine pools for a given cluster
 func (c *SdkClient) GetClusterMachinePools(internalClusterID string) ([]*cmv1.MachinePool, error) {
-	response, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(internalClusterID).MachinePools().List().Page(1).Size(-1).Send()
+	response, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(internalClusterID).MachinePools().List().Page(1).Size(-1).SendContext(c.requestContext())
 	if err != nil {
 		return nil, err
 	}
@@ -187,7 +229,7 @@ func (c *SdkClient) GetClusterMachinePools(internalClusterID string) ([]*cmv1.Ma
 
 // getClusterResource allows to load different cluster resources
 func (c *SdkClient) getClusterResource(internalClusterID string, resourceKey string) (string, error) {
-	response, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(internalClusterID).Resources().Live().Get().Send()
+	response, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(internalClusterID).Resources().Live().Get().SendContext(c.requestContext())
 	if err != nil {
 		return "", err
 	}
@@ -221,7 +263,7 @@ func (c *SdkClient) PostLimitedSupportReason(cluster *cmv1.Cluster, limitedSuppo
 
 	request := c.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).LimitedSupportReasons().Add()
 	request = request.Body(ls)
-	resp, err := request.Send()
+	resp, err := request.SendContext(c.requestContext())
 	if err != nil && !strings.Contains(err.Error(), "Operation is not allowed for a cluster in 'uninstalling' state") {
 		return fmt.Errorf("received error from ocm: %w. Full Response: %#v", err, resp)
 	}
@@ -233,9 +275,9 @@ func (c *SdkClient) PostLimitedSupportReason(cluster *cmv1.Cluster, limitedSuppo
 // When supplying a filter it will use the Search call and pass it to this one directly.
 func (c *SdkClient) GetServiceLog(cluster *cmv1.Cluster, filter string) (*servicelogsv1.ClusterLogsUUIDListResponse, error) {
 	if filter != "" {
-		return c.conn.ServiceLogs().V1().Clusters().Cluster(cluster.ExternalID()).ClusterLogs().List().Search(filter).Send()
+		return c.conn.ServiceLogs().V1().Clusters().Cluster(cluster.ExternalID()).ClusterLogs().List().Search(filter).SendContext(c.requestContext())
 	}
-	return c.conn.ServiceLogs().V1().Clusters().Cluster(cluster.ExternalID()).ClusterLogs().List().Send()
+	return c.conn.ServiceLogs().V1().Clusters().Cluster(cluster.ExternalID()).ClusterLogs().List().SendContext(c.requestContext())
 }
 
 // PostServiceLog allows to send a generic servicelog to a cluster.
@@ -271,7 +313,7 @@ func (c *SdkClient) PostServiceLog(cluster *cmv1.Cluster, sl *ServiceLog) error
 	request := c.conn.ServiceLogs().V1().ClusterLogs().Add()
 	request = request.Body(le)
 
-	if _, err = request.Send(); err != nil {
+	if _, err = request.SendContext(c.requestContext()); err != nil {
 		return fmt.Errorf("could not post service log %s: %w", sl.Summary, err)
 	}
 
@@ -297,7 +339,7 @@ func (c *SdkClient) AwsClassicJumpRoleCompatible(cluster *cmv1.Cluster) (bool, e
 		return true, nil
 	}
 
-	resp, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).StsSupportJumpRole().Get().Send()
+	resp, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).StsSupportJumpRole().Get().SendContext(c.requestContext())
 	if err != nil {
 		return false, fmt.Errorf("could not query sts support role from ocm: %w", err)
 	}
@@ -320,7 +362,7 @@ func (c *SdkClient) GetConnection() *sdk.Connection {
 
 // IsAccessProtected returns whether access protection is enabled for a cluster
 func (c *SdkClient) IsAccessProtected(cluster *cmv1.Cluster) (bool, error) {
-	resp, err := c.conn.AccessTransparency().V1().AccessProtection().Get().ClusterId(cluster.ID()).Send()
+	resp, err := c.conn.AccessTransparency().V1().AccessProtection().Get().ClusterId(cluster.ID()).SendContext(c.requestContext())
 	if err != nil {
 		return false, fmt.Errorf("could not query access protection status from ocm: %w", err)
 	}
@@ -332,7 +374,7 @@ func (c *SdkClient) IsAccessProtected(cluster *cmv1.Cluster) (bool, error) {
 }
 
 func (c *SdkClient) GetClusterHypershiftConfig(cluster *cmv1.Cluster) (*cmv1.HypershiftConfig, error) {
-	resp, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).Hypershift().Get().Send()
+	resp, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(cluster.ID()).Hypershift().Get().SendContext(c.requestContext())
 	if err != nil {
 		return nil, fmt.Errorf("could not query hypershift status from ocm: %w", err)
 	}
@@ -368,7 +410,7 @@ const (
 // IsManagingCluster returns true if the cluster is a managing cluster,
 // meaning it is one of: hive shard, service cluster, or management cluster.
 func (c *SdkClient) IsManagingCluster(clusterID string) (bool, error) {
-	resp, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).ExternalConfiguration().Labels().List().Send()
+	resp, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).ExternalConfiguration().Labels().List().SendContext(c.requestContext())
 	if err != nil {
 		return false, fmt.Errorf("failed to fetch external configuration labels for cluster %s: %w", clusterID, err)
 	}
@@ -392,7 +434,7 @@ func (c *SdkClient) GetCreatorFromCluster(cluster *cmv1.Cluster) (*amv1.Account,
 	if !ok {
 		return nil, fmt.Errorf("failed to get subscription from cluster: %s", cluster.ID())
 	}
-	subscriptionResponse, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(cmv1Subscription.ID()).Get().Send()
+	subscriptionResponse, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(cmv1Subscription.ID()).Get().SendContext(c.requestContext())
 	if err != nil {
 		return nil, err
 	}
@@ -406,7 +448,7 @@ func (c *SdkClient) GetCreatorFromCluster(cluster *cmv1.Cluster) (*amv1.Account,
 		return nil, fmt.Errorf("expecting status 'Active' found %v", status)
 	}
 
-	accountResponse, err := c.conn.AccountsMgmt().V1().Accounts().Account(subscription.Creator().ID()).Get().Send()
+	accountResponse, err := c.conn.AccountsMgmt().V1().Accounts().Account(subscription.Creator().ID()).Get().SendContext(c.requestContext())
 	if err != nil {
 		return nil, err
 	}
@@ -427,7 +469,7 @@ func (c *SdkClient) GetDynatraceURL(cluster *cmv1.Cluster) (string, error) {
 		return "", fmt.Errorf("failed to get subscription from cluster: %s", cluster.ID())
 	}
 
-	subscriptionLabels, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(cmv1Subscription.ID()).Labels().List().Send()
+	subscriptionLabels, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(cmv1Subscription.ID()).Labels().List().SendContext(c.requestContext())
 	if err != nil {
 		return "", fmt.Errorf("failed to get subscription labels: %w", err)
 	}
@@ -472,6 +514,19 @@ func (c *SdkClient) GetSyncSets(internalClusterID string) ([]hivev1.SyncSet, err
 	return ssList.Items, nil
 }
 
+// GetInstallLog returns the last tailLines lines of the install log hive collected for a cluster.
+// The log is empty until the installer started.
+func (c *SdkClient) GetInstallLog(internalClusterID string, tailLines int) (string, error) {
+	response, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(internalClusterID).Logs().Install().Get().Tail(tailLines).SendContext(c.requestContext())
+	if err != nil {
+		if response != nil && response.Status() == http.StatusNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("client failed to load the install log: %w", err)
+	}
+	return response.Body().Content(), nil
+}
+
 func (e UserBannedError) Error() string {
 	return fmt.Sprintf("user is banned (%s): %s", e.Code, e.Description)
 }
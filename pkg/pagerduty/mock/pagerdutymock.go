@@ -40,17 +40,31 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 }
 
 // AddNote mocks base method.
-func (m *MockClient) AddNote(notes string) error {
+func (m *MockClient) AddNote(note string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddNote", notes)
+	ret := m.ctrl.Call(m, "AddNote", note)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddNote indicates an expected call of AddNote.
-func (mr *MockClientMockRecorder) AddNote(notes any) *gomock.Call {
+func (mr *MockClientMockRecorder) AddNote(note any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNote", reflect.TypeOf((*MockClient)(nil).AddNote), notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNote", reflect.TypeOf((*MockClient)(nil).AddNote), note)
+}
+
+// AddNoteToIncident mocks base method.
+func (m *MockClient) AddNoteToIncident(incidentID, noteContent string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddNoteToIncident", incidentID, noteContent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddNoteToIncident indicates an expected call of AddNoteToIncident.
+func (mr *MockClientMockRecorder) AddNoteToIncident(incidentID, noteContent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNoteToIncident", reflect.TypeOf((*MockClient)(nil).AddNoteToIncident), incidentID, noteContent)
 }
 
 // EscalateIncident mocks base method.
@@ -68,17 +82,31 @@ func (mr *MockClientMockRecorder) EscalateIncident() *gomock.Call {
 }
 
 // EscalateIncidentWithNote mocks base method.
-func (m *MockClient) EscalateIncidentWithNote(notes string) error {
+func (m *MockClient) EscalateIncidentWithNote(note string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EscalateIncidentWithNote", notes)
+	ret := m.ctrl.Call(m, "EscalateIncidentWithNote", note)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // EscalateIncidentWithNote indicates an expected call of EscalateIncidentWithNote.
-func (mr *MockClientMockRecorder) EscalateIncidentWithNote(notes any) *gomock.Call {
+func (mr *MockClientMockRecorder) EscalateIncidentWithNote(note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateIncidentWithNote", reflect.TypeOf((*MockClient)(nil).EscalateIncidentWithNote), note)
+}
+
+// GetIncidentID mocks base method.
+func (m *MockClient) GetIncidentID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIncidentID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetIncidentID indicates an expected call of GetIncidentID.
+func (mr *MockClientMockRecorder) GetIncidentID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateIncidentWithNote", reflect.TypeOf((*MockClient)(nil).EscalateIncidentWithNote), notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIncidentID", reflect.TypeOf((*MockClient)(nil).GetIncidentID))
 }
 
 // GetServiceID mocks base method.
@@ -153,17 +181,17 @@ func (mr *MockClientMockRecorder) SilenceIncident() *gomock.Call {
 }
 
 // SilenceIncidentWithNote mocks base method.
-func (m *MockClient) SilenceIncidentWithNote(notes string) error {
+func (m *MockClient) SilenceIncidentWithNote(note string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SilenceIncidentWithNote", notes)
+	ret := m.ctrl.Call(m, "SilenceIncidentWithNote", note)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SilenceIncidentWithNote indicates an expected call of SilenceIncidentWithNote.
-func (mr *MockClientMockRecorder) SilenceIncidentWithNote(notes any) *gomock.Call {
+func (mr *MockClientMockRecorder) SilenceIncidentWithNote(note any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SilenceIncidentWithNote", reflect.TypeOf((*MockClient)(nil).SilenceIncidentWithNote), notes)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SilenceIncidentWithNote", reflect.TypeOf((*MockClient)(nil).SilenceIncidentWithNote), note)
 }
 
 // UpdateIncidentTitle mocks base method.
@@ -8,12 +8,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"regexp"
 	"strings"
 	"time"
 
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
-	"gopkg.in/yaml.v3"
 
 	sdk "github.com/PagerDuty/go-pagerduty"
 )
@@ -32,16 +32,9 @@ const (
 
 // Client is the interface exposing pagerduty functions
 type Client interface {
-	AddNote(notes string) error
-	EscalateIncident() error
-	EscalateIncidentWithNote(notes string) error
-	GetServiceID() string
-	GetTitle() string
+	incident.Backend
 	MoveToEscalationPolicy(escalationPolicyID string) error
-	RetrieveClusterID() (string, error)
-	SilenceIncident() error
-	SilenceIncidentWithNote(notes string) error
-	UpdateIncidentTitle(title string) error
+	AddNoteToIncident(incidentID string, noteContent string) error
 }
 
 // SdkClient will hold all the required fields for any SdkClient Operation
@@ -54,6 +47,8 @@ type SdkClient struct {
 	incidentData *IncidentData
 	// clusterID ( only gets initialized after the first GetclusterID call )
 	clusterID *string
+	// clusterIDExtractor extracts the cluster ID from alert bodies, defaults to clusterid.Default()
+	clusterIDExtractor *clusterid.Extractor
 }
 
 // GetPDClient will retrieve the PagerDuty from the 'pagerduty' package
@@ -117,6 +112,27 @@ func (c *SdkClient) initializeIncidentData(payload []byte) (*IncidentData, error
 	return incidentData, nil
 }
 
+// ParseIncidentData extracts the incident data from a webhook payload without calling the
+// PagerDuty API. Event orchestration payloads only carry the incident ID, so all other
+// fields are left empty for them.
+func ParseIncidentData(payload []byte) (*IncidentData, error) {
+	if unmarshalled, err := unmarshalWebhookV3(payload); err == nil {
+		return &IncidentData{
+			IncidentTitle:  unmarshalled.Event.Data.Title,
+			IncidentID:     unmarshalled.Event.Data.IncidentID,
+			IncidentRef:    unmarshalled.Event.Data.IncidentRef,
+			ServiceID:      unmarshalled.Event.Data.Service.ServiceID,
+			ServiceSummary: unmarshalled.Event.Data.Service.Summary,
+		}, nil
+	}
+
+	unmarshalled, err := unmarshalEventOrchestrationWebhook(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &IncidentData{IncidentID: unmarshalled.PDMetadata.Incident.ID}, nil
+}
+
 // webhookV3 is a struct to fill with information we parse out of the webhook
 // The data field schema can differ depending on the event type that triggered the webhook.
 // https://developer.pagerduty.com/docs/db0fa8c8984fc-overview#event-data-types
@@ -244,6 +260,18 @@ func (c *SdkClient) GetSilentEscalationPolicy() string {
 	return c.silentEscalationPolicy
 }
 
+// SetClusterIDExtractor sets the rules used by RetrieveClusterID to find the cluster ID in alert bodies
+func (c *SdkClient) SetClusterIDExtractor(extractor *clusterid.Extractor) {
+	c.clusterIDExtractor = extractor
+}
+
+func (c *SdkClient) getClusterIDExtractor() *clusterid.Extractor {
+	if c.clusterIDExtractor == nil {
+		c.clusterIDExtractor = clusterid.Default()
+	}
+	return c.clusterIDExtractor
+}
+
 // GetIncidentRef returns a link to the pagerduty incident
 func (c *SdkClient) GetIncidentRef() string {
 	return c.incidentData.IncidentRef
@@ -398,7 +426,7 @@ func (c *SdkClient) GetAlertsForIncident(incidentID string) (*[]sdk.IncidentAler
 func (c *SdkClient) GetAlertListDetails(alertList *[]sdk.IncidentAlert) ([]AlertDetails, error) {
 	res := []AlertDetails{}
 	for _, alert := range *alertList {
-		alertDetails, err := extractAlertDetails(alert)
+		alertDetails, err := c.extractAlertDetails(alert)
 		if err != nil {
 			return nil, fmt.Errorf("could not extract alert details from alert '%s': %w", alert.ID, err)
 		}
@@ -407,97 +435,52 @@ func (c *SdkClient) GetAlertListDetails(alertList *[]sdk.IncidentAlert) ([]Alert
 	return res, nil
 }
 
-type notesData struct {
-	ClusterID string `yaml:"cluster_id"`
-}
-
-// clusterIDFromFiringRe matches standalone "cluster_id = <value>" within a (potentially
-// multi-line) firing alert text. The named capture group "id" extracts the value.
-// (?:^|\s) requires either start-of-string or a whitespace character immediately before
-// "cluster_id", preventing false matches inside compound names such as "hosted_cluster_id".
-// \s includes \n, so the anchor correctly finds cluster_id on any line of a multi-line payload.
-var clusterIDFromFiringRe = regexp.MustCompile(`(?:^|\s)cluster_id = (?P<id>\S+)`)
-
-func extractClusterIDFromAlertBody(data map[string]interface{}) (string, error) {
-	details, found := data["details"].(map[string]interface{})
-	if !found {
-		return "", fmt.Errorf("could not find alert details field: %s", data)
+// AlertBodies returns the alert bodies contained in a captured payload, so cluster ID rules can
+// be tested against it. The payload may be a single alert body, an alert (with a "body" field),
+// a list of alerts, or a list incident alerts API response ({"alerts": [...]}).
+func AlertBodies(payload []byte) ([]map[string]interface{}, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return nil, UnmarshalError{Err: err}
+	}
+
+	var items []interface{}
+	switch v := decoded.(type) {
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		if alerts, ok := v["alerts"].([]interface{}); ok {
+			items = alerts
+		} else {
+			items = []interface{}{v}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported payload type %T", decoded)
 	}
 
-	type extractor = func(map[string]interface{}) (string, error)
-	extractors := []extractor{parseClusterIdFromField, parseClusterIdFromNotes, parseClusterIdFromFiring}
-	var errs []error
-	for _, extractor := range extractors {
-		id, err := extractor(details)
-		if err != nil {
-			logging.Info("failed to extract cluster id (continuing): %s", err)
-			errs = append(errs, err)
+	bodies := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		alert, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("alert %d is not an object", i)
 		}
-		if id != "" {
-			return id, nil
+		if body, ok := alert["body"].(map[string]interface{}); ok {
+			alert = body
 		}
+		bodies = append(bodies, alert)
 	}
-	mergedErr := errors.Join(errs...)
-	logging.Info("failed to extract cluster id ( terminally ): %s", mergedErr)
-	return "", mergedErr
-}
-
-// PARSE OPTION 1 (new format): cluster_id directly contained in custom details
-func parseClusterIdFromField(details map[string]interface{}) (string, error) {
-	clusterID, found := details["cluster_id"].(string)
-	if !found {
-		logging.Warn("Unable to parse cluster_id as direct field directly from the alert details.")
-	} else {
-		return clusterID, nil
-	}
-	return "", errors.New("'cluster_id' field not found")
-}
-
-// PARSE OPTION 2 (old format: OSD-18006): cluster_id contained in custom_details[notes]
-// We have quite a few alerts fired from a few months ago that still are in this format.
-// We will have to wait a bit until we remove the backwards compatibility.
-// In theory, it's not a big issue that the alerts fail to get handled by CAD, as this
-// only affects alerts that already exist, and re-pass CAD for the 'resolve' state.
-// We still don't want to many failing pipelines though.
-func parseClusterIdFromNotes(details map[string]interface{}) (string, error) {
-	notes, found := details["notes"].(string)
-	if !found {
-		return "", errors.New("'notes' field not found")
-	}
-	logging.Warn("Trying to parse cluster_id from the notes field...")
-	var notesUnmarshalled notesData
-	if err := yaml.Unmarshal([]byte(notes), &notesUnmarshalled); err != nil {
-		return "", fmt.Errorf("error decoding notes YAML: %w", err)
-	}
-	if notesUnmarshalled.ClusterID == "" {
-		return "", errors.New("could not find cluster_id field in notes")
-	}
-	return notesUnmarshalled.ClusterID, nil
-}
-
-// PARSE OPTION 3 (HCPNodepoolUpgradeDelay): these alerts have their own format and set neither
-// 'notes' nor 'cluster_id', so the ID must be extracted from the free-text 'firing' field.
-func parseClusterIdFromFiring(details map[string]interface{}) (string, error) {
-	logging.Warn("Trying to parse 'cluster_id = <id>' free text from 'firing'")
-	firing, found := details["firing"].(string)
-	if !found {
-		return "", errors.New("could not find firing field")
-	}
-	match := clusterIDFromFiringRe.FindStringSubmatch(firing)
-	idIdx := clusterIDFromFiringRe.SubexpIndex("id")
-	if idIdx < 0 || idIdx >= len(match) {
-		return "", errors.New("no cluster_id found in firing field")
-	}
-	return match[idIdx], nil
+	return bodies, nil
 }
 
 // extractAlertDetails will extract required details from a sdk.IncidentAlert
-func extractAlertDetails(sdkAlert sdk.IncidentAlert) (AlertDetails, error) {
+func (c *SdkClient) extractAlertDetails(sdkAlert sdk.IncidentAlert) (AlertDetails, error) {
 	logging.Debugf("Extracting clusterID from alert body: %s", sdkAlert.Body)
-	clusterID, err := extractClusterIDFromAlertBody(sdkAlert.Body)
+	clusterID, rule, err := c.getClusterIDExtractor().Extract(clusterid.SourcePagerDuty, sdkAlert.Body)
 	if err != nil {
-		return AlertDetails{}, fmt.Errorf("failed to extractClusterIDFromAlertBody: %w", err)
+		logging.Info("failed to extract cluster id ( terminally ): %s", err)
+		return AlertDetails{}, fmt.Errorf("failed to extract cluster ID from alert body: %w", err)
 	}
+	logging.Debugf("Extracted clusterID with rule %q", rule)
 
 	alertDetails := AlertDetails{
 		ID:        sdkAlert.ID,
@@ -40,9 +40,9 @@ type RegistryValidationResult struct {
 }
 
 // GetPullSecret retrieves the pull secret from the cluster
-func GetPullSecret(k8scli client.Client) (*corev1.Secret, error) {
+func GetPullSecret(ctx context.Context, k8scli client.Client) (*corev1.Secret, error) {
 	secret := &corev1.Secret{}
-	err := k8scli.Get(context.TODO(), k8stypes.NamespacedName{
+	err := k8scli.Get(ctx, k8stypes.NamespacedName{
 		Namespace: PullSecretNamespace,
 		Name:      PullSecretName,
 	}, secret)
@@ -117,8 +117,8 @@ func ValidateEmailWithSecret(secret *corev1.Secret, ocmEmail string) *Validation
 }
 
 // ValidateEmail validates the pull secret email against the OCM account email
-func ValidateEmail(k8scli client.Client, ocmEmail string) *ValidationResult {
-	secret, err := GetPullSecret(k8scli)
+func ValidateEmail(ctx context.Context, k8scli client.Client, ocmEmail string) *ValidationResult {
+	secret, err := GetPullSecret(ctx, k8scli)
 	if err != nil {
 		result := &ValidationResult{IsValid: false}
 		result.AddWarning("Failed to get pull secret from cluster: %v", err)
@@ -128,11 +128,11 @@ func ValidateEmail(k8scli client.Client, ocmEmail string) *ValidationResult {
 }
 
 // ValidateRegistryCredentials validates the cluster pull secret against OCM registry credentials
-func ValidateRegistryCredentials(k8scli client.Client, ocmConn *sdk.Connection, accountID string, ocmEmail string) (*ValidationResult, []RegistryValidationResult) {
+func ValidateRegistryCredentials(ctx context.Context, k8scli client.Client, ocmConn *sdk.Connection, accountID string, ocmEmail string) (*ValidationResult, []RegistryValidationResult) {
 	result := &ValidationResult{IsValid: true}
 
 	// Get the pull secret from the cluster
-	secret, err := GetPullSecret(k8scli)
+	secret, err := GetPullSecret(ctx, k8scli)
 	if err != nil {
 		result.IsValid = false
 		result.AddWarning("Failed to get pull secret from cluster: %v", err)
@@ -140,7 +140,7 @@ func ValidateRegistryCredentials(k8scli client.Client, ocmConn *sdk.Connection,
 	}
 
 	// get credentials from OCM
-	registryCredentials, err := getOCMRegistryCredentials(ocmConn, accountID)
+	registryCredentials, err := getOCMRegistryCredentials(ctx, ocmConn, accountID)
 	if err != nil {
 		result.IsValid = false
 		result.AddWarning("Failed to get registry credentials from OCM: %v", err)
@@ -182,7 +182,7 @@ func ValidateRegistryCredentials(k8scli client.Client, ocmConn *sdk.Connection,
 		}
 
 		registryID := reg.ID()
-		registry, err := getRegistryFromOCM(ocmConn, registryID)
+		registry, err := getRegistryFromOCM(ctx, ocmConn, registryID)
 		if err != nil {
 			regResult.Registry = registryID
 			regResult.Error = fmt.Errorf("failed to fetch registry from OCM: %w", err)
@@ -264,9 +264,9 @@ func ValidateRegistryCredentials(k8scli client.Client, ocmConn *sdk.Connection,
 }
 
 // getOCMRegistryCredentials fetches registry credentials for an account from OCM
-func getOCMRegistryCredentials(ocmConn *sdk.Connection, accountID string) ([]*v1.RegistryCredential, error) {
+func getOCMRegistryCredentials(ctx context.Context, ocmConn *sdk.Connection, accountID string) ([]*v1.RegistryCredential, error) {
 	searchString := fmt.Sprintf("account_id = '%s'", accountID)
-	response, err := ocmConn.AccountsMgmt().V1().RegistryCredentials().List().Search(searchString).Send()
+	response, err := ocmConn.AccountsMgmt().V1().RegistryCredentials().List().Search(searchString).SendContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -274,8 +274,8 @@ func getOCMRegistryCredentials(ocmConn *sdk.Connection, accountID string) ([]*v1
 }
 
 // getRegistryFromOCM fetches registry details from OCM
-func getRegistryFromOCM(ocmConn *sdk.Connection, registryID string) (*v1.Registry, error) {
-	response, err := ocmConn.AccountsMgmt().V1().Registries().Registry(registryID).Get().Send()
+func getRegistryFromOCM(ctx context.Context, ocmConn *sdk.Connection, registryID string) (*v1.Registry, error) {
+	response, err := ocmConn.AccountsMgmt().V1().Registries().Registry(registryID).Get().SendContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package pullsecret
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -251,7 +252,7 @@ func TestValidateEmail(t *testing.T) {
 			secret := createTestSecret(tt.secretData)
 			k8scli := fake.NewClientBuilder().WithObjects(secret).Build()
 
-			result := ValidateEmail(k8scli, tt.ocmEmail)
+			result := ValidateEmail(context.Background(), k8scli, tt.ocmEmail)
 
 			if result.IsValid != tt.expectedValid {
 				t.Errorf("expected IsValid=%v, got %v (warnings: %v)", tt.expectedValid, result.IsValid, result.Warnings)
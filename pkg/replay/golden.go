@@ -0,0 +1,75 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteGolden stores actions as the golden file of the fixture in dir
+func WriteGolden(dir string, actions any) error {
+	return writeJSON(filepath.Join(dir, GoldenFile), actions)
+}
+
+// CompareGolden compares actions with the golden file of the fixture in dir.
+// It returns an empty string when they match, and a line diff otherwise.
+func CompareGolden(dir string, actions any) (string, error) {
+	want, err := os.ReadFile(filepath.Join(dir, GoldenFile)) //nolint:gosec // fixture paths are provided by the operator
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("fixture has no %s, rerun with --update to create it", GoldenFile)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read golden file: %w", err)
+	}
+	got, err := json.MarshalIndent(actions, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode actions: %w", err)
+	}
+	return Diff(strings.TrimSpace(string(want)), strings.TrimSpace(string(got))), nil
+}
+
+// Diff returns a line diff between want and got, with removed lines prefixed by "-" and
+// added lines prefixed by "+". It returns an empty string if both are equal.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,145 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Player serves recorded exchanges instead of sending requests over the network.
+//
+// A request is matched against the recording in order of preference:
+//   - the first unused exchange with the same method, URL and request body
+//   - the first unused exchange with the same method and URL
+//   - the first unused exchange with the same method and URL path, ignoring the query
+//     (requests embedding timestamps, e.g. time-bounded searches)
+//   - the last used exchange with the same method and URL (repeated reads)
+//
+// Requests without any match fail and are reported by Misses.
+type Player struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	used      []bool
+	lastUsed  map[string]int
+	misses    []string
+}
+
+// NewPlayer creates a Player serving the given exchanges
+func NewPlayer(exchanges []Exchange) *Player {
+	return &Player{
+		exchanges: exchanges,
+		used:      make([]bool, len(exchanges)),
+		lastUsed:  map[string]int{},
+	}
+}
+
+// Wrap returns a transport wrapper that replaces any transport with the player.
+// The client name is accepted for symmetry with Recorder.Wrap; matching is done on the request only.
+func (p *Player) Wrap(_ string) func(http.RoundTripper) http.RoundTripper {
+	return func(http.RoundTripper) http.RoundTripper {
+		return p
+	}
+}
+
+// Misses returns the requests that could not be matched against the recording
+func (p *Player) Misses() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.misses...)
+}
+
+// Unused returns the recorded exchanges that were never served
+func (p *Player) Unused() []Exchange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var unused []Exchange
+	for i, exchange := range p.exchanges {
+		if !p.used[i] {
+			unused = append(unused, exchange)
+		}
+	}
+	return unused
+}
+
+// RoundTrip implements http.RoundTripper
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	requestURL := req.URL.String()
+	key := req.Method + " " + requestURL
+	index := p.match(func(e Exchange) bool {
+		return e.Method == req.Method && e.URL == requestURL && e.RequestBody == string(body)
+	})
+	if index < 0 {
+		index = p.match(func(e Exchange) bool {
+			return e.Method == req.Method && e.URL == requestURL
+		})
+	}
+	if index < 0 {
+		index = p.match(func(e Exchange) bool {
+			return e.Method == req.Method && samePath(e.URL, req.URL)
+		})
+	}
+	if index < 0 {
+		last, ok := p.lastUsed[key]
+		if !ok {
+			p.misses = append(p.misses, key)
+			return nil, fmt.Errorf("replay: no recorded response for %s", key)
+		}
+		index = last
+	}
+
+	p.used[index] = true
+	p.lastUsed[key] = index
+	return p.exchanges[index].response(req), nil
+}
+
+// match returns the index of the first unused exchange accepted by f, or -1
+func (p *Player) match(f func(Exchange) bool) int {
+	for i, exchange := range p.exchanges {
+		if !p.used[i] && f(exchange) {
+			return i
+		}
+	}
+	return -1
+}
+
+func samePath(recorded string, requested *url.URL) bool {
+	u, err := url.Parse(recorded)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == requested.Scheme && u.Host == requested.Host && u.Path == requested.Path
+}
+
+func (e Exchange) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Recorder captures the HTTP exchanges of every transport it wraps
+type Recorder struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// NewRecorder creates an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Wrap returns a transport wrapper that records the traffic of the named client
+func (r *Recorder) Wrap(client string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		return &recordingTransport{recorder: r, client: client, next: next}
+	}
+}
+
+// Exchanges returns the exchanges recorded so far, in the order their responses arrived
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// Fixture returns the recorded exchanges as a fixture described by meta
+func (r *Recorder) Fixture(meta Meta) *Fixture {
+	return &Fixture{Meta: meta, Exchanges: r.Exchanges()}
+}
+
+func (r *Recorder) add(exchange Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = append(r.exchanges, exchange)
+}
+
+type recordingTransport struct {
+	recorder *Recorder
+	client   string
+	next     http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		requestBody, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Token exchanges carry credentials and are never replayed, so they are not stored.
+	if isCredentialExchange(req, requestBody) {
+		return resp, nil
+	}
+
+	t.recorder.add(Exchange{
+		Client:      t.client,
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: string(requestBody),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(body),
+	})
+	return resp, nil
+}
+
+// isCredentialExchange reports whether req is an OAuth token request
+func isCredentialExchange(req *http.Request, body []byte) bool {
+	return req.Method == http.MethodPost &&
+		strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded") &&
+		bytes.Contains(body, []byte("grant_type="))
+}
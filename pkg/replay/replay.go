@@ -0,0 +1,104 @@
+// Package replay records the HTTP traffic of an investigation run into a fixture directory
+// and plays it back, so investigations can be rerun fully offline.
+//
+// Recording and playback happen at the transport level: the OCM connection, the backplane API
+// client, the cluster rest.Config and the customer AWS and GCP clients all accept a transport wrapper,
+// so k8sclient.Client, aws.Client, gcp.Client, ocm.Client and backplane.Client are covered without
+// touching their interfaces.
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Client names used to tag recorded exchanges
+const (
+	ClientOCM       = "ocm"
+	ClientBackplane = "backplane"
+	ClientCluster   = "cluster"
+	ClientAWS       = "aws"
+	ClientGCP       = "gcp"
+)
+
+// Fixture file names inside a fixture directory
+const (
+	MetaFile      = "meta.json"
+	ExchangesFile = "exchanges.json"
+	GoldenFile    = "actions.json"
+)
+
+// Exchange is a single recorded HTTP request and its response
+type Exchange struct {
+	Client      string `json:"client"`
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	RequestBody string `json:"request_body,omitempty"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// Meta describes the run a fixture was recorded from
+type Meta struct {
+	ClusterID     string            `json:"cluster_id"`
+	Investigation string            `json:"investigation"`
+	Params        map[string]string `json:"params,omitempty"`
+	OCMURL        string            `json:"ocm_url"`
+	BackplaneURL  string            `json:"backplane_url"`
+	RecordedAt    time.Time         `json:"recorded_at"`
+}
+
+// Fixture is the content of a fixture directory
+type Fixture struct {
+	Meta      Meta
+	Exchanges []Exchange
+}
+
+// Load reads the fixture stored in dir
+func Load(dir string) (*Fixture, error) {
+	fixture := &Fixture{}
+	if err := readJSON(filepath.Join(dir, MetaFile), &fixture.Meta); err != nil {
+		return nil, err
+	}
+	if err := readJSON(filepath.Join(dir, ExchangesFile), &fixture.Exchanges); err != nil {
+		return nil, err
+	}
+	return fixture, nil
+}
+
+// Save writes the fixture to dir, creating it if needed
+func (f *Fixture) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := writeJSON(filepath.Join(dir, MetaFile), f.Meta); err != nil {
+		return err
+	}
+	return writeJSON(filepath.Join(dir, ExchangesFile), f.Exchanges)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path) //nolint:gosec // fixture paths are provided by the operator
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package replay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","body":"` + string(body) + `"}`))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder()
+	live := &http.Client{Transport: recorder.Wrap(ClientOCM)(nil)}
+
+	mustGet(t, live, server.URL+"/clusters/abc")
+	mustPost(t, live, server.URL+"/service_logs", "application/json", "one")
+	mustPost(t, live, server.URL+"/service_logs", "application/json", "two")
+	mustPost(t, live, server.URL+"/token", "application/x-www-form-urlencoded", "grant_type=client_credentials")
+
+	exchanges := recorder.Exchanges()
+	if len(exchanges) != 3 {
+		t.Fatalf("expected 3 recorded exchanges (token request skipped), got %d", len(exchanges))
+	}
+	if exchanges[0].Client != ClientOCM || exchanges[0].ContentType != "application/json" {
+		t.Errorf("unexpected exchange metadata: %+v", exchanges[0])
+	}
+
+	dir := t.TempDir()
+	if err := recorder.Fixture(Meta{ClusterID: "abc"}).Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	fixture, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if fixture.Meta.ClusterID != "abc" || len(fixture.Exchanges) != 3 {
+		t.Fatalf("unexpected fixture: %+v", fixture)
+	}
+
+	server.Close()
+	callsBefore := calls
+	player := NewPlayer(fixture.Exchanges)
+	offline := &http.Client{Transport: player.Wrap(ClientOCM)(nil)}
+
+	// Bodies select the matching exchange, regardless of order
+	if got := mustPost(t, offline, server.URL+"/service_logs", "application/json", "two"); !strings.Contains(got, `"body":"two"`) {
+		t.Errorf("expected exact body match, got %s", got)
+	}
+	if got := mustPost(t, offline, server.URL+"/service_logs", "application/json", "changed"); !strings.Contains(got, `"body":"one"`) {
+		t.Errorf("expected fallback to the remaining exchange, got %s", got)
+	}
+	// Repeated reads reuse the last served exchange
+	for range 2 {
+		if got := mustGet(t, offline, server.URL+"/clusters/abc"); !strings.Contains(got, "/clusters/abc") {
+			t.Errorf("unexpected replayed body %s", got)
+		}
+	}
+	if _, err := offline.Get(server.URL + "/clusters/unknown"); err == nil {
+		t.Error("expected an error for an unrecorded request")
+	}
+
+	if calls != callsBefore {
+		t.Error("player must not reach the network")
+	}
+	if misses := player.Misses(); len(misses) != 1 || !strings.HasSuffix(misses[0], "/clusters/unknown") {
+		t.Errorf("unexpected misses %v", misses)
+	}
+	if unused := player.Unused(); len(unused) != 0 {
+		t.Errorf("expected every exchange to be used, got %v", unused)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	type action struct {
+		Type    string `json:"type"`
+		Summary string `json:"summary"`
+	}
+	dir := t.TempDir()
+
+	if _, err := CompareGolden(dir, nil); err == nil {
+		t.Error("expected an error for a missing golden file")
+	}
+
+	want := []action{{Type: "service_log", Summary: "a"}, {Type: "pagerduty_note", Summary: "b"}}
+	if err := WriteGolden(dir, want); err != nil {
+		t.Fatalf("WriteGolden() error = %v", err)
+	}
+	diff, err := CompareGolden(dir, want)
+	if err != nil || diff != "" {
+		t.Fatalf("expected no diff, got %q (err %v)", diff, err)
+	}
+
+	diff, err = CompareGolden(dir, []action{{Type: "service_log", Summary: "a"}, {Type: "limited_support", Summary: "b"}})
+	if err != nil {
+		t.Fatalf("CompareGolden() error = %v", err)
+	}
+	if !strings.Contains(diff, `-     "type": "pagerduty_note",`) || !strings.Contains(diff, `+     "type": "limited_support",`) {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+}
+
+func mustGet(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url) //nolint:noctx // test helper
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	return readBody(t, resp)
+}
+
+func mustPost(t *testing.T, client *http.Client, url, contentType, body string) string {
+	t.Helper()
+	resp, err := client.Post(url, contentType, strings.NewReader(body)) //nolint:noctx // test helper
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	return readBody(t, resp)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -0,0 +1,132 @@
+// Package routing moves PagerDuty incidents to dedicated escalation policies based on
+// the routing rules of the investigation config. The same engine is used by the
+// interceptor (for incidents it escalates directly) and by the controller (for
+// incidents it investigates), so every incident is routed exactly once.
+package routing
+
+import (
+	"fmt"
+	"slices"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	"github.com/openshift/configuration-anomaly-detection/pkg/logging"
+	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
+	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+)
+
+// Engine evaluates routing rules against incidents.
+type Engine struct {
+	rules        []config.RoutingRule
+	requiredKeys []string
+}
+
+// NewEngine creates a routing engine from the config's routing rules.
+// A nil config results in an engine without rules.
+func NewEngine(cfg *config.Config) *Engine {
+	if cfg == nil {
+		return &Engine{}
+	}
+	return &Engine{rules: cfg.Routing, requiredKeys: cfg.RoutingKeys()}
+}
+
+// HasRules reports whether any routing rule is configured.
+func (e *Engine) HasRules() bool {
+	return e != nil && len(e.rules) > 0
+}
+
+// Match returns the first rule matching the FilterContext, or nil if no rule matches.
+// The returned reason describes the deciding filter leaf of the matched rule.
+func (e *Engine) Match(filterCtx *types.FilterContext) (*config.RoutingRule, string, error) {
+	if !e.HasRules() {
+		return nil, "", nil
+	}
+	for i := range e.rules {
+		matched, reason, err := e.rules[i].Matches(filterCtx)
+		if err != nil {
+			return nil, "", fmt.Errorf("routing rule %q: %w", e.rules[i].Name, err)
+		}
+		if matched {
+			return &e.rules[i], reason, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// Route populates the FilterContext for the cluster, finds the first matching rule and
+// moves the incident to the rule's escalation policy. Reassignment failures are reported
+// on the incident so SRE can route it manually; only evaluation errors are returned.
+func (e *Engine) Route(pdClient pagerduty.Client, ocmClient ocm.Client, cluster *cmv1.Cluster, filterCtx *types.FilterContext) error {
+	if !e.HasRules() {
+		return nil
+	}
+
+	if err := PopulateFilterContext(ocmClient, cluster, filterCtx, e.requiredKeys); err != nil {
+		return fmt.Errorf("could not populate filter context for routing: %w", err)
+	}
+
+	rule, reason, err := e.Match(filterCtx)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		logging.Debugf("No routing rule matched cluster %s", filterCtx.ClusterID)
+		return nil
+	}
+	logging.Infof("Routing rule %q matched cluster %s: %s", rule.Name, filterCtx.ClusterID, reason)
+
+	if err := pdClient.MoveToEscalationPolicy(rule.EscalationPolicy); err != nil {
+		if noteErr := pdClient.AddNote(fmt.Sprintf("This incident matches routing rule %q and should be escalated to policy %s, but CAD failed to reassign: %v. Please manually route to the appropriate team.", rule.Name, rule.EscalationPolicy, err)); noteErr != nil {
+			logging.Warnf("Failed to add note about reassignment failure: %v", noteErr)
+		}
+		logging.Errorf("Failed to reassign to escalation policy %s: %v", rule.EscalationPolicy, err)
+		return nil
+	}
+
+	if err := pdClient.AddNote(fmt.Sprintf("Reassigned to escalation policy %s (routing rule %q: %s).", rule.EscalationPolicy, rule.Name, reason)); err != nil {
+		logging.Warnf("Failed to add note about successful reassignment: %v", err)
+	}
+	return nil
+}
+
+// PopulateFilterContext enriches the FilterContext with the cluster's OCM fields.
+// Organization and owner fields require additional OCM calls and are only fetched
+// when one of requiredKeys references them.
+func PopulateFilterContext(ocmClient ocm.Client, cluster *cmv1.Cluster, filterCtx *types.FilterContext, requiredKeys []string) error {
+	if cluster == nil {
+		return fmt.Errorf("cluster must not be nil")
+	}
+
+	filterCtx.ClusterID = cluster.ID()
+	filterCtx.ClusterName = cluster.Name()
+	filterCtx.ClusterState = string(cluster.State())
+	filterCtx.HCP = cluster.Hypershift() != nil && cluster.Hypershift().Enabled()
+	filterCtx.Product = cluster.Product().ID()
+	filterCtx.Region = cluster.Region().ID()
+
+	if cp := cluster.CloudProvider(); cp != nil {
+		filterCtx.CloudProvider = cp.ID()
+	}
+
+	// Organization ID requires a subscription lookup — only call if a filter needs it.
+	if slices.Contains(requiredKeys, config.FieldOrganizationID) {
+		orgID, err := ocmClient.GetOrganizationID(cluster.ID())
+		if err != nil {
+			return fmt.Errorf("could not populate filter context organization ID: %w", err)
+		}
+		filterCtx.OrganizationID = orgID
+	}
+
+	// Owner ID and email require subscription + account lookups — only call if a filter needs them.
+	if slices.Contains(requiredKeys, config.FieldOwnerID) || slices.Contains(requiredKeys, config.FieldOwnerEmail) {
+		creator, err := ocmClient.GetCreatorFromCluster(cluster)
+		if err != nil {
+			return fmt.Errorf("could not populate filter context owner fields: %w", err)
+		}
+		filterCtx.OwnerID = creator.ID()
+		filterCtx.OwnerEmail = creator.Email()
+	}
+
+	return nil
+}
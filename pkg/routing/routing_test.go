@@ -0,0 +1,223 @@
+package routing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+	ocmmock "github.com/openshift/configuration-anomaly-detection/pkg/ocm/mock"
+	pdmock "github.com/openshift/configuration-anomaly-detection/pkg/pagerduty/mock"
+	"github.com/openshift/configuration-anomaly-detection/pkg/types"
+	"go.uber.org/mock/gomock"
+)
+
+const testRoutingYAML = `
+routing:
+  - name: "Customer Alpha"
+    escalation_policy: POL123
+    when:
+      field: OrganizationID
+      operator: in
+      values: ["org-123"]
+  - name: "ROSA HCP in eu-west-1"
+    escalation_policy: POL456
+    when:
+      and:
+        - field: Product
+          operator: in
+          values: ["rosa"]
+        - field: HCP
+          operator: in
+          values: ["true"]
+        - field: Region
+          operator: in
+          values: ["eu-west-1"]
+  - name: "GCP CHGM"
+    escalation_policy: POL789
+    when:
+      and:
+        - field: CloudProvider
+          operator: in
+          values: ["gcp"]
+        - field: AlertName
+          operator: in
+          values: ["ClusterHasGoneMissing"]
+alerts:
+  - alert_title: "TestAlert"
+    investigations:
+      - precheck
+`
+
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	cfg, err := config.ParseConfig([]byte(testRoutingYAML), []string{"precheck"})
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	return NewEngine(cfg)
+}
+
+func newTestCluster(t *testing.T, cloud, product, region string, hcp bool) *cmv1.Cluster {
+	t.Helper()
+	cluster, err := cmv1.NewCluster().
+		ID("cluster-1").
+		CloudProvider(cmv1.NewCloudProvider().ID(cloud)).
+		Product(cmv1.NewProduct().ID(product)).
+		Region(cmv1.NewCloudRegion().ID(region)).
+		Hypershift(cmv1.NewHypershift().Enabled(hcp)).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+	return cluster
+}
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		name               string
+		cloud              string
+		product            string
+		region             string
+		hcp                bool
+		alertName          string
+		orgID              string
+		orgIDErr           error
+		moveToEPErr        error
+		expectPolicy       string
+		expectNoteContains string
+		wantErr            bool
+	}{
+		{
+			name:               "cluster in mapped org is reassigned",
+			cloud:              "aws",
+			product:            "osd",
+			region:             "us-east-1",
+			orgID:              "org-123",
+			expectPolicy:       "POL123",
+			expectNoteContains: `Reassigned to escalation policy POL123 (routing rule "Customer Alpha"`,
+		},
+		{
+			name:               "rule on product, HCP and region",
+			cloud:              "aws",
+			product:            "rosa",
+			region:             "eu-west-1",
+			hcp:                true,
+			orgID:              "org-456",
+			expectPolicy:       "POL456",
+			expectNoteContains: "POL456",
+		},
+		{
+			name:               "rule on cloud provider and alert name",
+			cloud:              "gcp",
+			product:            "osd",
+			region:             "us-central1",
+			alertName:          "ClusterHasGoneMissing",
+			orgID:              "org-456",
+			expectPolicy:       "POL789",
+			expectNoteContains: "POL789",
+		},
+		{
+			name:      "no rule matches",
+			cloud:     "gcp",
+			product:   "osd",
+			region:    "us-central1",
+			alertName: "ClusterProvisioningDelay",
+			orgID:     "org-456",
+		},
+		{
+			name:     "org lookup failure is returned",
+			cloud:    "aws",
+			product:  "osd",
+			region:   "us-east-1",
+			orgIDErr: errors.New("OCM error"),
+			wantErr:  true,
+		},
+		{
+			name:               "failed reassignment adds failure note",
+			cloud:              "aws",
+			product:            "osd",
+			region:             "us-east-1",
+			orgID:              "org-123",
+			moveToEPErr:        errors.New("invalid policy"),
+			expectPolicy:       "POL123",
+			expectNoteContains: "CAD failed to reassign",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPD := pdmock.NewMockClient(ctrl)
+			mockOCM := ocmmock.NewMockClient(ctrl)
+
+			mockOCM.EXPECT().GetOrganizationID("cluster-1").Return(tt.orgID, tt.orgIDErr).Times(1)
+			if tt.expectPolicy != "" {
+				mockPD.EXPECT().MoveToEscalationPolicy(tt.expectPolicy).Return(tt.moveToEPErr).Times(1)
+				mockPD.EXPECT().AddNote(gomock.Any()).DoAndReturn(func(note string) error {
+					if !strings.Contains(note, tt.expectNoteContains) {
+						t.Errorf("AddNote() note = %q, want to contain %q", note, tt.expectNoteContains)
+					}
+					return nil
+				}).Times(1)
+			}
+
+			engine := newTestEngine(t)
+			cluster := newTestCluster(t, tt.cloud, tt.product, tt.region, tt.hcp)
+			err := engine.Route(mockPD, mockOCM, cluster, &types.FilterContext{AlertName: tt.alertName})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Route() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRouteWithoutRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No expectations: an engine without rules must not call PD or OCM.
+	engine := NewEngine(nil)
+	if engine.HasRules() {
+		t.Fatal("expected engine without rules")
+	}
+	if err := engine.Route(pdmock.NewMockClient(ctrl), ocmmock.NewMockClient(ctrl), nil, &types.FilterContext{}); err != nil {
+		t.Errorf("Route() error = %v, want nil", err)
+	}
+}
+
+func TestMatchFirstRuleWins(t *testing.T) {
+	engine := newTestEngine(t)
+
+	rule, _, err := engine.Match(&types.FilterContext{
+		OrganizationID: "org-123",
+		CloudProvider:  "gcp",
+		AlertName:      "ClusterHasGoneMissing",
+	})
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+	if rule == nil || rule.Name != "Customer Alpha" {
+		t.Errorf("Match() = %+v, want rule \"Customer Alpha\"", rule)
+	}
+}
+
+func TestPopulateFilterContextSkipsOptionalLookups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No org or owner keys required: no OCM calls expected.
+	mockOCM := ocmmock.NewMockClient(ctrl)
+	filterCtx := &types.FilterContext{}
+
+	err := PopulateFilterContext(mockOCM, newTestCluster(t, "aws", "rosa", "us-east-1", true), filterCtx, []string{config.FieldRegion})
+	if err != nil {
+		t.Fatalf("PopulateFilterContext() error = %v", err)
+	}
+	if filterCtx.Product != "rosa" || filterCtx.Region != "us-east-1" || !filterCtx.HCP || filterCtx.CloudProvider != "aws" {
+		t.Errorf("unexpected filter context: %+v", filterCtx)
+	}
+}
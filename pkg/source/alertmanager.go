@@ -0,0 +1,119 @@
+package source
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
+)
+
+// Alertmanager is the source for Alertmanager webhook receivers. Requests are
+// authenticated with a bearer token configured in the receiver's http_config.
+type Alertmanager struct {
+	tokens    []string
+	extractor *clusterid.Extractor
+}
+
+// NewAlertmanager creates an Alertmanager source accepting any of the bearer tokens.
+func NewAlertmanager(tokens []string) *Alertmanager {
+	return &Alertmanager{tokens: tokens, extractor: clusterid.Default()}
+}
+
+// SetClusterIDExtractor sets the rules used to find the cluster ID in each alert.
+func (a *Alertmanager) SetClusterIDExtractor(extractor *clusterid.Extractor) {
+	a.extractor = extractor
+}
+
+func (a *Alertmanager) Name() string {
+	return NameAlertmanager
+}
+
+func (a *Alertmanager) Detect(header http.Header) bool {
+	return strings.HasPrefix(header.Get("Authorization"), "Bearer ")
+}
+
+// Verify succeeds if the bearer token matches any of the configured tokens.
+func (a *Alertmanager) Verify(header http.Header, _ []byte) error {
+	token, found := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return errors.New("missing bearer token")
+	}
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return nil
+		}
+	}
+	return errors.New("bearer token does not match any configured token")
+}
+
+// alertmanagerWebhook is the Alertmanager webhook payload (version 4).
+// https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type alertmanagerWebhook struct {
+	Version     string              `json:"version"`
+	Status      string              `json:"status"`
+	ExternalURL string              `json:"externalURL"`
+	Alerts      []alertmanagerAlert `json:"alerts"`
+}
+
+// alertmanagerRawAlerts holds the alerts as generic JSON for cluster ID extraction.
+type alertmanagerRawAlerts struct {
+	Alerts []map[string]any `json:"alerts"`
+}
+
+type alertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// Parse returns one incident per firing alert. Resolved alerts are ignored.
+func (a *Alertmanager) Parse(body []byte) ([]Incident, error) {
+	var payload alertmanagerWebhook
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse Alertmanager payload: %w", err)
+	}
+	if payload.Version == "" {
+		return nil, errors.New("failed to parse Alertmanager payload: missing field version")
+	}
+	var raw alertmanagerRawAlerts
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Alertmanager payload: %w", err)
+	}
+
+	incidents := make([]Incident, 0, len(payload.Alerts))
+	for i, alert := range payload.Alerts {
+		if alert.Status != "firing" {
+			continue
+		}
+		title := alert.Labels["alertname"]
+		if title == "" {
+			continue
+		}
+
+		url := alert.GeneratorURL
+		if url == "" {
+			url = payload.ExternalURL
+		}
+
+		// Alerts without a cluster ID are still returned, callers decide whether they can be handled.
+		clusterID, _, _ := a.extractor.Extract(clusterid.SourceAlertmanager, raw.Alerts[i])
+
+		incidents = append(incidents, Incident{
+			Title:     title,
+			ClusterID: clusterID,
+			Labels:    alert.Labels,
+			Handle: Handle{
+				Source: NameAlertmanager,
+				ID:     alert.Fingerprint,
+				URL:    url,
+			},
+		})
+	}
+	return incidents, nil
+}
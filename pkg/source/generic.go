@@ -0,0 +1,106 @@
+package source
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
+)
+
+const genericSignatureHeader = "X-CAD-Signature"
+
+// Generic is the source for alerting systems without a dedicated adapter. Senders post
+// a genericPayload and sign the body with HMAC-SHA256 using a shared secret:
+//
+//	X-CAD-Signature: sha256=<hex(hmac_sha256(secret, body))>
+type Generic struct {
+	secrets   []string
+	extractor *clusterid.Extractor
+}
+
+// NewGeneric creates a generic source accepting signatures made with any of secrets.
+func NewGeneric(secrets []string) *Generic {
+	return &Generic{secrets: secrets, extractor: clusterid.Default()}
+}
+
+// SetClusterIDExtractor sets the rules used to find the cluster ID in the payload.
+func (g *Generic) SetClusterIDExtractor(extractor *clusterid.Extractor) {
+	g.extractor = extractor
+}
+
+func (g *Generic) Name() string {
+	return NameGeneric
+}
+
+func (g *Generic) Detect(header http.Header) bool {
+	return header.Get(genericSignatureHeader) != ""
+}
+
+// Verify succeeds if the body signature matches any of the configured secrets.
+func (g *Generic) Verify(header http.Header, body []byte) error {
+	signature, found := strings.CutPrefix(header.Get(genericSignatureHeader), "sha256=")
+	if !found {
+		return fmt.Errorf("%s header must have the format sha256=<hex>", genericSignatureHeader)
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid %s header: %w", genericSignatureHeader, err)
+	}
+	for _, secret := range g.secrets {
+		if hmac.Equal(got, Sign(secret, body)) {
+			return nil
+		}
+	}
+	return errors.New("signature does not match any configured secret")
+}
+
+// Sign computes the HMAC-SHA256 signature of body, as expected in the X-CAD-Signature header.
+func Sign(secret string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// genericPayload is the schema accepted by the generic source.
+type genericPayload struct {
+	ID        string            `json:"id"`
+	Title     string            `json:"title"`
+	ClusterID string            `json:"cluster_id"`
+	Labels    map[string]string `json:"labels"`
+	URL       string            `json:"url"`
+}
+
+func (g *Generic) Parse(body []byte) ([]Incident, error) {
+	var payload genericPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse generic payload: %w", err)
+	}
+	if payload.ID == "" {
+		return nil, errors.New("failed to parse generic payload: missing field id")
+	}
+	if payload.Title == "" {
+		return nil, errors.New("failed to parse generic payload: missing field title")
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse generic payload: %w", err)
+	}
+	clusterID, _, _ := g.extractor.Extract(clusterid.SourceGeneric, raw)
+
+	return []Incident{{
+		Title:     payload.Title,
+		ClusterID: clusterID,
+		Labels:    payload.Labels,
+		Handle: Handle{
+			Source: NameGeneric,
+			ID:     payload.ID,
+			URL:    payload.URL,
+		},
+	}}, nil
+}
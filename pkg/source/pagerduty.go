@@ -0,0 +1,72 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/PagerDuty/go-pagerduty/webhookv3"
+	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
+)
+
+const pagerDutySignatureHeader = "X-PagerDuty-Signature"
+
+// PagerDuty is the source for PagerDuty webhook v3 and event orchestration payloads.
+type PagerDuty struct {
+	tokens []string
+}
+
+// NewPagerDuty creates a PagerDuty source verifying signatures against any of tokens.
+func NewPagerDuty(tokens []string) *PagerDuty {
+	return &PagerDuty{tokens: tokens}
+}
+
+func (p *PagerDuty) Name() string {
+	return NamePagerDuty
+}
+
+func (p *PagerDuty) Detect(header http.Header) bool {
+	return header.Get(pagerDutySignatureHeader) != ""
+}
+
+// Verify succeeds if the webhook signature matches any of the configured tokens.
+func (p *PagerDuty) Verify(header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request for signature verification: %w", err)
+	}
+	req.Header = header
+
+	var sigErrs []error
+	for _, token := range p.tokens {
+		// VerifySignature restores the request body, so the request can be reused.
+		err := webhookv3.VerifySignature(req, token)
+		if err == nil {
+			return nil
+		}
+		sigErrs = append(sigErrs, err)
+	}
+	if len(sigErrs) == 0 {
+		return errors.New("no PagerDuty signature tokens configured")
+	}
+	return errors.Join(sigErrs...)
+}
+
+// Parse normalizes the payload without calling the PagerDuty API. Cluster IDs are not part
+// of PagerDuty webhooks and need to be retrieved with pagerduty.Client.RetrieveClusterID.
+func (p *PagerDuty) Parse(body []byte) ([]Incident, error) {
+	data, err := pagerduty.ParseIncidentData(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PagerDuty payload: %w", err)
+	}
+	return []Incident{{
+		Title: data.IncidentTitle,
+		Handle: Handle{
+			Source: NamePagerDuty,
+			ID:     data.IncidentID,
+			URL:    data.IncidentRef,
+		},
+	}}, nil
+}
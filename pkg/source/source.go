@@ -0,0 +1,99 @@
+// Package source abstracts the systems CAD receives alerts from. Each adapter
+// authenticates the webhook request of its system and normalizes the payload into
+// Incidents, so the same investigations can run regardless of where an alert came from.
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/clusterid"
+)
+
+// Source names.
+const (
+	NamePagerDuty    = clusterid.SourcePagerDuty
+	NameAlertmanager = clusterid.SourceAlertmanager
+	NameGeneric      = clusterid.SourceGeneric
+)
+
+// Incident is the normalized representation of an alert from any source.
+type Incident struct {
+	Title     string            `json:"title"`
+	ClusterID string            `json:"cluster_id,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Handle    Handle            `json:"handle"`
+}
+
+// Handle references an incident in the system it originates from, so notes and
+// escalations can be sent back to it.
+type Handle struct {
+	// Source is the name of the originating source (e.g. "alertmanager").
+	Source string `json:"source"`
+	// ID identifies the incident within the source (PD incident ID, alert fingerprint, ...).
+	ID string `json:"id"`
+	// URL links to the incident or the alert's origin, if the source provides one.
+	URL string `json:"url,omitempty"`
+}
+
+// Source authenticates and normalizes webhook payloads of one alerting system.
+type Source interface {
+	// Name returns the source name.
+	Name() string
+	// Detect reports whether a request with the given header was sent by this source.
+	Detect(header http.Header) bool
+	// Verify authenticates the request.
+	Verify(header http.Header, body []byte) error
+	// Parse normalizes the payload into incidents. Sources that batch alerts
+	// (e.g. Alertmanager) may return several incidents.
+	Parse(body []byte) ([]Incident, error)
+}
+
+// ClusterIDConfigurable is implemented by sources that extract the cluster ID from
+// the payload with configurable rules.
+type ClusterIDConfigurable interface {
+	SetClusterIDExtractor(extractor *clusterid.Extractor)
+}
+
+// Select returns the first source detecting the request, or fallback if none does.
+func Select(sources []Source, header http.Header, fallback Source) Source {
+	for _, s := range sources {
+		if s.Detect(header) {
+			return s
+		}
+	}
+	return fallback
+}
+
+// Encode serializes normalized incidents for handing them to the investigation pipeline.
+func Encode(incidents ...Incident) ([]byte, error) {
+	data, err := json.Marshal(incidents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode incidents: %w", err)
+	}
+	return data, nil
+}
+
+// Decode parses a payload produced by Encode, or a single incident as produced by
+// earlier versions. It returns false if the payload is not made of normalized incidents,
+// e.g. because it is a raw PagerDuty webhook.
+func Decode(payload []byte) ([]Incident, bool) {
+	var incidents []Incident
+	if err := json.Unmarshal(payload, &incidents); err != nil {
+		var incident Incident
+		if err := json.Unmarshal(payload, &incident); err != nil {
+			return nil, false
+		}
+		incidents = []Incident{incident}
+	}
+	if len(incidents) == 0 {
+		return nil, false
+	}
+	for _, incident := range incidents {
+		if incident.Handle.Source == "" || incident.Title == "" {
+			return nil, false
+		}
+	}
+	return incidents, true
+}
@@ -0,0 +1,175 @@
+package source
+
+import (
+	"encoding/hex"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestAlertmanagerParse(t *testing.T) {
+	body, err := os.ReadFile("testdata/alertmanager.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	incidents, err := NewAlertmanager(nil).Parse(body)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("expected 1 firing incident, got %d", len(incidents))
+	}
+
+	inc := incidents[0]
+	if inc.Title != "ClusterProvisioningDelay" {
+		t.Errorf("Title = %q", inc.Title)
+	}
+	if inc.ClusterID != "2abc3def4ghi5jkl6mno7pqr8stu9vwx" {
+		t.Errorf("ClusterID = %q", inc.ClusterID)
+	}
+	if inc.Labels["severity"] != "critical" {
+		t.Errorf("Labels = %v", inc.Labels)
+	}
+	if inc.Handle.Source != NameAlertmanager || inc.Handle.ID != "a1b2c3d4e5f60718" {
+		t.Errorf("Handle = %+v", inc.Handle)
+	}
+}
+
+func TestAlertmanagerParseRejectsInvalidPayload(t *testing.T) {
+	if _, err := NewAlertmanager(nil).Parse([]byte(`{"alerts": []}`)); err == nil {
+		t.Error("expected error for payload without version")
+	}
+}
+
+func TestAlertmanagerVerify(t *testing.T) {
+	am := NewAlertmanager([]string{"token-1", "token-2"})
+
+	tests := []struct {
+		name    string
+		auth    string
+		wantErr bool
+	}{
+		{name: "matching token", auth: "Bearer token-2"},
+		{name: "wrong token", auth: "Bearer token-3", wantErr: true},
+		{name: "missing token", auth: "", wantErr: true},
+		{name: "basic auth", auth: "Basic dXNlcjpwYXNz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Authorization", tt.auth)
+			if err := am.Verify(header, nil); (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenericVerifyAndParse(t *testing.T) {
+	body := []byte(`{"id":"evt-1","title":"ClusterHasGoneMissing","cluster_id":"cluster-1","labels":{"env":"staging"}}`)
+	g := NewGeneric([]string{"other-secret", "shared-secret"})
+
+	header := http.Header{}
+	header.Set(genericSignatureHeader, "sha256="+hex.EncodeToString(Sign("shared-secret", body)))
+	if err := g.Verify(header, body); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	header.Set(genericSignatureHeader, "sha256="+hex.EncodeToString(Sign("wrong-secret", body)))
+	if err := g.Verify(header, body); err == nil {
+		t.Error("expected signature made with an unknown secret to be rejected")
+	}
+
+	header.Set(genericSignatureHeader, hex.EncodeToString(Sign("shared-secret", body)))
+	if err := g.Verify(header, body); err == nil {
+		t.Error("expected signature without sha256= prefix to be rejected")
+	}
+
+	incidents, err := g.Parse(body)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(incidents) != 1 || incidents[0].ClusterID != "cluster-1" || incidents[0].Handle.ID != "evt-1" {
+		t.Errorf("unexpected incidents: %+v", incidents)
+	}
+
+	if _, err := g.Parse([]byte(`{"title":"missing id"}`)); err == nil {
+		t.Error("expected error for payload without id")
+	}
+}
+
+func TestPagerDutyParse(t *testing.T) {
+	incidents, err := NewPagerDuty(nil).Parse([]byte(`{"__pd_metadata":{"incident":{"id":"Q0OGN8S5WIM0FX"}}}`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(incidents) != 1 || incidents[0].Handle.ID != "Q0OGN8S5WIM0FX" || incidents[0].Handle.Source != NamePagerDuty {
+		t.Errorf("unexpected incidents: %+v", incidents)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	pd := NewPagerDuty(nil)
+	sources := []Source{pd, NewGeneric(nil), NewAlertmanager(nil)}
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   string
+	}{
+		{name: "pagerduty signature", header: map[string]string{"X-PagerDuty-Signature": "v1=abc"}, want: NamePagerDuty},
+		{name: "generic signature", header: map[string]string{"X-CAD-Signature": "sha256=abc"}, want: NameGeneric},
+		{name: "bearer token", header: map[string]string{"Authorization": "Bearer abc"}, want: NameAlertmanager},
+		{name: "unknown request falls back", header: map[string]string{}, want: NamePagerDuty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+			if got := Select(sources, header, pd).Name(); got != tt.want {
+				t.Errorf("Select() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	in := []Incident{
+		{Title: "ClusterHasGoneMissing", ClusterID: "cluster-1", Handle: Handle{Source: NameGeneric, ID: "evt-1"}},
+		{Title: "ClusterHasGoneMissing", ClusterID: "cluster-2", Handle: Handle{Source: NameGeneric, ID: "evt-2"}},
+	}
+	data, err := Encode(in...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, ok := Decode(data)
+	if !ok {
+		t.Fatal("expected normalized incidents to decode")
+	}
+	if len(out) != len(in) {
+		t.Fatalf("Decode() returned %d incidents, want %d", len(out), len(in))
+	}
+	for i := range in {
+		if out[i].Title != in[i].Title || out[i].ClusterID != in[i].ClusterID || out[i].Handle != in[i].Handle {
+			t.Errorf("Decode()[%d] = %+v, want %+v", i, out[i], in[i])
+		}
+	}
+
+	single, ok := Decode([]byte(`{"title":"ClusterHasGoneMissing","cluster_id":"cluster-1","handle":{"source":"generic","id":"evt-1"}}`))
+	if !ok || len(single) != 1 || single[0].ClusterID != "cluster-1" {
+		t.Errorf("expected a single incident payload to decode, got %+v", single)
+	}
+
+	if _, ok := Decode([]byte(`{"event":{"event_type":"incident.triggered"}}`)); ok {
+		t.Error("expected raw PagerDuty webhook not to decode as normalized incident")
+	}
+	if _, ok := Decode([]byte(`[]`)); ok {
+		t.Error("expected an empty batch not to decode")
+	}
+}
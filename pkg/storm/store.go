@@ -0,0 +1,101 @@
+package storm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stateKey is the ConfigMap key the state is saved in
+const stateKey = "state.json"
+
+// ErrConflict is returned by Store.Save when the state was saved by someone else since it was loaded.
+var ErrConflict = errors.New("storm state was modified concurrently")
+
+// Store saves the state of a tracker. The state is opaque to the store.
+type Store interface {
+	// Load returns the saved state, or nil if none was saved yet, and its version.
+	Load(ctx context.Context) (data []byte, version string, err error)
+	// Save replaces the state if it is still at version, and returns ErrConflict otherwise.
+	Save(ctx context.Context, data []byte, version string) error
+}
+
+// MemoryStore keeps the state in memory. It is only shared by the trackers of a single process.
+type MemoryStore struct {
+	mu      sync.Mutex
+	data    []byte
+	version int
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(_ context.Context) ([]byte, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data, strconv.Itoa(s.version), nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, data []byte, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if version != strconv.Itoa(s.version) {
+		return ErrConflict
+	}
+	s.data = data
+	s.version++
+	return nil
+}
+
+// ConfigMapStore keeps the state in a ConfigMap, so all replicas of the interceptor share it.
+// Concurrent saves are detected with the resource version of the ConfigMap.
+type ConfigMapStore struct {
+	client    client.Client
+	namespace string
+	name      string
+}
+
+// NewConfigMapStore creates a store for the ConfigMap name in namespace. The ConfigMap is created on the first save.
+func NewConfigMapStore(c client.Client, namespace, name string) *ConfigMapStore {
+	return &ConfigMapStore{client: c, namespace: namespace, name: name}
+}
+
+// Load implements Store. The version is empty if the ConfigMap doesn't exist yet.
+func (s *ConfigMapStore) Load(ctx context.Context) ([]byte, string, error) {
+	cm := &corev1.ConfigMap{}
+	err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.name}, cm)
+	if apierrors.IsNotFound(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+	return []byte(cm.Data[stateKey]), cm.ResourceVersion, nil
+}
+
+// Save implements Store.
+func (s *ConfigMapStore) Save(ctx context.Context, data []byte, version string) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name, ResourceVersion: version},
+		Data:       map[string]string{stateKey: string(data)},
+	}
+	var err error
+	if version == "" {
+		err = s.client.Create(ctx, cm)
+	} else {
+		err = s.client.Update(ctx, cm)
+	}
+	if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save configmap %s/%s: %w", s.namespace, s.name, err)
+	}
+	return nil
+}
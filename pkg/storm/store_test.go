@@ -0,0 +1,36 @@
+package storm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewConfigMapStore(fake.NewClientBuilder().Build(), "cad", "cad-storm-state")
+
+	data, version, err := store.Load(ctx)
+	if err != nil || data != nil || version != "" {
+		t.Fatalf("expected no state before the first save, got %q, %q, %v", data, version, err)
+	}
+	if err := store.Save(ctx, []byte(`{"a":1}`), version); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Save(ctx, []byte(`{"a":2}`), ""); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict when the configmap was created concurrently, got %v", err)
+	}
+
+	data, version, err = store.Load(ctx)
+	if err != nil || string(data) != `{"a":1}` {
+		t.Fatalf("expected the saved state, got %q, %v", data, err)
+	}
+	if err := store.Save(ctx, []byte(`{"a":3}`), version); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.Save(ctx, []byte(`{"a":4}`), version); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for a stale version, got %v", err)
+	}
+}
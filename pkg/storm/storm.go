@@ -0,0 +1,253 @@
+// Package storm detects incident storms: many clusters raising the same alert
+// within a short window while sharing a region, management cluster or AWS account. Such
+// incidents usually have a shared cause and are tagged with a common correlation ID.
+package storm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+)
+
+// maxUpdateAttempts is how often the tracker retries an update that conflicted with another tracker
+const maxUpdateAttempts = 5
+
+// Incident describes a single incident observed by the tracker.
+type Incident struct {
+	// ID identifies the incident, e.g. the PagerDuty incident ID. Incidents with an ID that are observed
+	// before their storm was detected are returned as Correlation.Uncorrelated once it is.
+	ID                string
+	ClusterID         string
+	AlertName         string
+	Region            string
+	ManagementCluster string
+	AWSAccount        string
+}
+
+// Correlation is returned when an incident is part of a storm.
+type Correlation struct {
+	// ID is shared by all incidents of the same storm.
+	ID string
+	// Dimension is the grouping dimension that crossed the threshold (e.g. "region").
+	Dimension string
+	// Value is the dimension value shared by the correlated incidents (e.g. "us-east-1").
+	Value string
+	// AlertName is the alert the correlated incidents were raised for.
+	AlertName string
+	// ClusterCount is the number of distinct clusters seen within the window.
+	ClusterCount int
+	// Uncorrelated are the IDs of earlier incidents of the storm that were observed before it was
+	// detected, so they weren't correlated. They are only returned with the first correlation.
+	Uncorrelated []string
+}
+
+// Summary returns a human readable one-line description of the correlation.
+func (c *Correlation) Summary() string {
+	if c.Dimension == config.StormGroupByAlertName {
+		return fmt.Sprintf("alert %q fleet-wide", c.AlertName)
+	}
+	return fmt.Sprintf("alert %q, %s %s", c.AlertName, c.Dimension, c.Value)
+}
+
+// bucket tracks the clusters of a single group. Its exported fields are saved by the store.
+type bucket struct {
+	Dimension string `json:"dimension"`
+	Value     string `json:"value"`
+	AlertName string `json:"alert_name"`
+	// Started is the time the first incident of the group was observed. Together with the key it
+	// derives the correlation ID, so every replica sharing the store returns the same ID.
+	Started  time.Time            `json:"started"`
+	LastSeen map[string]time.Time `json:"last_seen"` // cluster ID -> last time an incident was observed
+	// Uncorrelated are the incidents of the group that were observed while no storm was detected.
+	// They are handed to the first correlation of the group, so they can be noted retroactively.
+	Uncorrelated []pendingIncident `json:"uncorrelated,omitempty"`
+}
+
+type pendingIncident struct {
+	ID   string    `json:"id"`
+	Seen time.Time `json:"seen"`
+}
+
+// Tracker keeps a sliding window of recent incidents in a store. It is safe for concurrent use,
+// and trackers sharing a store, e.g. in several replicas of the interceptor, count their incidents together.
+type Tracker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	groupBy   []string
+	store     Store
+	now       func() time.Time
+}
+
+// NewTracker creates a tracker from the storm configuration, which keeps its state in memory.
+func NewTracker(cfg *config.StormConfig) *Tracker {
+	return NewSharedTracker(cfg, &MemoryStore{})
+}
+
+// NewSharedTracker creates a tracker from the storm configuration, which keeps its state in store.
+func NewSharedTracker(cfg *config.StormConfig, store Store) *Tracker {
+	return &Tracker{
+		threshold: cfg.Threshold,
+		window:    cfg.GetWindow(),
+		groupBy:   cfg.GroupBy,
+		store:     store,
+		now:       time.Now,
+	}
+}
+
+// Observe records an incident and returns the correlation it belongs to, or nil
+// if none of its groups crossed the threshold. When several groups crossed the
+// threshold, the one with the most clusters wins.
+func (t *Tracker) Observe(ctx context.Context, inc Incident) (*Correlation, error) {
+	if inc.ClusterID == "" || inc.AlertName == "" {
+		return nil, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	var best *Correlation
+	err := t.update(ctx, func(buckets map[string]*bucket) {
+		best = t.observe(buckets, inc, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return best, nil
+}
+
+// observe records the incident in buckets. It is called again with fresh buckets when
+// the store rejected the previous attempt, so it must only change buckets.
+func (t *Tracker) observe(buckets map[string]*bucket, inc Incident, now time.Time) *Correlation {
+	t.prune(buckets, now)
+
+	var best *Correlation
+	var bestBucket *bucket
+	var seenIn []*bucket
+	for _, dim := range t.groupBy {
+		value := dimensionValue(dim, inc)
+		if value == "" {
+			continue
+		}
+
+		key := dim + "|" + inc.AlertName + "|" + value
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{Dimension: dim, Value: value, AlertName: inc.AlertName, Started: now, LastSeen: make(map[string]time.Time)}
+			buckets[key] = b
+		}
+		b.LastSeen[inc.ClusterID] = now
+		seenIn = append(seenIn, b)
+
+		if len(b.LastSeen) < t.threshold {
+			continue
+		}
+		if best == nil || len(b.LastSeen) > best.ClusterCount {
+			best = &Correlation{
+				ID:           newCorrelationID(key, b.Started),
+				Dimension:    b.Dimension,
+				Value:        b.Value,
+				AlertName:    b.AlertName,
+				ClusterCount: len(b.LastSeen),
+			}
+			bestBucket = b
+		}
+	}
+
+	if best == nil {
+		if inc.ID != "" {
+			for _, b := range seenIn {
+				b.Uncorrelated = append(b.Uncorrelated, pendingIncident{ID: inc.ID, Seen: now})
+			}
+		}
+		return nil
+	}
+	for _, pending := range bestBucket.Uncorrelated {
+		if pending.ID != inc.ID {
+			best.Uncorrelated = append(best.Uncorrelated, pending.ID)
+		}
+	}
+	bestBucket.Uncorrelated = nil
+	return best
+}
+
+// prune drops clusters and uncorrelated incidents that have not been seen within the window and
+// removes empty buckets, which also ends the storm and its correlation ID.
+func (t *Tracker) prune(buckets map[string]*bucket, now time.Time) {
+	for key, b := range buckets {
+		for clusterID, seen := range b.LastSeen {
+			if now.Sub(seen) > t.window {
+				delete(b.LastSeen, clusterID)
+			}
+		}
+		b.Uncorrelated = slices.DeleteFunc(b.Uncorrelated, func(pending pendingIncident) bool {
+			return now.Sub(pending.Seen) > t.window
+		})
+		if len(b.LastSeen) == 0 {
+			delete(buckets, key)
+		}
+	}
+}
+
+// update loads the buckets from the store, applies fn and saves them, retrying when
+// another tracker saved the buckets in the meantime.
+func (t *Tracker) update(ctx context.Context, fn func(buckets map[string]*bucket)) error {
+	for attempt := 1; ; attempt++ {
+		data, version, err := t.store.Load(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load storm state: %w", err)
+		}
+		buckets := make(map[string]*bucket)
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &buckets); err != nil {
+				return fmt.Errorf("failed to parse storm state: %w", err)
+			}
+		}
+
+		fn(buckets)
+
+		data, err = json.Marshal(buckets)
+		if err != nil {
+			return fmt.Errorf("failed to encode storm state: %w", err)
+		}
+		err = t.store.Save(ctx, data, version)
+		if errors.Is(err, ErrConflict) && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save storm state: %w", err)
+		}
+		return nil
+	}
+}
+
+func dimensionValue(dimension string, inc Incident) string {
+	switch dimension {
+	case config.StormGroupByRegion:
+		return inc.Region
+	case config.StormGroupByManagementCluster:
+		return inc.ManagementCluster
+	case config.StormGroupByAlertName:
+		return inc.AlertName
+	case config.StormGroupByAWSAccount:
+		return inc.AWSAccount
+	default:
+		return ""
+	}
+}
+
+// newCorrelationID derives a short, stable identifier from the bucket key and the
+// time the first incident of the bucket was observed.
+func newCorrelationID(key string, started time.Time) string {
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("storm-%s-%s", hex.EncodeToString(sum[:4]), started.UTC().Format("20060102T150405"))
+}
@@ -0,0 +1,223 @@
+package storm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift/configuration-anomaly-detection/pkg/config"
+)
+
+func newTestTracker(threshold int, groupBy ...string) (*Tracker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tr := NewTracker(&config.StormConfig{Threshold: threshold, WindowMinutes: 10, GroupBy: groupBy})
+	tr.now = func() time.Time { return now }
+	return tr, &now
+}
+
+// observe observes inc and fails the test if the tracker returns an error
+func observe(t *testing.T, tr *Tracker, inc Incident) *Correlation {
+	t.Helper()
+	c, err := tr.Observe(context.Background(), inc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestObserveBelowThreshold(t *testing.T) {
+	tr, _ := newTestTracker(3, config.StormGroupByRegion)
+
+	for i := range 2 {
+		if c := observe(t, tr, Incident{ClusterID: fmt.Sprintf("c%d", i), AlertName: "chgm", Region: "us-east-1"}); c != nil {
+			t.Fatalf("incident %d: expected no correlation, got %+v", i, c)
+		}
+	}
+}
+
+func TestObserveCrossesThreshold(t *testing.T) {
+	tr, _ := newTestTracker(3, config.StormGroupByRegion)
+
+	var last *Correlation
+	for i := range 4 {
+		last = observe(t, tr, Incident{ClusterID: fmt.Sprintf("c%d", i), AlertName: "chgm", Region: "us-east-1"})
+	}
+	if last == nil {
+		t.Fatal("expected correlation after threshold crossed")
+	}
+	if last.ClusterCount != 4 {
+		t.Errorf("ClusterCount = %d, want 4", last.ClusterCount)
+	}
+	if last.Dimension != config.StormGroupByRegion || last.Value != "us-east-1" {
+		t.Errorf("unexpected dimension %s=%s", last.Dimension, last.Value)
+	}
+
+	// The correlation ID stays stable for the duration of the storm.
+	again := observe(t, tr, Incident{ClusterID: "c9", AlertName: "chgm", Region: "us-east-1"})
+	if again == nil || again.ID != last.ID {
+		t.Errorf("expected stable correlation ID %q, got %+v", last.ID, again)
+	}
+}
+
+func TestObserveDeduplicatesClusters(t *testing.T) {
+	tr, _ := newTestTracker(2, config.StormGroupByRegion)
+
+	for range 3 {
+		if c := observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1"}); c != nil {
+			t.Fatalf("expected repeated incidents for one cluster not to form a storm, got %+v", c)
+		}
+	}
+}
+
+func TestObserveSeparatesAlertsAndRegions(t *testing.T) {
+	tr, _ := newTestTracker(2, config.StormGroupByRegion)
+
+	observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1"})
+	if c := observe(t, tr, Incident{ClusterID: "c2", AlertName: "cpd", Region: "us-east-1"}); c != nil {
+		t.Errorf("different alerts must not correlate, got %+v", c)
+	}
+	if c := observe(t, tr, Incident{ClusterID: "c3", AlertName: "chgm", Region: "eu-west-1"}); c != nil {
+		t.Errorf("different regions must not correlate, got %+v", c)
+	}
+}
+
+func TestObserveWindowExpiry(t *testing.T) {
+	tr, now := newTestTracker(2, config.StormGroupByRegion)
+
+	observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1"})
+	first := observe(t, tr, Incident{ClusterID: "c2", AlertName: "chgm", Region: "us-east-1"})
+	if first == nil {
+		t.Fatal("expected correlation")
+	}
+
+	*now = now.Add(11 * time.Minute)
+	if c := observe(t, tr, Incident{ClusterID: "c3", AlertName: "chgm", Region: "us-east-1"}); c != nil {
+		t.Errorf("expected storm to end after window expiry, got %+v", c)
+	}
+	data, _, _ := tr.store.Load(context.Background())
+	var buckets map[string]*bucket
+	if err := json.Unmarshal(data, &buckets); err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 1 || len(buckets["region|chgm|us-east-1"].LastSeen) != 1 {
+		t.Errorf("expected stale clusters to be pruned, got %+v", buckets)
+	}
+}
+
+func TestObservePicksLargestGroup(t *testing.T) {
+	tr, _ := newTestTracker(2, config.StormGroupByRegion, config.StormGroupByManagementCluster)
+
+	observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1", ManagementCluster: "mc-1"})
+	observe(t, tr, Incident{ClusterID: "c2", AlertName: "chgm", Region: "us-east-1", ManagementCluster: "mc-2"})
+	c := observe(t, tr, Incident{ClusterID: "c3", AlertName: "chgm", Region: "us-east-1", ManagementCluster: "mc-1"})
+	if c == nil {
+		t.Fatal("expected correlation")
+	}
+	if c.Dimension != config.StormGroupByRegion || c.ClusterCount != 3 {
+		t.Errorf("expected region group with 3 clusters, got %+v", c)
+	}
+}
+
+func TestObserveSkipsEmptyDimensions(t *testing.T) {
+	tr, _ := newTestTracker(2, config.StormGroupByManagementCluster)
+
+	observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm"})
+	if c := observe(t, tr, Incident{ClusterID: "c2", AlertName: "chgm"}); c != nil {
+		t.Errorf("classic clusters without a management cluster must not correlate, got %+v", c)
+	}
+}
+
+func TestObserveGroupsByAWSAccount(t *testing.T) {
+	tr, _ := newTestTracker(2, config.StormGroupByAWSAccount)
+
+	observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1", AWSAccount: "123456789012"})
+	if c := observe(t, tr, Incident{ClusterID: "c2", AlertName: "chgm", Region: "eu-west-1", AWSAccount: "210987654321"}); c != nil {
+		t.Errorf("clusters in different accounts must not correlate, got %+v", c)
+	}
+	c := observe(t, tr, Incident{ClusterID: "c3", AlertName: "chgm", Region: "eu-west-1", AWSAccount: "123456789012"})
+	if c == nil {
+		t.Fatal("expected correlation")
+	}
+	if c.Dimension != config.StormGroupByAWSAccount || c.Value != "123456789012" || c.ClusterCount != 2 {
+		t.Errorf("expected aws_account group with 2 clusters, got %+v", c)
+	}
+}
+
+func TestObserveSharesStore(t *testing.T) {
+	// Replicas of the interceptor share their store, each observes a share of the incidents
+	replicas := make([]*Tracker, 3)
+	store := &MemoryStore{}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := range replicas {
+		replicas[i] = NewSharedTracker(&config.StormConfig{Threshold: 3, WindowMinutes: 10, GroupBy: []string{config.StormGroupByRegion}}, store)
+		replicas[i].now = func() time.Time { return now }
+	}
+
+	for i := range 2 {
+		if c := observe(t, replicas[i], Incident{ClusterID: fmt.Sprintf("c%d", i), AlertName: "chgm", Region: "us-east-1"}); c != nil {
+			t.Fatalf("incident %d: expected no correlation, got %+v", i, c)
+		}
+	}
+	now = now.Add(3 * time.Minute)
+	first := observe(t, replicas[2], Incident{ClusterID: "c2", AlertName: "chgm", Region: "us-east-1"})
+	if first == nil || first.ClusterCount != 3 {
+		t.Fatalf("expected the incidents of all replicas to be counted, got %+v", first)
+	}
+	again := observe(t, replicas[0], Incident{ClusterID: "c3", AlertName: "chgm", Region: "us-east-1"})
+	if again == nil || again.ID != first.ID {
+		t.Errorf("expected replicas to return correlation ID %q, got %+v", first.ID, again)
+	}
+}
+
+func TestObserveReturnsUncorrelatedIncidents(t *testing.T) {
+	tr, _ := newTestTracker(3, config.StormGroupByRegion)
+
+	observe(t, tr, Incident{ID: "P1", ClusterID: "c1", AlertName: "chgm", Region: "us-east-1"})
+	observe(t, tr, Incident{ID: "P2", ClusterID: "c2", AlertName: "chgm", Region: "us-east-1"})
+	observe(t, tr, Incident{ID: "P3", ClusterID: "c3", AlertName: "chgm", Region: "eu-west-1"})
+	c := observe(t, tr, Incident{ID: "P4", ClusterID: "c4", AlertName: "chgm", Region: "us-east-1"})
+	if c == nil {
+		t.Fatal("expected correlation")
+	}
+	if fmt.Sprint(c.Uncorrelated) != "[P1 P2]" {
+		t.Errorf("expected the earlier incidents of the storm, got %v", c.Uncorrelated)
+	}
+
+	// The earlier incidents are only returned once
+	c = observe(t, tr, Incident{ID: "P5", ClusterID: "c5", AlertName: "chgm", Region: "us-east-1"})
+	if c == nil || len(c.Uncorrelated) != 0 {
+		t.Errorf("expected no uncorrelated incidents, got %+v", c)
+	}
+}
+
+// conflictingStore fails the first conflicts saves with ErrConflict
+type conflictingStore struct {
+	MemoryStore
+	conflicts int
+}
+
+func (s *conflictingStore) Save(ctx context.Context, data []byte, version string) error {
+	if s.conflicts > 0 {
+		s.conflicts--
+		return ErrConflict
+	}
+	return s.MemoryStore.Save(ctx, data, version)
+}
+
+func TestObserveRetriesConflicts(t *testing.T) {
+	cfg := &config.StormConfig{Threshold: 2, WindowMinutes: 10, GroupBy: []string{config.StormGroupByRegion}}
+
+	tr := NewSharedTracker(cfg, &conflictingStore{conflicts: maxUpdateAttempts - 1})
+	observe(t, tr, Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1"})
+	if c := observe(t, tr, Incident{ClusterID: "c2", AlertName: "chgm", Region: "us-east-1"}); c == nil {
+		t.Error("expected correlation after retried saves")
+	}
+
+	tr = NewSharedTracker(cfg, &conflictingStore{conflicts: maxUpdateAttempts})
+	if _, err := tr.Observe(context.Background(), Incident{ClusterID: "c1", AlertName: "chgm", Region: "us-east-1"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict once the attempts are exhausted, got %v", err)
+	}
+}
@@ -3,9 +3,9 @@ package types
 import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/configuration-anomaly-detection/pkg/backplane"
+	"github.com/openshift/configuration-anomaly-detection/pkg/incident"
 	"github.com/openshift/configuration-anomaly-detection/pkg/notewriter"
 	"github.com/openshift/configuration-anomaly-detection/pkg/ocm"
-	"github.com/openshift/configuration-anomaly-detection/pkg/pagerduty"
 	"go.uber.org/zap"
 )
 
@@ -16,7 +16,7 @@ type ExecutionContext struct {
 
 	// Client instances
 	OCMClient       ocm.Client
-	PDClient        pagerduty.Client
+	Incident        incident.Backend
 	BackplaneClient backplane.Client
 
 	// NoteWriter for appending action results to notes
@@ -35,6 +35,12 @@ type FilterContext struct {
 	// ClusterState is the current cluster state (e.g. "ready", "uninstalling").
 	ClusterState string
 
+	// Product is the OCM product identifier (e.g. "rosa", "osd").
+	Product string
+
+	// Region is the cloud region the cluster runs in (e.g. "us-east-1").
+	Region string
+
 	// --- PagerDuty fields ---
 
 	// AlertName is the name of the alert as matched by investigation.AlertTitle().